@@ -15,4 +15,5 @@ func init() {
 	//Cmd.AddCommand(cmdArtifactUpload())
 	Cmd.AddCommand(cmdArtifactDownload())
 	Cmd.AddCommand(cmdArtifactList())
+	Cmd.AddCommand(cmdArtifactPromote())
 }
@@ -6,7 +6,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var environment string
+var environment, reference string
 
 func cmdArtifactDownload() *cobra.Command {
 	cmd := &cobra.Command{
@@ -17,10 +17,18 @@ func cmdArtifactDownload() *cobra.Command {
 		Aliases: []string{"dl"},
 	}
 	cmd.Flags().StringVarP(&environment, "env", "", "", "environment name")
+	cmd.Flags().StringVarP(&reference, "ref", "", "", "download the artifacts of a reference <projectName>/<applicationName>/<pipelineName>/<environment>/<buildNumber|tag|@channel>[/artifactName] instead")
 	return cmd
 }
 
 func downloadArtifacts(cmd *cobra.Command, args []string) {
+	if reference != "" {
+		if err := sdk.DownloadArtifactsByRef(reference, "."); err != nil {
+			sdk.Exit("Error: Cannot download artifacts of %s (%s)\n", reference, err)
+		}
+		return
+	}
+
 	if len(args) == 5 {
 		downloadArtifact(args)
 		return
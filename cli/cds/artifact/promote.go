@@ -0,0 +1,73 @@
+package artifact
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+
+	"github.com/spf13/cobra"
+)
+
+var promoteEnv string
+
+func cmdArtifactPromote() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote",
+		Short: "cds artifact promote <projectName> <applicationName> <pipelineName> <buildNumber> <channel>",
+		Long: `Promote the artifacts of a build in a channel, such as staging or prod.
+
+The artifacts of the build promoted in a channel are downloaded by other pipelines with the reference
+<projectName>/<applicationName>/<pipelineName>/<environment>/@<channel>`,
+		Run: promoteArtifacts,
+	}
+	cmd.Flags().StringVarP(&promoteEnv, "env", "", "", "environment name")
+	cmd.AddCommand(cmdArtifactPromoteList())
+	return cmd
+}
+
+func promoteArtifacts(cmd *cobra.Command, args []string) {
+	if len(args) != 5 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+	project := args[0]
+	appName := args[1]
+	pipeline := args[2]
+	channel := args[4]
+
+	buildNumber, err := strconv.ParseInt(args[3], 10, 64)
+	if err != nil {
+		sdk.Exit("Error: build number must be an integer (%s)\n", err)
+	}
+
+	if err := sdk.PromoteArtifacts(project, appName, pipeline, promoteEnv, buildNumber, channel); err != nil {
+		sdk.Exit("Error: Cannot promote build %d of %s-%s-%s in %s (%s)\n", buildNumber, project, appName, pipeline, channel, err)
+	}
+	fmt.Printf("Build %d promoted in %s\n", buildNumber, channel)
+}
+
+func cmdArtifactPromoteList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "cds artifact promote list <projectName> <applicationName> <pipelineName>",
+		Long:    ``,
+		Run:     listArtifactPromotions,
+		Aliases: []string{"ls"},
+	}
+	return cmd
+}
+
+func listArtifactPromotions(cmd *cobra.Command, args []string) {
+	if len(args) != 3 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+
+	promotions, err := sdk.ListArtifactPromotions(args[0], args[1], args[2])
+	if err != nil {
+		sdk.Exit("Error: Cannot list promotions of %s-%s-%s (%s)\n", args[0], args[1], args[2], err)
+	}
+
+	for _, p := range promotions {
+		fmt.Printf("- %s: build %d (%s) promoted by %s on %s\n", p.Channel, p.BuildNumber, p.Environment, p.PromotedBy, p.Promoted.Format("2006-01-02 15:04:05"))
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	eventsFilter sdk.EventFilter
+	eventsJSON   bool
+)
+
+func init() {
+	eventsCmd.Flags().StringVarP(&eventsFilter.ProjectKey, "project", "", "", "Only events of the project: --project KEY")
+	eventsCmd.Flags().StringVarP(&eventsFilter.ApplicationName, "application", "", "", "Only events of the application: --application myApp")
+	eventsCmd.Flags().StringVarP(&eventsFilter.PipelineName, "pipeline", "", "", "Only events of the pipeline: --pipeline build")
+	eventsCmd.Flags().StringSliceVarP(&eventsFilter.Types, "type", "", nil, "Only events of the types: --type EventPipelineBuild,EventJob")
+	eventsCmd.Flags().BoolVarP(&eventsJSON, "json", "", false, "Display events as json")
+}
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Stream CDS events in real time: cds events [--project KEY] [--application myApp] [--pipeline build] [--type EventJob]",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		events := make(chan sdk.Event)
+		go func() {
+			for e := range events {
+				displayEvent(e)
+			}
+		}()
+
+		if err := sdk.StreamEvents(eventsFilter, events); err != nil {
+			sdk.Exit("Error while streaming events: %s\n", err)
+		}
+	},
+}
+
+func displayEvent(e sdk.Event) {
+	if eventsJSON {
+		b, _ := json.Marshal(e)
+		fmt.Println(string(b))
+		return
+	}
+	fmt.Printf("%s %-24s", e.Timestamp.Format(time.RFC3339), e.EventType)
+	for _, k := range []string{"ProjectKey", "ApplicationName", "PipelineName", "EnvironmentName", "BranchName", "JobName", "Version", "Status", "Message"} {
+		if v, ok := e.Payload[k]; ok && v != "" {
+			fmt.Printf(" %s=%v", k, v)
+		}
+	}
+	fmt.Println()
+}
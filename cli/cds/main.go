@@ -104,6 +104,7 @@ func main() {
 	rootCmd.AddCommand(artifact.Cmd)
 	rootCmd.AddCommand(environment.Cmd())
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(eventsCmd)
 	rootCmd.AddCommand(pipeline.Cmd())
 	rootCmd.AddCommand(project.Cmd)
 	rootCmd.AddCommand(group.Cmd)
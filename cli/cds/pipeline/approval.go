@@ -0,0 +1,94 @@
+package pipeline
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var approvalComment string
+
+func pipelineApproveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve",
+		Short: "cds pipeline approve <projectKey> <appName> <pipelineName> [envName] <buildNumber> <stageName>",
+		Long:  `Approve a stage of a pipeline build waiting for approval`,
+		Run: func(cmd *cobra.Command, args []string) {
+			decideStageApproval(cmd, args, true)
+		},
+	}
+
+	cmd.Flags().StringVarP(&approvalComment, "comment", "", "", "Comment recorded with the approval")
+
+	return cmd
+}
+
+func pipelineRejectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reject",
+		Short: "cds pipeline reject <projectKey> <appName> <pipelineName> [envName] <buildNumber> <stageName>",
+		Long:  `Reject a stage of a pipeline build waiting for approval, the pipeline build fails`,
+		Run: func(cmd *cobra.Command, args []string) {
+			decideStageApproval(cmd, args, false)
+		},
+	}
+
+	cmd.Flags().StringVarP(&approvalComment, "comment", "", "", "Comment recorded with the rejection")
+
+	return cmd
+}
+
+func decideStageApproval(cmd *cobra.Command, args []string, approved bool) {
+	if len(args) < 5 {
+		sdk.Exit("Wrong usage: see %s\n", cmd.Short)
+	}
+
+	pk := args[0]
+	app := args[1]
+	name := args[2]
+	var env string
+	var bnS string
+	var stageName string
+	if len(args) > 5 {
+		env = args[3]
+		bnS = args[4]
+		stageName = args[5]
+	} else {
+		bnS = args[3]
+		stageName = args[4]
+	}
+
+	bn, err := strconv.Atoi(bnS)
+	if err != nil {
+		sdk.Exit("%s is not a valid build number (%s)\n", bnS, err)
+	}
+
+	pb, err := sdk.GetBuildState(pk, app, name, env, bnS)
+	if err != nil {
+		sdk.Exit("Cannot load pipeline build (%s)\n", err)
+	}
+
+	var stageID int64
+	for _, s := range pb.Stages {
+		if s.Name == stageName {
+			stageID = s.ID
+			break
+		}
+	}
+	if stageID == 0 {
+		sdk.Exit("Stage %s not found in pipeline build %d\n", stageName, bn)
+	}
+
+	if err := sdk.DecideStageApproval(pk, app, name, env, bn, stageID, approved, approvalComment); err != nil {
+		sdk.Exit("Cannot decide on stage %s (%s)\n", stageName, err)
+	}
+
+	if approved {
+		fmt.Printf("Stage %s approved.\n", stageName)
+	} else {
+		fmt.Printf("Stage %s rejected.\n", stageName)
+	}
+}
@@ -25,6 +25,9 @@ func Cmd() *cobra.Command {
 	cmd.AddCommand(pipelineListCmd())
 	cmd.AddCommand(pipelineRunCmd())
 	cmd.AddCommand(pipelineRestartCmd())
+	cmd.AddCommand(pipelineStopCmd())
+	cmd.AddCommand(pipelineApproveCmd())
+	cmd.AddCommand(pipelineRejectCmd())
 	cmd.AddCommand(pipelineShowBuildCmd())
 	cmd.AddCommand(pipelineCommitsCmd())
 	cmd.AddCommand(pipelineShowCmd())
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -16,6 +17,9 @@ func init() {
 	pipelineHookCmd.AddCommand(pipelineAddHookCmd())
 	pipelineHookCmd.AddCommand(pipelineDeleteHookCmd())
 	pipelineHookCmd.AddCommand(pipelineListHookCmd())
+	pipelineHookCmd.AddCommand(pipelineAddGenericHookCmd())
+	pipelineHookCmd.AddCommand(pipelineReceivedHookCmd())
+	pipelineHookCmd.AddCommand(pipelineReplayHookCmd())
 }
 
 var pipelineHookCmd = &cobra.Command{
@@ -64,6 +68,142 @@ func pipelineListHookCmd() *cobra.Command {
 	return cmd
 }
 
+var genericHookSecret, genericHookEnv string
+
+func pipelineAddGenericHookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generic",
+		Short: "cds pipeline hook generic <projectKey> <applicationName> <pipelineName> [<parameter>=<path>...]",
+		Long: `Creates a hook which can be called by any third party with a json payload.
+Each <parameter>=<path> maps a value of the payload to a pipeline parameter, for instance:
+
+	$ cds pipeline hook generic MYPROJ myapp deploy image='$.repository.name' tag='$.tags[0]'
+
+If a secret is set, the payload has to be signed with HMAC-SHA256 in header X-Cds-Signature: sha256=<hex digest>`,
+		Run: addPipelineGenericHook,
+	}
+
+	cmd.Flags().StringVarP(&genericHookSecret, "secret", "", "", "Secret used to check the payload signature")
+	cmd.Flags().StringVarP(&genericHookEnv, "env", "", "", "Environment for deployment and testing pipelines")
+
+	return cmd
+}
+
+func pipelineReceivedHookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "received",
+		Short: "cds pipeline hook received <projectKey> <applicationName> <pipelineName> <idHook>",
+		Long:  `List last payloads received on a hook`,
+		Run:   listPipelineReceivedHook,
+	}
+
+	return cmd
+}
+
+func pipelineReplayHookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "cds pipeline hook replay <projectKey> <applicationName> <pipelineName> <idHook> <idReceived>",
+		Long:  `Process again a payload received on a generic hook`,
+		Run:   replayPipelineReceivedHook,
+	}
+
+	return cmd
+}
+
+func addPipelineGenericHook(cmd *cobra.Command, args []string) {
+	if len(args) < 3 {
+		sdk.Exit("Wrong usage: See %s\n", cmd.Short)
+	}
+
+	pipelineProject := args[0]
+	appName := args[1]
+	pipelineName := args[2]
+
+	mapping := []sdk.HookMapping{}
+	for _, m := range args[3:] {
+		t := strings.SplitN(m, "=", 2)
+		if len(t) != 2 {
+			sdk.Exit("✘ Error: Expected mapping like <parameter>=<path>. Got %s\n", m)
+		}
+		mapping = append(mapping, sdk.HookMapping{Parameter: t[0], Path: t[1]})
+	}
+
+	p, err := sdk.GetPipeline(pipelineProject, pipelineName)
+	if err != nil {
+		sdk.Exit("✘ Error: Cannot retrieve pipeline %s-%s (%s)\n", pipelineProject, pipelineName, err)
+	}
+
+	a, err := sdk.GetApplication(pipelineProject, appName)
+	if err != nil {
+		sdk.Exit("✘ Error: Cannot retrieve application %s-%s (%s)\n", pipelineProject, appName, err)
+	}
+
+	h, err := sdk.AddGenericHook(a, p, genericHookEnv, genericHookSecret, mapping)
+	if err != nil {
+		sdk.Exit("✘ Error: Cannot add hook to pipeline %s-%s-%s (%s)\n", pipelineProject, appName, pipelineName, err)
+	}
+
+	fmt.Printf("Hook %d created on CDS. Send your json payloads to:\n\tPOST %s\n", h.ID, h.Link)
+}
+
+func listPipelineReceivedHook(cmd *cobra.Command, args []string) {
+	if len(args) != 4 {
+		sdk.Exit("Wrong usage: See %s\n", cmd.Short)
+	}
+
+	hookID, err := strconv.ParseInt(args[3], 10, 64)
+	if err != nil {
+		sdk.Exit("Hook id must be a number (%s)\n", err)
+	}
+
+	received, err := sdk.GetReceivedHooks(args[0], args[1], args[2], hookID)
+	if err != nil {
+		sdk.Exit("Cannot retrieve received hooks from %s/%s/%s (%s)\n", args[0], args[1], args[2], err)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "Date", "Data"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+
+	for _, r := range received {
+		data := r.Data
+		if len(data) > 80 {
+			data = data[:77] + "..."
+		}
+		table.Append([]string{
+			fmt.Sprintf("%d", r.ID),
+			r.Created.Format(time.RFC3339),
+			data,
+		})
+	}
+	table.Render()
+}
+
+func replayPipelineReceivedHook(cmd *cobra.Command, args []string) {
+	if len(args) != 5 {
+		sdk.Exit("Wrong usage: See %s\n", cmd.Short)
+	}
+
+	hookID, err := strconv.ParseInt(args[3], 10, 64)
+	if err != nil {
+		sdk.Exit("Hook id must be a number (%s)\n", err)
+	}
+
+	receivedID, err := strconv.ParseInt(args[4], 10, 64)
+	if err != nil {
+		sdk.Exit("Received hook id must be a number (%s)\n", err)
+	}
+
+	pb, err := sdk.ReplayReceivedHook(args[0], args[1], args[2], hookID, receivedID)
+	if err != nil {
+		sdk.Exit("Cannot replay received hook %d on %s/%s/%s (%s)\n", receivedID, args[0], args[1], args[2], err)
+	}
+
+	fmt.Printf("✔ Pipeline %s started: build %d\n", args[2], pb.BuildNumber)
+}
+
 func addPipelineHook(cmd *cobra.Command, args []string) {
 
 	if len(args) < 3 {
@@ -197,9 +337,13 @@ func listPipelineHook(cmd *cobra.Command, args []string) {
 	table.SetCenterSeparator("|")
 
 	for _, h := range hooks {
+		repository := fmt.Sprintf("%s/%s/%s", h.Host, h.Project, h.Repository)
+		if h.Kind == sdk.HookKindGeneric {
+			repository = sdk.HookKindGeneric
+		}
 		table.Append([]string{
 			fmt.Sprintf("%d", h.ID),
-			fmt.Sprintf("%s/%s/%s", h.Host, h.Project, h.Repository),
+			repository,
 			h.Link,
 		})
 	}
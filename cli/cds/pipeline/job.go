@@ -29,7 +29,7 @@ var cmdJoinedActionAddStageNumber string
 func pipelineJobCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "job",
-		Short: "cds pipeline job {add | append | remove}",
+		Short: "cds pipeline job {add | append | remove | explain}",
 	}
 
 	addCmd := &cobra.Command{
@@ -58,6 +58,7 @@ func pipelineJobCmd() *cobra.Command {
 	cmd.AddCommand(addCmd)
 	cmd.AddCommand(appendCmd)
 	cmd.AddCommand(removeCmd)
+	cmd.AddCommand(pipelineJobExplainCmd())
 	return cmd
 }
 
@@ -0,0 +1,61 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func pipelineJobExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain",
+		Short: "cds pipeline job explain <jobID>",
+		Long: `Explain why the hatcheries can or cannot spawn a worker for a queued job.
+Every registered hatchery and model pair is checked against the requirements of the job.`,
+		Run: pipelineJobExplain,
+	}
+	return cmd
+}
+
+func pipelineJobExplain(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		sdk.Exit("Wrong usage. See %s\n", cmd.Short)
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		sdk.Exit("Error: job id is not a number (%s)\n", err)
+	}
+
+	e, err := sdk.ExplainPipelineBuildJob(id)
+	if err != nil {
+		sdk.Exit("Error: cannot explain job %d (%s)\n", id, err)
+	}
+
+	fmt.Printf("Job %s (%d) queued since %ds\n", e.JobName, e.JobID, e.QueuedSeconds)
+	if e.BookedBy != "" {
+		fmt.Printf("Booked by hatchery %s\n", e.BookedBy)
+	}
+	if len(e.Spawns) == 0 {
+		fmt.Printf("No hatchery registered\n")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 27, 1, 2, ' ', 0)
+	titles := []string{"HATCHERY", "MODEL", "TYPE", "SPAWN", "REASONS"}
+	fmt.Fprintln(w, strings.Join(titles, "\t"))
+	for _, s := range e.Spawns {
+		spawn := "yes"
+		if !s.CanSpawn {
+			spawn = "no"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.HatcheryName, s.ModelName, s.ModelType, spawn, strings.Join(s.Rejections, "; "))
+	}
+	w.Flush()
+}
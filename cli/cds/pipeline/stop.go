@@ -0,0 +1,78 @@
+package pipeline
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var stopJobName string
+
+func pipelineStopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "cds pipeline stop <projectKey> <appName> <pipelineName> [envName] <buildNumber>",
+		Long:  `Stop a running pipeline build, or only one of its jobs with --job`,
+		Run:   stopPipeline,
+	}
+
+	cmd.Flags().StringVarP(&stopJobName, "job", "", "", "Name of the job to stop")
+
+	return cmd
+}
+
+func stopPipeline(cmd *cobra.Command, args []string) {
+	if len(args) < 4 {
+		sdk.Exit("Wrong usage: see %s\n", cmd.Short)
+	}
+
+	pk := args[0]
+	app := args[1]
+	name := args[2]
+	var env string
+	var bnS string
+	if len(args) > 4 {
+		env = args[3]
+		bnS = args[4]
+	} else {
+		bnS = args[3]
+	}
+
+	bn, err := strconv.Atoi(bnS)
+	if err != nil {
+		sdk.Exit("%s is not a valid build number (%s)\n", bnS, err)
+	}
+
+	if stopJobName == "" {
+		if err := sdk.StopPipelineBuild(pk, app, name, env, bn); err != nil {
+			sdk.Exit("Cannot stop pipeline build (%s)\n", err)
+		}
+		fmt.Printf("Pipeline build %d stopped.\n", bn)
+		return
+	}
+
+	pb, err := sdk.GetBuildState(pk, app, name, env, bnS)
+	if err != nil {
+		sdk.Exit("Cannot load pipeline build (%s)\n", err)
+	}
+
+	var stopped int
+	for _, s := range pb.Stages {
+		for _, pbJob := range s.PipelineBuildJobs {
+			if pbJob.Job.Action.Name != stopJobName {
+				continue
+			}
+			if err := sdk.StopPipelineBuildJob(pk, app, name, env, bn, pbJob.ID); err != nil {
+				sdk.Exit("Cannot stop job %s (%s)\n", stopJobName, err)
+			}
+			stopped++
+		}
+	}
+	if stopped == 0 {
+		sdk.Exit("Job %s not found in pipeline build %d\n", stopJobName, bn)
+	}
+	fmt.Printf("Job %s stopped.\n", stopJobName)
+}
@@ -3,7 +3,6 @@ package repositoriesmanager
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -33,14 +32,21 @@ func connectReposManager(cmd *cobra.Command, args []string) {
 	}
 	fmt.Printf("Go to the following link in your browser\n - %s\n", url)
 
-	if strings.HasPrefix(url, "https://github.com") {
+	t := rmType(rmName)
+	//OAuth2 repositories managers redirect on CDS API callback, there is no verifier to enter
+	if t == sdk.Github || t == sdk.Gitlab {
 		fmt.Println("And follow instructions.")
 		os.Exit(0)
 	}
 
 	// scan for user input of response
 	var verifier string
-	fmt.Println("Enter verification code ?")
+	if t == sdk.Gitea {
+		//Gitea doesn't provide OAuth, the verifier is a personal access token
+		fmt.Println("Generate a new access token and enter it ?")
+	} else {
+		fmt.Println("Enter verification code ?")
+	}
 	fmt.Scan(&verifier)
 
 	access, secret, err := sdk.ConnectReposManagerCallback(projectKey, rmName, token, verifier)
@@ -49,3 +55,16 @@ func connectReposManager(cmd *cobra.Command, args []string) {
 	}
 	fmt.Printf("✔ Connection successfull to %s \n - access token: %s\n - secret: %s\n", rmName, access, secret)
 }
+
+func rmType(rmName string) sdk.RepositoriesManagerType {
+	rms, err := sdk.GetReposManager()
+	if err != nil {
+		sdk.Exit("✘ Error: %s\n", err)
+	}
+	for _, rm := range rms {
+		if rm.Name == rmName {
+			return rm.Type
+		}
+	}
+	return ""
+}
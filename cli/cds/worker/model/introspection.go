@@ -0,0 +1,96 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func cmdWorkerModelIntrospect() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "introspect",
+		Short: "cds worker model introspect <workerModelName>",
+		Long: `
+A worker of the model is spawned by a hatchery, it discovers the binaries installed on its PATH,
+their versions, its memory, os and arch, and the capabilities of the model are updated with what it found.
+
+See the result with "cds worker model introspection <workerModelName>".
+`,
+		Run: introspectWorkerModel,
+	}
+	return cmd
+}
+
+func introspectWorkerModel(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+
+	m, err := sdk.GetWorkerModel(args[0])
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve worker model %s (%s)\n", args[0], err)
+	}
+
+	i, err := sdk.RequestWorkerModelIntrospection(m.ID)
+	if err != nil {
+		sdk.Exit("Error: cannot request introspection of worker model %s (%s)\n", args[0], err)
+	}
+	fmt.Printf("Introspection of worker model %s requested by %s at %s\n", args[0], i.RequestedBy, i.Requested)
+}
+
+func cmdWorkerModelIntrospection() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "introspection",
+		Short: "cds worker model introspection <workerModelName>",
+		Long:  ``,
+		Run:   showWorkerModelIntrospections,
+	}
+	return cmd
+}
+
+func showWorkerModelIntrospections(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+
+	m, err := sdk.GetWorkerModel(args[0])
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve worker model %s (%s)\n", args[0], err)
+	}
+
+	is, err := sdk.GetWorkerModelIntrospections(m.ID)
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve introspections of worker model %s (%s)\n", args[0], err)
+	}
+
+	for _, i := range is {
+		fmt.Printf("#%d %s, requested by %s at %s\n", i.ID, i.Status, i.RequestedBy, i.Requested)
+		if i.Status != sdk.IntrospectionDone {
+			fmt.Println()
+			continue
+		}
+
+		fmt.Printf("  done by %s at %s\n", i.WorkerName, i.Done)
+		if i.Result != nil {
+			fmt.Printf("  %s/%s, %d MB\n", i.Result.OS, i.Result.Arch, i.Result.Memory)
+			binaries := make([]string, 0, len(i.Result.Versions))
+			for b := range i.Result.Versions {
+				binaries = append(binaries, b)
+			}
+			sort.Strings(binaries)
+			for _, b := range binaries {
+				fmt.Printf("  %s: %s\n", b, i.Result.Versions[b])
+			}
+		}
+		for _, c := range i.Added {
+			fmt.Printf("  + %s (%s) %s\n", c.Name, c.Type, c.Value)
+		}
+		for _, c := range i.Removed {
+			fmt.Printf("  - %s (%s) %s\n", c.Name, c.Type, c.Value)
+		}
+		fmt.Println()
+	}
+}
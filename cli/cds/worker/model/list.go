@@ -33,7 +33,7 @@ func listWorkerModel(cmd *cobra.Command, args []string) {
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 27, 1, 2, ' ', 0)
-	titles := []string{"NAME", "TYPE", "IMAGE"}
+	titles := []string{"NAME", "TYPE", "VERSION", "STATE", "IMAGE"}
 	fmt.Fprintln(w, strings.Join(titles, "\t"))
 
 	for _, m := range models {
@@ -41,9 +41,11 @@ func listWorkerModel(cmd *cobra.Command, args []string) {
 			m.Image = m.Image[:97] + "..."
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\n",
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
 			m.Name,
 			m.Type,
+			m.Version,
+			m.State,
 			m.Image,
 		)
 
@@ -8,6 +8,13 @@ func init() {
 	Cmd.AddCommand(cmdWorkerModelUpdate())
 	Cmd.AddCommand(cmdWorkerModelList())
 	Cmd.AddCommand(cmdWorkerModelCapability())
+	Cmd.AddCommand(cmdWorkerModelScaling())
+	Cmd.AddCommand(cmdWorkerModelStatus())
+	Cmd.AddCommand(cmdWorkerModelIntrospect())
+	Cmd.AddCommand(cmdWorkerModelIntrospection())
+	Cmd.AddCommand(cmdWorkerModelVersion())
+	Cmd.AddCommand(cmdWorkerModelState())
+	Cmd.AddCommand(cmdWorkerModelAudit())
 }
 
 // Cmd model
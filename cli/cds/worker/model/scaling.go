@@ -0,0 +1,188 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	scalingMinWarm     int
+	scalingMaxWarm     int
+	scalingStep        int
+	scalingJobAge      int64
+	scalingIdleMinutes int
+	scalingTimezone    string
+	scalingSchedules   []string
+)
+
+func cmdWorkerModelScaling() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scaling",
+		Short: "",
+		Long:  ``,
+	}
+
+	cmd.AddCommand(cmdWorkerModelScalingShow())
+	cmd.AddCommand(cmdWorkerModelScalingSet())
+	cmd.AddCommand(cmdWorkerModelScalingRemove())
+	return cmd
+}
+
+func cmdWorkerModelScalingShow() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "cds worker model scaling show <workerModelName>",
+		Long:  ``,
+		Run:   showWorkerModelScaling,
+	}
+	return cmd
+}
+
+func showWorkerModelScaling(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+
+	m, err := sdk.GetWorkerModel(args[0])
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve worker model %s (%s)\n", args[0], err)
+	}
+
+	p, err := sdk.GetWorkerModelScaling(m.ID)
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve scaling policy of worker model %s (%s)\n", args[0], err)
+	}
+
+	fmt.Printf("Min warm: %d\n", p.MinWarm)
+	fmt.Printf("Max warm: %d\n", p.MaxWarm)
+	fmt.Printf("Scale up step: %d\n", p.ScaleUpStep)
+	fmt.Printf("Job age: %ds\n", p.JobAgeSeconds)
+	fmt.Printf("Idle: %dmin\n", p.IdleMinutes)
+	if p.Timezone != "" {
+		fmt.Printf("Timezone: %s\n", p.Timezone)
+	}
+	for _, s := range p.Schedules {
+		fmt.Printf("Schedule: %s\n", s)
+	}
+}
+
+func cmdWorkerModelScalingSet() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "cds worker model scaling set <workerModelName> --max <n> [--min <n>] [--step <n>] [--job-age <seconds>] [--idle <minutes>] [--timezone <tz>] [--schedule \"mon-fri 08:00-19:00 3\"]",
+		Long: `
+Hatcheries keep between min idle workers and max workers of the model.
+When jobs are waiting, step workers are spawned on each provisioning, one more step for each job-age seconds the oldest job has waited.
+Idle workers beyond the wanted count are killed after idle minutes.
+Schedules raise the min idle workers during a time window, such as office hours, in the given timezone.
+		`,
+		Run: setWorkerModelScaling,
+	}
+
+	cmd.Flags().IntVar(&scalingMinWarm, "min", 0, "Idle workers to keep")
+	cmd.Flags().IntVar(&scalingMaxWarm, "max", 0, "Max workers of the model")
+	cmd.Flags().IntVar(&scalingStep, "step", 1, "Workers spawned on each provisioning when jobs are waiting")
+	cmd.Flags().Int64Var(&scalingJobAge, "job-age", 0, "Add a step for each job-age seconds the oldest job has waited")
+	cmd.Flags().IntVar(&scalingIdleMinutes, "idle", 0, "Minutes before killing an idle worker beyond the wanted count")
+	cmd.Flags().StringVar(&scalingTimezone, "timezone", "", "Timezone of the schedules (ex: Europe/Paris)")
+	cmd.Flags().StringArrayVar(&scalingSchedules, "schedule", nil, "Schedule \"days HH:MM-HH:MM min\" (ex: \"mon-fri 08:00-19:00 3\")")
+	return cmd
+}
+
+func setWorkerModelScaling(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+
+	m, err := sdk.GetWorkerModel(args[0])
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve worker model %s (%s)\n", args[0], err)
+	}
+
+	p := sdk.ModelScalingPolicy{
+		ModelID:       m.ID,
+		MinWarm:       scalingMinWarm,
+		MaxWarm:       scalingMaxWarm,
+		ScaleUpStep:   scalingStep,
+		JobAgeSeconds: scalingJobAge,
+		IdleMinutes:   scalingIdleMinutes,
+		Timezone:      scalingTimezone,
+	}
+	for _, str := range scalingSchedules {
+		s, err := sdk.ParseModelScalingSchedule(str)
+		if err != nil {
+			sdk.Exit("Error: %s\n", err)
+		}
+		p.Schedules = append(p.Schedules, s)
+	}
+
+	if err := sdk.CheckModelScalingPolicy(&p); err != nil {
+		sdk.Exit("Error: invalid scaling policy (%s)\n", err)
+	}
+
+	if err := sdk.UpdateWorkerModelScaling(p); err != nil {
+		sdk.Exit("Error: cannot update scaling policy (%s)\n", err)
+	}
+}
+
+func cmdWorkerModelScalingRemove() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove",
+		Short:   "cds worker model scaling remove <workerModelName>",
+		Long:    `Hatcheries then use their flat provisioning for the model`,
+		Aliases: []string{"delete", "rm", "del"},
+		Run:     removeWorkerModelScaling,
+	}
+	return cmd
+}
+
+func removeWorkerModelScaling(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+
+	m, err := sdk.GetWorkerModel(args[0])
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve worker model %s (%s)\n", args[0], err)
+	}
+
+	if err := sdk.DeleteWorkerModelScaling(m.ID); err != nil {
+		sdk.Exit("Error: cannot remove scaling policy (%s)\n", err)
+	}
+}
+
+func cmdWorkerModelStatus() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "cds worker model status",
+		Long:  `Workers started, wanted and building of each model, summed over all hatcheries`,
+		Run:   statusWorkerModel,
+	}
+	return cmd
+}
+
+func statusWorkerModel(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+
+	status, err := sdk.GetWorkerModelStatus()
+	if err != nil {
+		sdk.Exit("Error: cannot get worker models status (%s)\n", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 27, 1, 2, ' ', 0)
+	titles := []string{"NAME", "CURRENT", "WANTED", "BUILDING"}
+	fmt.Fprintln(w, strings.Join(titles, "\t"))
+
+	for _, s := range status {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", s.ModelName, s.CurrentCount, s.WantedCount, s.BuildingCount)
+	}
+	w.Flush()
+}
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func cmdWorkerModelVersion() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "",
+		Long: `
+Every change of the type, image or capabilities of a worker model creates a new version of the model,
+which becomes its current version. Jobs use the current version of a model, unless they pin a version
+in their model requirement: "golang@3" is the version 3 of the model golang.
+`,
+	}
+
+	cmd.AddCommand(cmdWorkerModelVersionList())
+	cmd.AddCommand(cmdWorkerModelVersionCurrent())
+	return cmd
+}
+
+func cmdWorkerModelVersionList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "cds worker model version list <workerModelName>",
+		Long:  ``,
+		Run:   listWorkerModelVersions,
+	}
+	return cmd
+}
+
+func listWorkerModelVersions(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+
+	m, err := sdk.GetWorkerModel(args[0])
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve worker model %s (%s)\n", args[0], err)
+	}
+
+	vs, err := sdk.GetWorkerModelVersions(m.ID)
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve versions of worker model %s (%s)\n", args[0], err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+	titles := []string{"VERSION", "CURRENT", "TYPE", "CREATED", "CREATED BY", "IMAGE"}
+	fmt.Fprintln(w, strings.Join(titles, "\t"))
+
+	for _, v := range vs {
+		var current string
+		if v.Version == m.Version {
+			current = "*"
+		}
+		if len(v.Image) > 100 {
+			v.Image = v.Image[:97] + "..."
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", v.Version, current, v.Type, v.Created.Format("2006-01-02 15:04:05"), v.CreatedBy, v.Image)
+	}
+	w.Flush()
+}
+
+func cmdWorkerModelVersionCurrent() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "current",
+		Short: "cds worker model version current <workerModelName> <version>",
+		Long:  `Set the current version of a worker model, to rollback a change of the model for instance`,
+		Run:   setWorkerModelCurrentVersion,
+	}
+	return cmd
+}
+
+func setWorkerModelCurrentVersion(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+
+	version, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		sdk.Exit("Error: version must be an integer (%s)\n", err)
+	}
+
+	m, err := sdk.GetWorkerModel(args[0])
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve worker model %s (%s)\n", args[0], err)
+	}
+
+	if err := sdk.SetWorkerModelCurrentVersion(m.ID, version); err != nil {
+		sdk.Exit("Error: cannot set current version of worker model %s (%s)\n", args[0], err)
+	}
+	fmt.Printf("Worker model %s is at version %d\n", args[0], version)
+}
+
+func cmdWorkerModelState() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "cds worker model state <workerModelName> <active|deprecated|disabled>",
+		Long: `
+Deprecated models are still spawned, but pipelines using them get a warning.
+Disabled models are not spawned anymore, pipelines using them get a warning too.
+`,
+		Run: setWorkerModelState,
+	}
+	return cmd
+}
+
+func setWorkerModelState(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+
+	m, err := sdk.GetWorkerModel(args[0])
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve worker model %s (%s)\n", args[0], err)
+	}
+
+	if err := sdk.SetWorkerModelState(m.ID, args[1]); err != nil {
+		sdk.Exit("Error: cannot set state of worker model %s (%s)\n", args[0], err)
+	}
+	fmt.Printf("Worker model %s is %s\n", args[0], args[1])
+}
+
+func cmdWorkerModelAudit() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "cds worker model audit <workerModelName>",
+		Long:  ``,
+		Run:   showWorkerModelAudits,
+	}
+	return cmd
+}
+
+func showWorkerModelAudits(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		sdk.Exit("Wrong usage: %s\n", cmd.Short)
+	}
+
+	m, err := sdk.GetWorkerModel(args[0])
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve worker model %s (%s)\n", args[0], err)
+	}
+
+	as, err := sdk.GetWorkerModelAudits(m.ID)
+	if err != nil {
+		sdk.Exit("Error: cannot retrieve audit of worker model %s (%s)\n", args[0], err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 10, 1, 2, ' ', 0)
+	titles := []string{"DATE", "USER", "CHANGE", "DETAIL"}
+	fmt.Fprintln(w, strings.Join(titles, "\t"))
+
+	for _, a := range as {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", a.Created.Format("2006-01-02 15:04:05"), a.Username, a.Change, a.Detail)
+	}
+	w.Flush()
+}
@@ -128,6 +128,50 @@ Clone a repository into a new directory.`
 		return err
 	}
 
+	// ----------------------------------- Cache save   -----------------------
+	cacheSave := sdk.NewAction(sdk.CacheSaveAction)
+	cacheSave.Type = sdk.BuiltinAction
+	cacheSave.Description = `CDS Builtin Action.
+Save directories in a cache, which can be restored by the next builds
+of the project with CacheRestore.`
+	cacheSave.Parameter(sdk.Parameter{
+		Name: "key",
+		Description: `Key of the cache. It can use variables and {{hashFiles "pattern"}},
+which is replaced by a hash of the content of the matching files.
+ie: {{.cds.application}}-{{hashFiles "go.sum"}}`,
+		Value: "{{.cds.application}}-{{.cds.pipeline}}",
+		Type:  sdk.StringParameter,
+	})
+	cacheSave.Parameter(sdk.Parameter{
+		Name:        "path",
+		Description: "Directories to save, one per line, relative to the workspace.",
+		Type:        sdk.TextParameter,
+	})
+	if err := checkBuiltinAction(db, cacheSave); err != nil {
+		return err
+	}
+
+	// ----------------------------------- Cache restore -----------------------
+	cacheRestore := sdk.NewAction(sdk.CacheRestoreAction)
+	cacheRestore.Type = sdk.BuiltinAction
+	cacheRestore.Description = `CDS Builtin Action.
+Restore directories saved with CacheSave. Nothing is restored if no cache matches.`
+	cacheRestore.Parameter(sdk.Parameter{
+		Name:        "key",
+		Description: `Key of the cache, with the same syntax as CacheSave.`,
+		Value:       "{{.cds.application}}-{{.cds.pipeline}}",
+		Type:        sdk.StringParameter,
+	})
+	cacheRestore.Parameter(sdk.Parameter{
+		Name: "prefixes",
+		Description: `If there is no cache for the key, the most recent cache with a key
+starting with one of these prefixes is restored. One prefix per line, the first matching wins.`,
+		Type: sdk.TextParameter,
+	})
+	if err := checkBuiltinAction(db, cacheRestore); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -242,6 +286,10 @@ func createBuiltinArtifactDownloadAction(db *gorp.DbMap) error {
 		Name:        "application",
 		Description: "Application from where artifacts will be downloaded, generally {{.cds.application}}",
 		Type:        sdk.StringParameter})
+	dl.Parameter(sdk.Parameter{
+		Name:        "reference",
+		Description: "Reference of artifacts of another application or pipeline: project/application/pipeline/environment/build[/name], build being a build number, a tag or @channel. Replaces tag, pipeline and application",
+		Type:        sdk.StringParameter})
 	dl.Parameter(sdk.Parameter{
 		Name:        "enabled",
 		Type:        sdk.BooleanParameter,
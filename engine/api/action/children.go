@@ -10,11 +10,11 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, final, enabled bool) (int64, error) {
-	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, final, enabled) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, final, enabled bool, timeout int64) (int64, error) {
+	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, final, enabled, timeout) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
 
 	var id int64
-	err := db.QueryRow(query, parentID, childID, execOrder, final, enabled).Scan(&id)
+	err := db.QueryRow(query, parentID, childID, execOrder, final, enabled, timeout).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -27,7 +27,7 @@ func insertActionChild(db gorp.SqlExecutor, actionID int64, child sdk.Action, ex
 		return fmt.Errorf("insertActionChild: child action has no id")
 	}
 
-	id, err := insertEdge(db, actionID, child.ID, execOrder, child.Final, child.Enabled)
+	id, err := insertEdge(db, actionID, child.ID, execOrder, child.Final, child.Enabled, child.Timeout)
 	if err != nil {
 		return err
 	}
@@ -67,7 +67,7 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	var children []sdk.Action
 	var edgeIDs []int64
 	var childrenIDs []int64
-	query := `SELECT id, child_id, exec_order, final, enabled FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
+	query := `SELECT id, child_id, exec_order, final, enabled, timeout FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
 
 	rows, err := db.Query(query, actionID)
 	if err != nil {
@@ -75,14 +75,15 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	}
 	defer rows.Close()
 
-	var edgeID, childID int64
+	var edgeID, childID, timeout int64
 	var execOrder int
 	var final, enabled bool
 	var mapFinal = make(map[int64]bool)
 	var mapEnabled = make(map[int64]bool)
+	var mapTimeout = make(map[int64]int64)
 
 	for rows.Next() {
-		err = rows.Scan(&edgeID, &childID, &execOrder, &final, &enabled)
+		err = rows.Scan(&edgeID, &childID, &execOrder, &final, &enabled, &timeout)
 		if err != nil {
 			return nil, err
 		}
@@ -90,6 +91,7 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 		childrenIDs = append(childrenIDs, childID)
 		mapFinal[edgeID] = final
 		mapEnabled[edgeID] = enabled
+		mapTimeout[edgeID] = timeout
 	}
 	rows.Close()
 
@@ -115,6 +117,8 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 		children[i].Final = mapFinal[edgeIDs[i]]
 		// Get enable flag
 		children[i].Enabled = mapEnabled[edgeIDs[i]]
+		// Get step timeout
+		children[i].Timeout = mapTimeout[edgeIDs[i]]
 	}
 
 	return children, nil
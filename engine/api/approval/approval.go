@@ -0,0 +1,194 @@
+package approval
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// timeoutUser is the user recorded when a gate is rejected because its timeout is reached
+const timeoutUser = "cds"
+
+// Record is the audit of a decision on a stage approval gate
+type Record struct {
+	ID              int64         `json:"id" db:"id"`
+	PipelineBuildID int64         `json:"pipeline_build_id" db:"pipeline_build_id"`
+	StageID         int64         `json:"stage_id" db:"stage_id"`
+	UserID          sql.NullInt64 `json:"-" db:"user_id"`
+	Username        string        `json:"username" db:"username"`
+	Approved        bool          `json:"approved" db:"approved"`
+	Comment         string        `json:"comment" db:"comment"`
+	Created         time.Time     `json:"created" db:"created"`
+}
+
+// LoadRecords returns all decisions taken on the approval gates of a pipeline build
+func LoadRecords(db gorp.SqlExecutor, pbID int64) ([]Record, error) {
+	records := []Record{}
+	query := `SELECT * FROM pipeline_build_approval WHERE pipeline_build_id = $1 ORDER BY created ASC`
+	if _, err := db.Select(&records, query, pbID); err != nil {
+		return nil, sdk.WrapError(err, "LoadRecords> Cannot load approvals of pipeline build %d", pbID)
+	}
+	return records, nil
+}
+
+// Decide approves or rejects the stage of a pipeline build waiting for an approval.
+// The user must be a member of one of the approver groups and can decide only once
+func Decide(db *gorp.DbMap, pbID, stageID int64, u *sdk.User, approved bool, comment string) (*sdk.PipelineBuild, error) {
+	tx, errb := db.Begin()
+	if errb != nil {
+		return nil, sdk.WrapError(errb, "Decide> Cannot begin transaction")
+	}
+	defer tx.Rollback()
+
+	pb, stage, err := loadWaitingStage(tx, pbID)
+	if err != nil {
+		return nil, err
+	}
+	if stage == nil || stage.ID != stageID {
+		return nil, sdk.ErrNoApprovalWaiting
+	}
+	if !stage.Approval.CanApprove(u) {
+		return nil, sdk.ErrNotApprover
+	}
+	if stage.Approval.HasDecided(u.Username) {
+		return nil, sdk.ErrAlreadyDecided
+	}
+
+	if err := decide(tx, pb, stage, u, approved, comment); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, sdk.WrapError(err, "Decide> Cannot commit transaction")
+	}
+	return pb, nil
+}
+
+// TimeoutKiller rejects approval gates which have been waiting for longer than their timeout
+func TimeoutKiller(DBFunc func() *gorp.DbMap) {
+	defer log.Error("approval.TimeoutKiller> has been exited !")
+
+	for {
+		time.Sleep(1 * time.Minute)
+		db := DBFunc()
+		if db == nil {
+			continue
+		}
+
+		ids, err := pipeline.LoadWaitingApprovalPipelinesIDs(db)
+		if err != nil {
+			log.Warning("TimeoutKiller> Cannot load pipeline builds waiting approval: %s", err)
+			continue
+		}
+
+		for _, id := range ids {
+			if err := rejectTimedOut(db, id, time.Now()); err != nil {
+				log.Warning("TimeoutKiller> Cannot reject approval of pipeline build %d: %s", id, err)
+			}
+		}
+	}
+}
+
+func rejectTimedOut(db *gorp.DbMap, pbID int64, now time.Time) error {
+	tx, errb := db.Begin()
+	if errb != nil {
+		return sdk.WrapError(errb, "rejectTimedOut> Cannot begin transaction")
+	}
+	defer tx.Rollback()
+
+	pb, stage, err := loadWaitingStage(tx, pbID)
+	if err != nil {
+		if err == sdk.ErrNoApprovalWaiting {
+			return nil
+		}
+		return err
+	}
+	if stage == nil || !stage.Approval.Expired(now) {
+		return nil
+	}
+
+	if err := decide(tx, pb, stage, nil, false, "Approval timeout reached"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// loadWaitingStage locks the pipeline build waiting for an approval and returns its gated stage
+func loadWaitingStage(db gorp.SqlExecutor, pbID int64) (*sdk.PipelineBuild, *sdk.Stage, error) {
+	if err := pipeline.SelectBuildWaitingApprovalForUpdate(db, pbID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, sdk.ErrNoApprovalWaiting
+		}
+		return nil, nil, sdk.WrapError(err, "loadWaitingStage> Cannot lock pipeline build %d", pbID)
+	}
+
+	pb, err := pipeline.LoadPipelineBuildByID(db, pbID)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "loadWaitingStage> Cannot load pipeline build %d", pbID)
+	}
+
+	for i := range pb.Stages {
+		s := &pb.Stages[i]
+		if s.Status == sdk.StatusWaitingApproval && s.Approval != nil {
+			return pb, s, nil
+		}
+	}
+	return pb, nil, nil
+}
+
+// decide records the decision of the user on the gated stage, then resumes or fails the pipeline build.
+// A nil user is CDS rejecting the stage when its timeout is reached
+func decide(db gorp.SqlExecutor, pb *sdk.PipelineBuild, stage *sdk.Stage, u *sdk.User, approved bool, comment string) error {
+	username := timeoutUser
+	if u != nil {
+		username = u.Username
+	}
+
+	d := sdk.ApprovalDecision{
+		User:     username,
+		Approved: approved,
+		Comment:  comment,
+		Date:     time.Now(),
+	}
+	stage.Approval.Decisions = append(stage.Approval.Decisions, d)
+
+	r := Record{
+		PipelineBuildID: pb.ID,
+		StageID:         stage.ID,
+		Username:        d.User,
+		Approved:        d.Approved,
+		Comment:         d.Comment,
+		Created:         d.Date,
+	}
+	if u != nil {
+		r.UserID = sql.NullInt64{Int64: u.ID, Valid: true}
+	}
+	if err := db.Insert(&r); err != nil {
+		return sdk.WrapError(err, "decide> Cannot insert approval of stage %d", stage.ID)
+	}
+
+	newStatus := sdk.StatusWaitingApproval
+	switch {
+	case stage.Approval.Rejected():
+		stage.Status = sdk.StatusFail
+		pb.Done = time.Now()
+		newStatus = sdk.StatusFail
+	case stage.Approval.Approved():
+		// The queue will run the jobs of the stage
+		stage.Status = sdk.StatusWaiting
+		newStatus = sdk.StatusBuilding
+	}
+
+	if err := pipeline.UpdatePipelineBuildStatusAndStage(db, pb, newStatus); err != nil {
+		return sdk.WrapError(err, "decide> Cannot update pipeline build %d", pb.ID)
+	}
+
+	event.PublishStageApproval(pb, stage, d)
+	return nil
+}
@@ -0,0 +1,132 @@
+package approval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+)
+
+// insertWaitingBuild inserts a pipeline build waiting for the approval of its first stage
+func insertWaitingBuild(t *testing.T, approval *sdk.StageApproval) *sdk.PipelineBuild {
+	db := test.SetupPG(t)
+
+	key := assets.RandomString(t, 10)
+	proj := assets.InsertTestProject(t, db, key, key)
+
+	pip := &sdk.Pipeline{
+		Name:       key + "_PIP",
+		Type:       sdk.BuildPipeline,
+		ProjectKey: proj.Key,
+		ProjectID:  proj.ID,
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, pip))
+
+	app := &sdk.Application{Name: key + "_APP"}
+	test.NoError(t, application.Insert(db, proj, app))
+	_, err := application.AttachPipeline(db, app.ID, pip.ID)
+	test.NoError(t, err)
+
+	pb, err := pipeline.InsertPipelineBuild(db, proj, pip, app, []sdk.Parameter{}, []sdk.Parameter{}, &sdk.DefaultEnv, 0, sdk.PipelineBuildTrigger{})
+	test.NoError(t, err)
+
+	pb.Stages = []sdk.Stage{{
+		ID:       42,
+		Name:     "Deploy",
+		Enabled:  true,
+		Status:   sdk.StatusWaitingApproval,
+		Approval: approval,
+	}}
+	test.NoError(t, pipeline.UpdatePipelineBuildStatusAndStage(db, pb, sdk.StatusWaitingApproval))
+	return pb
+}
+
+func TestDecide(t *testing.T) {
+	db := test.SetupPG(t)
+
+	approvers := &sdk.Group{Name: assets.RandomString(t, 10)}
+	approver, _ := assets.InsertLambaUser(t, db, approvers)
+	other, _ := assets.InsertLambaUser(t, db)
+
+	pb := insertWaitingBuild(t, &sdk.StageApproval{Groups: []string{approvers.Name}, Start: time.Now()})
+
+	_, err := Decide(db, pb.ID, 42, other, true, "")
+	assert.Equal(t, sdk.ErrNotApprover, err)
+
+	_, err = Decide(db, pb.ID, 43, approver, true, "")
+	assert.Equal(t, sdk.ErrNoApprovalWaiting, err)
+
+	pbDecided, err := Decide(db, pb.ID, 42, approver, true, "ship it")
+	test.NoError(t, err)
+	assert.Equal(t, sdk.StatusWaiting, pbDecided.Stages[0].Status)
+
+	pbDB, err := pipeline.LoadPipelineBuildByID(db, pb.ID)
+	test.NoError(t, err)
+	assert.Equal(t, sdk.StatusBuilding, pbDB.Status)
+
+	records, err := LoadRecords(db, pb.ID)
+	test.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, approver.Username, records[0].Username)
+	assert.Equal(t, approver.ID, records[0].UserID.Int64)
+	assert.True(t, records[0].Approved)
+
+	// The build does not wait for an approval anymore
+	_, err = Decide(db, pb.ID, 42, approver, true, "")
+	assert.Equal(t, sdk.ErrNoApprovalWaiting, err)
+
+	// The record is kept once the approver is deleted
+	test.NoError(t, user.DeleteUserWithDependencies(db, approver))
+	records, err = LoadRecords(db, pb.ID)
+	test.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, approver.Username, records[0].Username)
+	assert.False(t, records[0].UserID.Valid)
+}
+
+func TestDecideReject(t *testing.T) {
+	db := test.SetupPG(t)
+
+	approvers := &sdk.Group{Name: assets.RandomString(t, 10)}
+	approver, _ := assets.InsertLambaUser(t, db, approvers)
+
+	pb := insertWaitingBuild(t, &sdk.StageApproval{Groups: []string{approvers.Name}, Required: 2, Start: time.Now()})
+
+	_, err := Decide(db, pb.ID, 42, approver, false, "not today")
+	test.NoError(t, err)
+
+	pbDB, err := pipeline.LoadPipelineBuildByID(db, pb.ID)
+	test.NoError(t, err)
+	assert.Equal(t, sdk.StatusFail, pbDB.Status)
+	assert.Equal(t, sdk.StatusFail, pbDB.Stages[0].Status)
+}
+
+func TestRejectTimedOut(t *testing.T) {
+	db := test.SetupPG(t)
+
+	pb := insertWaitingBuild(t, &sdk.StageApproval{Groups: []string{"approvers"}, Timeout: 60, Start: time.Now()})
+
+	// The timeout is not reached yet
+	test.NoError(t, rejectTimedOut(db, pb.ID, time.Now()))
+	pbDB, err := pipeline.LoadPipelineBuildByID(db, pb.ID)
+	test.NoError(t, err)
+	assert.Equal(t, sdk.StatusWaitingApproval, pbDB.Status)
+
+	test.NoError(t, rejectTimedOut(db, pb.ID, time.Now().Add(2*time.Minute)))
+	pbDB, err = pipeline.LoadPipelineBuildByID(db, pb.ID)
+	test.NoError(t, err)
+	assert.Equal(t, sdk.StatusFail, pbDB.Status)
+
+	records, err := LoadRecords(db, pb.ID)
+	test.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, timeoutUser, records[0].Username)
+	assert.False(t, records[0].UserID.Valid)
+}
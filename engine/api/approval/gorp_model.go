@@ -0,0 +1,9 @@
+package approval
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+)
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(Record{}, "pipeline_build_approval", true, "id"))
+}
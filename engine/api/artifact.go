@@ -3,7 +3,6 @@ package main
 import (
 	"crypto/rand"
 	"encoding/hex"
-	"fmt"
 	"net/http"
 	"strconv"
 
@@ -14,6 +13,7 @@ import (
 	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/context"
 	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/sdk"
@@ -162,10 +162,11 @@ func downloadArtifactHandler(w http.ResponseWriter, r *http.Request, db *gorp.Db
 
 	log.Debug("downloadArtifactHandler: Serving %+v\n", art)
 
-	w.Header().Add("Content-Type", "application/octet-stream")
-	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
+	if redirected, err := redirectToArtifactURL(w, r, art); err != nil || redirected {
+		return err
+	}
 
-	if err = artifact.StreamFile(w, *art); err != nil {
+	if err = artifact.ServeFile(w, r, *art); err != nil {
 		log.Warning("downloadArtifactHandler: Cannot stream artifact %s-%s-%s-%s-%s file: %s\n", art.Project, art.Application, art.Environment, art.Pipeline, art.Tag, err)
 		return err
 	}
@@ -298,11 +299,12 @@ func downloadArtifactDirectHandler(w http.ResponseWriter, r *http.Request, db *g
 		return err
 	}
 
-	w.Header().Add("Content-Type", "application/octet-stream")
-	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
+	if redirected, err := redirectToArtifactURL(w, r, art); err != nil || redirected {
+		return err
+	}
 
 	log.Debug("downloadArtifactDirectHandler: Serving %+v\n", art)
-	err = artifact.StreamFile(w, *art)
+	err = artifact.ServeFile(w, r, *art)
 	if err != nil {
 		log.Warning("downloadArtifactDirectHandler: Cannot stream artifact %s-%s-%s-%s-%s file: %s\n", art.Project, art.Application, art.Environment, art.Pipeline, art.Tag, err)
 		return err
@@ -311,6 +313,19 @@ func downloadArtifactDirectHandler(w http.ResponseWriter, r *http.Request, db *g
 	return nil
 }
 
+// redirectToArtifactURL redirects the client to a temporary URL of the artifact if the storage backend provides one
+func redirectToArtifactURL(w http.ResponseWriter, r *http.Request, art *sdk.Artifact) (bool, error) {
+	url, err := objectstore.FetchArtifactURL(*art)
+	if err != nil {
+		return false, sdk.WrapError(err, "redirectToArtifactURL> Cannot get URL of artifact %s", art.Name)
+	}
+	if url == "" {
+		return false, nil
+	}
+	http.Redirect(w, r, url, http.StatusTemporaryRedirect)
+	return true, nil
+}
+
 func generateHash() (string, error) {
 	size := 128
 	bs := make([]byte, size)
@@ -18,7 +18,7 @@ func LoadArtifactByHash(db gorp.SqlExecutor, hash string) (*sdk.Artifact, error)
 	art := &sdk.Artifact{}
 	query := `SELECT artifact.id, artifact.name, artifact.tag, 
 		  pipeline.name, project.projectKey, application.name, environment.name,
-		  artifact.size, artifact.perm, artifact.md5sum, artifact.object_path
+		  artifact.size, artifact.perm, artifact.md5sum, artifact.sha256sum, artifact.object_path
 		  FROM artifact
 		  JOIN pipeline ON artifact.pipeline_id = pipeline.id
 		  JOIN project ON pipeline.project_id = project.id
@@ -26,15 +26,18 @@ func LoadArtifactByHash(db gorp.SqlExecutor, hash string) (*sdk.Artifact, error)
 		  JOIN environment ON environment.id = artifact.environment_id
 		  WHERE download_hash = $1`
 
-	var md5sum, objectpath sql.NullString
+	var md5sum, sha256sum, objectpath sql.NullString
 	var size, perm sql.NullInt64
-	err := db.QueryRow(query, hash).Scan(&art.ID, &art.Name, &art.Tag, &art.Pipeline, &art.Project, &art.Application, &art.Environment, &size, &perm, &md5sum, &objectpath)
+	err := db.QueryRow(query, hash).Scan(&art.ID, &art.Name, &art.Tag, &art.Pipeline, &art.Project, &art.Application, &art.Environment, &size, &perm, &md5sum, &sha256sum, &objectpath)
 	if err != nil {
 		return nil, err
 	}
 	if md5sum.Valid {
 		art.MD5sum = md5sum.String
 	}
+	if sha256sum.Valid {
+		art.SHA256sum = sha256sum.String
+	}
 	if objectpath.Valid {
 		art.ObjectPath = objectpath.String
 	}
@@ -49,7 +52,7 @@ func LoadArtifactByHash(db gorp.SqlExecutor, hash string) (*sdk.Artifact, error)
 
 // LoadArtifactsByBuildNumber Load artifact by pipeline ID and buildNUmber
 func LoadArtifactsByBuildNumber(db gorp.SqlExecutor, pipelineID int64, applicationID int64, buildNumber int64, environmentID int64) ([]sdk.Artifact, error) {
-	query := `SELECT id, name, tag, download_hash, size, perm, md5sum, object_path
+	query := `SELECT id, name, tag, download_hash, size, perm, md5sum, sha256sum, object_path, pinned
 	          FROM "artifact"
 	          WHERE build_number = $1 AND pipeline_id = $2 AND application_id = $3 AND environment_id = $4
 	          ORDER BY name`
@@ -63,15 +66,18 @@ func LoadArtifactsByBuildNumber(db gorp.SqlExecutor, pipelineID int64, applicati
 	arts := []sdk.Artifact{}
 	for rows.Next() {
 		art := sdk.Artifact{}
-		var md5sum, objectpath sql.NullString
+		var md5sum, sha256sum, objectpath sql.NullString
 		var size, perm sql.NullInt64
-		err = rows.Scan(&art.ID, &art.Name, &art.Tag, &art.DownloadHash, &size, &perm, &md5sum, &objectpath)
+		err = rows.Scan(&art.ID, &art.Name, &art.Tag, &art.DownloadHash, &size, &perm, &md5sum, &sha256sum, &objectpath, &art.Pinned)
 		if err != nil {
 			return nil, err
 		}
 		if md5sum.Valid {
 			art.MD5sum = md5sum.String
 		}
+		if sha256sum.Valid {
+			art.SHA256sum = sha256sum.String
+		}
 		if objectpath.Valid {
 			art.ObjectPath = objectpath.String
 		}
@@ -88,7 +94,7 @@ func LoadArtifactsByBuildNumber(db gorp.SqlExecutor, pipelineID int64, applicati
 
 // LoadArtifacts Load artifact by pipeline ID
 func LoadArtifacts(db gorp.SqlExecutor, pipelineID int64, applicationID int64, environmentID int64, tag string) ([]sdk.Artifact, error) {
-	query := `SELECT id, name, download_hash, size, perm, md5sum, object_path
+	query := `SELECT id, name, download_hash, size, perm, md5sum, sha256sum, object_path
 		FROM "artifact" 
 		WHERE tag = $1 
 		AND pipeline_id = $2 
@@ -103,15 +109,18 @@ func LoadArtifacts(db gorp.SqlExecutor, pipelineID int64, applicationID int64, e
 	var arts []sdk.Artifact
 	for rows.Next() {
 		art := sdk.Artifact{}
-		var md5sum, objectpath sql.NullString
+		var md5sum, sha256sum, objectpath sql.NullString
 		var size, perm sql.NullInt64
-		err = rows.Scan(&art.ID, &art.Name, &art.DownloadHash, &size, &perm, &md5sum, &objectpath)
+		err = rows.Scan(&art.ID, &art.Name, &art.DownloadHash, &size, &perm, &md5sum, &sha256sum, &objectpath)
 		if err != nil {
 			return nil, err
 		}
 		if md5sum.Valid {
 			art.MD5sum = md5sum.String
 		}
+		if sha256sum.Valid {
+			art.SHA256sum = sha256sum.String
+		}
 		if objectpath.Valid {
 			art.ObjectPath = objectpath.String
 		}
@@ -130,7 +139,7 @@ func LoadArtifacts(db gorp.SqlExecutor, pipelineID int64, applicationID int64, e
 // LoadArtifact Load artifact by ID
 func LoadArtifact(db gorp.SqlExecutor, id int64) (*sdk.Artifact, error) {
 	query := `SELECT 
-			artifact.name, artifact.tag, artifact.download_hash, artifact.size, artifact.perm, artifact.md5sum, artifact.object_path, 
+			artifact.name, artifact.tag, artifact.download_hash, artifact.size, artifact.perm, artifact.md5sum, artifact.sha256sum, artifact.object_path, 
 			pipeline.name, project.projectKey, application.name, environment.name FROM artifact
 			JOIN pipeline ON artifact.pipeline_id = pipeline.id
 			JOIN project ON pipeline.project_id = project.id
@@ -138,14 +147,17 @@ func LoadArtifact(db gorp.SqlExecutor, id int64) (*sdk.Artifact, error) {
 			JOIN environment ON environment.id = artifact.environment_id
 			WHERE artifact.id = $1`
 
-	s := &sdk.Artifact{}
-	var md5sum, objectpath sql.NullString
+	s := &sdk.Artifact{ID: id}
+	var md5sum, sha256sum, objectpath sql.NullString
 	var size, perm sql.NullInt64
-	err := db.QueryRow(query, id).Scan(&s.Name, &s.Tag, &s.DownloadHash, &size, &perm, &md5sum, &objectpath,
+	err := db.QueryRow(query, id).Scan(&s.Name, &s.Tag, &s.DownloadHash, &size, &perm, &md5sum, &sha256sum, &objectpath,
 		&s.Pipeline, &s.Project, &s.Application, &s.Environment)
 	if md5sum.Valid {
 		s.MD5sum = md5sum.String
 	}
+	if sha256sum.Valid {
+		s.SHA256sum = sha256sum.String
+	}
 	if objectpath.Valid {
 		s.ObjectPath = objectpath.String
 	}
@@ -199,17 +211,17 @@ func insertArtifact(db gorp.SqlExecutor, pipelineID, applicationID int64, enviro
 	}
 
 	query = `INSERT INTO "artifact" 
-			(name, tag, pipeline_id, application_id, build_number, environment_id, download_hash, size, perm, md5sum, object_path) 
+			(name, tag, pipeline_id, application_id, build_number, environment_id, download_hash, size, perm, md5sum, sha256sum, object_path) 
 			VALUES 
-			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
-	_, err = db.Exec(query, art.Name, art.Tag, pipelineID, applicationID, art.BuildNumber, environmentID, art.DownloadHash, art.Size, art.Perm, art.MD5sum, art.ObjectPath)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	_, err = db.Exec(query, art.Name, art.Tag, pipelineID, applicationID, art.BuildNumber, environmentID, art.DownloadHash, art.Size, art.Perm, art.MD5sum, art.SHA256sum, art.ObjectPath)
 	if err != nil {
 		return sdk.WrapError(err, "insertArtifact> Unable to insert artifact")
 	}
 	return nil
 }
 
-// SaveFile Insert file in db and write it in data directory
+// SaveFile Insert file in db and write it in data directory, checking its md5sum if given
 func SaveFile(db *gorp.DbMap, p *sdk.Pipeline, a *sdk.Application, art sdk.Artifact, content io.ReadCloser, e *sdk.Environment) error {
 	tx, err := db.Begin()
 	if err != nil {
@@ -217,7 +229,7 @@ func SaveFile(db *gorp.DbMap, p *sdk.Pipeline, a *sdk.Application, art sdk.Artif
 	}
 	defer tx.Rollback()
 
-	objectPath, err := objectstore.StoreArtifact(art, content)
+	objectPath, err := storeVerified(&art, content)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,133 @@
+package artifact
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+// ServeFile streams an artifact to an http client, or the byte range requested with a Range header to resume a download
+func ServeFile(w http.ResponseWriter, r *http.Request, art sdk.Artifact) error {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
+	w.Header().Set("Accept-Ranges", "bytes")
+
+	start, end, ok, err := parseRange(r.Header.Get("Range"), art.Size)
+	if err != nil {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", art.Size))
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		return nil
+	}
+
+	f, err := objectstore.FetchArtifact(art)
+	if err != nil {
+		return fmt.Errorf("cannot fetch artifact: %s", err)
+	}
+	defer f.Close()
+
+	// Files of the filesystem driver are served with all the features of the http package
+	if rs, isSeeker := f.(io.ReadSeeker); isSeeker {
+		http.ServeContent(w, r, art.Name, time.Time{}, rs)
+		return nil
+	}
+
+	if !ok {
+		if art.Size > 0 {
+			w.Header().Set("Content-Length", strconv.FormatInt(art.Size, 10))
+		}
+		return objectstore.StreamFile(w, f)
+	}
+
+	if _, err := io.CopyN(ioutil.Discard, f, start); err != nil {
+		return fmt.Errorf("cannot seek artifact to %d: %s", start, err)
+	}
+	w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, art.Size))
+	w.Header().Set("Content-Length", strconv.FormatInt(end-start+1, 10))
+	w.WriteHeader(http.StatusPartialContent)
+	return objectstore.StreamFile(w, ioutil.NopCloser(io.LimitReader(f, end-start+1)))
+}
+
+// parseRange parses a Range header with a single byte range: "bytes=start-end", "bytes=start-" or "bytes=-suffix".
+// ok is false if the whole file must be served: no header, unknown size or several ranges
+func parseRange(header string, size int64) (start, end int64, ok bool, err error) {
+	if header == "" || size <= 0 {
+		return 0, 0, false, nil
+	}
+	if !strings.HasPrefix(header, "bytes=") {
+		return 0, 0, false, fmt.Errorf("invalid range %s", header)
+	}
+	spec := strings.TrimSpace(strings.TrimPrefix(header, "bytes="))
+	if strings.Contains(spec, ",") {
+		return 0, 0, false, nil
+	}
+
+	i := strings.Index(spec, "-")
+	if i < 0 {
+		return 0, 0, false, fmt.Errorf("invalid range %s", header)
+	}
+	first, last := strings.TrimSpace(spec[:i]), strings.TrimSpace(spec[i+1:])
+
+	if first == "" {
+		// Suffix range: the last bytes of the file
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, false, fmt.Errorf("invalid range %s", header)
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, size - 1, true, nil
+	}
+
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, false, fmt.Errorf("invalid range %s", header)
+	}
+	end = size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false, fmt.Errorf("invalid range %s", header)
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	return start, end, true, nil
+}
+
+// Sign returns the signature of a temporary download URL of an artifact, valid until expires
+func Sign(id int64, expires time.Time) (string, error) {
+	signature, err := secret.Sign([]byte(fmt.Sprintf("%d:%d", id, expires.Unix())))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(signature), nil
+}
+
+// CheckSignature checks the signature of a temporary download URL of an artifact, and that it has not expired
+func CheckSignature(id int64, expires int64, signature string, now time.Time) error {
+	if now.Unix() > expires {
+		return sdk.WrapError(sdk.ErrInvalidArtifactSignature, "CheckSignature> URL of artifact %d expired at %s", id, time.Unix(expires, 0))
+	}
+
+	expected, err := secret.Sign([]byte(fmt.Sprintf("%d:%d", id, expires)))
+	if err != nil {
+		return err
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, got) {
+		return sdk.WrapError(sdk.ErrInvalidArtifactSignature, "CheckSignature> Invalid signature of artifact %d", id)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package artifact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		header     string
+		size       int64
+		start, end int64
+		ok, err    bool
+	}{
+		{header: "", size: 100},
+		{header: "bytes=0-", size: 0},
+		{header: "bytes=10-", size: 100, start: 10, end: 99, ok: true},
+		{header: "bytes=10-19", size: 100, start: 10, end: 19, ok: true},
+		{header: "bytes=90-200", size: 100, start: 90, end: 99, ok: true},
+		{header: "bytes=-10", size: 100, start: 90, end: 99, ok: true},
+		{header: "bytes=-200", size: 100, start: 0, end: 99, ok: true},
+		{header: "bytes=0-1,5-6", size: 100},
+		{header: "bytes=100-", size: 100, err: true},
+		{header: "bytes=20-10", size: 100, err: true},
+		{header: "bytes=abc", size: 100, err: true},
+		{header: "items=0-10", size: 100, err: true},
+	}
+
+	for _, tt := range tests {
+		start, end, ok, err := parseRange(tt.header, tt.size)
+		assert.Equal(t, tt.err, err != nil, tt.header)
+		assert.Equal(t, tt.ok, ok, tt.header)
+		assert.Equal(t, tt.start, start, tt.header)
+		assert.Equal(t, tt.end, end, tt.header)
+	}
+}
@@ -0,0 +1,129 @@
+package artifact
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Promote links the artifacts of a build in a channel, replacing the build previously promoted in this channel.
+// Promoted artifacts are never deleted by the retention policies
+func Promote(db gorp.SqlExecutor, pipelineID, applicationID, environmentID, buildNumber int64, channel, user string) error {
+	if err := sdk.IsValidArtifactChannel(channel); err != nil {
+		return err
+	}
+
+	arts, err := LoadArtifactsByBuildNumber(db, pipelineID, applicationID, buildNumber, environmentID)
+	if err != nil {
+		return sdk.WrapError(err, "Promote> Unable to load artifacts of build %d", buildNumber)
+	}
+	if len(arts) == 0 {
+		return sdk.WrapError(sdk.ErrNotFound, "Promote> Build %d has no artifact", buildNumber)
+	}
+
+	query := `DELETE FROM artifact_promotion WHERE application_id = $1 AND pipeline_id = $2 AND channel = $3`
+	if _, err := db.Exec(query, applicationID, pipelineID, channel); err != nil {
+		return sdk.WrapError(err, "Promote> Unable to delete previous promotion in %s", channel)
+	}
+
+	query = `INSERT INTO artifact_promotion (channel, application_id, pipeline_id, environment_id, build_number, artifact_id, promoted_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, a := range arts {
+		if _, err := db.Exec(query, channel, applicationID, pipelineID, environmentID, buildNumber, a.ID, user); err != nil {
+			return sdk.WrapError(err, "Promote> Unable to promote artifact %d in %s", a.ID, channel)
+		}
+	}
+	return nil
+}
+
+// LoadPromotions loads the builds promoted in the channels of an application pipeline
+func LoadPromotions(db gorp.SqlExecutor, pipelineID, applicationID int64) ([]sdk.ArtifactPromotion, error) {
+	query := `SELECT artifact_promotion.channel, application.name, pipeline.name, environment.name, artifact_promotion.build_number,
+			MAX(artifact_promotion.promoted), MAX(artifact_promotion.promoted_by)
+		FROM artifact_promotion
+		JOIN application ON application.id = artifact_promotion.application_id
+		JOIN pipeline ON pipeline.id = artifact_promotion.pipeline_id
+		JOIN environment ON environment.id = artifact_promotion.environment_id
+		WHERE artifact_promotion.pipeline_id = $1 AND artifact_promotion.application_id = $2
+		GROUP BY artifact_promotion.channel, application.name, pipeline.name, environment.name, artifact_promotion.build_number
+		ORDER BY artifact_promotion.channel`
+	rows, err := db.Query(query, pipelineID, applicationID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadPromotions> Unable to load promotions")
+	}
+	defer rows.Close()
+
+	promotions := []sdk.ArtifactPromotion{}
+	for rows.Next() {
+		var p sdk.ArtifactPromotion
+		if err := rows.Scan(&p.Channel, &p.Application, &p.Pipeline, &p.Environment, &p.BuildNumber, &p.Promoted, &p.PromotedBy); err != nil {
+			return nil, sdk.WrapError(err, "LoadPromotions> Unable to scan promotion")
+		}
+		promotions = append(promotions, p)
+	}
+	return promotions, nil
+}
+
+// LoadArtifactsByRef loads the artifacts of a reference, once its pipeline, application and environment loaded
+func LoadArtifactsByRef(db gorp.SqlExecutor, ref *sdk.ArtifactRef, pipelineID, applicationID, environmentID int64) ([]sdk.Artifact, error) {
+	var arts []sdk.Artifact
+	var err error
+	switch {
+	case ref.Channel != "":
+		arts, err = loadPromotedArtifacts(db, ref.Channel, pipelineID, applicationID, environmentID)
+	case ref.BuildNumber > 0:
+		arts, err = LoadArtifactsByBuildNumber(db, pipelineID, applicationID, ref.BuildNumber, environmentID)
+	default:
+		arts, err = LoadArtifacts(db, pipelineID, applicationID, environmentID, ref.Tag)
+	}
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadArtifactsByRef> Unable to load artifacts of %s", ref)
+	}
+
+	res := []sdk.Artifact{}
+	for _, a := range arts {
+		if ref.Name != "" && a.Name != ref.Name {
+			continue
+		}
+		a.Project = ref.Project
+		a.Application = ref.Application
+		a.Pipeline = ref.Pipeline
+		a.Environment = ref.Environment
+		res = append(res, a)
+	}
+	return res, nil
+}
+
+func loadPromotedArtifacts(db gorp.SqlExecutor, channel string, pipelineID, applicationID, environmentID int64) ([]sdk.Artifact, error) {
+	query := `SELECT artifact.id, artifact.name, artifact.tag, artifact.build_number, artifact.download_hash, artifact.size, artifact.perm,
+			artifact.md5sum, artifact.sha256sum, artifact.object_path
+		FROM artifact
+		JOIN artifact_promotion ON artifact_promotion.artifact_id = artifact.id
+		WHERE artifact_promotion.channel = $1 AND artifact_promotion.pipeline_id = $2
+		AND artifact_promotion.application_id = $3 AND artifact_promotion.environment_id = $4
+		ORDER BY artifact.name`
+	rows, err := db.Query(query, channel, pipelineID, applicationID, environmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	arts := []sdk.Artifact{}
+	for rows.Next() {
+		art := sdk.Artifact{}
+		var md5sum, sha256sum, objectpath sql.NullString
+		var size, perm sql.NullInt64
+		if err := rows.Scan(&art.ID, &art.Name, &art.Tag, &art.BuildNumber, &art.DownloadHash, &size, &perm, &md5sum, &sha256sum, &objectpath); err != nil {
+			return nil, err
+		}
+		art.MD5sum = md5sum.String
+		art.SHA256sum = sha256sum.String
+		art.ObjectPath = objectpath.String
+		art.Size = size.Int64
+		art.Perm = uint32(perm.Int64)
+		arts = append(arts, art)
+	}
+	return arts, nil
+}
@@ -0,0 +1,341 @@
+package artifact
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// gcMetrics counts what the cleaner reclaimed since startup
+var gcMetrics struct {
+	sync.Mutex
+	lastRun   time.Time
+	lastError error
+	builds    int
+	artifacts int
+	size      int64
+}
+
+const selectRetention = `SELECT id, project_id, application_id, pipeline_id, keep_last, keep_days, keep_deployed FROM artifact_retention`
+
+// LoadRetention loads the retention policy of a project if application and pipeline are 0,
+// of an application if pipeline is 0 or of a pipeline if application is 0
+func LoadRetention(db gorp.SqlExecutor, projectID, applicationID, pipelineID int64) (*sdk.ArtifactRetention, error) {
+	query := selectRetention + ` WHERE project_id = $1 AND COALESCE(application_id, 0) = $2 AND COALESCE(pipeline_id, 0) = $3`
+	rules, err := loadRetentions(db, query, projectID, applicationID, pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, sdk.ErrNotFound
+	}
+	return &rules[0], nil
+}
+
+// SaveRetention inserts or replaces the retention policy of a project, an application or a pipeline
+func SaveRetention(db gorp.SqlExecutor, r *sdk.ArtifactRetention) error {
+	old, err := LoadRetention(db, r.ProjectID, r.ApplicationID, r.PipelineID)
+	switch err {
+	case nil:
+		r.ID = old.ID
+		query := `UPDATE artifact_retention SET keep_last = $2, keep_days = $3, keep_deployed = $4 WHERE id = $1`
+		if _, err := db.Exec(query, r.ID, r.KeepLast, r.KeepDays, r.KeepDeployed); err != nil {
+			return sdk.WrapError(err, "SaveRetention> Unable to update artifact retention %d", r.ID)
+		}
+	case sdk.ErrNotFound:
+		query := `INSERT INTO artifact_retention (project_id, application_id, pipeline_id, keep_last, keep_days, keep_deployed)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+		if err := db.QueryRow(query, r.ProjectID, nullID(r.ApplicationID), nullID(r.PipelineID), r.KeepLast, r.KeepDays, r.KeepDeployed).Scan(&r.ID); err != nil {
+			return sdk.WrapError(err, "SaveRetention> Unable to insert artifact retention")
+		}
+	default:
+		return err
+	}
+	return nil
+}
+
+// DeleteRetention deletes the retention policy of a project, an application or a pipeline
+func DeleteRetention(db gorp.SqlExecutor, projectID, applicationID, pipelineID int64) error {
+	query := `DELETE FROM artifact_retention WHERE project_id = $1 AND COALESCE(application_id, 0) = $2 AND COALESCE(pipeline_id, 0) = $3`
+	res, err := db.Exec(query, projectID, applicationID, pipelineID)
+	if err != nil {
+		return sdk.WrapError(err, "DeleteRetention> Unable to delete artifact retention")
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.ErrNotFound
+	}
+	return nil
+}
+
+func loadRetentions(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.ArtifactRetention, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadRetentions> Unable to load artifact retentions")
+	}
+	defer rows.Close()
+
+	rules := []sdk.ArtifactRetention{}
+	for rows.Next() {
+		var r sdk.ArtifactRetention
+		var appID, pipID sql.NullInt64
+		if err := rows.Scan(&r.ID, &r.ProjectID, &appID, &pipID, &r.KeepLast, &r.KeepDays, &r.KeepDeployed); err != nil {
+			return nil, sdk.WrapError(err, "loadRetentions> Unable to scan artifact retention")
+		}
+		r.ApplicationID = appID.Int64
+		r.PipelineID = pipID.Int64
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// PinBuild pins or unpins the artifacts of a build, the artifacts of a pinned build are never deleted by the retention policies
+func PinBuild(db gorp.SqlExecutor, pipelineID, applicationID, environmentID, buildNumber int64, pinned bool) error {
+	query := `UPDATE artifact SET pinned = $5 WHERE pipeline_id = $1 AND application_id = $2 AND environment_id = $3 AND build_number = $4`
+	res, err := db.Exec(query, pipelineID, applicationID, environmentID, buildNumber, pinned)
+	if err != nil {
+		return sdk.WrapError(err, "PinBuild> Unable to pin artifacts of build %d", buildNumber)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.ErrNotFound
+	}
+	return nil
+}
+
+// artifactBuild is a build having artifacts
+type artifactBuild struct {
+	sdk.ArtifactGCBuild
+	projectID     int64
+	applicationID int64
+	pipelineID    int64
+	environmentID int64
+	pinned        bool
+}
+
+// loadArtifactBuilds loads the builds having artifacts, from the most recent for each application, pipeline and environment
+func loadArtifactBuilds(db gorp.SqlExecutor, projectID int64) ([]artifactBuild, error) {
+	query := `SELECT project.id, project.projectkey, application.id, application.name, pipeline.id, pipeline.name, environment.id, environment.name,
+			artifact.build_number, COALESCE(MAX(artifact.created), LOCALTIMESTAMP),
+			BOOL_OR(artifact.pinned OR EXISTS (SELECT 1 FROM artifact_promotion WHERE artifact_promotion.artifact_id = artifact.id)),
+			COUNT(artifact.id), COALESCE(SUM(artifact.size), 0)
+		FROM artifact
+		JOIN pipeline ON artifact.pipeline_id = pipeline.id
+		JOIN project ON pipeline.project_id = project.id
+		JOIN application ON application.id = artifact.application_id
+		JOIN environment ON environment.id = artifact.environment_id
+		WHERE $1 = 0 OR project.id = $1
+		GROUP BY project.id, project.projectkey, application.id, application.name, pipeline.id, pipeline.name, environment.id, environment.name, artifact.build_number
+		ORDER BY application.id, pipeline.id, environment.id, artifact.build_number DESC`
+	rows, err := db.Query(query, projectID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadArtifactBuilds> Unable to load builds")
+	}
+	defer rows.Close()
+
+	var builds []artifactBuild
+	for rows.Next() {
+		var b artifactBuild
+		if err := rows.Scan(&b.projectID, &b.Project, &b.applicationID, &b.Application, &b.pipelineID, &b.Pipeline, &b.environmentID, &b.Environment,
+			&b.BuildNumber, &b.Created, &b.pinned, &b.Artifacts, &b.Size); err != nil {
+			return nil, sdk.WrapError(err, "loadArtifactBuilds> Unable to scan build")
+		}
+		builds = append(builds, b)
+	}
+	return builds, nil
+}
+
+// retentionOf returns the most specific retention policy of a build, nil if there is none
+func retentionOf(rules []sdk.ArtifactRetention, b *artifactBuild) *sdk.ArtifactRetention {
+	var projectRule, applicationRule *sdk.ArtifactRetention
+	for i := range rules {
+		r := &rules[i]
+		if r.ProjectID != b.projectID {
+			continue
+		}
+		switch {
+		case r.PipelineID != 0:
+			if r.PipelineID == b.pipelineID {
+				return r
+			}
+		case r.ApplicationID != 0:
+			if r.ApplicationID == b.applicationID {
+				applicationRule = r
+			}
+		default:
+			projectRule = r
+		}
+	}
+	if applicationRule != nil {
+		return applicationRule
+	}
+	return projectRule
+}
+
+// isDeployed returns true if a deployment pipeline triggered by the build succeeded
+func isDeployed(db gorp.SqlExecutor, b *artifactBuild) (bool, error) {
+	query := `SELECT COUNT(1) FROM pipeline_build parent
+		JOIN pipeline_build child ON child.parent_pipeline_build_id = parent.id
+		JOIN pipeline ON pipeline.id = child.pipeline_id
+		WHERE parent.pipeline_id = $1 AND parent.application_id = $2 AND parent.environment_id = $3 AND parent.build_number = $4
+		AND pipeline.type = $5 AND child.status = $6`
+	var nb int
+	if err := db.QueryRow(query, b.pipelineID, b.applicationID, b.environmentID, b.BuildNumber, sdk.DeploymentPipeline, sdk.StatusSuccess.String()).Scan(&nb); err != nil {
+		return false, sdk.WrapError(err, "isDeployed> Unable to load deployments of build %d", b.BuildNumber)
+	}
+	return nb > 0, nil
+}
+
+// deleteBuildArtifacts deletes the stored objects and the rows of the artifacts of a build neither pinned nor promoted.
+// It returns the number and the size of the artifacts actually deleted, or which would be with a dry run
+func deleteBuildArtifacts(db gorp.SqlExecutor, b *artifactBuild, dryRun bool) (int, int64, error) {
+	query := `SELECT id, COALESCE(size, 0) FROM artifact WHERE pipeline_id = $1 AND application_id = $2 AND environment_id = $3 AND build_number = $4 AND pinned = false
+		AND NOT EXISTS (SELECT 1 FROM artifact_promotion WHERE artifact_promotion.artifact_id = artifact.id)`
+	rows, err := db.Query(query, b.pipelineID, b.applicationID, b.environmentID, b.BuildNumber)
+	if err != nil {
+		return 0, 0, err
+	}
+	var ids, sizes []int64
+	for rows.Next() {
+		var id, size int64
+		if err := rows.Scan(&id, &size); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		ids = append(ids, id)
+		sizes = append(sizes, size)
+	}
+	rows.Close()
+
+	var nb int
+	var freed int64
+	for i, id := range ids {
+		if !dryRun {
+			// The artifact may have been deleted meanwhile by another API instance
+			err := DeleteArtifact(db, id)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return nb, freed, sdk.WrapError(err, "deleteBuildArtifacts> Unable to delete artifact %d", id)
+			}
+		}
+		nb++
+		freed += sizes[i]
+	}
+	return nb, freed, nil
+}
+
+// GC applies the retention policies to the artifacts of a project, or of all projects if projectID is 0.
+// A build is expired when its policy keeps it neither as one of the last builds nor as a recent one,
+// unless it is pinned, promoted in a channel or deployed with a policy keeping deployed builds.
+// With a dry run, nothing is deleted and the report lists the builds which would be
+func GC(db gorp.SqlExecutor, projectID int64, dryRun bool, now time.Time) (*sdk.ArtifactGCReport, error) {
+	report := &sdk.ArtifactGCReport{DryRun: dryRun, Builds: []sdk.ArtifactGCBuild{}}
+
+	rules, err := loadRetentions(db, selectRetention+` WHERE $1 = 0 OR project_id = $1`, projectID)
+	if err != nil {
+		return report, err
+	}
+	if len(rules) == 0 {
+		return report, nil
+	}
+
+	builds, err := loadArtifactBuilds(db, projectID)
+	if err != nil {
+		return report, err
+	}
+
+	var rank int
+	for i := range builds {
+		b := &builds[i]
+		if i > 0 && builds[i-1].applicationID == b.applicationID && builds[i-1].pipelineID == b.pipelineID && builds[i-1].environmentID == b.environmentID {
+			rank++
+		} else {
+			rank = 0
+		}
+
+		r := retentionOf(rules, b)
+		if r == nil || b.pinned || r.Keeps(rank, b.Created, now) {
+			continue
+		}
+		if r.KeepDeployed {
+			deployed, err := isDeployed(db, b)
+			if err != nil {
+				return report, err
+			}
+			if deployed {
+				continue
+			}
+		}
+
+		// Only the artifacts actually deleted are reported
+		nb, freed, err := deleteBuildArtifacts(db, b, dryRun)
+		if nb > 0 {
+			b.Artifacts = nb
+			b.Size = freed
+			report.Builds = append(report.Builds, b.ArtifactGCBuild)
+			report.Artifacts += nb
+			report.Size += freed
+		}
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+//Cleaner is the goroutine deleting the artifacts expired by the retention policies, and the uploads never completed
+func Cleaner(DBFunc func() *gorp.DbMap) {
+	defer log.Error("artifact.Cleaner> has been exited !")
+	for {
+		time.Sleep(time.Hour)
+		if err := purgeUploads(DBFunc(), time.Now().Add(-uploadTTL)); err != nil {
+			log.Warning("artifact.Cleaner> Error : %s", err)
+		}
+		report, err := GC(DBFunc(), 0, false, time.Now())
+		recordGC(report, err)
+		if err != nil {
+			log.Warning("artifact.Cleaner> Error : %s", err)
+		}
+		if len(report.Builds) > 0 {
+			log.Info("artifact.Cleaner> %d artifacts of %d builds deleted, %d bytes reclaimed", report.Artifacts, len(report.Builds), report.Size)
+		}
+	}
+}
+
+func recordGC(report *sdk.ArtifactGCReport, err error) {
+	gcMetrics.Lock()
+	defer gcMetrics.Unlock()
+	gcMetrics.lastRun = time.Now()
+	gcMetrics.lastError = err
+	gcMetrics.builds += len(report.Builds)
+	gcMetrics.artifacts += report.Artifacts
+	gcMetrics.size += report.Size
+}
+
+//GCStatus returns the status of the cleaner and the space reclaimed since startup
+func GCStatus() string {
+	gcMetrics.Lock()
+	defer gcMetrics.Unlock()
+	if gcMetrics.lastRun.IsZero() {
+		return "Not Running"
+	}
+	status := []string{"OK"}
+	if gcMetrics.lastError != nil {
+		status = []string{"⚠ " + gcMetrics.lastError.Error()}
+	}
+	status = append(status, fmt.Sprintf("last run %s", gcMetrics.lastRun.Format(time.RFC3339)))
+	status = append(status, fmt.Sprintf("%d artifacts of %d builds deleted", gcMetrics.artifacts, gcMetrics.builds))
+	status = append(status, fmt.Sprintf("%d bytes reclaimed", gcMetrics.size))
+	return strings.Join(status, ", ")
+}
@@ -0,0 +1,333 @@
+package artifact
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// uploadTTL is the time after which an incomplete upload is purged by the cleaner
+const uploadTTL = 24 * time.Hour
+
+// upload is a resumable upload with the build it belongs to
+type upload struct {
+	sdk.ArtifactChunkedUpload
+	EnvironmentID int64
+	BuildNumber   int
+	Tag           string
+	Completing    bool
+}
+
+// InsertUpload starts a resumable upload of an artifact of a build
+func InsertUpload(db gorp.SqlExecutor, pipelineID, applicationID, environmentID int64, buildNumber int, tag string, u *sdk.ArtifactChunkedUpload) error {
+	if u.Name == "" || u.MD5sum == "" || u.Size < 0 {
+		return sdk.WrapError(sdk.ErrWrongRequest, "InsertUpload> name, size and md5sum are mandatory")
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return sdk.WrapError(err, "InsertUpload> Cannot generate upload id")
+	}
+	u.ID = hex.EncodeToString(id)
+	u.Offset = 0
+	u.Chunks = 0
+
+	query := `INSERT INTO artifact_upload
+		(id, pipeline_id, application_id, environment_id, build_number, tag, name, environment_name, size, perm, md5sum, sha256sum)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	if _, err := db.Exec(query, u.ID, pipelineID, applicationID, environmentID, buildNumber, tag, u.Name, u.Environment, u.Size, u.Perm, u.MD5sum, u.SHA256sum); err != nil {
+		return sdk.WrapError(err, "InsertUpload> Cannot insert upload of %s", u.Name)
+	}
+	return nil
+}
+
+// LoadUpload loads a resumable upload of an artifact of the given pipeline and application
+func LoadUpload(db gorp.SqlExecutor, id string, pipelineID, applicationID int64) (*sdk.ArtifactChunkedUpload, error) {
+	u, err := loadUpload(db, id, pipelineID, applicationID, false)
+	if err != nil {
+		return nil, err
+	}
+	return &u.ArtifactChunkedUpload, nil
+}
+
+// LoadUploadEnvironmentID loads the id of the environment of a resumable upload of the given pipeline and application
+func LoadUploadEnvironmentID(db gorp.SqlExecutor, id string, pipelineID, applicationID int64) (int64, error) {
+	u, err := loadUpload(db, id, pipelineID, applicationID, false)
+	if err != nil {
+		return 0, err
+	}
+	return u.EnvironmentID, nil
+}
+
+func loadUpload(db gorp.SqlExecutor, id string, pipelineID, applicationID int64, forUpdate bool) (*upload, error) {
+	query := `SELECT id, name, environment_name, size, perm, md5sum, sha256sum, received, chunks, environment_id, build_number, tag, completing
+		FROM artifact_upload
+		WHERE id = $1 AND pipeline_id = $2 AND application_id = $3`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+
+	u := &upload{}
+	err := db.QueryRow(query, id, pipelineID, applicationID).Scan(&u.ID, &u.Name, &u.Environment, &u.Size, &u.Perm, &u.MD5sum, &u.SHA256sum,
+		&u.Offset, &u.Chunks, &u.EnvironmentID, &u.BuildNumber, &u.Tag, &u.Completing)
+	if err == sql.ErrNoRows {
+		return nil, sdk.ErrNotFound
+	}
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadUpload> Cannot load upload %s", id)
+	}
+	return u, nil
+}
+
+// StoreChunk stores the chunk of a resumable upload starting at offset.
+// The offset must be the number of bytes already received, so that a chunk is never stored twice
+func StoreChunk(db *gorp.DbMap, id string, pipelineID, applicationID, offset int64, data io.Reader) (*sdk.ArtifactChunkedUpload, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	u, err := loadUpload(tx, id, pipelineID, applicationID, true)
+	if err != nil {
+		return nil, err
+	}
+	if u.Completing {
+		return nil, sdk.WrapError(sdk.ErrUploadCompleting, "StoreChunk> Upload %s is being completed", id)
+	}
+	if offset != u.Offset {
+		return nil, sdk.WrapError(sdk.ErrInvalidUploadOffset, "StoreChunk> Upload %s is at offset %d, got %d", id, u.Offset, offset)
+	}
+
+	max := u.Size - u.Offset
+	if max > sdk.ArtifactChunkSize {
+		max = sdk.ArtifactChunkSize
+	}
+	chunk, err := ioutil.ReadAll(io.LimitReader(data, max+1))
+	if err != nil {
+		return nil, sdk.WrapError(err, "StoreChunk> Cannot read chunk of upload %s", id)
+	}
+	if int64(len(chunk)) > max {
+		return nil, sdk.WrapError(sdk.ErrWrongRequest, "StoreChunk> Chunk of upload %s is larger than %d bytes", id, max)
+	}
+	if len(chunk) == 0 {
+		return &u.ArtifactChunkedUpload, nil
+	}
+
+	c := sdk.ArtifactChunk{UploadID: id, Index: u.Chunks}
+	if _, err := objectstore.StoreArtifactChunk(c, ioutil.NopCloser(bytes.NewReader(chunk))); err != nil {
+		return nil, sdk.WrapError(err, "StoreChunk> Cannot store chunk %d of upload %s", c.Index, id)
+	}
+
+	u.Offset += int64(len(chunk))
+	u.Chunks++
+	if _, err := tx.Exec(`UPDATE artifact_upload SET received = $2, chunks = $3 WHERE id = $1`, id, u.Offset, u.Chunks); err != nil {
+		return nil, sdk.WrapError(err, "StoreChunk> Cannot update upload %s", id)
+	}
+	return &u.ArtifactChunkedUpload, tx.Commit()
+}
+
+// CompleteUpload assembles the chunks of an upload into the artifact and verifies its checksums.
+// The chunks are deleted, and the artifact too if a checksum does not match.
+// The upload is marked as completing while the artifact is stored, so that no transaction is held during the storage
+func CompleteUpload(db *gorp.DbMap, p *sdk.Pipeline, a *sdk.Application, id string, downloadHash string) (*sdk.Artifact, error) {
+	u, err := startCompletion(db, id, p.ID, a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	art := sdk.Artifact{
+		Name:         u.Name,
+		Project:      p.ProjectKey,
+		Pipeline:     p.Name,
+		Application:  a.Name,
+		Tag:          u.Tag,
+		Environment:  u.Environment,
+		BuildNumber:  u.BuildNumber,
+		DownloadHash: downloadHash,
+		Size:         u.Size,
+		Perm:         u.Perm,
+		MD5sum:       u.MD5sum,
+		SHA256sum:    u.SHA256sum,
+	}
+
+	chunks := &chunkReader{uploadID: id, chunks: u.Chunks}
+	objectPath, err := storeVerified(&art, chunks)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrArtifactChecksum) {
+		cancelCompletion(db, id)
+		return nil, sdk.WrapError(err, "CompleteUpload> Cannot store artifact %s", art.Name)
+	}
+	art.ObjectPath = objectPath
+
+	if errF := finishCompletion(db, p, a, u, &art, err == nil); errF != nil {
+		cancelCompletion(db, id)
+		return nil, errF
+	}
+
+	deleteChunks(id, u.Chunks)
+	if err != nil {
+		return nil, sdk.WrapError(err, "CompleteUpload> Upload %s of %s is corrupted", id, art.Name)
+	}
+	return &art, nil
+}
+
+// startCompletion checks that an upload is fully received and marks it as completing
+func startCompletion(db *gorp.DbMap, id string, pipelineID, applicationID int64) (*upload, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	u, err := loadUpload(tx, id, pipelineID, applicationID, true)
+	if err != nil {
+		return nil, err
+	}
+	if u.Completing {
+		return nil, sdk.WrapError(sdk.ErrUploadCompleting, "startCompletion> Upload %s is already being completed", id)
+	}
+	if u.Offset != u.Size {
+		return nil, sdk.WrapError(sdk.ErrInvalidUploadOffset, "startCompletion> Upload %s is incomplete: %d/%d bytes", id, u.Offset, u.Size)
+	}
+	if _, err := tx.Exec(`UPDATE artifact_upload SET completing = true WHERE id = $1`, id); err != nil {
+		return nil, sdk.WrapError(err, "startCompletion> Cannot update upload %s", id)
+	}
+	return u, tx.Commit()
+}
+
+// finishCompletion deletes a completed upload and records its artifact if it has been stored
+func finishCompletion(db *gorp.DbMap, p *sdk.Pipeline, a *sdk.Application, u *upload, art *sdk.Artifact, stored bool) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM artifact_upload WHERE id = $1`, u.ID); err != nil {
+		return sdk.WrapError(err, "finishCompletion> Cannot delete upload %s", u.ID)
+	}
+	if stored {
+		if err := insertArtifact(tx, p.ID, a.ID, u.EnvironmentID, *art); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// cancelCompletion marks an upload as not completing anymore, so that its completion can be retried
+func cancelCompletion(db gorp.SqlExecutor, id string) {
+	if _, err := db.Exec(`UPDATE artifact_upload SET completing = false WHERE id = $1`, id); err != nil {
+		log.Warning("cancelCompletion> Cannot update upload %s: %s", id, err)
+	}
+}
+
+// storeVerified stores an artifact and checks its md5 and sha256 sums while it is stored.
+// The artifact sums are computed if they are not set, the artifact is deleted if they do not match
+func storeVerified(art *sdk.Artifact, content io.ReadCloser) (string, error) {
+	md5Hash := md5.New()
+	sha256Hash := sha256.New()
+	data := struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(content, io.MultiWriter(md5Hash, sha256Hash)), content}
+
+	objectPath, err := objectstore.StoreArtifact(*art, data)
+	if err != nil {
+		return "", err
+	}
+
+	md5sum := hex.EncodeToString(md5Hash.Sum(nil))
+	sha256sum := hex.EncodeToString(sha256Hash.Sum(nil))
+	if (art.MD5sum != "" && art.MD5sum != md5sum) || (art.SHA256sum != "" && art.SHA256sum != sha256sum) {
+		log.Warning("storeVerified> Checksums of artifact %s are md5:%s sha256:%s, expected md5:%s sha256:%s", art.Name, md5sum, sha256sum, art.MD5sum, art.SHA256sum)
+		if err := objectstore.DeleteArtifact(*art); err != nil {
+			log.Warning("storeVerified> Cannot delete corrupted artifact %s: %s", art.Name, err)
+		}
+		return "", sdk.ErrArtifactChecksum
+	}
+	art.MD5sum = md5sum
+	art.SHA256sum = sha256sum
+	return objectPath, nil
+}
+
+func deleteChunks(id string, chunks int) {
+	for i := 0; i < chunks; i++ {
+		if err := objectstore.DeleteArtifactChunk(sdk.ArtifactChunk{UploadID: id, Index: i}); err != nil {
+			log.Warning("deleteChunks> Cannot delete chunk %d of upload %s: %s", i, id, err)
+		}
+	}
+}
+
+// purgeUploads deletes the uploads started before t and never completed
+func purgeUploads(db gorp.SqlExecutor, t time.Time) error {
+	rows, err := db.Query(`DELETE FROM artifact_upload WHERE created < $1 RETURNING id, chunks`, t)
+	if err != nil {
+		return sdk.WrapError(err, "purgeUploads> Cannot delete uploads")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var chunks int
+		if err := rows.Scan(&id, &chunks); err != nil {
+			return err
+		}
+		log.Info("purgeUploads> Deleting incomplete upload %s", id)
+		deleteChunks(id, chunks)
+	}
+	return nil
+}
+
+// chunkReader reads the chunks of an upload one after the other
+type chunkReader struct {
+	uploadID string
+	chunks   int
+	index    int
+	current  io.ReadCloser
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if c.current == nil {
+			if c.index == c.chunks {
+				return 0, io.EOF
+			}
+			f, err := objectstore.FetchArtifactChunk(sdk.ArtifactChunk{UploadID: c.uploadID, Index: c.index})
+			if err != nil {
+				return 0, err
+			}
+			c.current = f
+		}
+
+		n, err := c.current.Read(p)
+		if err == io.EOF {
+			c.current.Close()
+			c.current = nil
+			c.index++
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (c *chunkReader) Close() error {
+	if c.current == nil {
+		return nil
+	}
+	return c.current.Close()
+}
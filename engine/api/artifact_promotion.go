@@ -0,0 +1,122 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/artifact"
+	"github.com/ovh/cds/engine/api/context"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/sdk"
+)
+
+func getArtifactsByReferenceHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	ref, err := sdk.ParseArtifactRef(r.FormValue("ref"))
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactsByReferenceHandler> Invalid reference %s", r.FormValue("ref"))
+	}
+
+	// Applications are loaded with the permissions of the user
+	app, err := application.LoadByName(db, ref.Project, ref.Application, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactsByReferenceHandler> Cannot load application %s", ref.Application)
+	}
+
+	pip, err := pipeline.LoadPipeline(db, ref.Project, ref.Pipeline, false)
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactsByReferenceHandler> Cannot load pipeline %s", ref.Pipeline)
+	}
+
+	env := &sdk.DefaultEnv
+	if ref.Environment != sdk.DefaultEnv.Name {
+		env, err = environment.LoadEnvironmentByName(db, ref.Project, ref.Environment)
+		if err != nil {
+			return sdk.WrapError(err, "getArtifactsByReferenceHandler> Cannot load environment %s", ref.Environment)
+		}
+	}
+
+	if !permission.AccessToPipeline(env.ID, pip.ID, c.User, permission.PermissionRead) {
+		return sdk.WrapError(sdk.ErrForbidden, "getArtifactsByReferenceHandler> No read permission on pipeline %s in environment %s", pip.Name, env.Name)
+	}
+
+	arts, err := artifact.LoadArtifactsByRef(db, ref, pip.ID, app.ID, env.ID)
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactsByReferenceHandler> Cannot load artifacts of %s", ref)
+	}
+	return WriteJSON(w, r, arts, http.StatusOK)
+}
+
+func promoteBuildArtifactsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+	channel := vars["channel"]
+	envName := r.FormValue("envName")
+
+	buildNumber, err := requestVarInt(r, "build")
+	if err != nil {
+		return sdk.WrapError(err, "promoteBuildArtifactsHandler> invalid build number")
+	}
+
+	pip, err := pipeline.LoadPipeline(db, projectKey, vars["permPipelineKey"], false)
+	if err != nil {
+		return sdk.WrapError(err, "promoteBuildArtifactsHandler> Cannot load pipeline")
+	}
+
+	app, err := application.LoadByName(db, projectKey, vars["permApplicationName"], c.User)
+	if err != nil {
+		return sdk.WrapError(err, "promoteBuildArtifactsHandler> Cannot load application")
+	}
+
+	env := &sdk.DefaultEnv
+	if envName != "" && envName != sdk.DefaultEnv.Name {
+		env, err = environment.LoadEnvironmentByName(db, projectKey, envName)
+		if err != nil {
+			return sdk.WrapError(err, "promoteBuildArtifactsHandler> Cannot load environment %s", envName)
+		}
+	}
+
+	if !permission.AccessToEnvironment(env.ID, c.User, permission.PermissionReadExecute) {
+		return sdk.WrapError(sdk.ErrForbidden, "promoteBuildArtifactsHandler> You do not have access to this environment %s", env.Name)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "promoteBuildArtifactsHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	if err := artifact.Promote(tx, pip.ID, app.ID, env.ID, buildNumber, channel, c.User.Username); err != nil {
+		return sdk.WrapError(err, "promoteBuildArtifactsHandler> Cannot promote build %d in %s", buildNumber, channel)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "promoteBuildArtifactsHandler> Cannot commit transaction")
+	}
+	return nil
+}
+
+func getArtifactPromotionsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+
+	pip, err := pipeline.LoadPipeline(db, projectKey, vars["permPipelineKey"], false)
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactPromotionsHandler> Cannot load pipeline")
+	}
+
+	app, err := application.LoadByName(db, projectKey, vars["permApplicationName"], c.User)
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactPromotionsHandler> Cannot load application")
+	}
+
+	promotions, err := artifact.LoadPromotions(db, pip.ID, app.ID)
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactPromotionsHandler> Cannot load promotions")
+	}
+	return WriteJSON(w, r, promotions, http.StatusOK)
+}
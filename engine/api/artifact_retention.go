@@ -0,0 +1,157 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/artifact"
+	"github.com/ovh/cds/engine/api/context"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/sdk"
+)
+
+func getArtifactRetentionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	level, err := loadRetentionLevelFromRequest(db, r, c)
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactRetentionHandler> Cannot load retention level")
+	}
+
+	retention, err := artifact.LoadRetention(db, level.ProjectID, level.ApplicationID, level.PipelineID)
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactRetentionHandler> Cannot load artifact retention")
+	}
+	return WriteJSON(w, r, retention, http.StatusOK)
+}
+
+func putArtifactRetentionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	level, err := loadRetentionLevelFromRequest(db, r, c)
+	if err != nil {
+		return sdk.WrapError(err, "putArtifactRetentionHandler> Cannot load retention level")
+	}
+
+	var retention sdk.ArtifactRetention
+	if err := UnmarshalBody(r, &retention); err != nil {
+		return err
+	}
+	if err := retention.IsValid(); err != nil {
+		return err
+	}
+	retention.ProjectID = level.ProjectID
+	retention.ApplicationID = level.ApplicationID
+	retention.PipelineID = level.PipelineID
+
+	if err := artifact.SaveRetention(db, &retention); err != nil {
+		return sdk.WrapError(err, "putArtifactRetentionHandler> Cannot save artifact retention")
+	}
+	return WriteJSON(w, r, retention, http.StatusOK)
+}
+
+func deleteArtifactRetentionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	level, err := loadRetentionLevelFromRequest(db, r, c)
+	if err != nil {
+		return sdk.WrapError(err, "deleteArtifactRetentionHandler> Cannot load retention level")
+	}
+
+	if err := artifact.DeleteRetention(db, level.ProjectID, level.ApplicationID, level.PipelineID); err != nil {
+		return sdk.WrapError(err, "deleteArtifactRetentionHandler> Cannot delete artifact retention")
+	}
+	return nil
+}
+
+// loadRetentionLevelFromRequest returns the project, application or pipeline of a retention route
+func loadRetentionLevelFromRequest(db gorp.SqlExecutor, r *http.Request, c *context.Ctx) (*sdk.ArtifactRetention, error) {
+	vars := mux.Vars(r)
+	if appName, ok := vars["permApplicationName"]; ok {
+		app, err := application.LoadByName(db, vars["key"], appName, c.User)
+		if err != nil {
+			return nil, err
+		}
+		return &sdk.ArtifactRetention{ProjectID: app.ProjectID, ApplicationID: app.ID}, nil
+	}
+
+	if pipName, ok := vars["permPipelineKey"]; ok {
+		pip, err := pipeline.LoadPipeline(db, vars["key"], pipName, false)
+		if err != nil {
+			return nil, err
+		}
+		return &sdk.ArtifactRetention{ProjectID: pip.ProjectID, PipelineID: pip.ID}, nil
+	}
+
+	proj, err := project.Load(db, vars["permProjectKey"], c.User)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.ArtifactRetention{ProjectID: proj.ID}, nil
+}
+
+func getArtifactGCReportHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	projectKey := mux.Vars(r)["permProjectKey"]
+
+	proj, err := project.Load(db, projectKey, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactGCReportHandler> Cannot load project %s", projectKey)
+	}
+
+	report, err := artifact.GC(db, proj.ID, true, time.Now())
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactGCReportHandler> Cannot compute artifacts to delete in project %s", projectKey)
+	}
+	return WriteJSON(w, r, report, http.StatusOK)
+}
+
+func pinBuildArtifactsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	return pinBuildArtifacts(db, r, c, true)
+}
+
+func unpinBuildArtifactsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	return pinBuildArtifacts(db, r, c, false)
+}
+
+func pinBuildArtifacts(db gorp.SqlExecutor, r *http.Request, c *context.Ctx, pinned bool) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+
+	if err := r.ParseForm(); err != nil {
+		return sdk.WrapError(sdk.ErrUnknownError, "pinBuildArtifacts> Cannot parse form")
+	}
+	envName := r.Form.Get("envName")
+
+	buildNumber, err := requestVarInt(r, "build")
+	if err != nil {
+		return sdk.WrapError(err, "pinBuildArtifacts> invalid build number")
+	}
+
+	pip, err := pipeline.LoadPipeline(db, projectKey, vars["permPipelineKey"], false)
+	if err != nil {
+		return sdk.WrapError(err, "pinBuildArtifacts> Cannot load pipeline")
+	}
+
+	app, err := application.LoadByName(db, projectKey, vars["permApplicationName"], c.User)
+	if err != nil {
+		return sdk.WrapError(err, "pinBuildArtifacts> Cannot load application")
+	}
+
+	env := &sdk.DefaultEnv
+	if envName != "" && envName != sdk.DefaultEnv.Name {
+		env, err = environment.LoadEnvironmentByName(db, projectKey, envName)
+		if err != nil {
+			return sdk.WrapError(err, "pinBuildArtifacts> Cannot load environment %s", envName)
+		}
+	}
+
+	if !permission.AccessToEnvironment(env.ID, c.User, permission.PermissionReadExecute) {
+		return sdk.WrapError(sdk.ErrForbidden, "pinBuildArtifacts> You do not have access to this environment %s", env.Name)
+	}
+
+	if err := artifact.PinBuild(db, pip.ID, app.ID, env.ID, buildNumber, pinned); err != nil {
+		return sdk.WrapError(err, "pinBuildArtifacts> Cannot pin artifacts of build %d", buildNumber)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/artifact"
+	"github.com/ovh/cds/engine/api/context"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	artifactURLDefaultTTL = time.Hour
+	artifactURLMaxTTL     = 7 * 24 * time.Hour
+)
+
+func getArtifactURLHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	vars := mux.Vars(r)
+
+	id, err := requestVarInt(r, "id")
+	if err != nil {
+		return err
+	}
+
+	ttl := artifactURLDefaultTTL
+	if ttlString := r.FormValue("ttl"); ttlString != "" {
+		seconds, err := strconv.Atoi(ttlString)
+		if err != nil || seconds <= 0 {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getArtifactURLHandler> Invalid ttl %s", ttlString)
+		}
+		ttl = time.Duration(seconds) * time.Second
+		if ttl > artifactURLMaxTTL {
+			ttl = artifactURLMaxTTL
+		}
+	}
+
+	art, err := artifact.LoadArtifact(db, id)
+	if err != nil {
+		return sdk.WrapError(sdk.ErrNotFound, "getArtifactURLHandler> Cannot load artifact %d: %s", id, err)
+	}
+	if art.Project != vars["key"] || art.Application != vars["permApplicationName"] || art.Pipeline != vars["permPipelineKey"] {
+		return sdk.WrapError(sdk.ErrNotFound, "getArtifactURLHandler> Artifact %d does not belong to %s/%s/%s", id, vars["key"], vars["permApplicationName"], vars["permPipelineKey"])
+	}
+
+	expires := time.Now().Add(ttl)
+	signature, err := artifact.Sign(id, expires)
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactURLHandler> Cannot sign URL of artifact %d", id)
+	}
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", signature)
+	u := sdk.ArtifactURL{
+		URL:     fmt.Sprintf("%s/artifact/signed/%d?%s", viper.GetString(viperURLAPI), id, query.Encode()),
+		Expires: time.Unix(expires.Unix(), 0),
+	}
+	return WriteJSON(w, r, u, http.StatusOK)
+}
+
+func downloadSignedArtifactHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	id, err := requestVarInt(r, "id")
+	if err != nil {
+		return err
+	}
+
+	expires, err := strconv.ParseInt(r.FormValue("expires"), 10, 64)
+	if err != nil {
+		return sdk.WrapError(sdk.ErrInvalidArtifactSignature, "downloadSignedArtifactHandler> Invalid expiration date %s", r.FormValue("expires"))
+	}
+	if err := artifact.CheckSignature(id, expires, r.FormValue("signature"), time.Now()); err != nil {
+		return err
+	}
+
+	art, err := artifact.LoadArtifact(db, id)
+	if err != nil {
+		return sdk.WrapError(sdk.ErrNotFound, "downloadSignedArtifactHandler> Cannot load artifact %d: %s", id, err)
+	}
+
+	if redirected, err := redirectToArtifactURL(w, r, art); err != nil || redirected {
+		return err
+	}
+
+	log.Debug("downloadSignedArtifactHandler: Serving %+v\n", art)
+	if err := artifact.ServeFile(w, r, *art); err != nil {
+		log.Warning("downloadSignedArtifactHandler: Cannot stream artifact %s-%s-%s-%s-%s file: %s\n", art.Project, art.Application, art.Environment, art.Pipeline, art.Tag, err)
+		return err
+	}
+	return nil
+}
+
+func startArtifactUploadHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+
+	buildNumber, err := requestVarInt(r, "buildNumber")
+	if err != nil {
+		return err
+	}
+
+	var upload sdk.ArtifactChunkedUpload
+	if err := UnmarshalBody(r, &upload); err != nil {
+		return err
+	}
+
+	p, a, err := loadArtifactUploadPipeline(db, r, c)
+	if err != nil {
+		return err
+	}
+
+	env := &sdk.DefaultEnv
+	if upload.Environment != "" && upload.Environment != sdk.DefaultEnv.Name {
+		env, err = environment.LoadEnvironmentByName(db, projectKey, upload.Environment)
+		if err != nil {
+			return sdk.WrapError(err, "startArtifactUploadHandler> Cannot load environment %s", upload.Environment)
+		}
+	}
+
+	if !permission.AccessToEnvironment(env.ID, c.User, permission.PermissionReadExecute) {
+		return sdk.WrapError(sdk.ErrForbidden, "startArtifactUploadHandler> No enought right on this environment %s", upload.Environment)
+	}
+
+	if err := artifact.InsertUpload(db, p.ID, a.ID, env.ID, int(buildNumber), vars["tag"], &upload); err != nil {
+		return sdk.WrapError(err, "startArtifactUploadHandler> Cannot start upload of %s", upload.Name)
+	}
+	return WriteJSON(w, r, upload, http.StatusCreated)
+}
+
+func getArtifactUploadHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	p, a, err := loadArtifactUpload(db, r, c)
+	if err != nil {
+		return err
+	}
+
+	upload, err := artifact.LoadUpload(db, mux.Vars(r)["id"], p.ID, a.ID)
+	if err != nil {
+		return sdk.WrapError(err, "getArtifactUploadHandler> Cannot load upload")
+	}
+	return WriteJSON(w, r, upload, http.StatusOK)
+}
+
+func uploadArtifactChunkHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	offset, err := strconv.ParseInt(r.FormValue("offset"), 10, 64)
+	if err != nil {
+		return sdk.WrapError(sdk.ErrWrongRequest, "uploadArtifactChunkHandler> Invalid offset %s", r.FormValue("offset"))
+	}
+
+	p, a, err := loadArtifactUpload(db, r, c)
+	if err != nil {
+		return err
+	}
+
+	upload, err := artifact.StoreChunk(db, mux.Vars(r)["id"], p.ID, a.ID, offset, r.Body)
+	if err != nil {
+		return sdk.WrapError(err, "uploadArtifactChunkHandler> Cannot store chunk")
+	}
+	return WriteJSON(w, r, upload, http.StatusOK)
+}
+
+func completeArtifactUploadHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	p, a, err := loadArtifactUpload(db, r, c)
+	if err != nil {
+		return err
+	}
+
+	hash, err := generateHash()
+	if err != nil {
+		return sdk.WrapError(err, "completeArtifactUploadHandler> Could not generate hash")
+	}
+
+	art, err := artifact.CompleteUpload(db, p, a, mux.Vars(r)["id"], hash)
+	if err != nil {
+		return sdk.WrapError(err, "completeArtifactUploadHandler> Cannot complete upload")
+	}
+	return WriteJSON(w, r, art, http.StatusOK)
+}
+
+// loadArtifactUploadPipeline loads the pipeline and the application of an upload route
+func loadArtifactUploadPipeline(db gorp.SqlExecutor, r *http.Request, c *context.Ctx) (*sdk.Pipeline, *sdk.Application, error) {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+
+	p, err := pipeline.LoadPipeline(db, projectKey, vars["permPipelineKey"], false)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "loadArtifactUploadPipeline> Cannot load pipeline %s-%s", projectKey, vars["permPipelineKey"])
+	}
+
+	a, err := application.LoadByName(db, projectKey, vars["permApplicationName"], c.User)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "loadArtifactUploadPipeline> Cannot load application %s-%s", projectKey, vars["permApplicationName"])
+	}
+	return p, a, nil
+}
+
+// loadArtifactUpload loads the pipeline and the application of an upload and checks the access to the environment of the upload,
+// as when the upload has been started
+func loadArtifactUpload(db gorp.SqlExecutor, r *http.Request, c *context.Ctx) (*sdk.Pipeline, *sdk.Application, error) {
+	p, a, err := loadArtifactUploadPipeline(db, r, c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id := mux.Vars(r)["id"]
+	envID, err := artifact.LoadUploadEnvironmentID(db, id, p.ID, a.ID)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "loadArtifactUpload> Cannot load upload %s", id)
+	}
+	if !permission.AccessToEnvironment(envID, c.User, permission.PermissionReadExecute) {
+		return nil, nil, sdk.WrapError(sdk.ErrForbidden, "loadArtifactUpload> No enought right on the environment of upload %s", id)
+	}
+	return p, a, nil
+}
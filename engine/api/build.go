@@ -269,16 +269,57 @@ func addQueueResultHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 		// We want to update pipelineBuildJob status anyway
 	}
 
+	infos := []sdk.SpawnInfo{}
+
+	// Check declared outputs, a successful job must set all of them
+	if res.Status == sdk.StatusSuccess && len(pbJob.Job.Outputs) > 0 {
+		outputs, errO := sdk.ResolveJobOutputs(pbJob.Job.Outputs, res.Outputs)
+		if errO != nil {
+			log.Info("addQueueResultHandler> Job %d failed: %s", id, errO)
+			res.Status = sdk.StatusFail
+			res.FailureClass = sdk.FailureMissingOutputs
+			pbJob.Job.Reason = errO.Error()
+			infos = append(infos, sdk.SpawnInfo{
+				RemoteTime: res.RemoteTime,
+				Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobOutputError.ID, Args: []interface{}{errO.Error()}},
+			})
+		}
+		pbJob.Outputs = outputs
+	}
+
+	// A failed job may be retried by its retry policy, according to the failure class reported by the worker
+	if res.Status == sdk.StatusFail {
+		pbJob.Job.FailureClass = res.FailureClass
+		if pbJob.Job.FailureClass == "" {
+			pbJob.Job.FailureClass = sdk.FailureNonZeroExit
+		}
+	}
+
 	// Update action status
 	log.Debug("addQueueResultHandler> Updating %d to %s in queue", id, res.Status)
 	if err := pipeline.UpdatePipelineBuildJobStatus(tx, pbJob, res.Status); err != nil {
 		return sdk.WrapError(err, "addQueueResultHandler> Cannot update %d status", id)
 	}
 
-	infos := []sdk.SpawnInfo{{
+	if pbJob.Status == sdk.StatusSuccess.String() && len(pbJob.Outputs) > 0 {
+		pb, errPB := pipeline.LoadPipelineBuildByID(tx, pbJob.PipelineBuildID)
+		if errPB != nil {
+			return sdk.WrapError(errPB, "addQueueResultHandler> Cannot load pipeline build %d", pbJob.PipelineBuildID)
+		}
+		for _, s := range pb.Stages {
+			if s.ID != pbJob.Job.PipelineStageID {
+				continue
+			}
+			if err := pipeline.InsertJobOutputs(tx, pb.ID, s.Name, pbJob.Job.Action.Name, pbJob.Outputs); err != nil {
+				return sdk.WrapError(err, "addQueueResultHandler> Cannot add outputs of job %d", id)
+			}
+		}
+	}
+
+	infos = append(infos, sdk.SpawnInfo{
 		RemoteTime: res.RemoteTime,
 		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoWorkerEnd.ID, Args: []interface{}{c.Worker.Name, res.Duration}},
-	}}
+	})
 
 	if _, err := pipeline.AddSpawnInfosPipelineBuildJob(tx, pbJob.ID, infos); err != nil {
 		log.Error("addQueueResultHandler> Cannot save spawn info job %d: %s", pbJob.ID, err)
@@ -292,6 +333,26 @@ func addQueueResultHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 	return nil
 }
 
+// getPipelineBuildJobStatusHandler is called by workers while running a job, to know if it has been stopped
+func getPipelineBuildJobStatusHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	id, errc := requestVarInt(r, "id")
+	if errc != nil {
+		return sdk.WrapError(errc, "getPipelineBuildJobStatusHandler> invalid id")
+	}
+
+	if c.Worker == nil || c.Worker.ID == "" {
+		return sdk.WrapError(sdk.ErrForbidden, "getPipelineBuildJobStatusHandler> only workers can check a job status")
+	}
+
+	pbJob, errJob := pipeline.GetPipelineBuildJob(db, id)
+	if errJob != nil {
+		return sdk.WrapError(sdk.ErrNotFound, "getPipelineBuildJobStatusHandler> Cannot load pipeline build job %d: %s", id, errJob)
+	}
+
+	// Only the status is returned, the parameters of the job are not sent to any worker
+	return WriteJSON(w, r, sdk.PipelineBuildJob{ID: pbJob.ID, Status: pbJob.Status}, http.StatusOK)
+}
+
 func takePipelineBuildJobHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
 	id, errc := requestVarInt(r, "id")
 	if errc != nil {
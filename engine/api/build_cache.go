@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/buildcache"
+	"github.com/ovh/cds/engine/api/context"
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func uploadCacheHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+	key := r.FormValue("key")
+	if key == "" {
+		return sdk.ErrWrongRequest
+	}
+	defer r.Body.Close()
+
+	proj, err := project.Load(db, projectKey, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "uploadCacheHandler> Cannot load project %s", projectKey)
+	}
+
+	cache, err := buildcache.Store(db, proj, key, r.Body)
+	if err != nil {
+		return sdk.WrapError(err, "uploadCacheHandler> Cannot store cache %s", key)
+	}
+	return WriteJSON(w, r, cache, http.StatusOK)
+}
+
+func getCacheHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+	if err := r.ParseForm(); err != nil {
+		return sdk.ErrWrongRequest
+	}
+
+	proj, err := project.Load(db, projectKey, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "getCacheHandler> Cannot load project %s", projectKey)
+	}
+
+	cache, err := buildcache.Find(db, proj.ID, r.Form.Get("key"), r.Form["prefix"])
+	if err != nil {
+		return err
+	}
+	return WriteJSON(w, r, cache, http.StatusOK)
+}
+
+func downloadCacheHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	cache, err := loadCacheFromRequest(r, db, c, "key")
+	if err != nil {
+		return sdk.WrapError(err, "downloadCacheHandler> Cannot load cache")
+	}
+
+	f, err := objectstore.FetchCache(*cache)
+	if err != nil {
+		return sdk.WrapError(err, "downloadCacheHandler> Cannot fetch cache %d", cache.ID)
+	}
+
+	if err := buildcache.UpdateLastAccess(db, cache); err != nil {
+		log.Warning("downloadCacheHandler> %s", err)
+	}
+
+	w.Header().Add("Content-Type", "application/x-gzip")
+	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", cache.GetName()))
+	return objectstore.StreamFile(w, f)
+}
+
+func getCachesHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["permProjectKey"]
+
+	proj, err := project.Load(db, projectKey, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "getCachesHandler> Cannot load project %s", projectKey)
+	}
+
+	caches, err := buildcache.LoadAllByProject(db, proj.ID)
+	if err != nil {
+		return sdk.WrapError(err, "getCachesHandler> Cannot load caches of project %s", projectKey)
+	}
+	return WriteJSON(w, r, caches, http.StatusOK)
+}
+
+func deleteCacheHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	cache, err := loadCacheFromRequest(r, db, c, "permProjectKey")
+	if err != nil {
+		return sdk.WrapError(err, "deleteCacheHandler> Cannot load cache")
+	}
+
+	if err := buildcache.Remove(db, cache); err != nil {
+		return sdk.WrapError(err, "deleteCacheHandler> Cannot delete cache %d", cache.ID)
+	}
+	return nil
+}
+
+func loadCacheFromRequest(r *http.Request, db *gorp.DbMap, c *context.Ctx, projectVar string) (*sdk.Cache, error) {
+	vars := mux.Vars(r)
+	projectKey := vars[projectVar]
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		return nil, sdk.ErrWrongRequest
+	}
+
+	proj, err := project.Load(db, projectKey, c.User)
+	if err != nil {
+		return nil, err
+	}
+	return buildcache.LoadByID(db, proj.ID, id)
+}
@@ -222,3 +222,89 @@ func Test_addSpawnInfosPipelineBuildJobHandler(t *testing.T) {
 	assert.Equal(t, len(pbJobCheck.SpawnInfos), 1)
 	assert.Equal(t, pbJobCheck.SpawnInfos[0].Message.ID, sdk.MsgSpawnInfoHatcheryStarts.ID)
 }
+
+func Test_addQueueResultHandlerAfterStop(t *testing.T) {
+	db := test.SetupPG(t)
+
+	router = &Router{auth.TestLocalAuth(t), mux.NewRouter(), "/Test_addQueueResultHandlerAfterStop"}
+	router.init()
+
+	//Create a fancy httptester
+	tester := iffy.NewTester(t, router.mux)
+
+	//Insert Project
+	pkey := assets.RandomString(t, 10)
+	proj := assets.InsertTestProject(t, db, pkey, pkey)
+
+	//Insert Pipeline
+	pip := &sdk.Pipeline{
+		Name:       pkey + "_PIP",
+		Type:       sdk.BuildPipeline,
+		ProjectKey: proj.Key,
+		ProjectID:  proj.ID,
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, pip))
+
+	//Insert Application
+	app := &sdk.Application{
+		Name: "TEST_APP",
+	}
+	test.NoError(t, application.Insert(db, proj, app))
+	_, err := application.AttachPipeline(db, app.ID, pip.ID)
+	test.NoError(t, err)
+
+	pb, err := pipeline.InsertPipelineBuild(db, proj, pip, app, []sdk.Parameter{}, []sdk.Parameter{}, &sdk.DefaultEnv, 0, sdk.PipelineBuildTrigger{})
+	test.NoError(t, err)
+
+	pbJob := &sdk.PipelineBuildJob{
+		Status:          "Building",
+		PipelineBuildID: pb.ID,
+		Job: sdk.ExecutedJob{
+			Job:        sdk.Job{},
+			StepStatus: []sdk.StepStatus{},
+		},
+	}
+	test.NoError(t, pipeline.InsertPipelineBuildJob(db, pbJob))
+
+	w := &sdk.Worker{
+		ID:     assets.RandomString(t, 10),
+		Name:   assets.RandomString(t, 10),
+		Status: sdk.StatusBuilding,
+	}
+	test.NoError(t, worker.InsertWorker(db, w, 0))
+
+	test.NoError(t, pipeline.StopPipelineBuildJob(db, pbJob))
+
+	// The worker acknowledges the stop of its job
+	h := http.Header{}
+	h.Set(sdk.AuthHeader, base64.StdEncoding.EncodeToString([]byte(w.ID)))
+
+	vars := map[string]string{
+		"id": strconv.FormatInt(pbJob.ID, 10),
+	}
+	request := sdk.Result{
+		BuildID:  pbJob.ID,
+		Status:   sdk.StatusStopped,
+		Reason:   "Job has been stopped\n",
+		Duration: "1s",
+	}
+	route := router.getRoute("POST", addQueueResultHandler, vars)
+	tester.AddCall("Test_addQueueResultHandlerAfterStop", "POST", route, request).Headers(h).Checkers(iffy.ExpectStatus(200), iffy.DumpResponse(t))
+	tester.Run()
+
+	pbJobCheck, err := pipeline.GetPipelineBuildJob(db, pbJob.ID)
+	test.NoError(t, err)
+	assert.Equal(t, sdk.StatusStopped.String(), pbJobCheck.Status)
+
+	var workerEnd bool
+	for _, info := range pbJobCheck.SpawnInfos {
+		if info.Message.ID == sdk.MsgSpawnInfoWorkerEnd.ID {
+			workerEnd = true
+		}
+	}
+	assert.True(t, workerEnd, "the result of the stopped job must be recorded")
+
+	wCheck, err := worker.LoadWorker(db, w.ID)
+	test.NoError(t, err)
+	assert.Equal(t, sdk.StatusWaiting, wCheck.Status)
+}
@@ -0,0 +1,169 @@
+package buildcache
+
+import (
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+var (
+	// ttl is the duration after which a cache which has not been restored is deleted
+	ttl = 7 * 24 * time.Hour
+	// quota is the maximum size in bytes of all caches of a project, 0 means unlimited
+	quota int64
+)
+
+//Initialize sets the TTL of caches and the per project quota in bytes. A zero value keeps the default
+func Initialize(cacheTTL time.Duration, projectQuota int64) {
+	if cacheTTL > 0 {
+		ttl = cacheTTL
+	}
+	quota = projectQuota
+}
+
+// limitedReader counts the bytes read and fails once the limit is exceeded
+type limitedReader struct {
+	r        io.Reader
+	n        int64
+	max      int64
+	exceeded bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.max > 0 && l.n > l.max {
+		l.exceeded = true
+		return n, sdk.ErrCacheTooLarge
+	}
+	return n, err
+}
+
+//Store saves the tarball as the cache key of the project. Older caches with the same key are replaced
+//and the least recently used caches of the project are evicted to stay under the quota
+func Store(db gorp.SqlExecutor, proj *sdk.Project, key string, data io.Reader) (*sdk.Cache, error) {
+	now := time.Now()
+	c := &sdk.Cache{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Key:        key,
+		Created:    now,
+		LastAccess: now,
+	}
+	if err := Insert(db, c); err != nil {
+		return nil, err
+	}
+
+	r := &limitedReader{r: data, max: quota}
+	if _, err := objectstore.StoreCache(*c, ioutil.NopCloser(r)); err != nil {
+		remove(db, c)
+		if r.exceeded {
+			return nil, sdk.ErrCacheTooLarge
+		}
+		return nil, sdk.WrapError(err, "buildcache.Store> Cannot store cache %s", key)
+	}
+
+	c.Size = r.n
+	if err := Update(db, c); err != nil {
+		return nil, err
+	}
+
+	caches, err := LoadAllByProject(db, proj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for i := range caches {
+		if caches[i].ID != c.ID && caches[i].Key == key {
+			remove(db, &caches[i])
+			continue
+		}
+		total += caches[i].Size
+	}
+
+	if quota > 0 {
+		// caches are sorted from the least recently used
+		for i := 0; i < len(caches) && total > quota; i++ {
+			if caches[i].ID == c.ID || caches[i].Key == key {
+				continue
+			}
+			log.Info("buildcache.Store> Evicting cache %s of project %s to fit quota", caches[i].Key, proj.Key)
+			remove(db, &caches[i])
+			total -= caches[i].Size
+		}
+	}
+
+	return c, nil
+}
+
+//Find returns the cache key of the project. If there is no such cache, it returns the most
+//recent cache with a key starting with the first matching prefix
+func Find(db gorp.SqlExecutor, projectID int64, key string, prefixes []string) (*sdk.Cache, error) {
+	c, err := LoadByKey(db, projectID, key)
+	if err == nil {
+		return c, nil
+	}
+	if err != sdk.ErrCacheNotFound {
+		return nil, err
+	}
+
+	for _, p := range prefixes {
+		if p == "" {
+			continue
+		}
+		c, err := LoadByPrefix(db, projectID, p)
+		if err == nil {
+			return c, nil
+		}
+		if err != sdk.ErrCacheNotFound {
+			return nil, err
+		}
+	}
+	return nil, sdk.ErrCacheNotFound
+}
+
+//Remove deletes the cache and its tarball
+func Remove(db gorp.SqlExecutor, c *sdk.Cache) error {
+	if err := objectstore.DeleteCache(*c); err != nil {
+		log.Warning("buildcache.Remove> Cannot delete tarball of cache %d: %s", c.ID, err)
+	}
+	return Delete(db, c)
+}
+
+func remove(db gorp.SqlExecutor, c *sdk.Cache) {
+	if err := Remove(db, c); err != nil {
+		log.Warning("buildcache.remove> %s", err)
+	}
+}
+
+//Cleaner is the goroutine deleting caches which have not been restored during the TTL
+func Cleaner(DBFunc func() *gorp.DbMap) {
+	defer log.Error("buildcache.Cleaner> has been exited !")
+	for {
+		time.Sleep(30 * time.Minute)
+		if _, err := CleanerRun(DBFunc()); err != nil {
+			log.Warning("buildcache.Cleaner> Error : %s", err)
+		}
+	}
+}
+
+//CleanerRun is the core function of the cleaner goroutine
+func CleanerRun(db gorp.SqlExecutor) (int, error) {
+	caches, err := LoadUnusedSince(db, time.Now().Add(-ttl))
+	if err != nil {
+		return 0, err
+	}
+	for i := range caches {
+		if err := Remove(db, &caches[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(caches), nil
+}
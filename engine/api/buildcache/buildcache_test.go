@@ -0,0 +1,30 @@
+package buildcache
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestLimitedReader(t *testing.T) {
+	r := &limitedReader{r: strings.NewReader("0123456789"), max: 10}
+	b, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), r.n)
+	assert.Len(t, b, 10)
+	assert.False(t, r.exceeded)
+
+	r = &limitedReader{r: strings.NewReader("0123456789"), max: 5}
+	_, err = ioutil.ReadAll(r)
+	assert.Equal(t, sdk.ErrCacheTooLarge, err)
+	assert.True(t, r.exceeded)
+
+	r = &limitedReader{r: strings.NewReader("0123456789")}
+	_, err = ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.False(t, r.exceeded)
+}
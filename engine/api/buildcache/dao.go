@@ -0,0 +1,108 @@
+package buildcache
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const selectQuery = `SELECT cache.id, cache.project_id, project.projectkey, cache.cache_key, cache.size, cache.created, cache.last_access
+	FROM cache
+	JOIN project ON project.id = cache.project_id`
+
+// Insert a cache in database
+func Insert(db gorp.SqlExecutor, c *sdk.Cache) error {
+	dbc := Cache(*c)
+	if err := db.Insert(&dbc); err != nil {
+		return sdk.WrapError(err, "buildcache.Insert> Unable to insert cache %s", c.Key)
+	}
+	c.ID = dbc.ID
+	return nil
+}
+
+// Update a cache in database
+func Update(db gorp.SqlExecutor, c *sdk.Cache) error {
+	dbc := Cache(*c)
+	if _, err := db.Update(&dbc); err != nil {
+		return sdk.WrapError(err, "buildcache.Update> Unable to update cache %d", c.ID)
+	}
+	return nil
+}
+
+// Delete a cache from database
+func Delete(db gorp.SqlExecutor, c *sdk.Cache) error {
+	dbc := Cache(*c)
+	if _, err := db.Delete(&dbc); err != nil {
+		return sdk.WrapError(err, "buildcache.Delete> Unable to delete cache %d", c.ID)
+	}
+	return nil
+}
+
+// UpdateLastAccess sets the last access date of the cache to now
+func UpdateLastAccess(db gorp.SqlExecutor, c *sdk.Cache) error {
+	c.LastAccess = time.Now()
+	if _, err := db.Exec(`UPDATE cache SET last_access = $2 WHERE id = $1`, c.ID, c.LastAccess); err != nil {
+		return sdk.WrapError(err, "buildcache.UpdateLastAccess> Unable to update cache %d", c.ID)
+	}
+	return nil
+}
+
+// LoadByID loads a cache of the project
+func LoadByID(db gorp.SqlExecutor, projectID, id int64) (*sdk.Cache, error) {
+	return loadOne(db, selectQuery+` WHERE cache.project_id = $1 AND cache.id = $2`, projectID, id)
+}
+
+// LoadByKey loads the most recent cache of the project with the given key
+func LoadByKey(db gorp.SqlExecutor, projectID int64, key string) (*sdk.Cache, error) {
+	return loadOne(db, selectQuery+` WHERE cache.project_id = $1 AND cache.cache_key = $2 ORDER BY cache.created DESC LIMIT 1`, projectID, key)
+}
+
+// LoadByPrefix loads the most recent cache of the project with a key starting with the prefix
+func LoadByPrefix(db gorp.SqlExecutor, projectID int64, prefix string) (*sdk.Cache, error) {
+	return loadOne(db, selectQuery+` WHERE cache.project_id = $1 AND left(cache.cache_key, length($2)) = $2 ORDER BY cache.created DESC LIMIT 1`, projectID, prefix)
+}
+
+// LoadAllByProject loads all caches of the project, the least recently used first
+func LoadAllByProject(db gorp.SqlExecutor, projectID int64) ([]sdk.Cache, error) {
+	return loadAll(db, selectQuery+` WHERE cache.project_id = $1 ORDER BY cache.last_access ASC, cache.id ASC`, projectID)
+}
+
+// LoadUnusedSince loads all caches which have not been used since the given date
+func LoadUnusedSince(db gorp.SqlExecutor, t time.Time) ([]sdk.Cache, error) {
+	return loadAll(db, selectQuery+` WHERE cache.last_access < $1`, t)
+}
+
+func loadOne(db gorp.SqlExecutor, query string, args ...interface{}) (*sdk.Cache, error) {
+	caches, err := loadAll(db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(caches) == 0 {
+		return nil, sdk.ErrCacheNotFound
+	}
+	return &caches[0], nil
+}
+
+func loadAll(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.Cache, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return []sdk.Cache{}, nil
+		}
+		return nil, sdk.WrapError(err, "buildcache.loadAll> Unable to load caches")
+	}
+	defer rows.Close()
+
+	caches := []sdk.Cache{}
+	for rows.Next() {
+		var c sdk.Cache
+		if err := rows.Scan(&c.ID, &c.ProjectID, &c.ProjectKey, &c.Key, &c.Size, &c.Created, &c.LastAccess); err != nil {
+			return nil, sdk.WrapError(err, "buildcache.loadAll> Unable to scan cache")
+		}
+		caches = append(caches, c)
+	}
+	return caches, nil
+}
@@ -0,0 +1,13 @@
+package buildcache
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// Cache is a gorp wrapper around sdk.Cache
+type Cache sdk.Cache
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(Cache{}, "cache", true, "id"))
+}
@@ -18,6 +18,8 @@ type LocalStore struct {
 	Data   map[string][]byte
 	Queues map[string]*list.List
 	TTL    int
+
+	subscribers map[string][]chan<- []byte
 }
 
 //Get a key from local store
@@ -127,3 +129,29 @@ func (s *LocalStore) Dequeue(queueName string, value interface{}) {
 	json.Unmarshal(b, value)
 	return
 }
+
+//Publish sends the value to all local subscribers of the channel
+func (s *LocalStore) Publish(channel string, value interface{}) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		log.Warning("Cache> Cannot marshal message for %s: %s", channel, err)
+		return
+	}
+	s.Mutex.Lock()
+	subscribers := s.subscribers[channel]
+	s.Mutex.Unlock()
+	for _, c := range subscribers {
+		c <- b
+	}
+}
+
+//Subscribe registers the messages chan on the channel. This is blocking
+func (s *LocalStore) Subscribe(channel string, messages chan<- []byte) {
+	s.Mutex.Lock()
+	if s.subscribers == nil {
+		s.subscribers = map[string][]chan<- []byte{}
+	}
+	s.subscribers[channel] = append(s.subscribers[channel], messages)
+	s.Mutex.Unlock()
+	select {}
+}
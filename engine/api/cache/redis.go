@@ -155,3 +155,46 @@ func (s *RedisStore) Dequeue(queueName string, value interface{}) {
 		log.Warning("redis> Cannot unmarshal %s :%s", queueName, err)
 	}
 }
+
+//Publish sends the value to all subscribers of the channel
+func (s *RedisStore) Publish(channel string, value interface{}) {
+	if s.Client == nil {
+		log.Error("redis> cannot get redis client")
+		return
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		log.Warning("redis> Error publishing on %s:%s", channel, err)
+		return
+	}
+	if err := s.Client.Publish(channel, string(b)).Err(); err != nil {
+		log.Warning("redis> Error while PUBLISH to %s: %s", channel, err)
+	}
+}
+
+//Subscribe sends all messages published on the channel to the messages chan. This is blocking
+func (s *RedisStore) Subscribe(channel string, messages chan<- []byte) {
+	if s.Client == nil {
+		log.Error("redis> cannot get redis client")
+		return
+	}
+	for {
+		pubsub, err := s.Client.Subscribe(channel)
+		if err != nil {
+			log.Warning("redis> Error while SUBSCRIBE to %s: %s", channel, err)
+			time.Sleep(time.Second)
+			continue
+		}
+		//ReceiveMessage reconnects and subscribes again on network errors
+		for {
+			msg, err := pubsub.ReceiveMessage()
+			if err != nil {
+				log.Warning("redis> Error receiving message from %s: %s", channel, err)
+				break
+			}
+			messages <- []byte(msg.Payload)
+		}
+		pubsub.Close()
+		time.Sleep(time.Second)
+	}
+}
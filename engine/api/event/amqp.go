@@ -0,0 +1,154 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/streadway/amqp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// AMQPClient publishes events on an AMQP exchange
+type AMQPClient struct {
+	options AMQPConfig
+
+	mutex   sync.Mutex
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+// AMQPConfig handles all config to connect to an AMQP broker.
+// Events are published on a durable topic exchange, with the routing key or the event type (ie: sdk.EventPipelineBuild) as routing key
+type AMQPConfig struct {
+	Enabled    bool
+	URL        string
+	Exchange   string
+	RoutingKey string
+	Filter     string
+}
+
+// initialize returns broker, isInit and err if
+func (c *AMQPClient) initialize(options interface{}) (Broker, error) {
+	conf, ok := options.(AMQPConfig)
+	if !ok {
+		return nil, fmt.Errorf("Invalid AMQP Initialization")
+	}
+
+	if conf.URL == "" || conf.Exchange == "" {
+		return nil, fmt.Errorf("initAMQP> Invalid AMQP Configuration")
+	}
+	c.options = conf
+
+	if err := c.connect(); err != nil {
+		return nil, fmt.Errorf("initAMQP> Error with AMQP connection on exchange %s: %s", conf.Exchange, err)
+	}
+
+	log.Debug("initAMQP> AMQP used on exchange:%s", conf.Exchange)
+	return c, nil
+}
+
+// connect opens the connection and the channel, and declares the exchange.
+// The previous connection and channel are closed first, they are not reused once lost
+func (c *AMQPClient) connect() error {
+	c.disconnect()
+
+	conn, err := amqp.Dial(c.options.URL)
+	if err != nil {
+		return err
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	if err := channel.ExchangeDeclare(c.options.Exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c.conn = conn
+	c.channel = channel
+	return nil
+}
+
+// close closes connection
+func (c *AMQPClient) close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.disconnect()
+}
+
+// disconnect closes the channel and the connection, the caller must hold the mutex
+func (c *AMQPClient) disconnect() {
+	if c.channel != nil {
+		if err := c.channel.Close(); err != nil && err != amqp.ErrClosed {
+			log.Warning("closeAMQP> Error while closing AMQP channel:%s", err)
+		}
+		c.channel = nil
+	}
+	if c.conn != nil {
+		if err := c.conn.Close(); err != nil && err != amqp.ErrClosed {
+			log.Warning("closeAMQP> Error while closing AMQP connection:%s", err)
+		}
+		c.conn = nil
+	}
+}
+
+// sendEvent publishes the event, and reconnects once if the connection has been lost
+func (c *AMQPClient) sendEvent(event *sdk.Event) error {
+	data, errm := json.Marshal(event)
+	if errm != nil {
+		return errm
+	}
+
+	key := c.options.RoutingKey
+	if key == "" {
+		key = event.EventType
+	}
+
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    event.Timestamp,
+		Type:         event.EventType,
+		Body:         data,
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.channel.Publish(c.options.Exchange, key, false, false, msg); err != nil {
+		if err != amqp.ErrClosed {
+			return err
+		}
+		log.Warning("sendAMQP> Connection lost, reconnecting")
+		if err := c.connect(); err != nil {
+			return err
+		}
+		return c.channel.Publish(c.options.Exchange, key, false, false, msg)
+	}
+
+	log.Debug("Event %s published on exchange %s with key %s", event.EventType, c.options.Exchange, key)
+	return nil
+}
+
+// status returns the connection status
+func (c *AMQPClient) status() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.conn == nil {
+		return "AMQP KO"
+	}
+	return "AMQP OK"
+}
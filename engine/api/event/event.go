@@ -3,6 +3,7 @@ package event
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/docker/docker/pkg/namesgenerator"
 
@@ -12,8 +13,7 @@ import (
 )
 
 var hostname, cdsname string
-var kafkaBroker Broker
-var brokers []Broker
+var brokers []*filteredBroker
 
 // Broker event typed
 type Broker interface {
@@ -23,17 +23,71 @@ type Broker interface {
 	close()
 }
 
+// Config handles the configuration of all brokers. Each enabled broker receives events matching its filter
+type Config struct {
+	Kafka KafkaConfig
+	HTTP  HTTPConfig
+	AMQP  AMQPConfig
+	NATS  NATSConfig
+}
+
+// filteredBroker sends events matching the filter to a broker.
+// Each broker has its own queue so a slow broker doesn't delay the others
+type filteredBroker struct {
+	Broker
+	name   string
+	filter map[string]bool
+	queue  chan sdk.Event
+}
+
+//parseFilter returns the set of event types of a comma separated list, like "sdk.EventPipelineBuild,EventJob"
+func parseFilter(filter string) map[string]bool {
+	res := map[string]bool{}
+	for _, f := range strings.Split(filter, ",") {
+		f = strings.TrimPrefix(strings.TrimSpace(f), "sdk.")
+		if f != "" {
+			res[f] = true
+		}
+	}
+	return res
+}
+
+//match returns true if the event type is in the filter, or if there is no filter
+func (b *filteredBroker) match(e *sdk.Event) bool {
+	if len(b.filter) == 0 {
+		return true
+	}
+	return b.filter[strings.TrimPrefix(e.EventType, "sdk.")]
+}
+
+func (b *filteredBroker) run() {
+	for e := range b.queue {
+		if err := b.sendEvent(&e); err != nil {
+			log.Warning("Error while sending message to %s: %s", b.name, err)
+		}
+	}
+}
+
 func getBroker(t string, option interface{}) (Broker, error) {
 	switch t {
 	case "kafka":
 		k := &KafkaClient{}
 		return k.initialize(option)
+	case "http":
+		h := &HTTPClient{}
+		return h.initialize(option)
+	case "amqp":
+		a := &AMQPClient{}
+		return a.initialize(option)
+	case "nats":
+		n := &NATSClient{}
+		return n.initialize(option)
 	}
 	return nil, fmt.Errorf("Invalid Broker Type %s", t)
 }
 
 // Initialize initializes event system
-func Initialize(k KafkaConfig) error {
+func Initialize(c Config) error {
 	var err error
 	hostname, err = os.Hostname()
 	if err != nil {
@@ -41,26 +95,58 @@ func Initialize(k KafkaConfig) error {
 	}
 	cdsname = namesgenerator.GetRandomName(0)
 
-	brokers = []Broker{}
-	if k.Enabled {
-		var errk error
-		kafkaBroker, errk = getBroker("kafka", k)
-		if errk != nil {
-			return errk
+	brokers = []*filteredBroker{}
+	configs := []struct {
+		name    string
+		enabled bool
+		filter  string
+		options interface{}
+	}{
+		{"kafka", c.Kafka.Enabled, c.Kafka.Filter, c.Kafka},
+		{"http", c.HTTP.Enabled, c.HTTP.Filter, c.HTTP},
+		{"amqp", c.AMQP.Enabled, c.AMQP.Filter, c.AMQP},
+		{"nats", c.NATS.Enabled, c.NATS.Filter, c.NATS},
+	}
+
+	for _, conf := range configs {
+		if !conf.enabled {
+			continue
 		}
-		brokers = append(brokers, kafkaBroker)
+		b, errb := getBroker(conf.name, conf.options)
+		if errb != nil {
+			Close()
+			brokers = nil
+			return errb
+		}
+		brokers = append(brokers, &filteredBroker{
+			Broker: b,
+			name:   conf.name,
+			filter: parseFilter(conf.filter),
+			queue:  make(chan sdk.Event, 1000),
+		})
 	}
 	return nil
 }
 
 // DequeueEvent runs in a goroutine and dequeue event from cache
 func DequeueEvent() {
+	for _, b := range brokers {
+		go b.run()
+	}
+
 	for {
 		e := sdk.Event{}
 		cache.Dequeue("events", &e)
+		// fan out to the /events streams of all API instances
+		cache.Publish(streamChannel, e)
 		for _, b := range brokers {
-			if err := b.sendEvent(&e); err != nil {
-				log.Warning("Error while sending message: %s", err)
+			if !b.match(&e) {
+				continue
+			}
+			select {
+			case b.queue <- e:
+			default:
+				log.Warning("Event queue of %s is full, dropping event %s", b.name, e.EventType)
 			}
 		}
 	}
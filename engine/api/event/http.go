@@ -0,0 +1,132 @@
+package event
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// httpRetryDelay is the delay before the first retry, it is doubled for each retry
+var httpRetryDelay = time.Second
+
+// HTTPClient posts events on a webhook
+type HTTPClient struct {
+	options    HTTPConfig
+	httpClient *http.Client
+}
+
+// HTTPConfig handles all config to post events on a webhook.
+// If Secret is set, the HMAC-SHA256 signature of the body is sent in the X-Cds-Signature header, prefixed by "sha256="
+type HTTPConfig struct {
+	Enabled bool
+	URL     string
+	Secret  string
+	Retries int
+	Timeout time.Duration
+	Filter  string
+}
+
+// initialize returns broker, isInit and err if
+func (c *HTTPClient) initialize(options interface{}) (Broker, error) {
+	conf, ok := options.(HTTPConfig)
+	if !ok {
+		return nil, fmt.Errorf("Invalid HTTP Initialization")
+	}
+
+	if conf.URL == "" {
+		return nil, fmt.Errorf("initHTTP> Invalid HTTP Configuration")
+	}
+	if conf.Retries < 0 {
+		conf.Retries = 0
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = 10 * time.Second
+	}
+
+	c.options = conf
+	c.httpClient = &http.Client{Timeout: conf.Timeout}
+
+	log.Debug("initHTTP> Events posted on %s", conf.URL)
+	return c, nil
+}
+
+// close does nothing, there is no connection to close
+func (c *HTTPClient) close() {}
+
+// sign returns the signature header value of data
+func (c *HTTPClient) sign(data []byte) string {
+	mac := hmac.New(sha256.New, []byte(c.options.Secret))
+	mac.Write(data)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendEvent posts the event, and retries with an exponential backoff on network errors and 5xx responses
+func (c *HTTPClient) sendEvent(event *sdk.Event) error {
+	data, errm := json.Marshal(event)
+	if errm != nil {
+		return errm
+	}
+
+	var err error
+	delay := httpRetryDelay
+	for attempt := 0; attempt <= c.options.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		var retry bool
+		retry, err = c.post(data)
+		if err == nil {
+			log.Debug("Event %s posted on %s", event.EventType, c.options.URL)
+			return nil
+		}
+		if !retry {
+			break
+		}
+		log.Debug("sendEvent> attempt %d on %s failed: %s", attempt+1, c.options.URL, err)
+	}
+	return err
+}
+
+// post sends data and returns if the request can be retried on error
+func (c *HTTPClient) post(data []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, c.options.URL, bytes.NewReader(data))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.options.Secret != "" {
+		req.Header.Set(sdk.HookSignatureHeader, c.sign(data))
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+
+	if res.StatusCode >= 500 {
+		return true, fmt.Errorf("HTTP %d on %s", res.StatusCode, c.options.URL)
+	}
+	if res.StatusCode >= 400 {
+		return false, fmt.Errorf("HTTP %d on %s", res.StatusCode, c.options.URL)
+	}
+	return false, nil
+}
+
+// status: here, if c is initialized, HTTP is ok
+func (c *HTTPClient) status() string {
+	return "HTTP OK"
+}
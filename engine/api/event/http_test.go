@@ -0,0 +1,72 @@
+package event
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestFilteredBrokerMatch(t *testing.T) {
+	b := &filteredBroker{filter: parseFilter("sdk.EventPipelineBuild, EventJob")}
+	assert.True(t, b.match(&sdk.Event{EventType: "sdk.EventPipelineBuild"}))
+	assert.True(t, b.match(&sdk.Event{EventType: "sdk.EventJob"}))
+	assert.False(t, b.match(&sdk.Event{EventType: "sdk.EventEngine"}))
+
+	b = &filteredBroker{filter: parseFilter("")}
+	assert.True(t, b.match(&sdk.Event{EventType: "sdk.EventEngine"}))
+}
+
+func TestHTTPClientSendEvent(t *testing.T) {
+	httpRetryDelay = time.Millisecond
+
+	var calls int
+	var received sdk.Event
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		//First call fails, the event is sent again
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		c := &HTTPClient{options: HTTPConfig{Secret: "secret"}}
+		assert.Equal(t, c.sign(body), r.Header.Get(sdk.HookSignatureHeader))
+		assert.NoError(t, json.Unmarshal(body, &received))
+	}))
+	defer s.Close()
+
+	b, err := getBroker("http", HTTPConfig{Enabled: true, URL: s.URL, Secret: "secret", Retries: 2})
+	assert.NoError(t, err)
+
+	assert.NoError(t, b.sendEvent(&sdk.Event{EventType: "sdk.EventEngine", Payload: map[string]interface{}{"Message": "started"}}))
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "sdk.EventEngine", received.EventType)
+	assert.Equal(t, "started", received.Payload["Message"])
+}
+
+func TestHTTPClientSendEventNoRetry(t *testing.T) {
+	httpRetryDelay = time.Millisecond
+
+	var calls int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Empty(t, r.Header.Get(sdk.HookSignatureHeader))
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer s.Close()
+
+	b, err := getBroker("http", HTTPConfig{Enabled: true, URL: s.URL, Retries: 3})
+	assert.NoError(t, err)
+
+	//Client errors are not retried
+	assert.Error(t, b.sendEvent(&sdk.Event{EventType: "sdk.EventEngine"}))
+	assert.Equal(t, 1, calls)
+}
@@ -26,6 +26,7 @@ type KafkaConfig struct {
 	User            string
 	Password        string
 	Topic           string
+	Filter          string
 }
 
 // initialize returns broker, isInit and err if
@@ -83,6 +83,25 @@ func PublishPipelineBuild(db gorp.SqlExecutor, pb *sdk.PipelineBuild, previous *
 		EnvironmentName:       pb.Environment.Name,
 		BranchName:            pb.Trigger.VCSChangesBranch,
 		Hash:                  pb.Trigger.VCSChangesHash,
+		WaitingReason:         pb.WaitingReason,
+	}
+
+	Publish(e)
+}
+
+// PublishStageApproval sends a stage approval event
+func PublishStageApproval(pb *sdk.PipelineBuild, stage *sdk.Stage, d sdk.ApprovalDecision) {
+	e := sdk.EventStageApproval{
+		BuildNumber:     pb.BuildNumber,
+		StageName:       stage.Name,
+		Status:          stage.Status,
+		User:            d.User,
+		Approved:        d.Approved,
+		Comment:         d.Comment,
+		PipelineName:    pb.Pipeline.Name,
+		ProjectKey:      pb.Pipeline.ProjectKey,
+		ApplicationName: pb.Application.Name,
+		EnvironmentName: pb.Environment.Name,
 	}
 
 	Publish(e)
@@ -0,0 +1,61 @@
+package event
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// streamChannel is the cache channel used to fan out events to all API instances
+const streamChannel = "events_pubsub"
+
+var streams = struct {
+	sync.Mutex
+	subscribers map[chan sdk.Event]bool
+}{subscribers: map[chan sdk.Event]bool{}}
+
+// Subscribe returns a chan receiving all events published on any API instance.
+// The returned func must be called to unsubscribe. Events are dropped if the chan is full
+func Subscribe() (chan sdk.Event, func()) {
+	c := make(chan sdk.Event, 100)
+	streams.Lock()
+	streams.subscribers[c] = true
+	streams.Unlock()
+
+	return c, func() {
+		streams.Lock()
+		delete(streams.subscribers, c)
+		streams.Unlock()
+	}
+}
+
+// Stream runs in a goroutine, it listens events from the cache and dispatches them to all subscribers
+func Stream() {
+	messages := make(chan []byte, 100)
+	go cache.Subscribe(streamChannel, messages)
+
+	for m := range messages {
+		var e sdk.Event
+		if err := json.Unmarshal(m, &e); err != nil {
+			log.Warning("Stream> Cannot unmarshal event: %s", err)
+			continue
+		}
+		dispatch(e)
+	}
+}
+
+// dispatch sends the event to all subscribers without waiting for slow ones
+func dispatch(e sdk.Event) {
+	streams.Lock()
+	defer streams.Unlock()
+	for c := range streams.subscribers {
+		select {
+		case c <- e:
+		default:
+			log.Debug("Stream> subscriber is too slow, dropping event %s", e.EventType)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestDispatch(t *testing.T) {
+	c1, unsubscribe1 := Subscribe()
+	c2, unsubscribe2 := Subscribe()
+	defer unsubscribe2()
+
+	dispatch(sdk.Event{EventType: "sdk.EventEngine"})
+	assert.Equal(t, "sdk.EventEngine", (<-c1).EventType)
+	assert.Equal(t, "sdk.EventEngine", (<-c2).EventType)
+
+	//Unsubscribed chans don't receive events anymore
+	unsubscribe1()
+	dispatch(sdk.Event{EventType: "sdk.EventJob"})
+	assert.Len(t, c1, 0)
+	assert.Equal(t, "sdk.EventJob", (<-c2).EventType)
+
+	//Full chans don't block the others
+	for i := 0; i < cap(c2)+10; i++ {
+		dispatch(sdk.Event{EventType: "sdk.EventJob"})
+	}
+	assert.Len(t, c2, cap(c2))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/context"
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/sdk"
+)
+
+// eventsHeartbeat is the delay between two keep-alive comments on the /events stream
+var eventsHeartbeat = 30 * time.Second
+
+// eventStreamFilter filters the events sent on a /events stream
+type eventStreamFilter struct {
+	user   *sdk.User
+	filter sdk.EventFilter
+	types  map[string]bool
+}
+
+func newEventStreamFilter(r *http.Request, user *sdk.User) *eventStreamFilter {
+	f := &eventStreamFilter{
+		user: user,
+		filter: sdk.EventFilter{
+			ProjectKey:      r.FormValue("project"),
+			ApplicationName: r.FormValue("application"),
+			PipelineName:    r.FormValue("pipeline"),
+		},
+		types: map[string]bool{},
+	}
+	for _, t := range strings.Split(r.FormValue("type"), ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "sdk.")
+		if t != "" {
+			f.types[t] = true
+		}
+	}
+	return f
+}
+
+// match returns true if the user can read the event and if it matches the query filter.
+// Events which are not related to a project are only sent to CDS admins
+func (f *eventStreamFilter) match(e *sdk.Event) bool {
+	if len(f.types) > 0 && !f.types[strings.TrimPrefix(e.EventType, "sdk.")] {
+		return false
+	}
+
+	key := payloadString(e, "ProjectKey")
+	if !f.user.Admin && (key == "" || permission.ProjectPermission(key, f.user) < permission.PermissionRead) {
+		return false
+	}
+
+	if f.filter.ProjectKey != "" && f.filter.ProjectKey != key {
+		return false
+	}
+	if f.filter.ApplicationName != "" && f.filter.ApplicationName != payloadString(e, "ApplicationName") {
+		return false
+	}
+	if f.filter.PipelineName != "" && f.filter.PipelineName != payloadString(e, "PipelineName") {
+		return false
+	}
+	return true
+}
+
+func payloadString(e *sdk.Event, key string) string {
+	s, _ := e.Payload[key].(string)
+	return s
+}
+
+// getEventsHandler streams all events readable by the user as server-sent events, until the client goes away
+func getEventsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return sdk.WrapError(sdk.ErrUnknownError, "getEventsHandler> Streaming is not supported")
+	}
+
+	filter := newEventStreamFilter(r, c.User)
+
+	events, unsubscribe := event.Subscribe()
+	defer unsubscribe()
+
+	var closed <-chan bool
+	if cn, ok := w.(http.CloseNotifier); ok {
+		closed = cn.CloseNotify()
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return nil
+			}
+			flusher.Flush()
+		case e := <-events:
+			if !filter.match(&e) {
+				continue
+			}
+			b, err := json.Marshal(e)
+			if err != nil {
+				return sdk.WrapError(err, "getEventsHandler> Cannot marshal event %s", e.EventType)
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.EventType, b); err != nil {
+				return nil
+			}
+			flusher.Flush()
+		}
+	}
+}
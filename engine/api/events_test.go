@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_eventStreamFilter_match(t *testing.T) {
+	user := &sdk.User{
+		Groups: []sdk.Group{
+			{ProjectGroups: []sdk.ProjectGroup{{Project: sdk.Project{Key: "KEY"}, Permission: permission.PermissionRead}}},
+		},
+	}
+	admin := &sdk.User{Admin: true}
+
+	jobEvent := &sdk.Event{EventType: "sdk.EventJob", Payload: map[string]interface{}{"ProjectKey": "KEY", "ApplicationName": "app", "PipelineName": "build"}}
+	otherEvent := &sdk.Event{EventType: "sdk.EventJob", Payload: map[string]interface{}{"ProjectKey": "OTHER"}}
+	engineEvent := &sdk.Event{EventType: "sdk.EventEngine", Payload: map[string]interface{}{"Message": "started"}}
+
+	tests := []struct {
+		name  string
+		query string
+		user  *sdk.User
+		event *sdk.Event
+		want  bool
+	}{
+		{name: "Should match event of a readable project", user: user, event: jobEvent, want: true},
+		{name: "Should not match event of another project", user: user, event: otherEvent, want: false},
+		{name: "Should not match event without project for users", user: user, event: engineEvent, want: false},
+		{name: "Should match event without project for admins", user: admin, event: engineEvent, want: true},
+		{name: "Should match filtered pipeline", query: "?project=KEY&application=app&pipeline=build", user: user, event: jobEvent, want: true},
+		{name: "Should not match another pipeline", query: "?pipeline=deploy", user: user, event: jobEvent, want: false},
+		{name: "Should match filtered type", query: "?type=sdk.EventJob,EventPipelineBuild", user: user, event: jobEvent, want: true},
+		{name: "Should not match another type", query: "?type=EventPipelineBuild", user: user, event: jobEvent, want: false},
+	}
+	for _, tt := range tests {
+		f := newEventStreamFilter(httptest.NewRequest("GET", "/events"+tt.query, nil), tt.user)
+		if got := f.match(tt.event); got != tt.want {
+			t.Errorf("%q. eventStreamFilter.match() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
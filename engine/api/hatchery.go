@@ -1,7 +1,10 @@
 package main
 
 import (
+	"encoding/json"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 
 	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
@@ -50,5 +53,24 @@ func refreshHatcheryHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 		return err
 	}
 
+	// Hatcheries send the status of their models with their beat, older ones send nothing
+	data, errRead := ioutil.ReadAll(r.Body)
+	if errRead != nil || len(data) == 0 {
+		return nil
+	}
+
+	var status []sdk.ModelStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return sdk.WrapError(sdk.ErrWrongRequest, "refreshHatcheryHandler> cannot unmarshal model status: %s", err)
+	}
+
+	if c.Hatchery == nil || strconv.FormatInt(c.Hatchery.ID, 10) != hatcheryID {
+		return sdk.WrapError(sdk.ErrForbidden, "refreshHatcheryHandler> cannot update model status of hatchery %s", hatcheryID)
+	}
+
+	if err := hatchery.UpdateModelStatus(db, c.Hatchery.ID, status); err != nil {
+		return sdk.WrapError(err, "refreshHatcheryHandler> cannot update model status of hatchery %s", hatcheryID)
+	}
+
 	return nil
 }
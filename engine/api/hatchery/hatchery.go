@@ -27,8 +27,8 @@ func InsertHatchery(dbmap *gorp.DbMap, h *sdk.Hatchery) error {
 		return err
 	}
 
-	query := `INSERT INTO hatchery (name, group_id, last_beat, uid) VALUES ($1, $2, NOW(), $3) RETURNING id`
-	err = tx.QueryRow(query, h.Name, h.GroupID, h.UID).Scan(&h.ID)
+	query := `INSERT INTO hatchery (name, group_id, last_beat, uid, model_type) VALUES ($1, $2, NOW(), $3, $4) RETURNING id`
+	err = tx.QueryRow(query, h.Name, h.GroupID, h.UID, h.ModelType).Scan(&h.ID)
 	if err != nil {
 		return err
 	}
@@ -127,14 +127,14 @@ func LoadDeadHatcheries(db gorp.SqlExecutor, timeout float64) ([]sdk.Hatchery, e
 
 // LoadHatchery fetch hatchery info from database given UID
 func LoadHatchery(db gorp.SqlExecutor, uid string) (*sdk.Hatchery, error) {
-	query := `SELECT id, uid, name, last_beat, group_id, worker_model_id
+	query := `SELECT id, uid, name, last_beat, group_id, model_type, worker_model_id
 							FROM hatchery
 							LEFT JOIN hatchery_model ON hatchery_model.hatchery_id = hatchery.id
 							WHERE uid = $1`
 
 	var h sdk.Hatchery
 	var wmID sql.NullInt64
-	err := db.QueryRow(query, uid).Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &wmID)
+	err := db.QueryRow(query, uid).Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &h.ModelType, &wmID)
 	if err != nil {
 		return nil, err
 	}
@@ -148,14 +148,14 @@ func LoadHatchery(db gorp.SqlExecutor, uid string) (*sdk.Hatchery, error) {
 
 // LoadHatcheryByID fetch hatchery info from database given ID
 func LoadHatcheryByID(db gorp.SqlExecutor, id int64) (*sdk.Hatchery, error) {
-	query := `SELECT id, uid, name, last_beat, group_id, worker_model_id
+	query := `SELECT id, uid, name, last_beat, group_id, model_type, worker_model_id
 			FROM hatchery
 			LEFT JOIN hatchery_model ON hatchery_model.hatchery_id = hatchery.id
 			WHERE id = $1`
 
 	var h sdk.Hatchery
 	var wmID sql.NullInt64
-	err := db.QueryRow(query, id).Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &wmID)
+	err := db.QueryRow(query, id).Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &h.ModelType, &wmID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.ErrNoHatchery
@@ -174,7 +174,7 @@ func LoadHatcheryByID(db gorp.SqlExecutor, id int64) (*sdk.Hatchery, error) {
 func LoadHatcheries(db gorp.SqlExecutor) ([]sdk.Hatchery, error) {
 	var hatcheries []sdk.Hatchery
 
-	query := `SELECT id, uid, name, last_beat, group_id, worker_model_id
+	query := `SELECT id, uid, name, last_beat, group_id, model_type, worker_model_id
 							FROM hatchery
 							LEFT JOIN hatchery_model ON hatchery_model.hatchery_id = hatchery.id
 							LIMIT 10000`
@@ -187,7 +187,7 @@ func LoadHatcheries(db gorp.SqlExecutor) ([]sdk.Hatchery, error) {
 	var wmID sql.NullInt64
 	for rows.Next() {
 		var h sdk.Hatchery
-		err = rows.Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &wmID)
+		err = rows.Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &h.ModelType, &wmID)
 		if err != nil {
 			return nil, err
 		}
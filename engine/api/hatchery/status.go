@@ -0,0 +1,67 @@
+package hatchery
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// UpdateModelStatus replaces the workers started, wanted and building reported by a hatchery for each of its models
+func UpdateModelStatus(db gorp.SqlExecutor, hatcheryID int64, status []sdk.ModelStatus) error {
+	if _, err := db.Exec(`DELETE FROM hatchery_model_status WHERE hatchery_id = $1`, hatcheryID); err != nil {
+		return sdk.WrapError(err, "UpdateModelStatus> Unable to delete status of hatchery %d", hatcheryID)
+	}
+
+	query := `INSERT INTO hatchery_model_status (hatchery_id, worker_model_id, current_count, wanted_count, building_count, updated)
+		VALUES ($1, $2, $3, $4, $5, NOW())`
+	for _, s := range status {
+		if _, err := db.Exec(query, hatcheryID, s.ModelID, s.CurrentCount, s.WantedCount, s.BuildingCount); err != nil {
+			return sdk.WrapError(err, "UpdateModelStatus> Unable to insert status of model %d for hatchery %d", s.ModelID, hatcheryID)
+		}
+	}
+	return nil
+}
+
+// LoadModelStatus loads the workers started, wanted and building of each worker model, summed over all hatcheries
+func LoadModelStatus(db gorp.SqlExecutor) ([]sdk.ModelStatus, error) {
+	query := `SELECT worker_model.id, worker_model.name, worker_model.group_id,
+			SUM(hatchery_model_status.current_count), SUM(hatchery_model_status.wanted_count), SUM(hatchery_model_status.building_count)
+		FROM hatchery_model_status
+		JOIN worker_model ON worker_model.id = hatchery_model_status.worker_model_id
+		GROUP BY worker_model.id, worker_model.name, worker_model.group_id
+		ORDER BY worker_model.name`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadModelStatus> Unable to load status")
+	}
+	defer rows.Close()
+
+	status := []sdk.ModelStatus{}
+	for rows.Next() {
+		var s sdk.ModelStatus
+		if err := rows.Scan(&s.ModelID, &s.ModelName, &s.ModelGroupID, &s.CurrentCount, &s.WantedCount, &s.BuildingCount); err != nil {
+			return nil, sdk.WrapError(err, "LoadModelStatus> Unable to scan status")
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+// LoadModelIDsByHatchery loads the IDs of the models reported by a hatchery in its status
+func LoadModelIDsByHatchery(db gorp.SqlExecutor, hatcheryID int64) ([]int64, error) {
+	rows, err := db.Query(`SELECT worker_model_id FROM hatchery_model_status WHERE hatchery_id = $1`, hatcheryID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadModelIDsByHatchery> Unable to load status of hatchery %d", hatcheryID)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, sdk.WrapError(err, "LoadModelIDsByHatchery> Unable to scan status")
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
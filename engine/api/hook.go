@@ -16,6 +16,7 @@ import (
 	"github.com/ovh/cds/engine/api/hook"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/queue"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -46,6 +47,25 @@ func receiveHook(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *cont
 		UID:        r.FormValue("uid"),
 	}
 
+	if r.Header.Get("X-Gitlab-Event") != "" {
+		if err := rh.ParseGitlabPayload(); err != nil {
+			log.Warning("receiveHook> cannot parse gitlab payload: %s\n", err)
+			return sdk.ErrWrongRequest
+		}
+	}
+
+	//Gogs sends the same payloads as Gitea, with its own header
+	giteaEvent := r.Header.Get("X-Gitea-Event")
+	if giteaEvent == "" {
+		giteaEvent = r.Header.Get("X-Gogs-Event")
+	}
+	if giteaEvent != "" {
+		if err := rh.ParseGiteaPayload(giteaEvent); err != nil {
+			log.Warning("receiveHook> cannot parse gitea payload: %s\n", err)
+			return sdk.ErrWrongRequest
+		}
+	}
+
 	if db == nil {
 		hook.Recovery(rh, fmt.Errorf("database not available"))
 		return err
@@ -61,6 +81,189 @@ func receiveHook(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *cont
 	return nil
 }
 
+func receiveGenericHook(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	uid := mux.Vars(r)["uid"]
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return sdk.ErrWrongRequest
+	}
+
+	if db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	h, err := hook.LoadHookByUID(db, uid)
+	if err != nil {
+		return sdk.WrapError(err, "receiveGenericHook> Cannot load hook")
+	}
+	if h.Kind != sdk.HookKindGeneric {
+		return sdk.WrapError(sdk.ErrNoHook, "receiveGenericHook> Hook %d is not a generic hook", h.ID)
+	}
+
+	if err := hook.CheckSignature(h.Secret, r.Header.Get(sdk.HookSignatureHeader), data); err != nil {
+		return sdk.WrapError(err, "receiveGenericHook> Wrong signature for hook %d", h.ID)
+	}
+
+	if !h.Enabled {
+		log.Info("receiveGenericHook> Hook %d is disabled", h.ID)
+		return nil
+	}
+
+	if err := hook.InsertReceivedHook(db, r.URL.String(), h.UID, string(data)); err != nil {
+		return sdk.WrapError(err, "receiveGenericHook> Cannot insert received hook")
+	}
+
+	pb, err := processGenericHook(db, h, data, sdk.PipelineBuildTrigger{}, nil)
+	if err != nil {
+		return sdk.WrapError(err, "receiveGenericHook> Cannot process hook %d", h.ID)
+	}
+
+	return WriteJSON(w, r, pb, http.StatusOK)
+}
+
+//processGenericHook runs the pipeline of a generic hook with the parameters extracted from the payload
+func processGenericHook(db *gorp.DbMap, h sdk.Hook, data []byte, trigger sdk.PipelineBuildTrigger, u *sdk.User) (*sdk.PipelineBuild, error) {
+	params, err := hook.ExtractParameters(data, h.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	//git parameters are used as trigger informations
+	for _, p := range params {
+		switch p.Name {
+		case "git.branch":
+			trigger.VCSChangesBranch = p.Value
+		case "git.hash":
+			trigger.VCSChangesHash = p.Value
+		case "git.author":
+			trigger.VCSChangesAuthor = p.Value
+		}
+	}
+
+	app, err := application.LoadByID(db, h.ApplicationID, nil, application.LoadOptions.WithRepositoryManager)
+	if err != nil {
+		return nil, sdk.WrapError(err, "processGenericHook> Cannot load application %d", h.ApplicationID)
+	}
+
+	pip, err := pipeline.LoadPipelineByID(db, h.Pipeline.ID, false)
+	if err != nil {
+		return nil, sdk.WrapError(err, "processGenericHook> Cannot load pipeline %d", h.Pipeline.ID)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, sdk.WrapError(err, "processGenericHook> Cannot start tx")
+	}
+	defer tx.Rollback()
+
+	pb, err := queue.RunPipeline(tx, app.ProjectKey, app, pip.Name, h.Environment, params, 0, trigger, u)
+	if err != nil {
+		return nil, sdk.WrapError(err, "processGenericHook> Cannot run pipeline %s/%s/%s", app.ProjectKey, app.Name, pip.Name)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, sdk.WrapError(err, "processGenericHook> Cannot commit tx")
+	}
+
+	if trigger.VCSChangesHash != "" && app.RepositoriesManager != nil {
+		go func() {
+			proj, errp := project.Load(db, app.ProjectKey, nil)
+			if errp != nil {
+				log.Warning("processGenericHook> Unable to load project %s: %s", app.ProjectKey, errp)
+				return
+			}
+			if _, err := pipeline.UpdatePipelineBuildCommits(db, proj, pip, app, &pb.Environment, pb); err != nil {
+				log.Warning("processGenericHook> Unable to update pipeline build commits: %s", err)
+			}
+		}()
+	}
+
+	return pb, nil
+}
+
+//loadPipelineHook loads a hook checking it belongs to the application and the pipeline of the route
+func loadPipelineHook(db gorp.SqlExecutor, c *context.Ctx, vars map[string]string) (sdk.Hook, error) {
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		return sdk.Hook{}, sdk.ErrWrongRequest
+	}
+
+	h, err := hook.LoadHook(db, id)
+	if err != nil {
+		return h, sdk.ErrNoHook
+	}
+
+	app, err := application.LoadByName(db, vars["key"], vars["permApplicationName"], c.User)
+	if err != nil {
+		return h, err
+	}
+
+	pip, err := pipeline.LoadPipeline(db, vars["key"], vars["permPipelineKey"], false)
+	if err != nil {
+		return h, err
+	}
+
+	if h.ApplicationID != app.ID || h.Pipeline.ID != pip.ID {
+		return h, sdk.ErrNoHook
+	}
+
+	return h, nil
+}
+
+func getReceivedHooksHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	h, err := loadPipelineHook(db, c, mux.Vars(r))
+	if err != nil {
+		return sdk.WrapError(err, "getReceivedHooksHandler> Cannot load hook")
+	}
+
+	received, err := hook.LoadReceivedHooks(db, h.UID, 50)
+	if err != nil {
+		return sdk.WrapError(err, "getReceivedHooksHandler> Cannot load received hooks")
+	}
+
+	return WriteJSON(w, r, received, http.StatusOK)
+}
+
+func replayReceivedHookHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	vars := mux.Vars(r)
+	receivedID, err := strconv.ParseInt(vars["receivedID"], 10, 64)
+	if err != nil {
+		return sdk.ErrWrongRequest
+	}
+
+	h, err := loadPipelineHook(db, c, vars)
+	if err != nil {
+		return sdk.WrapError(err, "replayReceivedHookHandler> Cannot load hook")
+	}
+
+	//Only generic hooks payloads can be replayed, others depend on the headers of the original request
+	if h.Kind != sdk.HookKindGeneric {
+		return sdk.WrapError(sdk.ErrWrongRequest, "replayReceivedHookHandler> Hook %d is not a generic hook", h.ID)
+	}
+
+	h, err = hook.LoadHookByUID(db, h.UID)
+	if err != nil {
+		return sdk.WrapError(err, "replayReceivedHookHandler> Cannot load hook")
+	}
+
+	received, err := hook.LoadReceivedHook(db, h.UID, receivedID)
+	if err != nil {
+		return sdk.WrapError(err, "replayReceivedHookHandler> Cannot load received hook %d", receivedID)
+	}
+
+	trigger := sdk.PipelineBuildTrigger{
+		ManualTrigger: true,
+		TriggeredBy:   c.User,
+	}
+	pb, err := processGenericHook(db, h, []byte(received.Data), trigger, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "replayReceivedHookHandler> Cannot process hook %d", h.ID)
+	}
+
+	return WriteJSON(w, r, pb, http.StatusOK)
+}
+
 func addHook(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
 	vars := mux.Vars(r)
 	projectKey := vars["key"]
@@ -72,6 +275,10 @@ func addHook(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.
 	}
 	h.Enabled = true
 
+	if err := hook.ValidateMapping(h.Mapping); err != nil {
+		return err
+	}
+
 	// Insert hook in database
 	if err := hook.InsertHook(db, &h); err != nil {
 		log.Warning("addHook: cannot insert hook in db: %s\n", err)
@@ -102,6 +309,10 @@ func updateHookHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 		return sdk.WrapError(err, "updateHookHandler")
 	}
 
+	if err := hook.ValidateMapping(h.Mapping); err != nil {
+		return sdk.WrapError(err, "updateHookHandler")
+	}
+
 	app, errA := application.LoadByName(db, projectKey, appName, c.User, application.LoadOptions.WithHooks)
 	if errA != nil {
 		return sdk.WrapError(errA, "updateHookHandler> Cannot load application")
@@ -223,7 +434,7 @@ func processHook(db *gorp.DbMap, h hook.ReceivedHook) error {
 	}
 
 	// Logging stuff
-	if err := hook.InsertReceivedHook(db, h.URL.String(), string(h.Data)); err != nil {
+	if err := hook.InsertReceivedHook(db, h.URL.String(), h.UID, string(h.Data)); err != nil {
 		log.Warning("processHook> cannot insert received hook in db: %s\n", err)
 		return err
 	}
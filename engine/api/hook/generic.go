@@ -0,0 +1,190 @@
+package hook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// GenericHookLink format for generic hooks
+const GenericHookLink = "/hook/%s"
+
+//Link returns the URL third parties have to call to trigger the hook
+func Link(h sdk.Hook) string {
+	if h.Kind == sdk.HookKindGeneric {
+		return apiURL + fmt.Sprintf(GenericHookLink, h.UID)
+	}
+	return apiURL + fmt.Sprintf(HookLink, h.UID, h.Project, h.Repository)
+}
+
+//CheckSignature checks the signature of a generic hook payload.
+//The signature is the HMAC-SHA256 hex digest of the payload computed with the hook secret, prefixed by "sha256="
+func CheckSignature(secret, signature string, data []byte) error {
+	if secret == "" {
+		return nil
+	}
+
+	if !strings.HasPrefix(signature, "sha256=") {
+		return sdk.ErrInvalidHookSignature
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return sdk.ErrInvalidHookSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return sdk.ErrInvalidHookSignature
+	}
+	return nil
+}
+
+//ExtractParameters computes pipeline parameters from a json payload and the mapping of a generic hook.
+//Paths which are not found in the payload are ignored, so the pipeline default value is used
+func ExtractParameters(data []byte, mapping []sdk.HookMapping) ([]sdk.Parameter, error) {
+	params := []sdk.Parameter{}
+	if len(mapping) == 0 {
+		return params, nil
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, sdk.WrapError(sdk.ErrWrongRequest, "ExtractParameters> payload is not a valid json: %s", err)
+	}
+
+	for _, m := range mapping {
+		keys, err := parsePath(m.Path)
+		if err != nil {
+			return nil, sdk.WrapError(sdk.ErrWrongRequest, "ExtractParameters> %s", err)
+		}
+
+		v, found := lookup(payload, keys)
+		if !found {
+			log.Debug("ExtractParameters> %s not found in payload", m.Path)
+			continue
+		}
+
+		value, err := toString(v)
+		if err != nil {
+			return nil, err
+		}
+
+		params = append(params, sdk.Parameter{
+			Name:  m.Parameter,
+			Type:  sdk.StringParameter,
+			Value: value,
+		})
+	}
+
+	return params, nil
+}
+
+//ValidateMapping checks all parameters have a name and a valid path
+func ValidateMapping(mapping []sdk.HookMapping) error {
+	for _, m := range mapping {
+		if m.Parameter == "" {
+			return sdk.WrapError(sdk.ErrWrongRequest, "ValidateMapping> missing parameter name for path %s", m.Path)
+		}
+		if _, err := parsePath(m.Path); err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "ValidateMapping> %s", err)
+		}
+	}
+	return nil
+}
+
+//parsePath splits a JSONPath-style expression like $.a.b[0]['c.d'] in keys (string) and indexes (int)
+func parsePath(path string) ([]interface{}, error) {
+	p := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	keys := []interface{}{}
+
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+		case '[':
+			end := strings.Index(p, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %s: missing ]", path)
+			}
+			k := p[1:end]
+			p = p[end+1:]
+			if len(k) >= 2 && (k[0] == '\'' || k[0] == '"') && k[len(k)-1] == k[0] {
+				keys = append(keys, k[1:len(k)-1])
+				continue
+			}
+			i, err := strconv.Atoi(k)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %s: %s is not an index", path, k)
+			}
+			keys = append(keys, i)
+		default:
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			keys = append(keys, p[:end])
+			p = p[end:]
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("invalid path %s: empty", path)
+	}
+	return keys, nil
+}
+
+func lookup(v interface{}, keys []interface{}) (interface{}, bool) {
+	for _, k := range keys {
+		switch key := k.(type) {
+		case string:
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if v, ok = m[key]; !ok {
+				return nil, false
+			}
+		case int:
+			a, ok := v.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			if key < 0 {
+				key += len(a)
+			}
+			if key < 0 || key >= len(a) {
+				return nil, false
+			}
+			v = a[key]
+		}
+	}
+	return v, true
+}
+
+func toString(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return t, nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
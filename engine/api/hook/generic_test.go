@@ -0,0 +1,79 @@
+package hook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const testPayload = `{
+	"repository": {"name": "my-image", "tags": [{"name": "1.0.0"}, {"name": "latest"}]},
+	"event": {"size": 42, "pushed": true, "labels": {"env.name": "prod"}},
+	"nothing": null
+}`
+
+func TestExtractParameters(t *testing.T) {
+	mapping := []sdk.HookMapping{
+		{Parameter: "image", Path: "$.repository.name"},
+		{Parameter: "tag", Path: "$.repository.tags[0].name"},
+		{Parameter: "last", Path: "repository.tags[-1].name"},
+		{Parameter: "size", Path: "$.event.size"},
+		{Parameter: "pushed", Path: "$.event.pushed"},
+		{Parameter: "env", Path: "$.event.labels['env.name']"},
+		{Parameter: "labels", Path: "$.event.labels"},
+		{Parameter: "nothing", Path: "$.nothing"},
+		{Parameter: "unknown", Path: "$.repository.tags[4].name"},
+	}
+
+	params, err := ExtractParameters([]byte(testPayload), mapping)
+	assert.NoError(t, err)
+
+	values := map[string]string{}
+	for _, p := range params {
+		assert.Equal(t, sdk.StringParameter, p.Type)
+		values[p.Name] = p.Value
+	}
+
+	assert.Len(t, values, 8)
+	assert.Equal(t, "my-image", values["image"])
+	assert.Equal(t, "1.0.0", values["tag"])
+	assert.Equal(t, "latest", values["last"])
+	assert.Equal(t, "42", values["size"])
+	assert.Equal(t, "true", values["pushed"])
+	assert.Equal(t, "prod", values["env"])
+	assert.Equal(t, `{"env.name":"prod"}`, values["labels"])
+	assert.Equal(t, "", values["nothing"])
+
+	_, err = ExtractParameters([]byte("not json"), mapping)
+	assert.Error(t, err)
+
+	params, err = ExtractParameters([]byte("not json"), nil)
+	assert.NoError(t, err)
+	assert.Len(t, params, 0)
+}
+
+func TestValidateMapping(t *testing.T) {
+	assert.NoError(t, ValidateMapping([]sdk.HookMapping{{Parameter: "tag", Path: "$.tags[0]"}}))
+	assert.Error(t, ValidateMapping([]sdk.HookMapping{{Parameter: "", Path: "$.tags"}}))
+	assert.Error(t, ValidateMapping([]sdk.HookMapping{{Parameter: "tag", Path: "$.tags[0"}}))
+	assert.Error(t, ValidateMapping([]sdk.HookMapping{{Parameter: "tag", Path: "$.tags[a]"}}))
+	assert.Error(t, ValidateMapping([]sdk.HookMapping{{Parameter: "tag", Path: "$"}}))
+}
+
+func TestCheckSignature(t *testing.T) {
+	data := []byte(testPayload)
+	mac := hmac.New(sha256.New, []byte("my-secret"))
+	mac.Write(data)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.NoError(t, CheckSignature("my-secret", signature, data))
+	assert.NoError(t, CheckSignature("", "", data))
+	assert.Equal(t, sdk.ErrInvalidHookSignature, CheckSignature("my-secret", "", data))
+	assert.Equal(t, sdk.ErrInvalidHookSignature, CheckSignature("other-secret", signature, data))
+	assert.Equal(t, sdk.ErrInvalidHookSignature, CheckSignature("my-secret", "sha256=zz", data))
+}
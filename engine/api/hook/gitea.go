@@ -0,0 +1,63 @@
+package hook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const giteaNullSHA = "0000000000000000000000000000000000000000"
+
+type giteaUser struct {
+	Login    string `json:"login"`
+	Username string `json:"username"`
+}
+
+//giteaHook is the payload sent by gitea and gogs on push and delete events
+//https://docs.gitea.io/en-us/webhooks/
+type giteaHook struct {
+	Ref     string    `json:"ref"`
+	RefType string    `json:"ref_type"`
+	Before  string    `json:"before"`
+	After   string    `json:"after"`
+	Pusher  giteaUser `json:"pusher"`
+	Sender  giteaUser `json:"sender"`
+}
+
+//ParseGiteaPayload fills the received hook from a gitea (or gogs) event payload.
+//Like gitlab, gitea doesn't substitute variables in hook URL, so branch, hash and author are read in the body
+//and the message is set as stash does (ADD, UPDATE or DELETE)
+func (h *ReceivedHook) ParseGiteaPayload(event string) error {
+	var payload giteaHook
+	if err := json.Unmarshal(h.Data, &payload); err != nil {
+		return err
+	}
+
+	h.Branch = strings.TrimPrefix(payload.Ref, "refs/heads/")
+	h.Author = payload.Pusher.Login
+	if h.Author == "" {
+		h.Author = payload.Sender.Login
+	}
+
+	switch event {
+	case "push":
+		h.Hash = payload.After
+		switch {
+		case payload.Before == giteaNullSHA || payload.Before == "":
+			h.Message = "ADD"
+		case payload.After == giteaNullSHA:
+			h.Message = "DELETE"
+			h.Hash = payload.Before
+		default:
+			h.Message = "UPDATE"
+		}
+	case "delete":
+		if payload.RefType != "branch" {
+			return fmt.Errorf("unsupported gitea delete event on %s", payload.RefType)
+		}
+		h.Message = "DELETE"
+	default:
+		return fmt.Errorf("unsupported gitea event %s", event)
+	}
+	return nil
+}
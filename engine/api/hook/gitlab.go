@@ -0,0 +1,47 @@
+package hook
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const gitlabNullSHA = "0000000000000000000000000000000000000000"
+
+//gitlabPushHook is the payload sent by gitlab on push events
+//https://docs.gitlab.com/ce/user/project/integrations/webhooks.html#push-events
+type gitlabPushHook struct {
+	ObjectKind   string `json:"object_kind"`
+	Before       string `json:"before"`
+	After        string `json:"after"`
+	Ref          string `json:"ref"`
+	UserName     string `json:"user_name"`
+	UserUsername string `json:"user_username"`
+}
+
+//ParseGitlabPayload fills the received hook from a gitlab push event payload.
+//Gitlab doesn't substitute variables in hook URL, so branch, hash and author are read in the body
+//and the message is set as stash does (ADD, UPDATE or DELETE)
+func (h *ReceivedHook) ParseGitlabPayload() error {
+	var payload gitlabPushHook
+	if err := json.Unmarshal(h.Data, &payload); err != nil {
+		return err
+	}
+
+	h.Branch = strings.TrimPrefix(payload.Ref, "refs/heads/")
+	h.Hash = payload.After
+	h.Author = payload.UserUsername
+	if h.Author == "" {
+		h.Author = payload.UserName
+	}
+
+	switch {
+	case payload.Before == gitlabNullSHA:
+		h.Message = "ADD"
+	case payload.After == gitlabNullSHA:
+		h.Message = "DELETE"
+		h.Hash = payload.Before
+	default:
+		h.Message = "UPDATE"
+	}
+	return nil
+}
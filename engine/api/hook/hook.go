@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strings"
@@ -41,10 +42,10 @@ type ReceivedHook struct {
 const HookLink = "/hook?uid=%s&project=%s&name=%s&branch=${refChange.name}&hash=${refChange.toHash}&message=${refChange.type}&author=${user.name}"
 
 // InsertReceivedHook insert raw data received from public handler in database
-func InsertReceivedHook(db gorp.SqlExecutor, link string, data string) error {
-	query := `INSERT INTO received_hook (link, data) VALUES ($1, $2)`
+func InsertReceivedHook(db gorp.SqlExecutor, link string, uid string, data string) error {
+	query := `INSERT INTO received_hook (link, uid, data) VALUES ($1, $2, $3)`
 
-	_, err := db.Exec(query, link, data)
+	_, err := db.Exec(query, link, uid, data)
 	if err != nil {
 		return err
 	}
@@ -52,11 +53,77 @@ func InsertReceivedHook(db gorp.SqlExecutor, link string, data string) error {
 	return nil
 }
 
-// UpdateHook update the given hook
+// LoadReceivedHooks loads the last data received on a hook
+func LoadReceivedHooks(db gorp.SqlExecutor, uid string, limit int) ([]sdk.ReceivedHook, error) {
+	query := `SELECT id, uid, link, data, created FROM received_hook WHERE uid = $1 ORDER BY id DESC LIMIT $2`
+
+	rows, err := db.Query(query, uid, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	received := []sdk.ReceivedHook{}
+	for rows.Next() {
+		var r sdk.ReceivedHook
+		if err := rows.Scan(&r.ID, &r.UID, &r.Link, &r.Data, &r.Created); err != nil {
+			return nil, err
+		}
+		received = append(received, r)
+	}
+
+	return received, nil
+}
+
+// LoadReceivedHook loads a data received on a hook
+func LoadReceivedHook(db gorp.SqlExecutor, uid string, id int64) (sdk.ReceivedHook, error) {
+	r := sdk.ReceivedHook{}
+	query := `SELECT id, uid, link, data, created FROM received_hook WHERE uid = $1 AND id = $2`
+
+	if err := db.QueryRow(query, uid, id).Scan(&r.ID, &r.UID, &r.Link, &r.Data, &r.Created); err != nil {
+		if err == sql.ErrNoRows {
+			return r, sdk.ErrNoReceivedHook
+		}
+		return r, err
+	}
+
+	return r, nil
+}
+
+func mappingToDB(mapping []sdk.HookMapping) (interface{}, error) {
+	if len(mapping) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(mapping)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func mappingFromDB(b []byte) ([]sdk.HookMapping, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var mapping []sdk.HookMapping
+	if err := json.Unmarshal(b, &mapping); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// UpdateHook update the given hook. The secret is kept if it isn't provided, unless its removal is requested
 func UpdateHook(db gorp.SqlExecutor, h sdk.Hook) error {
-	query := `UPDATE hook set pipeline_id=$1, kind=$2, host=$3, project=$4, repository=$5, application_id=$6, enabled=$7 WHERE id=$8`
+	query := `UPDATE hook set pipeline_id=$1, kind=$2, host=$3, project=$4, repository=$5, application_id=$6, enabled=$7, environment=$8, params_mapping=$9,
+		secret = CASE WHEN $12 THEN '' WHEN $10 = '' THEN secret ELSE $10 END
+		WHERE id=$11`
 
-	res, err := db.Exec(query, h.Pipeline.ID, h.Kind, h.Host, h.Project, h.Repository, h.ApplicationID, h.Enabled, h.ID)
+	mapping, err := mappingToDB(h.Mapping)
+	if err != nil {
+		return err
+	}
+
+	res, err := db.Exec(query, h.Pipeline.ID, h.Kind, h.Host, h.Project, h.Repository, h.ApplicationID, h.Enabled, h.Environment, mapping, h.Secret, h.ID, h.RemoveSecret)
 	if err != nil {
 		return err
 	}
@@ -72,7 +139,7 @@ func UpdateHook(db gorp.SqlExecutor, h sdk.Hook) error {
 
 // InsertHook add link between git repository and pipeline in database
 func InsertHook(db gorp.SqlExecutor, h *sdk.Hook) error {
-	query := `INSERT INTO hook (pipeline_id, kind, host, project, repository, application_id,enabled, uid) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	query := `INSERT INTO hook (pipeline_id, kind, host, project, repository, application_id,enabled, uid, secret, environment, params_mapping) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
 
 	// Generate UID
 	uid, err := generateHash()
@@ -81,10 +148,16 @@ func InsertHook(db gorp.SqlExecutor, h *sdk.Hook) error {
 	}
 	h.UID = uid
 
-	err = db.QueryRow(query, h.Pipeline.ID, h.Kind, h.Host, h.Project, h.Repository, h.ApplicationID, h.Enabled, h.UID).Scan(&h.ID)
+	mapping, err := mappingToDB(h.Mapping)
+	if err != nil {
+		return err
+	}
+
+	err = db.QueryRow(query, h.Pipeline.ID, h.Kind, h.Host, h.Project, h.Repository, h.ApplicationID, h.Enabled, h.UID, h.Secret, h.Environment, mapping).Scan(&h.ID)
 	if err != nil {
 		return err
 	}
+	h.Link = Link(*h)
 
 	return nil
 }
@@ -92,12 +165,37 @@ func InsertHook(db gorp.SqlExecutor, h *sdk.Hook) error {
 // LoadHook loads a single hook
 func LoadHook(db gorp.SqlExecutor, id int64) (sdk.Hook, error) {
 	h := sdk.Hook{ID: id}
-	query := `SELECT application_id, pipeline_id, kind, host, project, repository, enabled FROM hook WHERE id = $1`
+	query := `SELECT application_id, pipeline_id, kind, host, project, repository, enabled, uid FROM hook WHERE id = $1`
+
+	err := db.QueryRow(query, id).Scan(&h.ApplicationID, &h.Pipeline.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.Enabled, &h.UID)
+	if err != nil {
+		return h, err
+	}
 
-	err := db.QueryRow(query, id).Scan(&h.ApplicationID, &h.Pipeline.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.Enabled)
+	return h, nil
+}
+
+// LoadHookByUID loads a single hook with its secret, environment and mapping
+func LoadHookByUID(db gorp.SqlExecutor, uid string) (sdk.Hook, error) {
+	h := sdk.Hook{UID: uid}
+	query := `SELECT id, application_id, pipeline_id, kind, host, project, repository, enabled, secret, environment, params_mapping FROM hook WHERE uid = $1`
+
+	var mapping []byte
+	var secret, env sql.NullString
+	err := db.QueryRow(query, uid).Scan(&h.ID, &h.ApplicationID, &h.Pipeline.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.Enabled, &secret, &env, &mapping)
+	if err == sql.ErrNoRows {
+		return h, sdk.ErrNoHook
+	}
 	if err != nil {
 		return h, err
 	}
+	h.Secret = secret.String
+	h.Environment = env.String
+
+	if h.Mapping, err = mappingFromDB(mapping); err != nil {
+		return h, err
+	}
+	h.Link = Link(h)
 
 	return h, nil
 }
@@ -143,7 +241,7 @@ func DeleteHook(db gorp.SqlExecutor, id int64) error {
 // LoadApplicationHooks will load all hooks related to given application
 func LoadApplicationHooks(db gorp.SqlExecutor, applicationID int64) ([]sdk.Hook, error) {
 	hooks := []sdk.Hook{}
-	query := `SELECT hook.id, hook.kind, hook.host, hook.project, hook.repository, hook.enabled, hook.uid, pipeline.id, pipeline.name
+	query := `SELECT hook.id, hook.kind, hook.host, hook.project, hook.repository, hook.enabled, hook.uid, hook.environment, hook.params_mapping, pipeline.id, pipeline.name
 		  FROM hook
 		  JOIN pipeline ON pipeline.id = hook.pipeline_id
 		  WHERE application_id= $1
@@ -157,13 +255,18 @@ func LoadApplicationHooks(db gorp.SqlExecutor, applicationID int64) ([]sdk.Hook,
 
 	for rows.Next() {
 		var h sdk.Hook
+		var mapping []byte
+		var env sql.NullString
 		h.ApplicationID = applicationID
-		err = rows.Scan(&h.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.Enabled, &h.UID, &h.Pipeline.ID, &h.Pipeline.Name)
+		err = rows.Scan(&h.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.Enabled, &h.UID, &env, &mapping, &h.Pipeline.ID, &h.Pipeline.Name)
 		if err != nil {
 			return hooks, err
 		}
-		link := apiURL + HookLink
-		h.Link = fmt.Sprintf(link, h.UID, h.Project, h.Repository)
+		h.Environment = env.String
+		if h.Mapping, err = mappingFromDB(mapping); err != nil {
+			return hooks, err
+		}
+		h.Link = Link(h)
 		hooks = append(hooks, h)
 	}
 
@@ -172,7 +275,7 @@ func LoadApplicationHooks(db gorp.SqlExecutor, applicationID int64) ([]sdk.Hook,
 
 // LoadPipelineHooks will load all hooks related to given pipeline
 func LoadPipelineHooks(db gorp.SqlExecutor, pipelineID int64, applicationID int64) ([]sdk.Hook, error) {
-	query := `SELECT id, kind, host, project, repository, uid, enabled FROM hook WHERE pipeline_id = $1 AND application_id= $2`
+	query := `SELECT id, kind, host, project, repository, uid, enabled, environment, params_mapping FROM hook WHERE pipeline_id = $1 AND application_id= $2`
 
 	rows, err := db.Query(query, pipelineID, applicationID)
 	if err != nil {
@@ -183,24 +286,29 @@ func LoadPipelineHooks(db gorp.SqlExecutor, pipelineID int64, applicationID int6
 	var hooks []sdk.Hook
 	for rows.Next() {
 		var h sdk.Hook
+		var mapping []byte
+		var env sql.NullString
 		h.Pipeline.ID = pipelineID
 		h.ApplicationID = applicationID
-		if err = rows.Scan(&h.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.UID, &h.Enabled); err != nil {
+		if err = rows.Scan(&h.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.UID, &h.Enabled, &env, &mapping); err != nil {
 			return nil, err
 		}
-		link := apiURL + HookLink
-		h.Link = fmt.Sprintf(link, h.UID, h.Project, h.Repository)
+		h.Environment = env.String
+		if h.Mapping, err = mappingFromDB(mapping); err != nil {
+			return nil, err
+		}
+		h.Link = Link(h)
 		hooks = append(hooks, h)
 	}
 
 	return hooks, nil
 }
 
-// LoadHooks related to given repository
+// LoadHooks related to given repository. Generic hooks are not loaded, they are only triggered through their own link
 func LoadHooks(db gorp.SqlExecutor, project string, repository string) ([]sdk.Hook, error) {
-	query := `SELECT id, pipeline_id, application_id, kind, host, enabled, uid FROM hook WHERE project = $1 AND repository = $2`
+	query := `SELECT id, pipeline_id, application_id, kind, host, enabled, uid FROM hook WHERE project = $1 AND repository = $2 AND kind <> $3`
 
-	rows, err := db.Query(query, project, repository)
+	rows, err := db.Query(query, project, repository, sdk.HookKindGeneric)
 	if err != nil {
 		return nil, err
 	}
@@ -283,9 +391,7 @@ func CreateHook(tx gorp.SqlExecutor, projectKey string, rm *sdk.RepositoriesMana
 		return nil, err
 	}
 
-	s := apiURL + HookLink
-	link := fmt.Sprintf(s, h.UID, t[0], t[1])
-
+	link := Link(h)
 	h.Link = link
 
 	err = client.CreateHook(repoFullName, link)
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/loopfz/gadgeto/iffy"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/auth"
+	"github.com/ovh/cds/engine/api/hook"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_updateHookHandlerRemoveSecret(t *testing.T) {
+	db := test.SetupPG(t)
+
+	router = &Router{auth.TestLocalAuth(t), mux.NewRouter(), "/Test_updateHookHandlerRemoveSecret"}
+	router.init()
+
+	//Create admin user
+	u, pass := assets.InsertAdminUser(t, db)
+
+	//Create a fancy httptester
+	tester := iffy.NewTester(t, router.mux)
+
+	//Insert Project
+	pkey := assets.RandomString(t, 10)
+	proj := assets.InsertTestProject(t, db, pkey, pkey)
+
+	//Insert Pipeline
+	pip := &sdk.Pipeline{
+		Name:       pkey + "_PIP",
+		Type:       sdk.BuildPipeline,
+		ProjectKey: proj.Key,
+		ProjectID:  proj.ID,
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, pip))
+
+	//Insert Application
+	app := &sdk.Application{
+		Name: "TEST_APP",
+	}
+	test.NoError(t, application.Insert(db, proj, app))
+	_, err := application.AttachPipeline(db, app.ID, pip.ID)
+	test.NoError(t, err)
+
+	h := &sdk.Hook{
+		Pipeline:      *pip,
+		ApplicationID: app.ID,
+		Kind:          sdk.HookKindGeneric,
+		Enabled:       true,
+		Secret:        "s3cret",
+	}
+	test.NoError(t, hook.InsertHook(db, h))
+
+	vars := map[string]string{
+		"key":                 proj.Key,
+		"permApplicationName": app.Name,
+		"permPipelineKey":     pip.Name,
+		"id":                  strconv.FormatInt(h.ID, 10),
+	}
+	route := router.getRoute("PUT", updateHookHandler, vars)
+	headers := assets.AuthHeaders(t, u, pass)
+
+	//An empty secret keeps the secret of the hook
+	request := *h
+	request.Secret = ""
+	tester.AddCall("Test_updateHookHandlerRemoveSecret", "PUT", route, request).Headers(headers).Checkers(iffy.ExpectStatus(200))
+	tester.Run()
+	tester.Reset()
+
+	hookCheck, err := hook.LoadHookByUID(db, h.UID)
+	test.NoError(t, err)
+	assert.Equal(t, "s3cret", hookCheck.Secret)
+
+	request.RemoveSecret = true
+	tester.AddCall("Test_updateHookHandlerRemoveSecret", "PUT", route, request).Headers(headers).Checkers(iffy.ExpectStatus(200))
+	tester.Run()
+	tester.Reset()
+
+	hookCheck, err = hook.LoadHookByUID(db, h.UID)
+	test.NoError(t, err)
+	assert.Equal(t, "", hookCheck.Secret)
+
+	//The payloads received by a disabled hook are not stored
+	request.RemoveSecret = false
+	request.Enabled = false
+	test.NoError(t, hook.UpdateHook(db, request))
+
+	route = router.getRoute("POST", receiveGenericHook, map[string]string{"uid": h.UID})
+	tester.AddCall("Test_updateHookHandlerRemoveSecret", "POST", route, map[string]string{"ref": "master"}).Checkers(iffy.ExpectStatus(http.StatusOK))
+	tester.Run()
+
+	received, err := hook.LoadReceivedHooks(db, h.UID, 10)
+	test.NoError(t, err)
+	assert.Empty(t, received)
+}
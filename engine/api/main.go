@@ -15,8 +15,11 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/approval"
+	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/auth"
 	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/buildcache"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/api/event"
@@ -102,6 +105,8 @@ var mainCmd = &cobra.Command{
 			objectstoreKind = objectstore.Openstack
 		case "filesystem", "local":
 			objectstoreKind = objectstore.Filesystem
+		case "s3":
+			objectstoreKind = objectstore.S3
 		default:
 			log.Fatalf("Unsupported objecstore mode : %s", viper.GetString(viperArtifactMode))
 		}
@@ -120,6 +125,19 @@ var mainCmd = &cobra.Command{
 				Filesystem: objectstore.ConfigOptionsFilesystem{
 					Basedir: viper.GetString(viperArtifactLocalBasedir),
 				},
+				S3: objectstore.ConfigOptionsS3{
+					Endpoint:        viper.GetString(viperArtifactS3Endpoint),
+					Region:          viper.GetString(viperArtifactS3Region),
+					Bucket:          viper.GetString(viperArtifactS3Bucket),
+					AccessKeyID:     viper.GetString(viperArtifactS3AccessKey),
+					SecretAccessKey: viper.GetString(viperArtifactS3SecretKey),
+					Prefix:          viper.GetString(viperArtifactS3Prefix),
+					PathStyle:       viper.GetBool(viperArtifactS3PathStyle),
+					SSE:             viper.GetString(viperArtifactS3SSE),
+					SSEKMSKeyID:     viper.GetString(viperArtifactS3SSEKMSKeyID),
+					PartSize:        int64(viper.GetInt(viperArtifactS3PartSize)) * 1024 * 1024,
+					PresignTTL:      time.Duration(viper.GetInt(viperArtifactS3PresignTTL)) * time.Minute,
+				},
 			},
 		}
 
@@ -127,6 +145,8 @@ var mainCmd = &cobra.Command{
 			log.Fatalf("Cannot initialize storage: %s", err)
 		}
 
+		buildcache.Initialize(time.Duration(viper.GetInt(viperArtifactCacheTTL))*time.Hour, int64(viper.GetInt(viperArtifactCacheQuota))*1024*1024)
+
 		//Intialize database
 		db, err := database.Init(
 			viper.GetString(viperDBUser),
@@ -176,8 +196,13 @@ var mainCmd = &cobra.Command{
 			APIBaseURL:             viper.GetString(viperURLAPI),
 			DisableGithubSetStatus: viper.GetBool(viperVCSRepoGithubStatusDisabled),
 			DisableGithubStatusURL: viper.GetBool(viperVCSRepoGithubStatusURLDisabled),
+			DisableGitlabSetStatus: viper.GetBool(viperVCSRepoGitlabStatusDisabled),
+			DisableGitlabStatusURL: viper.GetBool(viperVCSRepoGitlabStatusURLDisabled),
+			DisableGiteaSetStatus:  viper.GetBool(viperVCSRepoGiteaStatusDisabled),
+			DisableGiteaStatusURL:  viper.GetBool(viperVCSRepoGiteaStatusURLDisabled),
 			DisableStashSetStatus:  viper.GetBool(viperVCSRepoBitbucketStatusDisabled),
 			GithubSecret:           viper.GetString(viperVCSRepoGithubSecret),
+			GitlabSecret:           viper.GetString(viperVCSRepoGitlabSecret),
 			StashPrivateKey:        viper.GetString(viperVCSRepoBitbucketPrivateKey),
 		}
 		if err := repositoriesmanager.Initialize(rmInitOpts); err != nil {
@@ -226,18 +251,43 @@ var mainCmd = &cobra.Command{
 
 		cache.Initialize(viper.GetString(viperCacheMode), viper.GetString(viperCacheRedisHost), viper.GetString(viperCacheRedisPassword), viper.GetInt(viperCacheTTL))
 
-		kafkaOptions := event.KafkaConfig{
-			Enabled:         viper.GetBool(viperEventsKafkaEnabled),
-			BrokerAddresses: viper.GetString(viperEventsKafkaBroker),
-			User:            viper.GetString(viperEventsKafkaUser),
-			Password:        viper.GetString(viperEventsKafkaPassword),
-			Topic:           viper.GetString(viperEventsKafkaTopic),
+		eventOptions := event.Config{
+			Kafka: event.KafkaConfig{
+				Enabled:         viper.GetBool(viperEventsKafkaEnabled),
+				BrokerAddresses: viper.GetString(viperEventsKafkaBroker),
+				User:            viper.GetString(viperEventsKafkaUser),
+				Password:        viper.GetString(viperEventsKafkaPassword),
+				Topic:           viper.GetString(viperEventsKafkaTopic),
+				Filter:          viper.GetString(viperEventsKafkaFilter),
+			},
+			HTTP: event.HTTPConfig{
+				Enabled: viper.GetBool(viperEventsHTTPEnabled),
+				URL:     viper.GetString(viperEventsHTTPURL),
+				Secret:  viper.GetString(viperEventsHTTPSecret),
+				Retries: viper.GetInt(viperEventsHTTPRetries),
+				Timeout: time.Duration(viper.GetInt(viperEventsHTTPTimeout)) * time.Second,
+				Filter:  viper.GetString(viperEventsHTTPFilter),
+			},
+			AMQP: event.AMQPConfig{
+				Enabled:    viper.GetBool(viperEventsAMQPEnabled),
+				URL:        viper.GetString(viperEventsAMQPURL),
+				Exchange:   viper.GetString(viperEventsAMQPExchange),
+				RoutingKey: viper.GetString(viperEventsAMQPRoutingKey),
+				Filter:     viper.GetString(viperEventsAMQPFilter),
+			},
+			NATS: event.NATSConfig{
+				Enabled: viper.GetBool(viperEventsNATSEnabled),
+				URL:     viper.GetString(viperEventsNATSURL),
+				Subject: viper.GetString(viperEventsNATSSubject),
+				Filter:  viper.GetString(viperEventsNATSFilter),
+			},
 		}
-		if err := event.Initialize(kafkaOptions); err != nil {
+		if err := event.Initialize(eventOptions); err != nil {
 			log.Warning("⚠ Error while initializing event system: %s", err)
-		} else {
-			go event.DequeueEvent()
 		}
+		// events are dequeued even without broker, to feed the /events streams
+		go event.DequeueEvent()
+		go event.Stream()
 
 		if err := worker.Initialize(); err != nil {
 			log.Warning("⚠ Error while initializing workers routine: %s", err)
@@ -249,8 +299,12 @@ var mainCmd = &cobra.Command{
 
 		go queue.Pipelines()
 		go pipeline.AWOLPipelineKiller(database.GetDBMap)
+		go pipeline.JobTimeoutKiller(database.GetDBMap)
+		go approval.TimeoutKiller(database.GetDBMap)
 		go hatchery.Heartbeat(database.GetDBMap)
 		go auditCleanerRoutine(database.GetDBMap)
+		go buildcache.Cleaner(database.GetDBMap)
+		go artifact.Cleaner(database.GetDBMap)
 
 		go repositoriesmanager.ReceiveEvents()
 
@@ -62,16 +62,50 @@ const (
 	viperArtifactOSTenant               = "artifact.openstack.tenant"
 	viperArtifactOSRegion               = "artifact.openstack.region"
 	viperArtifactOSContainerPrefix      = "artifact.openstack.containerprefix"
+	viperArtifactS3Endpoint             = "artifact.s3.endpoint"
+	viperArtifactS3Region               = "artifact.s3.region"
+	viperArtifactS3Bucket               = "artifact.s3.bucket"
+	viperArtifactS3AccessKey            = "artifact.s3.accesskey"
+	viperArtifactS3SecretKey            = "artifact.s3.secretkey"
+	viperArtifactS3Prefix               = "artifact.s3.prefix"
+	viperArtifactS3PathStyle            = "artifact.s3.pathstyle"
+	viperArtifactS3SSE                  = "artifact.s3.sse"
+	viperArtifactS3SSEKMSKeyID          = "artifact.s3.ssekmskeyid"
+	viperArtifactS3PartSize             = "artifact.s3.partsize"
+	viperArtifactS3PresignTTL           = "artifact.s3.presignttl"
+	viperArtifactCacheTTL               = "artifact.cache.ttl"
+	viperArtifactCacheQuota             = "artifact.cache.quota"
 	viperEventsKafkaEnabled             = "events.kafka.enabled"
 	viperEventsKafkaBroker              = "events.kafka.broker"
 	viperEventsKafkaTopic               = "events.kafka.topic"
 	viperEventsKafkaUser                = "events.kafka.user"
 	viperEventsKafkaPassword            = "events.kafka.password"
+	viperEventsKafkaFilter              = "events.kafka.filter"
+	viperEventsHTTPEnabled              = "events.http.enabled"
+	viperEventsHTTPURL                  = "events.http.url"
+	viperEventsHTTPSecret               = "events.http.secret"
+	viperEventsHTTPRetries              = "events.http.retries"
+	viperEventsHTTPTimeout              = "events.http.timeout"
+	viperEventsHTTPFilter               = "events.http.filter"
+	viperEventsAMQPEnabled              = "events.amqp.enabled"
+	viperEventsAMQPURL                  = "events.amqp.url"
+	viperEventsAMQPExchange             = "events.amqp.exchange"
+	viperEventsAMQPRoutingKey           = "events.amqp.routingkey"
+	viperEventsAMQPFilter               = "events.amqp.filter"
+	viperEventsNATSEnabled              = "events.nats.enabled"
+	viperEventsNATSURL                  = "events.nats.url"
+	viperEventsNATSSubject              = "events.nats.subject"
+	viperEventsNATSFilter               = "events.nats.filter"
 	viperSchedulersDisabled             = "schedulers.disabled"
 	viperVCSPollingDisabled             = "vcs.polling.disabled"
 	viperVCSRepoGithubStatusDisabled    = "vcs.repositories.github.statuses_disabled"
 	viperVCSRepoGithubStatusURLDisabled = "vcs.repositories.github.statuses_url_disabled"
 	viperVCSRepoGithubSecret            = "vcs.repositories.github.clientsecret"
+	viperVCSRepoGitlabStatusDisabled    = "vcs.repositories.gitlab.statuses_disabled"
+	viperVCSRepoGitlabStatusURLDisabled = "vcs.repositories.gitlab.statuses_url_disabled"
+	viperVCSRepoGitlabSecret            = "vcs.repositories.gitlab.clientsecret"
+	viperVCSRepoGiteaStatusDisabled     = "vcs.repositories.gitea.statuses_disabled"
+	viperVCSRepoGiteaStatusURLDisabled  = "vcs.repositories.gitea.statuses_url_disabled"
 	viperVCSRepoBitbucketStatusDisabled = "vcs.repositories.bitbucket.statuses_disabled"
 	viperVCSRepoBitbucketPrivateKey     = "vcs.repositories.bitbucket.privatekey"
 )
@@ -183,16 +217,39 @@ const tmpl = `###################################
 # CDS_ARTIFACT_OPENSTACK_TENANT
 # CDS_ARTIFACT_OPENSTACK_REGION
 # CDS_ARTIFACT_OPENSTACK_CONTAINERPREFIX
+# CDS_ARTIFACT_CACHE_TTL
+# CDS_ARTIFACT_CACHE_QUOTA
 # CDS_EVENTS_KAFKA_ENABLED
 # CDS_EVENTS_KAFKA_BROKER
 # CDS_EVENTS_KAFKA_TOPIC
 # CDS_EVENTS_KAFKA_USER
 # CDS_EVENTS_KAFKA_PASSWORD
+# CDS_EVENTS_KAFKA_FILTER
+# CDS_EVENTS_HTTP_ENABLED
+# CDS_EVENTS_HTTP_URL
+# CDS_EVENTS_HTTP_SECRET
+# CDS_EVENTS_HTTP_RETRIES
+# CDS_EVENTS_HTTP_TIMEOUT
+# CDS_EVENTS_HTTP_FILTER
+# CDS_EVENTS_AMQP_ENABLED
+# CDS_EVENTS_AMQP_URL
+# CDS_EVENTS_AMQP_EXCHANGE
+# CDS_EVENTS_AMQP_ROUTINGKEY
+# CDS_EVENTS_AMQP_FILTER
+# CDS_EVENTS_NATS_ENABLED
+# CDS_EVENTS_NATS_URL
+# CDS_EVENTS_NATS_SUBJECT
+# CDS_EVENTS_NATS_FILTER
 # CDS_SCHEDULERS_DISABLED
 # CDS_VCS_POLLING_DISABLED
 # CDS_VCS_REPOSITORIES_GITHUB_STATUSES_DISABLED
 # CDS_VCS_REPOSITORIES_GITHUB_STATUSES_URL_DISABLED
 # CDS_VCS_REPOSITORIES_GITHUB_CLIENTSECRET
+# CDS_VCS_REPOSITORIES_GITLAB_STATUSES_DISABLED
+# CDS_VCS_REPOSITORIES_GITLAB_STATUSES_URL_DISABLED
+# CDS_VCS_REPOSITORIES_GITLAB_CLIENTSECRET
+# CDS_VCS_REPOSITORIES_GITEA_STATUSES_DISABLED
+# CDS_VCS_REPOSITORIES_GITEA_STATUSES_URL_DISABLED
 # CDS_VCS_REPOSITORIES_BITBUCKET_STATUSES_DISABLED
 # CDS_VCS_REPOSITORIES_BITBUCKET_PRIVATEKEY
 
@@ -309,9 +366,10 @@ from = "no-reply@cds.org"
 ##########################
 # CDS Artifacts Settings #
 ##########################
-# Either filesystem local storage or Openstack Swift Storage are supported
+# Either filesystem local storage, Openstack Swift Storage or S3 compatible storage are supported
 [artifact]
 # mode = "swift#
+# mode = "s3"
 mode = "local"
 
     [artifact.local]
@@ -325,10 +383,30 @@ mode = "local"
     region = "<OS_REGION_NAME>"
     containerprefix = "" # Use if your want to prefix containers
 
+    # AWS S3, MinIO, Ceph RGW...
+    [artifact.s3]
+    endpoint = "" # AWS endpoint of the region if empty, ie: "http://localhost:9000" for MinIO
+    region = "us-east-1"
+    bucket = "<S3_BUCKET>"
+    accesskey = "<S3_ACCESS_KEY_ID>"
+    secretkey = "<S3_SECRET_ACCESS_KEY>"
+    prefix = "" # Use if your want to prefix objects
+    pathstyle = false # Use bucket in path instead of host, needed by MinIO and Ceph RGW
+    sse = "" # Server side encryption: "AES256" or "aws:kms"
+    ssekmskeyid = "" # KMS key used with "aws:kms" server side encryption
+    partsize = 16 # Size in MB of the parts of multipart uploads, at least 5
+    presignttl = 15 # Artifacts are downloaded from presigned URLs valid ttl minutes, 0 to stream them through the API
+
+    # Build caches saved by the "Cache Save" action
+    [artifact.cache]
+    ttl = 168 # Caches which have not been restored for ttl hours are deleted
+    quota = 1024 # Maximum size in MB of all caches of a project, 0 for no limit
+
 #######################
 # CDS Events Settings #
 #######################
-#For now, only Kafka is supported as a event broker
+# Events can be sent to Kafka, to an HTTP webhook, to an AMQP exchange and to a NATS subject. Several brokers can be enabled at once.
+# For each broker, filter is a comma separated list of event types to send, ie: "sdk.EventPipelineBuild,sdk.EventJob". All events are sent if empty.
 [events]
     [events.kafka]
     enabled = false
@@ -336,6 +414,28 @@ mode = "local"
     topic = "<Kafka topic>"
     user = "<Kafka username>"
     password = "<Kafka password>"
+    filter = ""
+
+    [events.http]
+    enabled = false
+    url = "<Webhook URL>"
+    secret = "" # If set, the HMAC-SHA256 signature of the body is sent in X-Cds-Signature header
+    retries = 3
+    timeout = 10 # in seconds
+    filter = ""
+
+    [events.amqp]
+    enabled = false
+    url = "amqp://<user>:<password>@<host>:5672/<vhost>"
+    exchange = "cds"
+    routingkey = "" # The event type is used if empty
+    filter = ""
+
+    [events.nats]
+    enabled = false
+    url = "nats://<host>:4222"
+    subject = "cds.events"
+    filter = ""
 
 ###########################
 # CDS Schedulers Settings #
@@ -357,6 +457,15 @@ disabled = false #This is mainly for dev purpose, you should not have to change
     statuses_url_disabled = false # Set to true if you don't want CDS to push CDS URL in statuses on Github API
     clientsecret = "" # You can define here your github client secret if you don't use secret-backend-manager
 
+    [vcs.repositories.gitlab]
+    statuses_disabled = false # Set to true if you don't want CDS to push statuses on Gitlab API
+    statuses_url_disabled = false # Set to true if you don't want CDS to push CDS URL in statuses on Gitlab API
+    clientsecret = "" # You can define here your gitlab application secret if you don't use secret-backend-manager
+
+    [vcs.repositories.gitea]
+    statuses_disabled = false # Set to true if you don't want CDS to push statuses on Gitea API
+    statuses_url_disabled = false # Set to true if you don't want CDS to push CDS URL in statuses on Gitea API
+
     [vcs.repositories.bitbucket]
     statuses_disabled = false
     privatekey = "" # You can define here your bickcket private key if you don't use secret-backend-manager
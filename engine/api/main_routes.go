@@ -48,6 +48,7 @@ func (router *Router) init() {
 
 	// Hooks
 	router.Handle("/hook", Auth(false) /* Public handler called by third parties */, POST(receiveHook))
+	router.Handle("/hook/{uid}", Auth(false) /* Public handler called by third parties */, POST(receiveGenericHook))
 
 	// Overall health
 	router.Handle("/mon/status", Auth(false), GET(statusHandler))
@@ -62,6 +63,9 @@ func (router *Router) init() {
 	router.Handle("/mon/warning", GET(getUserWarnings))
 	router.Handle("/mon/lastupdates", GET(getUserLastUpdates))
 
+	// Events stream
+	router.Handle("/events", GET(getEventsHandler))
+
 	// Project
 	router.Handle("/project", GET(getProjectsHandler), POST(addProjectHandler))
 	router.Handle("/project/{permProjectKey}", GET(getProjectHandler), PUT(updateProjectHandler), DELETE(deleteProjectHandler))
@@ -108,7 +112,10 @@ func (router *Router) init() {
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}", GET(getBuildStateHandler), DELETE(deleteBuildHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/triggered", GET(getPipelineBuildTriggeredHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/stop", POSTEXECUTE(stopPipelineBuildHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/job/{jobID}/stop", POSTEXECUTE(stopPipelineBuildJobHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/restart", POSTEXECUTE(restartPipelineBuildHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/approval", GET(getPipelineBuildApprovalsHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/stage/{stageID}/approval", POSTEXECUTE(decideStageApprovalHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/commits", GET(getPipelineBuildCommitsHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/commits", GET(getPipelineCommitsHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/run", POSTEXECUTE(runPipelineHandler))
@@ -159,12 +166,36 @@ func (router *Router) init() {
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/{buildNumber}/artifact", GET(listArtifactsBuildHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/{buildNumber}/artifact/{tag}", POSTEXECUTE(uploadArtifactHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/artifact/download/{id}", GET(downloadArtifactHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/artifact/download/{id}/url", GET(getArtifactURLHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/{buildNumber}/artifact/{tag}/upload", POSTEXECUTE(startArtifactUploadHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/artifact/upload/{id}", GET(getArtifactUploadHandler), POSTEXECUTE(uploadArtifactChunkHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/artifact/upload/{id}/complete", POSTEXECUTE(completeArtifactUploadHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/artifact/promote/{channel}", POSTEXECUTE(promoteBuildArtifactsHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/promotion", GET(getArtifactPromotionsHandler))
+	// Must be declared before /artifact/{hash}
+	router.Handle("/artifact/reference", GET(getArtifactsByReferenceHandler))
 	router.Handle("/artifact/{hash}", Auth(false), GET(downloadArtifactDirectHandler))
+	router.Handle("/artifact/signed/{id}", Auth(false), GET(downloadSignedArtifactHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/build/{build}/artifact/pin", POSTEXECUTE(pinBuildArtifactsHandler), DELETE(unpinBuildArtifactsHandler))
+
+	// Artifact retention
+	router.Handle("/project/{permProjectKey}/artifact/retention", GET(getArtifactRetentionHandler), PUT(putArtifactRetentionHandler), DELETE(deleteArtifactRetentionHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/artifact/retention", GET(getArtifactRetentionHandler), PUT(putArtifactRetentionHandler), DELETE(deleteArtifactRetentionHandler))
+	router.Handle("/project/{key}/pipeline/{permPipelineKey}/artifact/retention", GET(getArtifactRetentionHandler), PUT(putArtifactRetentionHandler), DELETE(deleteArtifactRetentionHandler))
+	router.Handle("/project/{permProjectKey}/artifact/gc", GET(getArtifactGCReportHandler))
+
+	// Build caches
+	router.Handle("/project/{permProjectKey}/cache", GET(getCachesHandler))
+	router.Handle("/project/{permProjectKey}/cache/{id}", DELETE(deleteCacheHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/cache", GET(getCacheHandler), POSTEXECUTE(uploadCacheHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/cache/{id}/download", GET(downloadCacheHandler))
 
 	// Hooks
 	router.Handle("/project/{key}/application/{permApplicationName}/hook", GET(getApplicationHooksHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/hook", POST(addHook), GET(getHooks))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/hook/{id}", PUT(updateHookHandler), DELETE(deleteHook))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/hook/{id}/received", GET(getReceivedHooksHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/hook/{id}/received/{receivedID}/replay", POST(replayReceivedHookHandler))
 
 	// Pollers
 	router.Handle("/project/{key}/application/{permApplicationName}/polling", GET(getApplicationPollersHandler))
@@ -177,6 +208,8 @@ func (router *Router) init() {
 	router.Handle("/queue/{id}/book", NeedHatchery(), POST(bookPipelineBuildJobHandler))
 	router.Handle("/queue/{id}/spawn/infos", NeedHatchery(), POST(addSpawnInfosPipelineBuildJobHandler))
 	router.Handle("/queue/{id}/result", POST(addQueueResultHandler))
+	router.Handle("/queue/{id}/status", GET(getPipelineBuildJobStatusHandler))
+	router.Handle("/queue/{id}/explain", GET(getPipelineBuildJobExplainHandler))
 	router.Handle("/build/{id}/log", POST(addBuildLogHandler))
 	router.Handle("/build/{id}/step", POST(updateStepStatusHandler))
 
@@ -238,11 +271,21 @@ func (router *Router) init() {
 	router.Handle("/worker/waiting", POST(workerWaitingHandler))
 	router.Handle("/worker/unregister", POST(unregisterWorkerHandler))
 	router.Handle("/worker/{id}/disable", POST(disableWorkerHandler))
+	router.Handle("/worker/introspection", POST(postWorkerIntrospectionHandler))
 	router.Handle("/worker/model", POST(addWorkerModel), GET(getWorkerModels))
 	router.Handle("/worker/model/type", GET(getWorkerModelTypes))
+	// Must be declared before /worker/model/{permModelID}
+	router.Handle("/worker/model/status", GET(getWorkerModelStatusHandler))
 	router.Handle("/worker/model/{permModelID}", PUT(updateWorkerModel), DELETE(deleteWorkerModel))
 	router.Handle("/worker/model/{permModelID}/capability", POST(addWorkerModelCapa))
 	router.Handle("/worker/model/{permModelID}/instances", GET(getWorkerModelInstances))
+	router.Handle("/worker/model/{permModelID}/introspection", GET(getWorkerModelIntrospectionsHandler), POST(requestWorkerModelIntrospectionHandler))
+	router.Handle("/worker/model/{permModelID}/state", PUT(updateWorkerModelStateHandler))
+	router.Handle("/worker/model/{permModelID}/audit", GET(getWorkerModelAuditsHandler))
+	router.Handle("/worker/model/{permModelID}/version", GET(getWorkerModelVersionsHandler))
+	router.Handle("/worker/model/{modelID}/version/{version}", GET(getWorkerModelVersionHandler))
+	router.Handle("/worker/model/{permModelID}/version/{version}/current", PUT(setWorkerModelCurrentVersionHandler))
+	router.Handle("/worker/model/{permModelID}/scaling", GET(getWorkerModelScalingHandler), PUT(updateWorkerModelScalingHandler), DELETE(deleteWorkerModelScalingHandler))
 	router.Handle("/worker/model/capability/type", GET(getWorkerModelCapaTypes))
 	router.Handle("/worker/model/{permModelID}/capability/{capa}", PUT(updateWorkerModelCapa), DELETE(deleteWorkerModelCapa))
 
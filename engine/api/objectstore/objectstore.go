@@ -3,6 +3,7 @@ package objectstore
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/ovh/cds/sdk"
 )
@@ -42,6 +43,42 @@ func DeleteArtifact(art sdk.Artifact) error {
 	return fmt.Errorf("store not initialized")
 }
 
+//FetchArtifactURL returns a temporary URL to download the artifact directly from the storage backend,
+//or an empty string if the driver does not support it
+func FetchArtifactURL(art sdk.Artifact) (string, error) {
+	if storage == nil {
+		return "", fmt.Errorf("store not initialized")
+	}
+	if d, ok := storage.(DriverWithRedirect); ok {
+		return d.FetchURL(&art)
+	}
+	return "", nil
+}
+
+//StoreArtifactChunk stores a chunk of a resumable artifact upload with default objectstore driver
+func StoreArtifactChunk(c sdk.ArtifactChunk, data io.ReadCloser) (string, error) {
+	if storage != nil {
+		return storage.Store(&c, data)
+	}
+	return "", fmt.Errorf("store not initialized")
+}
+
+//FetchArtifactChunk fetches a chunk of a resumable artifact upload with default objectstore driver
+func FetchArtifactChunk(c sdk.ArtifactChunk) (io.ReadCloser, error) {
+	if storage != nil {
+		return storage.Fetch(&c)
+	}
+	return nil, fmt.Errorf("store not initialized")
+}
+
+//DeleteArtifactChunk deletes a chunk of a resumable artifact upload with default objectstore driver
+func DeleteArtifactChunk(c sdk.ArtifactChunk) error {
+	if storage != nil {
+		return storage.Delete(&c)
+	}
+	return fmt.Errorf("store not initialized")
+}
+
 //StorePlugin call Store on the common driver
 func StorePlugin(art sdk.ActionPlugin, data io.ReadCloser) (string, error) {
 	if storage != nil {
@@ -90,9 +127,34 @@ func DeleteTemplateExtension(tmpl sdk.TemplateExtension) error {
 	return fmt.Errorf("store not initialized")
 }
 
+//StoreCache call Store on the common driver
+func StoreCache(c sdk.Cache, data io.ReadCloser) (string, error) {
+	if storage != nil {
+		return storage.Store(&c, data)
+	}
+	return "", fmt.Errorf("store not initialized")
+}
+
+//FetchCache call Fetch on the common driver
+func FetchCache(c sdk.Cache) (io.ReadCloser, error) {
+	if storage != nil {
+		return storage.Fetch(&c)
+	}
+	return nil, fmt.Errorf("store not initialized")
+}
+
+//DeleteCache call Delete on the common driver
+func DeleteCache(c sdk.Cache) error {
+	if storage != nil {
+		return storage.Delete(&c)
+	}
+	return fmt.Errorf("store not initialized")
+}
+
 // Driver allows artifact to be stored and retrieve the same way to any backend
 // - Openstack / Swift
 // - Filesystem
+// - S3
 type Driver interface {
 	Status() string
 	Store(o Object, data io.ReadCloser) (string, error)
@@ -100,6 +162,13 @@ type Driver interface {
 	Delete(o Object) error
 }
 
+// DriverWithRedirect is a Driver able to give a temporary URL to download an object,
+// so that clients download it from the backend instead of through the API
+type DriverWithRedirect interface {
+	Driver
+	FetchURL(o Object) (string, error)
+}
+
 // Initialize setup wanted ObjectStore driver
 func Initialize(cfg Config) error {
 	var err error
@@ -118,6 +187,7 @@ const (
 	Openstack Kind = iota
 	Filesystem
 	Swift
+	S3
 )
 
 //TODO Use github.com/graymeta/stow
@@ -132,6 +202,7 @@ type Config struct {
 type ConfigOptions struct {
 	Openstack  ConfigOptionsOpenstack
 	Filesystem ConfigOptionsFilesystem
+	S3         ConfigOptionsS3
 }
 
 // ConfigOptionsOpenstack is used by ConfigOptions
@@ -144,6 +215,21 @@ type ConfigOptionsOpenstack struct {
 	ContainerPrefix string
 }
 
+// ConfigOptionsS3 is used by ConfigOptions
+type ConfigOptionsS3 struct {
+	Endpoint        string // AWS endpoint of the region if empty
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Prefix          string
+	PathStyle       bool          // bucket in path instead of host, for MinIO and Ceph RGW
+	SSE             string        // server side encryption: AES256 or aws:kms
+	SSEKMSKeyID     string        // KMS key used with aws:kms server side encryption
+	PartSize        int64         // size in bytes of the parts of multipart uploads
+	PresignTTL      time.Duration // validity of download URLs, 0 to stream artifacts through the API
+}
+
 // ConfigOptionsFilesystem is used by ConfigOptions
 type ConfigOptionsFilesystem struct {
 	Basedir string
@@ -161,6 +247,8 @@ func New(cfg Config) (Driver, error) {
 			cfg.Options.Openstack.ContainerPrefix)
 	case Filesystem:
 		return NewFilesystemStore(cfg.Options.Filesystem.Basedir)
+	case S3:
+		return NewS3Store(cfg.Options.S3)
 	default:
 		return nil, fmt.Errorf("Invalid flag --artifact-mode")
 	}
@@ -0,0 +1,190 @@
+package objectstore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// s3MinPartSize is the minimum size of a part of a multipart upload, except the last one
+	s3MinPartSize = 5 * 1024 * 1024
+	// s3DefaultPartSize is the size of the parts of a multipart upload if not configured
+	s3DefaultPartSize = 16 * 1024 * 1024
+)
+
+// S3Store implements ObjectStore interface with an S3 compatible implementation: AWS S3, MinIO, Ceph RGW...
+type S3Store struct {
+	endpoint    *url.URL
+	region      string
+	bucket      string
+	accessKey   string
+	secretKey   string
+	prefix      string
+	pathStyle   bool
+	sse         string
+	sseKMSKeyID string
+	partSize    int64
+	presignTTL  time.Duration
+}
+
+// NewS3Store create a new ObjectStore with S3 driver and check configuration
+func NewS3Store(cfg ConfigOptionsS3) (*S3Store, error) {
+	log.Info("Objectstore> Initialize S3 driver on endpoint: %s, region: %s, bucket: %s, prefix: %s", cfg.Endpoint, cfg.Region, cfg.Bucket, cfg.Prefix)
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("artifact storage is s3, but flag --artifact_s3_bucket is not provided")
+	}
+
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("artifact storage is s3, but flags --artifact_s3_accesskey and --artifact_s3_secretkey are not provided")
+	}
+
+	switch cfg.SSE {
+	case "", "AES256", "aws:kms":
+	default:
+		return nil, fmt.Errorf("artifact storage is s3, but server side encryption %s is not supported (AES256 or aws:kms)", cfg.SSE)
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("artifact storage is s3, but endpoint %s is invalid", endpoint)
+	}
+
+	partSize := cfg.PartSize
+	if partSize == 0 {
+		partSize = s3DefaultPartSize
+	}
+	if partSize < s3MinPartSize {
+		return nil, fmt.Errorf("artifact storage is s3, but part size must be at least 5MB")
+	}
+
+	s3s := &S3Store{
+		endpoint:    u,
+		region:      region,
+		bucket:      cfg.Bucket,
+		accessKey:   cfg.AccessKeyID,
+		secretKey:   cfg.SecretAccessKey,
+		prefix:      cfg.Prefix,
+		pathStyle:   cfg.PathStyle,
+		sse:         cfg.SSE,
+		sseKMSKeyID: cfg.SSEKMSKeyID,
+		partSize:    partSize,
+		presignTTL:  cfg.PresignTTL,
+	}
+	return s3s, nil
+}
+
+//Status return S3 storage status
+func (s3s *S3Store) Status() string {
+	if err := s3s.headBucket(); err != nil {
+		return "S3 KO (" + err.Error() + ")"
+	}
+	return "S3 OK"
+}
+
+// Store stores in S3, with a multipart upload if the object is larger than a part
+func (s3s *S3Store) Store(o Object, data io.ReadCloser) (string, error) {
+	defer data.Close()
+	key := s3s.key(o)
+	log.Debug("S3Store> Storing /%s/%s\n", s3s.bucket, key)
+
+	buf := make([]byte, s3s.partSize)
+	n, err := io.ReadFull(data, buf)
+	switch err {
+	case io.EOF, io.ErrUnexpectedEOF:
+		// The object fits in a single part
+		if err := s3s.putObject(key, buf[:n]); err != nil {
+			log.Warning("S3Store.Store> Cannot put object: %s\n", err)
+			return "", err
+		}
+		return s3s.bucket + "/" + key, nil
+	case nil:
+	default:
+		return "", err
+	}
+
+	uploadID, err := s3s.createMultipartUpload(key)
+	if err != nil {
+		log.Warning("S3Store.Store> Cannot create multipart upload: %s\n", err)
+		return "", err
+	}
+
+	var parts []s3CompletedPart
+	for partNumber := 1; n > 0; partNumber++ {
+		etag, err := s3s.uploadPart(key, uploadID, partNumber, buf[:n])
+		if err != nil {
+			log.Warning("S3Store.Store> Cannot upload part %d: %s\n", partNumber, err)
+			s3s.abortMultipartUpload(key, uploadID)
+			return "", err
+		}
+		parts = append(parts, s3CompletedPart{PartNumber: partNumber, ETag: etag})
+
+		n, err = io.ReadFull(data, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			s3s.abortMultipartUpload(key, uploadID)
+			return "", err
+		}
+	}
+
+	if err := s3s.completeMultipartUpload(key, uploadID, parts); err != nil {
+		log.Warning("S3Store.Store> Cannot complete multipart upload: %s\n", err)
+		s3s.abortMultipartUpload(key, uploadID)
+		return "", err
+	}
+	return s3s.bucket + "/" + key, nil
+}
+
+// Fetch lookup on S3 to fetch data
+func (s3s *S3Store) Fetch(o Object) (io.ReadCloser, error) {
+	key := s3s.key(o)
+	log.Debug("S3Store> Fetching /%s/%s\n", s3s.bucket, key)
+	return s3s.getObject(key)
+}
+
+// Delete should delete on S3
+func (s3s *S3Store) Delete(o Object) error {
+	return s3s.deleteObject(s3s.key(o))
+}
+
+// FetchURL returns a presigned URL to download the object directly from S3, an empty string if presigned URLs are disabled
+func (s3s *S3Store) FetchURL(o Object) (string, error) {
+	if s3s.presignTTL <= 0 {
+		return "", nil
+	}
+	query := url.Values{}
+	query.Set("response-content-disposition", fmt.Sprintf("attachment; filename=\"%s\"", o.GetName()))
+	return s3s.presign("GET", s3s.key(o), query, s3s.presignTTL, time.Now()), nil
+}
+
+func (s3s *S3Store) key(o Object) string {
+	path := strings.Trim(o.GetPath(), "/")
+	return s3s.prefix + path + "/" + o.GetName()
+}
+
+func (s3s *S3Store) abortMultipartUpload(key, uploadID string) {
+	if err := s3s.doAbortMultipartUpload(key, uploadID); err != nil {
+		log.Warning("S3Store> Cannot abort multipart upload %s: %s\n", uploadID, err)
+	}
+}
+
+// readAll reads a response body and closes it
+func readAll(r io.ReadCloser) []byte {
+	defer r.Close()
+	data, _ := ioutil.ReadAll(r)
+	return bytes.TrimSpace(data)
+}
@@ -0,0 +1,304 @@
+package objectstore
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//////////// S3 HANDLERS //////////
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3DateFormat      = "20060102T150405Z"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+type s3InitiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type s3CompleteMultipartUpload struct {
+	XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+	Parts   []s3CompletedPart `xml:"Part"`
+}
+
+/*<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>*/
+type s3Error struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+func unmarshalS3Error(data []byte, status string) error {
+	s3err := s3Error{}
+	if err := xml.Unmarshal(data, &s3err); err != nil || s3err.Code == "" {
+		return fmt.Errorf("%s", status)
+	}
+	return fmt.Errorf("%s: %s (%s)", status, s3err.Code, s3err.Message)
+}
+
+func (s3s *S3Store) headBucket() error {
+	resp, err := s3s.do("HEAD", "", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s3s *S3Store) putObject(key string, data []byte) error {
+	resp, err := s3s.do("PUT", key, nil, s3s.encryptionHeaders(), data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s3s *S3Store) getObject(key string) (io.ReadCloser, error) {
+	resp, err := s3s.do("GET", key, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s3s *S3Store) deleteObject(key string) error {
+	resp, err := s3s.do("DELETE", key, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s3s *S3Store) createMultipartUpload(key string) (string, error) {
+	resp, err := s3s.do("POST", key, url.Values{"uploads": {""}}, s3s.encryptionHeaders(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	var result s3InitiateMultipartUploadResult
+	if err := xml.Unmarshal(readAll(resp.Body), &result); err != nil {
+		return "", err
+	}
+	if result.UploadID == "" {
+		return "", fmt.Errorf("no upload id returned")
+	}
+	return result.UploadID, nil
+}
+
+func (s3s *S3Store) uploadPart(key, uploadID string, partNumber int, data []byte) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+	resp, err := s3s.do("PUT", key, query, nil, data)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return resp.Header.Get("ETag"), nil
+}
+
+func (s3s *S3Store) completeMultipartUpload(key, uploadID string, parts []s3CompletedPart) error {
+	body, err := xml.Marshal(s3CompleteMultipartUpload{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s3s.do("POST", key, url.Values{"uploadId": {uploadID}}, nil, body)
+	if err != nil {
+		return err
+	}
+
+	// The upload can still fail after a 200 OK, with an error in the body
+	rbody := readAll(resp.Body)
+	if bytes.Contains(rbody, []byte("<Error>")) {
+		return unmarshalS3Error(rbody, resp.Status)
+	}
+	return nil
+}
+
+func (s3s *S3Store) doAbortMultipartUpload(key, uploadID string) error {
+	resp, err := s3s.do("DELETE", key, url.Values{"uploadId": {uploadID}}, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s3s *S3Store) encryptionHeaders() map[string]string {
+	headers := map[string]string{}
+	if s3s.sse != "" {
+		headers["x-amz-server-side-encryption"] = s3s.sse
+	}
+	if s3s.sse == "aws:kms" && s3s.sseKMSKeyID != "" {
+		headers["x-amz-server-side-encryption-aws-kms-key-id"] = s3s.sseKMSKeyID
+	}
+	return headers
+}
+
+// do sends a request signed with AWS signature version 4, the response body must be closed if there is no error
+func (s3s *S3Store) do(method, key string, query url.Values, headers map[string]string, body []byte) (*http.Response, error) {
+	host, path := s3s.location(key)
+	now := time.Now().UTC()
+
+	payloadHash := sha256.Sum256(body)
+	signedHeaders := map[string]string{
+		"host":                 host,
+		"x-amz-date":           now.Format(s3DateFormat),
+		"x-amz-content-sha256": hex.EncodeToString(payloadHash[:]),
+	}
+	for k, v := range headers {
+		signedHeaders[strings.ToLower(k)] = v
+	}
+
+	canonicalQuery := s3CanonicalQuery(query)
+	names, signature := s3s.sign(method, path, canonicalQuery, signedHeaders, signedHeaders["x-amz-content-sha256"], now)
+
+	uri := fmt.Sprintf("%s://%s%s", s3s.endpoint.Scheme, host, path)
+	if canonicalQuery != "" {
+		uri += "?" + canonicalQuery
+	}
+	req, err := http.NewRequest(method, uri, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	for k, v := range signedHeaders {
+		if k != "host" {
+			req.Header.Set(k, v)
+		}
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s3s.accessKey, s3s.scope(now), names, signature))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s (%s)", uri, unmarshalS3Error(readAll(resp.Body), resp.Status))
+	}
+	return resp, nil
+}
+
+// presign returns an URL signed with AWS signature version 4 which is valid during ttl
+func (s3s *S3Store) presign(method, key string, query url.Values, ttl time.Duration, now time.Time) string {
+	host, path := s3s.location(key)
+	now = now.UTC()
+
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("X-Amz-Algorithm", s3Algorithm)
+	q.Set("X-Amz-Credential", s3s.accessKey+"/"+s3s.scope(now))
+	q.Set("X-Amz-Date", now.Format(s3DateFormat))
+	q.Set("X-Amz-Expires", strconv.FormatInt(int64(ttl/time.Second), 10))
+	q.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := s3CanonicalQuery(q)
+	_, signature := s3s.sign(method, path, canonicalQuery, map[string]string{"host": host}, s3UnsignedPayload, now)
+
+	return fmt.Sprintf("%s://%s%s?%s&X-Amz-Signature=%s", s3s.endpoint.Scheme, host, path, canonicalQuery, signature)
+}
+
+// location returns the host and the escaped path of an object, or of the bucket if key is empty
+func (s3s *S3Store) location(key string) (string, string) {
+	host := s3s.endpoint.Host
+	path := strings.TrimRight(s3s.endpoint.Path, "/")
+	if s3s.pathStyle {
+		path += "/" + s3s.bucket
+	} else {
+		host = s3s.bucket + "." + host
+	}
+	return host, s3URIEncode(path+"/"+key, false)
+}
+
+func (s3s *S3Store) scope(t time.Time) string {
+	return fmt.Sprintf("%s/%s/s3/aws4_request", t.Format("20060102"), s3s.region)
+}
+
+// sign returns the signed header names and the signature of a request
+func (s3s *S3Store) sign(method, path, canonicalQuery string, headers map[string]string, payloadHash string, t time.Time) (string, string) {
+	var names []string
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders bytes.Buffer
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{method, path, canonicalQuery, canonicalHeaders.String(), signedHeaders, payloadHash}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3Algorithm, t.Format(s3DateFormat), s3s.scope(t), hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := s3HMAC([]byte("AWS4"+s3s.secretKey), t.Format("20060102"))
+	key = s3HMAC(key, s3s.region)
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+	return signedHeaders, hex.EncodeToString(s3HMAC(key, stringToSign))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3CanonicalQuery returns the query string sorted and escaped as expected by the signature
+func s3CanonicalQuery(query url.Values) string {
+	var keys []string
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var params []string
+	for _, k := range keys {
+		values := append([]string{}, query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			params = append(params, s3URIEncode(k, true)+"="+s3URIEncode(v, true))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// s3URIEncode escapes all characters but the unreserved ones, and slashes if encodeSlash is false
+func s3URIEncode(s string, encodeSlash bool) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			buf.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			buf.WriteByte(c)
+		default:
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+	return buf.String()
+}
@@ -392,6 +392,18 @@ func updatePipelineActionHandler(w http.ResponseWriter, r *http.Request, db *gor
 		return err
 	}
 
+	if _, err := sdk.ExpandMatrix(job.Matrix); err != nil {
+		return err
+	}
+
+	if err := sdk.CheckJobOutputs(job.Outputs); err != nil {
+		return sdk.NewError(sdk.ErrInvalidJobOutput, err)
+	}
+
+	if err := sdk.CheckJobRetry(job.Retry); err != nil {
+		return sdk.NewError(sdk.ErrInvalidJobRetry, err)
+	}
+
 	if pipelineActionID != job.PipelineActionID {
 		log.Warning("updatePipelineActionHandler>Pipeline action does not match: %s\n", err)
 		return err
@@ -504,6 +516,12 @@ func updatePipelineHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 		return sdk.ErrInvalidPipelinePattern
 	}
 
+	if p.Concurrency != nil {
+		if err := p.Concurrency.IsValid(); err != nil {
+			return sdk.WrapError(err, "updatePipelineHandler> Invalid concurrency policy %s", p.Concurrency.Policy)
+		}
+	}
+
 	pipelineDB, err := pipeline.LoadPipeline(db, key, name, false)
 	if err != nil {
 		log.Warning("updatePipelineHandler> cannot load pipeline %s: %s\n", name, err)
@@ -512,6 +530,7 @@ func updatePipelineHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 
 	pipelineDB.Name = p.Name
 	pipelineDB.Type = p.Type
+	pipelineDB.Concurrency = p.Concurrency
 
 	err = pipeline.UpdatePipeline(db, pipelineDB)
 	if err != nil {
@@ -567,6 +586,12 @@ func addPipeline(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *cont
 		return sdk.ErrInvalidPipelinePattern
 	}
 
+	if p.Concurrency != nil {
+		if err := p.Concurrency.IsValid(); err != nil {
+			return sdk.WrapError(err, "addPipeline> Invalid concurrency policy %s", p.Concurrency.Policy)
+		}
+	}
+
 	// Check that pipeline does not already exists
 	exist, err := pipeline.ExistPipeline(db, project.ID, p.Name)
 	if err != nil {
@@ -800,6 +825,18 @@ func addJobToPipelineHandler(w http.ResponseWriter, r *http.Request, db *gorp.Db
 		return err
 	}
 
+	if _, err := sdk.ExpandMatrix(job.Matrix); err != nil {
+		return err
+	}
+
+	if err := sdk.CheckJobOutputs(job.Outputs); err != nil {
+		return sdk.NewError(sdk.ErrInvalidJobOutput, err)
+	}
+
+	if err := sdk.CheckJobRetry(job.Retry); err != nil {
+		return sdk.NewError(sdk.ErrInvalidJobRetry, err)
+	}
+
 	proj, errP := project.Load(db, projectKey, c.User, project.LoadOptions.Default)
 	if errP != nil {
 		log.Warning("addJoinedActionToPipelineHandler> Cannot load project %s: %s\n", projectKey, errP)
@@ -1076,46 +1113,47 @@ func getPipelineBuildingCommit(w http.ResponseWriter, r *http.Request, db *gorp.
 	return WriteJSON(w, r, pbs, http.StatusOK)
 }
 
-func stopPipelineBuildHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+// loadPipelineBuildFromRequest loads the pipeline build of the request, checking the user access to its environment
+func loadPipelineBuildFromRequest(db gorp.SqlExecutor, r *http.Request, c *context.Ctx, access int) (*sdk.PipelineBuild, error) {
 	vars := mux.Vars(r)
 	projectKey := vars["key"]
 	appName := vars["permApplicationName"]
 	pipName := vars["permPipelineKey"]
 
 	if err := r.ParseForm(); err != nil {
-		return sdk.WrapError(sdk.ErrUnknownError, "stopPipelineBuildHandler> Cannot parse form")
+		return nil, sdk.WrapError(sdk.ErrUnknownError, "loadPipelineBuildFromRequest> Cannot parse form")
 	}
 	envName := r.Form.Get("envName")
 
 	buildNumber, err := requestVarInt(r, "build")
 	if err != nil {
-		return sdk.WrapError(err, "stopPipelineBuildHandler> invalid build number")
+		return nil, sdk.WrapError(err, "loadPipelineBuildFromRequest> invalid build number")
 	}
 
 	pip, err := pipeline.LoadPipeline(db, projectKey, pipName, false)
 	if err != nil {
-		return sdk.WrapError(err, "stopPipelineBuildHandler> Cannot load pipeline")
+		return nil, sdk.WrapError(err, "loadPipelineBuildFromRequest> Cannot load pipeline")
 	}
 
 	app, err := application.LoadByName(db, projectKey, appName, c.User)
 	if err != nil {
-		return sdk.WrapError(err, "stopPipelineBuildHandler> Cannot load application")
+		return nil, sdk.WrapError(err, "loadPipelineBuildFromRequest> Cannot load application")
 	}
 
 	if pip.Type != sdk.BuildPipeline && (envName == "" || envName == sdk.DefaultEnv.Name) {
-		return sdk.ErrNoEnvironmentProvided
+		return nil, sdk.ErrNoEnvironmentProvided
 	}
 	env := &sdk.DefaultEnv
 
 	if pip.Type != sdk.BuildPipeline {
 		env, err = environment.LoadEnvironmentByName(db, projectKey, envName)
 		if err != nil {
-			return sdk.WrapError(err, "stopPipelineBuildHandler> Cannot load environment %s", envName)
+			return nil, sdk.WrapError(err, "loadPipelineBuildFromRequest> Cannot load environment %s", envName)
 		}
 	}
 
-	if !permission.AccessToEnvironment(env.ID, c.User, permission.PermissionReadExecute) {
-		return sdk.WrapError(sdk.ErrForbidden, "stopPipelineBuildHandler> You do not have Execution Right on this environment %s", env.Name)
+	if !permission.AccessToEnvironment(env.ID, c.User, access) {
+		return nil, sdk.WrapError(sdk.ErrForbidden, "loadPipelineBuildFromRequest> You do not have access to this environment %s", env.Name)
 	}
 
 	pb, err := pipeline.LoadPipelineBuildByApplicationPipelineEnvBuildNumber(db, app.ID, pip.ID, env.ID, buildNumber)
@@ -1124,27 +1162,50 @@ func stopPipelineBuildHandler(w http.ResponseWriter, r *http.Request, db *gorp.D
 		if err == sdk.ErrNoPipelineBuild {
 			errFinal = sdk.ErrBuildArchived
 		}
-		return sdk.WrapError(errFinal, "stopPipelineBuildHandler> Cannot load pipeline Build")
+		return nil, sdk.WrapError(errFinal, "loadPipelineBuildFromRequest> Cannot load pipeline Build")
 	}
+	return pb, nil
+}
 
-	// Disable building worker
-	for _, s := range pb.Stages {
-		if s.Status != sdk.StatusBuilding {
-			continue
-		}
-
-		for _, pipJob := range s.PipelineBuildJobs {
-			if err := pipeline.DisableBuildingWorker(db, pipJob.ID); err != nil {
-				return sdk.WrapError(err, "stopPipelineBuildHandler> Cannot stop worker for pipeline build [%d-%d]", pb.ID, pipJob.ID)
-			}
-		}
+func stopPipelineBuildHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	pb, err := loadPipelineBuildFromRequest(db, r, c, permission.PermissionReadExecute)
+	if err != nil {
+		return sdk.WrapError(err, "stopPipelineBuildHandler> Cannot load pipeline build")
 	}
 
+	// Building jobs are stopped, their workers kill the running steps and report the job result.
+	// The workers which do not report it within the grace period are disabled by pipeline.JobTimeoutKiller
 	if err := pipeline.StopPipelineBuild(db, pb); err != nil {
 		return sdk.WrapError(err, "stopPipelineBuildHandler> Cannot stop pipeline build")
 	}
 
-	k := cache.Key("application", projectKey, "builds", "*")
+	k := cache.Key("application", mux.Vars(r)["key"], "builds", "*")
+	cache.DeleteAll(k)
+
+	return nil
+}
+
+func stopPipelineBuildJobHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	jobID, err := requestVarInt(r, "jobID")
+	if err != nil {
+		return sdk.WrapError(err, "stopPipelineBuildJobHandler> invalid job id")
+	}
+
+	pb, err := loadPipelineBuildFromRequest(db, r, c, permission.PermissionReadExecute)
+	if err != nil {
+		return sdk.WrapError(err, "stopPipelineBuildJobHandler> Cannot load pipeline build")
+	}
+
+	pbJob, err := pipeline.GetPipelineBuildJob(db, jobID)
+	if err != nil || pbJob.PipelineBuildID != pb.ID {
+		return sdk.WrapError(sdk.ErrNotFound, "stopPipelineBuildJobHandler> Cannot load job %d of pipeline build %d", jobID, pb.ID)
+	}
+
+	if err := pipeline.StopPipelineBuildJob(db, pbJob); err != nil {
+		return sdk.WrapError(err, "stopPipelineBuildJobHandler> Cannot stop job %d", jobID)
+	}
+
+	k := cache.Key("application", mux.Vars(r)["key"], "builds", "*")
 	cache.DeleteAll(k)
 
 	return nil
@@ -56,6 +56,7 @@ func killAWOLPipelineBuildJob(db *gorp.DbMap, pbJobData awolPipelineBuildJob) er
 		return errJob
 	}
 	pbJob.Job.Reason = "Killed (Reason: Timeout)\n"
+	pbJob.Job.FailureClass = sdk.FailureWorkerLost
 
 	if err := UpdatePipelineBuildJobStatus(tx, pbJob, sdk.StatusFail); err != nil {
 		return err
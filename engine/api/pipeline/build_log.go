@@ -89,6 +89,13 @@ func LoadPipelineBuildJobLogs(db gorp.SqlExecutor, pipelineBuild *sdk.PipelineBu
 	}
 	buildLogResult.Status = sdk.StatusFromString(currentPbJob.Status)
 
+	// Get the previous attempts of a retried job
+	var errAttempts error
+	buildLogResult.Attempts, errAttempts = LoadPipelineBuildJobAttempts(db, currentPbJob.ID)
+	if errAttempts != nil {
+		return buildLogResult, errAttempts
+	}
+
 	return buildLogResult, nil
 }
 
@@ -31,9 +31,13 @@ func (p *PipelineBuildJob) PostInsert(s gorp.SqlExecutor) error {
 	if errS != nil {
 		return errS
 	}
+	outputs, errO := json.Marshal(p.Outputs)
+	if errO != nil {
+		return errO
+	}
 
-	query := "update pipeline_build_job set parameters = $1, job = $2, spawninfos = $4 where id = $3"
-	if _, err := s.Exec(query, params, job, p.ID, spawn); err != nil {
+	query := "update pipeline_build_job set parameters = $1, job = $2, spawninfos = $4, outputs = $5 where id = $3"
+	if _, err := s.Exec(query, params, job, p.ID, spawn, outputs); err != nil {
 		return err
 	}
 	return nil
@@ -56,8 +60,13 @@ func (p *PipelineBuildJob) PostUpdate(s gorp.SqlExecutor) error {
 		return errJ
 	}
 
-	query := "update pipeline_build_job set job = $2, parameters = $3, spawninfos= $4 where id = $1"
-	if _, err := s.Exec(query, p.ID, jobJSON, paramsJSON, spawnJSON); err != nil {
+	outputsJSON, errO := json.Marshal(p.Outputs)
+	if errO != nil {
+		return errO
+	}
+
+	query := "update pipeline_build_job set job = $2, parameters = $3, spawninfos= $4, outputs = $5 where id = $1"
+	if _, err := s.Exec(query, p.ID, jobJSON, paramsJSON, spawnJSON, outputsJSON); err != nil {
 		return err
 	}
 
@@ -71,9 +80,9 @@ func (p *PipelineBuildJob) PostGet(s gorp.SqlExecutor) error {
 		p.BookedBy = h
 	}
 
-	query := "SELECT job, parameters, spawninfos FROM pipeline_build_job WHERE id = $1"
-	var params, job, spawn []byte
-	if err := s.QueryRow(query, p.ID).Scan(&job, &params, &spawn); err != nil {
+	query := "SELECT job, parameters, spawninfos, outputs FROM pipeline_build_job WHERE id = $1"
+	var params, job, spawn, outputs []byte
+	if err := s.QueryRow(query, p.ID).Scan(&job, &params, &spawn, &outputs); err != nil {
 		return err
 	}
 
@@ -86,6 +95,11 @@ func (p *PipelineBuildJob) PostGet(s gorp.SqlExecutor) error {
 	if err := json.Unmarshal(spawn, &p.SpawnInfos); err != nil {
 		return err
 	}
+	if len(outputs) > 0 {
+		if err := json.Unmarshal(outputs, &p.Outputs); err != nil {
+			return err
+		}
+	}
 
 	p.QueuedSeconds = time.Now().Unix() - p.Queued.Unix()
 
@@ -0,0 +1,57 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var jobOutputReferenceRegexp = regexp.MustCompile(`{{\.cds\.stage\.(.+?)\.job\.(.+?)\.output\.([a-zA-Z0-9_-]+)}}`)
+
+// CheckJobOutputReferences checks that the {{.cds.stage.<stage>.job.<job>.output.<name>}} placeholders in the values
+// of the parameters refer to outputs declared by the jobs of the pipeline
+func CheckJobOutputReferences(db gorp.SqlExecutor, pipelineID int64, params []sdk.Parameter) error {
+	var p *sdk.Pipeline
+	for _, param := range params {
+		for _, ref := range jobOutputReferenceRegexp.FindAllStringSubmatch(param.Value, -1) {
+			if p == nil {
+				var errP error
+				p, errP = LoadPipelineByID(db, pipelineID, false)
+				if errP != nil {
+					return errP
+				}
+				if err := LoadPipelineStage(db, p); err != nil {
+					return err
+				}
+			}
+			if !hasJobOutput(p, ref[1], ref[2], ref[3]) {
+				return fmt.Errorf("parameter %s refers to output %s of job %s in stage %s, which is not declared in pipeline %s", param.Name, ref[3], ref[2], ref[1], p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// hasJobOutput returns true if the job declares the output. A cell of a job matrix is named after the job: "<job> (<values>)"
+func hasJobOutput(p *sdk.Pipeline, stageName, jobName, output string) bool {
+	for _, s := range p.Stages {
+		if s.Name != stageName {
+			continue
+		}
+		for _, j := range s.Jobs {
+			if j.Action.Name != jobName && !(len(j.Matrix) > 0 && strings.HasPrefix(jobName, j.Action.Name+" (")) {
+				continue
+			}
+			for _, o := range j.Outputs {
+				if o.Name == output {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
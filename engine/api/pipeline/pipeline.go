@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -41,11 +42,12 @@ func LoadPipeline(db gorp.SqlExecutor, projectKey, name string, deep bool) (*sdk
 	//}
 
 	var lastModified time.Time
-	query := `SELECT pipeline.id, pipeline.name, pipeline.project_id, pipeline.type, pipeline.last_modified FROM pipeline
+	var concurrency sql.NullString
+	query := `SELECT pipeline.id, pipeline.name, pipeline.project_id, pipeline.type, pipeline.last_modified, pipeline.concurrency FROM pipeline
 	 		JOIN project on pipeline.project_id = project.id
 	 		WHERE pipeline.name = $1 AND project.projectKey = $2`
 
-	err := db.QueryRow(query, name, projectKey).Scan(&p.ID, &p.Name, &p.ProjectID, &p.Type, &lastModified)
+	err := db.QueryRow(query, name, projectKey).Scan(&p.ID, &p.Name, &p.ProjectID, &p.Type, &lastModified, &concurrency)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.ErrPipelineNotFound
@@ -54,6 +56,9 @@ func LoadPipeline(db gorp.SqlExecutor, projectKey, name string, deep bool) (*sdk
 	}
 	p.LastModified = lastModified.Unix()
 	p.ProjectKey = projectKey
+	if p.Concurrency, err = unmarshalConcurrency(concurrency); err != nil {
+		return nil, sdk.WrapError(err, "LoadPipeline> Cannot unmarshal concurrency of pipeline %s", name)
+	}
 
 	if deep {
 		if err := loadPipelineDependencies(db, &p); err != nil {
@@ -67,11 +72,12 @@ func LoadPipeline(db gorp.SqlExecutor, projectKey, name string, deep bool) (*sdk
 // LoadPipelineByID loads a pipeline from database
 func LoadPipelineByID(db gorp.SqlExecutor, pipelineID int64, deep bool) (*sdk.Pipeline, error) {
 	var p sdk.Pipeline
-	query := `SELECT pipeline.name, pipeline.type, project.projectKey FROM pipeline
+	var concurrency sql.NullString
+	query := `SELECT pipeline.name, pipeline.type, project.projectKey, pipeline.concurrency FROM pipeline
 	JOIN project on pipeline.project_id = project.id
 	WHERE pipeline.id = $1`
 
-	err := db.QueryRow(query, pipelineID).Scan(&p.Name, &p.Type, &p.ProjectKey)
+	err := db.QueryRow(query, pipelineID).Scan(&p.Name, &p.Type, &p.ProjectKey, &concurrency)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.ErrPipelineNotFound
@@ -79,6 +85,9 @@ func LoadPipelineByID(db gorp.SqlExecutor, pipelineID int64, deep bool) (*sdk.Pi
 		return nil, err
 	}
 	p.ID = pipelineID
+	if p.Concurrency, err = unmarshalConcurrency(concurrency); err != nil {
+		return nil, sdk.WrapError(err, "LoadPipelineByID> Cannot unmarshal concurrency of pipeline %d", pipelineID)
+	}
 
 	if deep {
 		if err := loadPipelineDependencies(db, &p); err != nil {
@@ -200,13 +209,13 @@ func LoadPipelines(db gorp.SqlExecutor, projectID int64, loadDependencies bool,
 	var errquery error
 
 	if user == nil || user.Admin {
-		query := `SELECT id, name, project_id, type, last_modified
+		query := `SELECT id, name, project_id, type, last_modified, concurrency
 			  FROM pipeline
 			  WHERE project_id = $1
 			  ORDER BY pipeline.name`
 		rows, errquery = db.Query(query, projectID)
 	} else {
-		query := `SELECT distinct(pipeline.id), pipeline.name, pipeline.project_id, pipeline.type, last_modified
+		query := `SELECT distinct(pipeline.id), pipeline.name, pipeline.project_id, pipeline.type, last_modified, pipeline.concurrency
 			  FROM pipeline
 			  JOIN pipeline_group ON pipeline.id = pipeline_group.pipeline_id
 			  JOIN group_user ON pipeline_group.group_id = group_user.group_id
@@ -224,12 +233,18 @@ func LoadPipelines(db gorp.SqlExecutor, projectID int64, loadDependencies bool,
 	for rows.Next() {
 		var p sdk.Pipeline
 		var lastModified time.Time
+		var concurrency sql.NullString
 
 		// scan pipeline id
-		if err := rows.Scan(&p.ID, &p.Name, &p.ProjectID, &p.Type, &lastModified); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.ProjectID, &p.Type, &lastModified, &concurrency); err != nil {
 			return nil, err
 		}
 		p.LastModified = lastModified.Unix()
+		c, err := unmarshalConcurrency(concurrency)
+		if err != nil {
+			return nil, sdk.WrapError(err, "LoadPipelines> Cannot unmarshal concurrency of pipeline %d", p.ID)
+		}
+		p.Concurrency = c
 
 		if loadDependencies {
 			// load pipeline stages
@@ -334,15 +349,20 @@ func UpdatePipeline(db gorp.SqlExecutor, p *sdk.Pipeline) error {
 		return err
 	}
 
+	concurrency, err := marshalConcurrency(p.Concurrency)
+	if err != nil {
+		return err
+	}
+
 	//Update pipeline
-	query = `UPDATE pipeline SET name=$1, type=$2, last_modified = current_timestamp WHERE id=$3`
-	_, err = db.Exec(query, p.Name, string(p.Type), p.ID)
+	query = `UPDATE pipeline SET name=$1, type=$2, concurrency=$3, last_modified = current_timestamp WHERE id=$4`
+	_, err = db.Exec(query, p.Name, string(p.Type), concurrency, p.ID)
 	return err
 }
 
 // InsertPipeline inserts pipeline informations in database
 func InsertPipeline(db gorp.SqlExecutor, p *sdk.Pipeline) error {
-	query := `INSERT INTO pipeline (name, project_id, type, last_modified) VALUES ($1,$2,$3, current_timestamp) RETURNING id, last_modified`
+	query := `INSERT INTO pipeline (name, project_id, type, concurrency, last_modified) VALUES ($1,$2,$3,$4, current_timestamp) RETURNING id, last_modified`
 
 	if p.Name == "" {
 		return sdk.ErrInvalidName
@@ -356,8 +376,13 @@ func InsertPipeline(db gorp.SqlExecutor, p *sdk.Pipeline) error {
 		return sdk.ErrInvalidProject
 	}
 
+	concurrency, err := marshalConcurrency(p.Concurrency)
+	if err != nil {
+		return err
+	}
+
 	var lastModified time.Time
-	if err := db.QueryRow(query, p.Name, p.ProjectID, string(p.Type)).Scan(&p.ID, &lastModified); err != nil {
+	if err := db.QueryRow(query, p.Name, p.ProjectID, string(p.Type), concurrency).Scan(&p.ID, &lastModified); err != nil {
 		return err
 	}
 	p.LastModified = lastModified.Unix()
@@ -385,3 +410,25 @@ func ExistPipeline(db gorp.SqlExecutor, projectID int64, name string) (bool, err
 	}
 	return false, nil
 }
+
+func marshalConcurrency(c *sdk.Concurrency) (sql.NullString, error) {
+	if c == nil {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+func unmarshalConcurrency(s sql.NullString) (*sdk.Concurrency, error) {
+	if !s.Valid || s.String == "" {
+		return nil, nil
+	}
+	c := &sdk.Concurrency{}
+	if err := json.Unmarshal([]byte(s.String), c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
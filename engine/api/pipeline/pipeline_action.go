@@ -1,6 +1,7 @@
 package pipeline
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -102,9 +103,24 @@ func InsertJob(db gorp.SqlExecutor, job *sdk.Job, stageID int64, pip *sdk.Pipeli
 	}
 	job.PipelineStageID = stage.ID
 
+	matrix, errM := json.Marshal(job.Matrix)
+	if errM != nil {
+		return errM
+	}
+
+	outputs, errO := json.Marshal(job.Outputs)
+	if errO != nil {
+		return errO
+	}
+
+	retry, errR := json.Marshal(job.Retry)
+	if errR != nil {
+		return errR
+	}
+
 	// Create pipeline action
-	query := `INSERT INTO pipeline_action (pipeline_stage_id, action_id, enabled) VALUES ($1, $2, $3) RETURNING id`
-	if err := db.QueryRow(query, job.PipelineStageID, job.Action.ID, job.Enabled).Scan(&job.PipelineActionID); err != nil {
+	query := `INSERT INTO pipeline_action (pipeline_stage_id, action_id, enabled, matrix, timeout, outputs, retry) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	if err := db.QueryRow(query, job.PipelineStageID, job.Action.ID, job.Enabled, string(matrix), job.Timeout, string(outputs), string(retry)).Scan(&job.PipelineActionID); err != nil {
 		return err
 	}
 	return nil
@@ -121,9 +137,7 @@ func UpdateJob(db gorp.SqlExecutor, job *sdk.Job, userID int64) error {
 		return sdk.ErrForbidden
 	}
 
-	query := `UPDATE pipeline_action set action_id=$1, pipeline_stage_id=$2, enabled=$4  WHERE id=$3`
-	_, err = db.Exec(query, job.Action.ID, job.PipelineStageID, job.PipelineActionID, job.Enabled)
-	if err != nil {
+	if err := UpdatePipelineAction(db, *job); err != nil {
 		return err
 	}
 	job.Action.Enabled = job.Enabled
@@ -137,9 +151,24 @@ func DeleteJob(db gorp.SqlExecutor, job sdk.Job, userID int64) error {
 
 // UpdatePipelineAction Update an action in a pipeline
 func UpdatePipelineAction(db gorp.SqlExecutor, job sdk.Job) error {
-	query := `UPDATE pipeline_action set action_id=$1, pipeline_stage_id=$2, enabled=$4  WHERE id=$3`
+	matrix, errM := json.Marshal(job.Matrix)
+	if errM != nil {
+		return errM
+	}
+
+	outputs, errO := json.Marshal(job.Outputs)
+	if errO != nil {
+		return errO
+	}
+
+	retry, errR := json.Marshal(job.Retry)
+	if errR != nil {
+		return errR
+	}
+
+	query := `UPDATE pipeline_action set action_id=$1, pipeline_stage_id=$2, enabled=$4, matrix=$5, timeout=$6, outputs=$7, retry=$8  WHERE id=$3`
 
-	_, err := db.Exec(query, job.Action.ID, job.PipelineStageID, job.PipelineActionID, job.Enabled)
+	_, err := db.Exec(query, job.Action.ID, job.PipelineStageID, job.PipelineActionID, job.Enabled, string(matrix), job.Timeout, string(outputs), string(retry))
 	if err != nil {
 		return err
 	}
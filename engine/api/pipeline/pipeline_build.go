@@ -47,6 +47,8 @@ type PipelineBuildDbResult struct {
 	ParentPipelineBuildID sql.NullInt64  `db:"parent_pipeline_build"`
 	Username              sql.NullString `db:"username"`
 	ScheduledTrigger      bool           `db:"scheduled_trigger"`
+	ConcurrencyKey        string         `db:"concurrency_key"`
+	WaitingReason         string         `db:"waiting_reason"`
 }
 
 const (
@@ -62,7 +64,8 @@ const (
 			pb.vcs_changes_branch as vcs_branch, pb.vcs_changes_hash as vcs_hash, pb.vcs_changes_author as vcs_author,
 			pb.parent_pipeline_build_id as parent_pipeline_build,
 			"user".username as username,
-			pb.scheduled_trigger as scheduled_trigger
+			pb.scheduled_trigger as scheduled_trigger,
+			pb.concurrency_key as concurrency_key, pb.waiting_reason as waiting_reason
 		FROM pipeline_build pb
 		JOIN application ON application.id = pb.application_id
 		JOIN pipeline ON pipeline.id = pb.pipeline_id
@@ -82,6 +85,16 @@ func SelectBuildForUpdate(db gorp.SqlExecutor, buildID int64) error {
 	return db.QueryRow(query, buildID, sdk.StatusBuilding.String()).Scan(&id)
 }
 
+// SelectBuildWaitingApprovalForUpdate selects and locks a build waiting for a stage approval
+func SelectBuildWaitingApprovalForUpdate(db gorp.SqlExecutor, buildID int64) error {
+	var id int64
+	query := `SELECT id
+                 FROM pipeline_build
+                 WHERE id = $1 AND status = $2
+                 FOR UPDATE`
+	return db.QueryRow(query, buildID, sdk.StatusWaitingApproval.String()).Scan(&id)
+}
+
 // LoadPipelineBuildID Load only id of pipeline build
 func LoadPipelineBuildID(db gorp.SqlExecutor, applicationID, pipelineID, environmentID, buildNumber int64) (int64, error) {
 	var pbID int64
@@ -130,8 +143,17 @@ func LoadPipelineBuildByApplicationAndBranch(db gorp.SqlExecutor, appID int64, b
 
 // LoadBuildingPipelinesIDs Load all building pipeline id
 func LoadBuildingPipelinesIDs(db gorp.SqlExecutor) ([]int64, error) {
+	return loadPipelineBuildsIDsByStatus(db, sdk.StatusBuilding)
+}
+
+// LoadWaitingApprovalPipelinesIDs Load all pipeline build id waiting for a stage approval
+func LoadWaitingApprovalPipelinesIDs(db gorp.SqlExecutor) ([]int64, error) {
+	return loadPipelineBuildsIDsByStatus(db, sdk.StatusWaitingApproval)
+}
+
+func loadPipelineBuildsIDsByStatus(db gorp.SqlExecutor, status sdk.Status) ([]int64, error) {
 	query := "SELECT id FROM pipeline_build WHERE status = $1 ORDER BY id ASC"
-	rows, err := db.Query(query, sdk.StatusBuilding.String())
+	rows, err := db.Query(query, status.String())
 	if err != nil {
 		return nil, err
 	}
@@ -151,12 +173,12 @@ func LoadBuildingPipelinesIDs(db gorp.SqlExecutor) ([]int64, error) {
 // less than a minute ago
 func LoadRecentPipelineBuild(db gorp.SqlExecutor, args ...FuncArg) ([]sdk.PipelineBuild, error) {
 	whereCondition := `
-		WHERE pb.status = $1 OR (pb.status != $1 AND pb.done > NOW() -  INTERVAL '1 minutes')
+		WHERE pb.status IN ($1, $2, $3) OR (pb.status NOT IN ($1, $2, $3) AND pb.done > NOW() -  INTERVAL '1 minutes')
 		ORDER by pb.id ASC
 	`
 	query := fmt.Sprintf("%s %s", selectPipelineBuild, whereCondition)
 	var rows []PipelineBuildDbResult
-	_, err := db.Select(&rows, query, sdk.StatusBuilding.String())
+	_, err := db.Select(&rows, query, sdk.StatusBuilding.String(), sdk.StatusWaitingApproval.String(), sdk.StatusPending.String())
 	if err != nil {
 		return nil, err
 	}
@@ -178,13 +200,13 @@ func LoadUserRecentPipelineBuild(db gorp.SqlExecutor, userID int64) ([]sdk.Pipel
 	whereCondition := `
 		JOIN pipeline_group ON pipeline_group.pipeline_id = pb.pipeline_id
 		JOIN group_user ON group_user.group_id = pipeline_group.group_id
-		WHERE pb.status = $1 OR (pb.status != $1 AND pb.done > NOW() - INTERVAL '1 minutes')
+		WHERE pb.status IN ($1, $3, $4) OR (pb.status NOT IN ($1, $3, $4) AND pb.done > NOW() - INTERVAL '1 minutes')
 		AND group_user.user_id = $2
 		ORDER by pb.id ASC`
 
 	query := fmt.Sprintf("%s %s", selectPipelineBuild, whereCondition)
 	var rows []PipelineBuildDbResult
-	_, err := db.Select(&rows, query, sdk.StatusBuilding.String(), userID)
+	_, err := db.Select(&rows, query, sdk.StatusBuilding.String(), userID, sdk.StatusWaitingApproval.String(), sdk.StatusPending.String())
 	if err != nil {
 		return nil, err
 	}
@@ -366,10 +388,12 @@ func scanPipelineBuild(pbResult PipelineBuildDbResult) (*sdk.PipelineBuild, erro
 			ProjectKey: pbResult.ProjectKey,
 			ProjectID:  pbResult.ProjectID,
 		},
-		BuildNumber: pbResult.BuildNumber,
-		Version:     pbResult.Version,
-		Status:      sdk.StatusFromString(pbResult.Status),
-		Start:       pbResult.Start,
+		BuildNumber:    pbResult.BuildNumber,
+		Version:        pbResult.Version,
+		Status:         sdk.StatusFromString(pbResult.Status),
+		Start:          pbResult.Start,
+		ConcurrencyKey: pbResult.ConcurrencyKey,
+		WaitingReason:  pbResult.WaitingReason,
 		Trigger: sdk.PipelineBuildTrigger{
 			ManualTrigger:    pbResult.ManualTrigger,
 			ScheduledTrigger: pbResult.ScheduledTrigger,
@@ -475,6 +499,10 @@ func DeletePipelineBuildByID(db gorp.SqlExecutor, pbID int64) error {
 		return err
 	}
 
+	if err := DeletePipelineBuildJobAttemptsByPipelineBuildID(db, pbID); err != nil {
+		return err
+	}
+
 	if err := DeletePipelineBuildJob(db, pbID); err != nil {
 		return err
 	}
@@ -525,7 +553,32 @@ func GetLastBuildNumber(db gorp.SqlExecutor, pipID, appID, envID int64) (int64,
 
 // InsertBuildVariable adds a variable exported in user scripts and forwarded by building worker
 func InsertBuildVariable(db gorp.SqlExecutor, pbID int64, v sdk.Variable) error {
+	return insertBuildParameters(db, pbID, []sdk.Parameter{{
+		Name:  "cds.build." + v.Name,
+		Type:  sdk.StringParameter,
+		Value: v.Value,
+	}})
+}
+
+// InsertJobOutputs adds the outputs of a job as cds.stage.<stage>.job.<job>.output.<name> build parameters
+func InsertJobOutputs(db gorp.SqlExecutor, pbID int64, stageName, jobName string, outputs []sdk.JobOutput) error {
+	if len(outputs) == 0 {
+		return nil
+	}
+
+	params := make([]sdk.Parameter, len(outputs))
+	for i, o := range outputs {
+		params[i] = sdk.Parameter{
+			Name:  sdk.JobOutputParameterName(stageName, jobName, o.Name),
+			Type:  sdk.StringParameter,
+			Value: o.Value,
+		}
+	}
+	return insertBuildParameters(db, pbID, params)
+}
 
+// insertBuildParameters adds parameters to the pipeline build and to all its pending jobs
+func insertBuildParameters(db gorp.SqlExecutor, pbID int64, newParams []sdk.Parameter) error {
 	// Load args from pipeline build and lock it
 	query := `SELECT args FROM pipeline_build WHERE id = $1 FOR UPDATE`
 	var argsJSON string
@@ -539,12 +592,8 @@ func InsertBuildVariable(db gorp.SqlExecutor, pbID int64, v sdk.Variable) error
 		return err
 	}
 
-	// Add build variable
-	params = append(params, sdk.Parameter{
-		Name:  "cds.build." + v.Name,
-		Type:  sdk.StringParameter,
-		Value: v.Value,
-	})
+	// Add build parameters
+	params = append(params, newParams...)
 
 	// Update pb in database
 	data, errj := json.Marshal(params)
@@ -564,11 +613,7 @@ func InsertBuildVariable(db gorp.SqlExecutor, pbID int64, v sdk.Variable) error
 	}
 
 	for _, j := range pbJobs {
-		j.Parameters = append(j.Parameters, sdk.Parameter{
-			Name:  "cds.build." + v.Name,
-			Type:  sdk.StringParameter,
-			Value: v.Value,
-		})
+		j.Parameters = append(j.Parameters, newParams...)
 
 		// Update
 		if err := UpdatePipelineBuildJob(db, &j); err != nil {
@@ -792,6 +837,16 @@ func InsertPipelineBuild(tx gorp.SqlExecutor, project *sdk.Project, p *sdk.Pipel
 		return nil, sdk.WrapError(err, "InsertPipelineBuild> Unable to load pipeline stages")
 	}
 
+	// Hold the build or stop the previous ones if its concurrency group is busy
+	concurrency, errc := LoadConcurrency(tx, p.ID)
+	if errc != nil {
+		return nil, sdk.WrapError(errc, "InsertPipelineBuild> Unable to load pipeline concurrency")
+	}
+	p.Concurrency = concurrency
+	if err := applyConcurrency(tx, concurrency, &pb, argsFinal); err != nil {
+		return nil, sdk.WrapError(err, "InsertPipelineBuild> Unable to apply pipeline concurrency")
+	}
+
 	// Init Action build
 	for stageIndex := range p.Stages {
 		stage := &p.Stages[stageIndex]
@@ -810,7 +865,6 @@ func InsertPipelineBuild(tx gorp.SqlExecutor, project *sdk.Project, p *sdk.Pipel
 		return nil, sdk.WrapError(err, "InsertPipelineBuild> Cannot insert pipeline build")
 	}
 
-	pb.Status = sdk.StatusBuilding
 	pb.Pipeline = *p
 	pb.Parameters = params
 	pb.Application = *app
@@ -849,8 +903,8 @@ func InsertPipelineBuild(tx gorp.SqlExecutor, project *sdk.Project, p *sdk.Pipel
 }
 
 func insertPipelineBuild(db gorp.SqlExecutor, args string, applicationID, pipelineID int64, pb *sdk.PipelineBuild, envID int64, stages string, commits []sdk.VCSCommit) error {
-	query := `INSERT INTO pipeline_build (pipeline_id, build_number, version, status, args, start, application_id,environment_id, done, manual_trigger, triggered_by, parent_pipeline_build_id, vcs_changes_branch, vcs_changes_hash, vcs_changes_author, scheduled_trigger, stages, commits)
-						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18) RETURNING id`
+	query := `INSERT INTO pipeline_build (pipeline_id, build_number, version, status, args, start, application_id,environment_id, done, manual_trigger, triggered_by, parent_pipeline_build_id, vcs_changes_branch, vcs_changes_hash, vcs_changes_author, scheduled_trigger, stages, commits, concurrency_key, waiting_reason)
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20) RETURNING id`
 
 	var triggeredBy, parentPipelineID int64
 	if pb.Trigger.TriggeredBy != nil {
@@ -866,11 +920,12 @@ func insertPipelineBuild(db gorp.SqlExecutor, args string, applicationID, pipeli
 	}
 
 	statement := db.QueryRow(
-		query, pipelineID, pb.BuildNumber, pb.Version, sdk.StatusBuilding.String(),
+		query, pipelineID, pb.BuildNumber, pb.Version, pb.Status.String(),
 		args, time.Now(), applicationID, envID, time.Now(), pb.Trigger.ManualTrigger,
 		sql.NullInt64{Int64: triggeredBy, Valid: triggeredBy != 0},
 		sql.NullInt64{Int64: parentPipelineID, Valid: parentPipelineID != 0},
-		pb.Trigger.VCSChangesBranch, pb.Trigger.VCSChangesHash, pb.Trigger.VCSChangesAuthor, pb.Trigger.ScheduledTrigger, stages, commitsBtes)
+		pb.Trigger.VCSChangesBranch, pb.Trigger.VCSChangesHash, pb.Trigger.VCSChangesAuthor, pb.Trigger.ScheduledTrigger, stages, commitsBtes,
+		pb.ConcurrencyKey, pb.WaitingReason)
 
 	if err := statement.Scan(&pb.ID); err != nil {
 		return sdk.WrapError(err, "insertPipelineBuild> Unable to insert pipeline_build : App:%d,Pip:%d,Env:%s", applicationID, pipelineID, envID)
@@ -1279,8 +1334,25 @@ func CurrentAndPreviousPipelineBuildNumberAndHash(db gorp.SqlExecutor, buildNumb
 	return cur, prev, nil
 }
 
-// StopPipelineBuild fails all currently building actions
+// StopPipelineBuild stops all currently building actions
 func StopPipelineBuild(db gorp.SqlExecutor, pb *sdk.PipelineBuild) error {
+	// A build waiting for an approval has no job to stop: stop the gated stage
+	if pb.Status == sdk.StatusWaitingApproval {
+		for i := range pb.Stages {
+			if pb.Stages[i].Status == sdk.StatusWaitingApproval {
+				pb.Stages[i].Status = sdk.StatusStopped
+			}
+		}
+		pb.Done = time.Now()
+		return UpdatePipelineBuildStatusAndStage(db, pb, sdk.StatusStopped)
+	}
+
+	// A build held by its concurrency group has not started yet
+	if pb.Status == sdk.StatusPending {
+		pb.Done = time.Now()
+		return UpdatePipelineBuildStatusAndStage(db, pb, sdk.StatusStopped)
+	}
+
 	if err := StopBuildingPipelineBuildJob(db, pb); err != nil {
 		return err
 	}
@@ -1313,11 +1385,17 @@ func RestartPipelineBuild(db gorp.SqlExecutor, pb *sdk.PipelineBuild) error {
 			if i == 0 {
 				stage.Status = sdk.StatusWaiting
 			}
+			if stage.Approval != nil {
+				stage.Approval.Reset()
+			}
 			// Delete logs
 			for _, pbJob := range stage.PipelineBuildJobs {
 				if err := DeleteBuildLogs(db, pbJob.ID); err != nil {
 					return err
 				}
+				if err := DeletePipelineBuildJobAttempts(db, pbJob.ID); err != nil {
+					return err
+				}
 			}
 			stage.PipelineBuildJobs = nil
 		}
@@ -1343,16 +1421,22 @@ func RestartPipelineBuild(db gorp.SqlExecutor, pb *sdk.PipelineBuild) error {
 	} else {
 		for i := range pb.Stages {
 			stage := &pb.Stages[i]
-			if stage.Status != sdk.StatusFail {
+			if stage.Status != sdk.StatusFail && stage.Status != sdk.StatusStopped {
 				continue
 			}
 			stage.Status = sdk.StatusWaiting
+			if stage.Approval != nil {
+				stage.Approval.Reset()
+			}
 			// Delete logs
 			for _, pbJob := range stage.PipelineBuildJobs {
-				if pbJob.Status == sdk.StatusFail.String() {
+				if pbJob.Status == sdk.StatusFail.String() || pbJob.Status == sdk.StatusStopped.String() {
 					if err := DeleteBuildLogs(db, pbJob.ID); err != nil {
 						return err
 					}
+					if err := DeletePipelineBuildJobAttempts(db, pbJob.ID); err != nil {
+						return err
+					}
 				}
 			}
 			stage.PipelineBuildJobs = nil
@@ -1409,6 +1493,17 @@ func DeleteBranchBuilds(db gorp.SqlExecutor, appID int64, branch string) error {
 	return nil
 }
 
+// DisableStoppedJobWorkers disables the workers still working on jobs stopped before the given date,
+// they did not acknowledge the stop of their job
+func DisableStoppedJobWorkers(db gorp.SqlExecutor, before time.Time) error {
+	query := `UPDATE worker SET status = $1, action_build_id = NULL
+		WHERE action_build_id IN (SELECT id FROM pipeline_build_job WHERE status = $2 AND done < $3)`
+	if _, err := db.Exec(query, sdk.StatusDisabled.String(), sdk.StatusStopped.String(), before); err != nil {
+		return sdk.WrapError(err, "DisableStoppedJobWorkers> Error while disabling workers of stopped jobs")
+	}
+	return nil
+}
+
 // DisableBuildingWorker Disable all workers working on given pipeline build job
 func DisableBuildingWorker(db gorp.SqlExecutor, pipJobID int64) error {
 	query := `UPDATE worker set status=$1, action_build_id = NULL where action_build_id = $2`
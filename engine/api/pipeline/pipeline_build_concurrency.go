@@ -0,0 +1,134 @@
+package pipeline
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadConcurrency loads the concurrency policy of a pipeline, nil if builds are not limited
+func LoadConcurrency(db gorp.SqlExecutor, pipelineID int64) (*sdk.Concurrency, error) {
+	var concurrency sql.NullString
+	query := `SELECT concurrency FROM pipeline WHERE id = $1`
+	if err := db.QueryRow(query, pipelineID).Scan(&concurrency); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrPipelineNotFound
+		}
+		return nil, err
+	}
+	return unmarshalConcurrency(concurrency)
+}
+
+// LoadPendingPipelinesIDs Load all pipeline build id held by their concurrency group
+func LoadPendingPipelinesIDs(db gorp.SqlExecutor) ([]int64, error) {
+	return loadPipelineBuildsIDsByStatus(db, sdk.StatusPending)
+}
+
+// SelectBuildPendingForUpdate selects and locks a build held by its concurrency group
+func SelectBuildPendingForUpdate(db gorp.SqlExecutor, buildID int64) error {
+	var id int64
+	query := `SELECT id
+                 FROM pipeline_build
+                 WHERE id = $1 AND status = $2
+                 FOR UPDATE NOWAIT`
+	return db.QueryRow(query, buildID, sdk.StatusPending.String()).Scan(&id)
+}
+
+// CountBuildsBefore counts the builds of a concurrency group which must end before the given build can start:
+// running builds and builds held since before it
+func CountBuildsBefore(db gorp.SqlExecutor, key string, pbID int64) (int, error) {
+	var nb int
+	query := `SELECT count(1) FROM pipeline_build
+		WHERE concurrency_key = $1 AND id <> $2
+		AND (status IN ($3, $4) OR (status = $5 AND id < $2))`
+	if err := db.QueryRow(query, key, pbID, sdk.StatusBuilding.String(), sdk.StatusWaitingApproval.String(), sdk.StatusPending.String()).Scan(&nb); err != nil {
+		return 0, err
+	}
+	return nb, nil
+}
+
+// StartPendingPipelineBuild releases a build held by its concurrency group, the queue will run its first stage
+func StartPendingPipelineBuild(db gorp.SqlExecutor, pb *sdk.PipelineBuild) error {
+	pb.WaitingReason = ""
+	pb.Start = time.Now()
+	query := `UPDATE pipeline_build SET waiting_reason = $2, start = $3 WHERE id = $1`
+	if _, err := db.Exec(query, pb.ID, pb.WaitingReason, pb.Start); err != nil {
+		return err
+	}
+	return UpdatePipelineBuildStatusAndStage(db, pb, sdk.StatusBuilding)
+}
+
+// loadActiveBuildsByConcurrencyKey loads the builds of a concurrency group which are not done
+func loadActiveBuildsByConcurrencyKey(db gorp.SqlExecutor, key string) ([]sdk.PipelineBuild, error) {
+	whereCondition := `
+		WHERE pb.concurrency_key = $1 AND pb.status IN ($2, $3, $4)
+		ORDER by pb.id ASC
+	`
+	query := fmt.Sprintf("%s %s", selectPipelineBuild, whereCondition)
+
+	var rows []PipelineBuildDbResult
+	if _, err := db.Select(&rows, query, key, sdk.StatusBuilding.String(), sdk.StatusWaitingApproval.String(), sdk.StatusPending.String()); err != nil {
+		return nil, err
+	}
+
+	pbs := []sdk.PipelineBuild{}
+	for _, r := range rows {
+		pb, errScan := scanPipelineBuild(r)
+		if errScan != nil {
+			return nil, errScan
+		}
+		pbs = append(pbs, *pb)
+	}
+	return pbs, nil
+}
+
+// applyConcurrency computes the concurrency group of a new build and the status it starts with.
+// With the queue policy, the build is held while other builds of its group are not done.
+// With the cancel-previous policy, the other builds of the group are stopped and
+// the build is held until the running ones are effectively stopped.
+// db must be the transaction inserting the build: the concurrency group is locked until its end
+func applyConcurrency(db gorp.SqlExecutor, c *sdk.Concurrency, pb *sdk.PipelineBuild, params []sdk.Parameter) error {
+	pb.Status = sdk.StatusBuilding
+	if c == nil {
+		return nil
+	}
+
+	pb.ConcurrencyKey = c.ResolveKey(params)
+
+	// Serialize the builds of the group until the end of the transaction, so that two builds triggered
+	// at the same time cannot both see an idle group and start
+	if _, err := db.Exec("SELECT pg_advisory_xact_lock(hashtext($1))", "concurrency:"+pb.ConcurrencyKey); err != nil {
+		return sdk.WrapError(err, "applyConcurrency> Cannot lock concurrency group %s", pb.ConcurrencyKey)
+	}
+
+	active, err := loadActiveBuildsByConcurrencyKey(db, pb.ConcurrencyKey)
+	if err != nil {
+		return sdk.WrapError(err, "applyConcurrency> Cannot load builds of concurrency group %s", pb.ConcurrencyKey)
+	}
+
+	var blocking []string
+	for i := range active {
+		previous := &active[i]
+		if c.Policy == sdk.ConcurrencyCancelPrevious {
+			if err := StopPipelineBuild(db, previous); err != nil {
+				return sdk.WrapError(err, "applyConcurrency> Cannot stop pipeline build %d", previous.ID)
+			}
+			// Jobs of a building pipeline are stopped, the queue will end it
+			if previous.Status != sdk.StatusBuilding {
+				continue
+			}
+		}
+		blocking = append(blocking, fmt.Sprintf("%s #%d", previous.Pipeline.Name, previous.BuildNumber))
+	}
+
+	if len(blocking) > 0 {
+		pb.Status = sdk.StatusPending
+		pb.WaitingReason = fmt.Sprintf("Waiting for %s in concurrency group %s", strings.Join(blocking, ", "), pb.ConcurrencyKey)
+	}
+	return nil
+}
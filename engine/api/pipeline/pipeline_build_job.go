@@ -243,6 +243,14 @@ func AddSpawnInfosPipelineBuildJob(db gorp.SqlExecutor, pbJobID int64, infos []s
 		return nil, sdk.WrapError(err, "AddSpawnInfosPipelineBuildJob> Cannot prepare swpan infos")
 	}
 
+	failed, errF := failOnSpawnError(db, pbJob, infos)
+	if errF != nil {
+		return nil, sdk.WrapError(errF, "AddSpawnInfosPipelineBuildJob> Cannot fail pipeline build job on spawn error")
+	}
+	if failed {
+		return pbJob, nil
+	}
+
 	if err := UpdatePipelineBuildJob(db, pbJob); err != nil {
 		return nil, sdk.WrapError(err, "AddSpawnInfosPipelineBuildJob> Cannot update pipeline build job")
 	}
@@ -292,23 +300,31 @@ func StopBuildingPipelineBuildJob(db gorp.SqlExecutor, pb *sdk.PipelineBuild) er
 		return sdk.WrapError(err, "StopBuildingPipelineBuildJob> Cannot get pipeline build job")
 	}
 	for j := range pbJobs {
-		pbJ := &pbJobs[j]
-		if pbJ.Status == string(sdk.StatusBuilding) || pbJ.Status == string(sdk.StatusWaiting) {
-			pbJ.Job.Reason = "Job has been stopped"
-			pbJ.Status = string(sdk.StatusFail)
+		if err := StopPipelineBuildJob(db, &pbJobs[j]); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		for i := range pbJ.Job.StepStatus {
-			ss := &pbJ.Job.StepStatus[i]
-			if ss.Status == sdk.StatusBuilding.String() {
-				ss.Status = "Fail"
-			}
-		}
+// StopPipelineBuildJob marks a waiting or building job as stopped.
+// The worker running it checks the job status and kills the running step
+func StopPipelineBuildJob(db gorp.SqlExecutor, pbJob *sdk.PipelineBuildJob) error {
+	if pbJob.Status != sdk.StatusBuilding.String() && pbJob.Status != sdk.StatusWaiting.String() {
+		return nil
+	}
 
-		if err := UpdatePipelineBuildJobStatus(db, pbJ, sdk.StatusFail); err != nil {
-			return sdk.WrapError(err, "StopBuildingPipelineBuildJob> Cannot stop pipeline build job")
+	pbJob.Job.Reason = "Job has been stopped"
+	for i := range pbJob.Job.StepStatus {
+		ss := &pbJob.Job.StepStatus[i]
+		if ss.Status == sdk.StatusBuilding.String() {
+			ss.Status = sdk.StatusStopped.String()
 		}
 	}
+
+	if err := UpdatePipelineBuildJobStatus(db, pbJob, sdk.StatusStopped); err != nil {
+		return sdk.WrapError(err, "StopPipelineBuildJob> Cannot stop pipeline build job %d", pbJob.ID)
+	}
 	return nil
 }
 
@@ -339,7 +355,14 @@ func UpdatePipelineBuildJobStatus(db gorp.SqlExecutor, pbJob *sdk.PipelineBuildJ
 		pbJob.Start = time.Now()
 		pbJob.Status = status.String()
 
-	case sdk.StatusFail, sdk.StatusSuccess, sdk.StatusDisabled, sdk.StatusSkipped:
+	case sdk.StatusFail, sdk.StatusSuccess, sdk.StatusDisabled, sdk.StatusSkipped, sdk.StatusStopped:
+		// The worker of a stopped job reports its result once the running step is killed:
+		// the job stays stopped, but its final step statuses and end date are recorded
+		if currentStatus == sdk.StatusStopped.String() && (status == sdk.StatusFail || status == sdk.StatusSuccess || status == sdk.StatusStopped) {
+			pbJob.Done = time.Now()
+			pbJob.Status = currentStatus
+			break
+		}
 		if currentStatus != string(sdk.StatusWaiting) && currentStatus != string(sdk.StatusBuilding) && status != sdk.StatusDisabled && status != sdk.StatusSkipped {
 			log.Debug("UpdatePipelineBuildJobStatus> Status is %s, cannot update %d to %s", currentStatus, pbJob.ID, status)
 			// too late, Nate
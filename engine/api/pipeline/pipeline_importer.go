@@ -25,6 +25,11 @@ func ImportUpdate(db gorp.SqlExecutor, proj *sdk.Project, pip *sdk.Pipeline, msg
 
 	pip.ID = oldPipeline.ID
 
+	oldPipeline.Concurrency = pip.Concurrency
+	if err := UpdatePipeline(db, oldPipeline); err != nil {
+		return sdk.WrapError(err, "ImportUpdate> Unable to update concurrency of pipeline %s", pip.Name)
+	}
+
 	if pip.GroupPermission != nil {
 		//Browse all new persmission to know if we had to insert of update
 		for _, gp := range pip.GroupPermission {
@@ -14,6 +14,7 @@ import (
 	"github.com/ovh/cds/engine/api/action"
 	"github.com/ovh/cds/engine/api/trigger"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/condition"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -25,7 +26,7 @@ var (
 // LoadStage Get a stage from its ID and pipeline ID
 func LoadStage(db gorp.SqlExecutor, pipelineID int64, stageID int64) (*sdk.Stage, error) {
 	query := `
-		SELECT pipeline_stage.id, pipeline_stage.pipeline_id, pipeline_stage.name, pipeline_stage.build_order, pipeline_stage.enabled, pipeline_stage_prerequisite.parameter, pipeline_stage_prerequisite.expected_value
+		SELECT pipeline_stage.id, pipeline_stage.pipeline_id, pipeline_stage.name, pipeline_stage.build_order, pipeline_stage.enabled, pipeline_stage.condition_expression, pipeline_stage.approval, pipeline_stage_prerequisite.parameter, pipeline_stage_prerequisite.expected_value
 		FROM pipeline_stage
 		LEFT OUTER JOIN pipeline_stage_prerequisite ON pipeline_stage_prerequisite.pipeline_stage_id = pipeline_stage.id
 		WHERE pipeline_stage.pipeline_id = $1 
@@ -45,8 +46,11 @@ func LoadStage(db gorp.SqlExecutor, pipelineID int64, stageID int64) (*sdk.Stage
 	defer rows.Close()
 
 	for rows.Next() {
-		var parameter, expectedValue sql.NullString
-		rows.Scan(&stage.ID, &stage.PipelineID, &stage.Name, &stage.BuildOrder, &stage.Enabled, &parameter, &expectedValue)
+		var parameter, expectedValue, approval sql.NullString
+		rows.Scan(&stage.ID, &stage.PipelineID, &stage.Name, &stage.BuildOrder, &stage.Enabled, &stage.Condition, &approval, &parameter, &expectedValue)
+		if stage.Approval, err = unmarshalStageApproval(approval); err != nil {
+			return nil, err
+		}
 		if parameter.Valid && expectedValue.Valid {
 			p := sdk.Prerequisite{
 				Parameter:     parameter.String,
@@ -61,9 +65,13 @@ func LoadStage(db gorp.SqlExecutor, pipelineID int64, stageID int64) (*sdk.Stage
 
 // InsertStage insert given stage into given database
 func InsertStage(db gorp.SqlExecutor, s *sdk.Stage) error {
-	query := `INSERT INTO "pipeline_stage" (pipeline_id, name, build_order, enabled) VALUES($1,$2,$3,$4) RETURNING id`
+	query := `INSERT INTO "pipeline_stage" (pipeline_id, name, build_order, enabled, condition_expression, approval) VALUES($1,$2,$3,$4,$5,$6) RETURNING id`
 
-	if err := db.QueryRow(query, s.PipelineID, s.Name, s.BuildOrder, s.Enabled).Scan(&s.ID); err != nil {
+	approval, err := marshalStageApproval(s.Approval)
+	if err != nil {
+		return err
+	}
+	if err := db.QueryRow(query, s.PipelineID, s.Name, s.BuildOrder, s.Enabled, s.Condition, approval).Scan(&s.ID); err != nil {
 		return err
 	}
 	return InsertStagePrequisites(db, s)
@@ -95,7 +103,7 @@ func LoadStages(db gorp.SqlExecutor, pipelineID int64) ([]sdk.Stage, error) {
 	var stages []sdk.Stage
 
 	query := `
-		SELECT pipeline_stage.id, pipeline_stage.name, pipeline_stage.enabled, pipeline_stage_prerequisite.parameter, pipeline_stage_prerequisite.expected_value
+		SELECT pipeline_stage.id, pipeline_stage.name, pipeline_stage.enabled, pipeline_stage.condition_expression, pipeline_stage.approval, pipeline_stage_prerequisite.parameter, pipeline_stage_prerequisite.expected_value
 		FROM pipeline_stage
 		LEFT OUTER JOIN pipeline_stage_prerequisite ON pipeline_stage_prerequisite.pipeline_stage_id = pipeline_stage.id
 	 	WHERE pipeline_id = $1 
@@ -113,8 +121,8 @@ func LoadStages(db gorp.SqlExecutor, pipelineID int64) ([]sdk.Stage, error) {
 	for rows.Next() {
 		var id int64
 		var enabled bool
-		var name, parameter, expectedValue sql.NullString
-		err = rows.Scan(&id, &name, &enabled, &parameter, &expectedValue)
+		var name, cond, approval, parameter, expectedValue sql.NullString
+		err = rows.Scan(&id, &name, &enabled, &cond, &approval, &parameter, &expectedValue)
 		if err != nil {
 			return stages, err
 		}
@@ -122,9 +130,13 @@ func LoadStages(db gorp.SqlExecutor, pipelineID int64) ([]sdk.Stage, error) {
 		var stageData = mapStages[id]
 		if stageData == nil {
 			stageData = &sdk.Stage{
-				ID:      id,
-				Name:    name.String,
-				Enabled: enabled,
+				ID:        id,
+				Name:      name.String,
+				Enabled:   enabled,
+				Condition: cond.String,
+			}
+			if stageData.Approval, err = unmarshalStageApproval(approval); err != nil {
+				return stages, err
 			}
 			mapStages[id] = stageData
 		}
@@ -153,13 +165,14 @@ func LoadPipelineStage(db gorp.SqlExecutor, p *sdk.Pipeline, args ...FuncArg) er
 
 	query := `
 	SELECT  pipeline_stage_R.id as stage_id, pipeline_stage_R.pipeline_id, pipeline_stage_R.name, pipeline_stage_R.last_modified, 
-			pipeline_stage_R.build_order, pipeline_stage_R.enabled, pipeline_stage_R.parameter, 
+			pipeline_stage_R.build_order, pipeline_stage_R.enabled, pipeline_stage_R.condition_expression, pipeline_stage_R.approval, pipeline_stage_R.parameter, 
 			pipeline_stage_R.expected_value, pipeline_action_R.id as pipeline_action_id, pipeline_action_R.action_id, pipeline_action_R.action_last_modified,
-			pipeline_action_R.action_args, pipeline_action_R.action_enabled
+			pipeline_action_R.action_args, pipeline_action_R.action_enabled, pipeline_action_R.action_matrix, pipeline_action_R.action_timeout, pipeline_action_R.action_outputs,
+			pipeline_action_R.action_retry
 	FROM (
 		SELECT  pipeline_stage.id, pipeline_stage.pipeline_id, 
 				pipeline_stage.name, pipeline_stage.last_modified ,pipeline_stage.build_order, 
-				pipeline_stage.enabled, 
+				pipeline_stage.enabled, pipeline_stage.condition_expression, pipeline_stage.approval,
 				pipeline_stage_prerequisite.parameter, pipeline_stage_prerequisite.expected_value
 		FROM pipeline_stage
 		LEFT OUTER JOIN pipeline_stage_prerequisite ON pipeline_stage.id = pipeline_stage_prerequisite.pipeline_stage_id
@@ -168,7 +181,8 @@ func LoadPipelineStage(db gorp.SqlExecutor, p *sdk.Pipeline, args ...FuncArg) er
 	LEFT OUTER JOIN (
 		SELECT  pipeline_action.id, action.id as action_id, action.name as action_name, action.last_modified as action_last_modified, 
 				pipeline_action.args as action_args, pipeline_action.enabled as action_enabled, 
-				pipeline_action.pipeline_stage_id
+				pipeline_action.matrix as action_matrix, pipeline_action.timeout as action_timeout, pipeline_action.outputs as action_outputs,
+				pipeline_action.retry as action_retry, pipeline_action.pipeline_stage_id
 		FROM action
 		JOIN pipeline_action ON pipeline_action.action_id = action.id
 	) as pipeline_action_R ON pipeline_action_R.pipeline_stage_id = pipeline_stage_R.id
@@ -189,17 +203,17 @@ func LoadPipelineStage(db gorp.SqlExecutor, p *sdk.Pipeline, args ...FuncArg) er
 	for rows.Next() {
 		var stageID, pipelineID int64
 		var stageBuildOrder int
-		var pipelineActionID, actionID sql.NullInt64
-		var stageName string
-		var stagePrerequisiteParameter, stagePrerequisiteExpectedValue, actionArgs sql.NullString
+		var pipelineActionID, actionID, actionTimeout sql.NullInt64
+		var stageName, stageCondition string
+		var stageApproval, stagePrerequisiteParameter, stagePrerequisiteExpectedValue, actionArgs, actionMatrix, actionOutputs, actionRetry sql.NullString
 		var stageEnabled, actionEnabled sql.NullBool
 		var stageLastModified, actionLastModified pq.NullTime
 
 		err = rows.Scan(
 			&stageID, &pipelineID, &stageName, &stageLastModified,
-			&stageBuildOrder, &stageEnabled, &stagePrerequisiteParameter,
+			&stageBuildOrder, &stageEnabled, &stageCondition, &stageApproval, &stagePrerequisiteParameter,
 			&stagePrerequisiteExpectedValue, &pipelineActionID, &actionID, &actionLastModified,
-			&actionArgs, &actionEnabled)
+			&actionArgs, &actionEnabled, &actionMatrix, &actionTimeout, &actionOutputs, &actionRetry)
 		if err != nil {
 			return err
 		}
@@ -214,6 +228,10 @@ func LoadPipelineStage(db gorp.SqlExecutor, p *sdk.Pipeline, args ...FuncArg) er
 				Enabled:      stageEnabled.Bool,
 				BuildOrder:   stageBuildOrder,
 				LastModified: stageLastModified.Time.Unix(),
+				Condition:    stageCondition,
+			}
+			if stageData.Approval, err = unmarshalStageApproval(stageApproval); err != nil {
+				return fmt.Errorf("loadPipelineStage> cannot unmarshal approval of stage %d > %s", stageID, err)
 			}
 			mapStages[stageID] = stageData
 			stagesPtr = append(stagesPtr, stageData)
@@ -247,10 +265,26 @@ func LoadPipelineStage(db gorp.SqlExecutor, p *sdk.Pipeline, args ...FuncArg) er
 					PipelineActionID: pipelineActionID.Int64,
 					LastModified:     actionLastModified.Time.Unix(),
 					Enabled:          actionEnabled.Bool,
+					Timeout:          actionTimeout.Int64,
 					Action: sdk.Action{
 						ID: actionID.Int64,
 					},
 				}
+				if actionMatrix.Valid {
+					if err := json.Unmarshal([]byte(actionMatrix.String), &j.Matrix); err != nil {
+						return fmt.Errorf("loadPipelineStage> cannot unmarshal matrix of job %d > %s", pipelineActionID.Int64, err)
+					}
+				}
+				if actionOutputs.Valid {
+					if err := json.Unmarshal([]byte(actionOutputs.String), &j.Outputs); err != nil {
+						return fmt.Errorf("loadPipelineStage> cannot unmarshal outputs of job %d > %s", pipelineActionID.Int64, err)
+					}
+				}
+				if actionRetry.Valid {
+					if err := json.Unmarshal([]byte(actionRetry.String), &j.Retry); err != nil {
+						return fmt.Errorf("loadPipelineStage> cannot unmarshal retry policy of job %d > %s", pipelineActionID.Int64, err)
+					}
+				}
 				mapAllActions[pipelineActionID.Int64] = j
 				mapActionsStages[stageID] = append(mapActionsStages[stageID], *j)
 
@@ -300,10 +334,42 @@ func LoadPipelineStage(db gorp.SqlExecutor, p *sdk.Pipeline, args ...FuncArg) er
 	return nil
 }
 
+// marshalStageApproval returns the approval configuration of a stage as stored in database, without runtime decisions
+func marshalStageApproval(a *sdk.StageApproval) (sql.NullString, error) {
+	if a == nil {
+		return sql.NullString{}, nil
+	}
+	conf := sdk.StageApproval{
+		Groups:   a.Groups,
+		Required: a.Required,
+		Timeout:  a.Timeout,
+	}
+	b, err := json.Marshal(conf)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+func unmarshalStageApproval(s sql.NullString) (*sdk.StageApproval, error) {
+	if !s.Valid || s.String == "" {
+		return nil, nil
+	}
+	a := &sdk.StageApproval{}
+	if err := json.Unmarshal([]byte(s.String), a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
 // UpdateStage update Stage and all its prequisites
 func UpdateStage(db gorp.SqlExecutor, s *sdk.Stage) error {
-	query := `UPDATE pipeline_stage SET name=$1, build_order=$2, enabled=$3 WHERE id=$4`
-	_, err := db.Exec(query, s.Name, s.BuildOrder, s.Enabled, s.ID)
+	approval, err := marshalStageApproval(s.Approval)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE pipeline_stage SET name=$1, build_order=$2, enabled=$3, condition_expression=$4, approval=$5 WHERE id=$6`
+	_, err = db.Exec(query, s.Name, s.BuildOrder, s.Enabled, s.Condition, approval, s.ID)
 	if err != nil {
 		return err
 	}
@@ -522,5 +588,19 @@ func CheckPrerequisites(s sdk.Stage, pb *sdk.PipelineBuild) (bool, error) {
 			}
 		}
 	}
-	return true, nil
+
+	// Check condition expression
+	vars := make(map[string]string, len(pb.Parameters))
+	for _, pbp := range pb.Parameters {
+		vars[pbp.Name] = pbp.Value
+	}
+	ok, err := condition.Eval(s.Condition, vars)
+	if err != nil {
+		log.Warning("CheckPrerequisites> Cannot eval condition '%s': %s", s.Condition, err)
+		return false, fmt.Errorf("CheckPrerequisites> %s", err)
+	}
+	if !ok {
+		log.Debug("CheckPrerequisites> Condition '%s' is false\n", s.Condition)
+	}
+	return ok, nil
 }
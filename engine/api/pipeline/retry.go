@@ -0,0 +1,164 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// RetryPipelineBuildJob queues again a failed job according to its retry policy, once the backoff delay after
+// its last attempt is elapsed. The logs and spawn infos of the failed attempt are kept as a job attempt
+func RetryPipelineBuildJob(db gorp.SqlExecutor, pbJob *sdk.PipelineBuildJob, now time.Time) error {
+	attempts := pbJob.Job.Retries + 1
+	if pbJob.Status != sdk.StatusFail.String() || !pbJob.Job.Retry.ShouldRetry(pbJob.Job.FailureClass, attempts) {
+		return nil
+	}
+	if now.Before(pbJob.Done.Add(pbJob.Job.Retry.Delay(attempts))) {
+		return nil
+	}
+
+	logs, errL := LoadLogs(db, pbJob.ID)
+	if errL != nil {
+		return sdk.WrapError(errL, "RetryPipelineBuildJob> Cannot load logs of pipeline build job %d", pbJob.ID)
+	}
+
+	a := sdk.JobAttempt{
+		Attempt:      attempts,
+		Status:       pbJob.Status,
+		FailureClass: pbJob.Job.FailureClass,
+		Reason:       pbJob.Job.Reason,
+		WorkerName:   pbJob.Job.WorkerName,
+		StepStatus:   pbJob.Job.StepStatus,
+		Start:        pbJob.Start,
+		Done:         pbJob.Done,
+		SpawnInfos:   pbJob.SpawnInfos,
+		Logs:         logs,
+	}
+	if err := insertPipelineBuildJobAttempt(db, pbJob, a); err != nil {
+		return err
+	}
+	if err := DeleteBuildLogs(db, pbJob.ID); err != nil {
+		return sdk.WrapError(err, "RetryPipelineBuildJob> Cannot delete logs of pipeline build job %d", pbJob.ID)
+	}
+
+	log.Info("RetryPipelineBuildJob> Retrying pipeline build job %d after %s (attempt %d/%d)", pbJob.ID, pbJob.Job.FailureClass, attempts+1, pbJob.Job.Retry.MaxAttempts)
+
+	pbJob.Job.Retries = attempts
+	pbJob.Job.FailureClass = ""
+	pbJob.Job.Reason = fmt.Sprintf("Retry after %s (attempt %d/%d)", a.FailureClass, attempts+1, pbJob.Job.Retry.MaxAttempts)
+	pbJob.Job.WorkerName = ""
+	pbJob.Job.StepStatus = nil
+	pbJob.SpawnInfos = nil
+	pbJob.Model = ""
+	pbJob.Status = sdk.StatusWaiting.String()
+	pbJob.Queued = now
+	pbJob.Start = time.Time{}
+	pbJob.Done = time.Time{}
+	if err := UpdatePipelineBuildJob(db, pbJob); err != nil {
+		return sdk.WrapError(err, "RetryPipelineBuildJob> Cannot queue pipeline build job %d", pbJob.ID)
+	}
+
+	pb, errLoad := LoadPipelineBuildByID(db, pbJob.PipelineBuildID)
+	if errLoad != nil {
+		return sdk.WrapError(errLoad, "RetryPipelineBuildJob> Cannot load pipeline build %d", pbJob.PipelineBuildID)
+	}
+	event.PublishActionBuild(pb, pbJob)
+	return nil
+}
+
+// IsRetryPending returns true if the failed job will be queued again by its retry policy
+func IsRetryPending(pbJob *sdk.PipelineBuildJob) bool {
+	return pbJob.Status == sdk.StatusFail.String() && pbJob.Job.Retry.ShouldRetry(pbJob.Job.FailureClass, pbJob.Job.Retries+1)
+}
+
+// WorkerLostPipelineBuildJob handles the job of a worker which disappeared while building it.
+// A job whose retry policy handles lost workers fails and is retried by its policy, other jobs are restarted
+func WorkerLostPipelineBuildJob(db gorp.SqlExecutor, pbJobID int64, workerName string) error {
+	pbJob, err := GetPipelineBuildJobForUpdate(db, pbJobID)
+	if err != nil {
+		return sdk.WrapError(err, "WorkerLostPipelineBuildJob> Cannot load pipeline build job %d", pbJobID)
+	}
+	if pbJob.Job.Retry == nil || !pbJob.Job.Retry.Handles(sdk.FailureWorkerLost) {
+		return RestartPipelineBuildJob(db, pbJobID)
+	}
+
+	pbJob.Job.FailureClass = sdk.FailureWorkerLost
+	pbJob.Job.Reason = fmt.Sprintf("Worker %s has been lost while building", workerName)
+	return UpdatePipelineBuildJobStatus(db, pbJob, sdk.StatusFail)
+}
+
+// failOnSpawnError fails a waiting job when a hatchery cannot spawn a worker for it, if its retry policy handles
+// spawn errors. Without such a policy, the job stays in queue and the hatcheries try to spawn a worker again
+func failOnSpawnError(db gorp.SqlExecutor, pbJob *sdk.PipelineBuildJob, infos []sdk.SpawnInfo) (bool, error) {
+	if pbJob.Status != sdk.StatusWaiting.String() || !pbJob.Job.Retry.Handles(sdk.FailureSpawnError) {
+		return false, nil
+	}
+	for _, info := range infos {
+		if info.Message.ID != sdk.MsgSpawnInfoHatcheryErrorSpawn.ID {
+			continue
+		}
+		pbJob.Job.FailureClass = sdk.FailureSpawnError
+		pbJob.Job.Reason = "Worker could not be spawned"
+		if err := UpdatePipelineBuildJobStatus(db, pbJob, sdk.StatusFail); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func insertPipelineBuildJobAttempt(db gorp.SqlExecutor, pbJob *sdk.PipelineBuildJob, a sdk.JobAttempt) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return sdk.WrapError(err, "insertPipelineBuildJobAttempt> Cannot marshal attempt %d of pipeline build job %d", a.Attempt, pbJob.ID)
+	}
+	query := `INSERT INTO pipeline_build_job_attempt (pipeline_build_id, pipeline_build_job_id, attempt, data) VALUES ($1, $2, $3, $4)`
+	if _, err := db.Exec(query, pbJob.PipelineBuildID, pbJob.ID, a.Attempt, data); err != nil {
+		return sdk.WrapError(err, "insertPipelineBuildJobAttempt> Cannot insert attempt %d of pipeline build job %d", a.Attempt, pbJob.ID)
+	}
+	return nil
+}
+
+// LoadPipelineBuildJobAttempts loads the previous failed attempts of a job
+func LoadPipelineBuildJobAttempts(db gorp.SqlExecutor, pbJobID int64) ([]sdk.JobAttempt, error) {
+	query := `SELECT data FROM pipeline_build_job_attempt WHERE pipeline_build_job_id = $1 ORDER BY attempt ASC`
+	rows, err := db.Query(query, pbJobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []sdk.JobAttempt
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var a sdk.JobAttempt
+		if err := json.Unmarshal(data, &a); err != nil {
+			return nil, sdk.WrapError(err, "LoadPipelineBuildJobAttempts> Cannot unmarshal attempt of pipeline build job %d", pbJobID)
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, nil
+}
+
+// DeletePipelineBuildJobAttempts deletes the previous attempts of a job
+func DeletePipelineBuildJobAttempts(db gorp.SqlExecutor, pbJobID int64) error {
+	query := `DELETE FROM pipeline_build_job_attempt WHERE pipeline_build_job_id = $1`
+	_, err := db.Exec(query, pbJobID)
+	return err
+}
+
+// DeletePipelineBuildJobAttemptsByPipelineBuildID deletes the previous attempts of all jobs of a build
+func DeletePipelineBuildJobAttemptsByPipelineBuildID(db gorp.SqlExecutor, pbID int64) error {
+	query := `DELETE FROM pipeline_build_job_attempt WHERE pipeline_build_id = $1`
+	_, err := db.Exec(query, pbID)
+	return err
+}
@@ -0,0 +1,114 @@
+package pipeline
+
+import (
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// timeoutGracePeriod lets the worker report a timed out job by itself before the API fails it
+var timeoutGracePeriod = time.Minute
+
+// stopGracePeriod lets the worker of a stopped job kill its running step and report the job result before it is disabled
+var stopGracePeriod = time.Minute
+
+// JobTimeoutKiller fails building jobs which have been running for longer than their timeout,
+// and disables the workers which did not acknowledge the stop of their job
+func JobTimeoutKiller(DBFunc func() *gorp.DbMap) {
+	defer log.Error("pipeline.JobTimeoutKiller> has been exited !")
+
+	for {
+		time.Sleep(1 * time.Minute)
+		db := DBFunc()
+		if db == nil {
+			continue
+		}
+
+		if err := DisableStoppedJobWorkers(db, time.Now().Add(-stopGracePeriod)); err != nil {
+			log.Warning("JobTimeoutKiller> %s", err)
+		}
+
+		ids, err := loadTimedOutPipelineBuildJobs(db, time.Now())
+		if err != nil {
+			log.Warning("JobTimeoutKiller> Cannot load timed out jobs: %s", err)
+			continue
+		}
+
+		for _, id := range ids {
+			if err := killTimedOutPipelineBuildJob(db, id); err != nil {
+				log.Warning("JobTimeoutKiller> Cannot kill pipeline build job %d: %s", id, err)
+			}
+		}
+	}
+}
+
+// loadTimedOutPipelineBuildJobs returns the ids of building jobs which exceeded their timeout at the given time
+func loadTimedOutPipelineBuildJobs(db gorp.SqlExecutor, now time.Time) ([]int64, error) {
+	var pbJobs []PipelineBuildJob
+	if _, err := db.Select(&pbJobs, `SELECT * FROM pipeline_build_job WHERE status = $1`, sdk.StatusBuilding.String()); err != nil {
+		return nil, sdk.WrapError(err, "loadTimedOutPipelineBuildJobs> Cannot load building jobs")
+	}
+
+	ids := []int64{}
+	for _, j := range pbJobs {
+		if isTimedOut(sdk.PipelineBuildJob(j), now) {
+			ids = append(ids, j.ID)
+		}
+	}
+	return ids, nil
+}
+
+func isTimedOut(pbJob sdk.PipelineBuildJob, now time.Time) bool {
+	if pbJob.Job.Timeout <= 0 || pbJob.Start.IsZero() {
+		return false
+	}
+	return now.Sub(pbJob.Start) > time.Duration(pbJob.Job.Timeout)*time.Second+timeoutGracePeriod
+}
+
+func killTimedOutPipelineBuildJob(db *gorp.DbMap, id int64) error {
+	tx, errb := db.Begin()
+	if errb != nil {
+		return sdk.WrapError(errb, "killTimedOutPipelineBuildJob> cannot begin transaction")
+	}
+	defer tx.Rollback()
+
+	pbJob, err := GetPipelineBuildJobForUpdate(tx, id)
+	if err != nil {
+		return sdk.WrapError(err, "killTimedOutPipelineBuildJob> Cannot load pipeline build job")
+	}
+	if pbJob.Status != sdk.StatusBuilding.String() {
+		return nil
+	}
+	log.Warning("killTimedOutPipelineBuildJob> Killing pipeline build job %d after %ds", id, pbJob.Job.Timeout)
+
+	if err := setTimedOut(pbJob); err != nil {
+		return err
+	}
+	if err := UpdatePipelineBuildJobStatus(tx, pbJob, sdk.StatusFail); err != nil {
+		return err
+	}
+
+	query := `UPDATE worker SET status = $1, action_build_id = NULL WHERE action_build_id = $2`
+	if _, err := tx.Exec(query, string(sdk.StatusDisabled), id); err != nil {
+		return sdk.WrapError(err, "killTimedOutPipelineBuildJob> Cannot release worker of pipeline build job %d", id)
+	}
+
+	return tx.Commit()
+}
+
+// setTimedOut records the timeout of the job before it fails, its failure class lets its retry policy retry it
+func setTimedOut(pbJob *sdk.PipelineBuildJob) error {
+	timeout := (time.Duration(pbJob.Job.Timeout) * time.Second).String()
+	if err := prepareSpawnInfos(pbJob, []sdk.SpawnInfo{{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobTimeout.ID, Args: []interface{}{timeout}},
+	}}); err != nil {
+		return err
+	}
+	pbJob.Job.Reason = "Killed (Reason: job timeout of " + timeout + " exceeded)\n"
+	pbJob.Job.FailureClass = sdk.FailureTimeout
+	return nil
+}
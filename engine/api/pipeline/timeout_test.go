@@ -0,0 +1,35 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSetTimedOutRetry(t *testing.T) {
+	tests := []struct {
+		retry *sdk.JobRetry
+		want  bool
+	}{
+		{&sdk.JobRetry{MaxAttempts: 2, On: []string{sdk.FailureTimeout}}, true},
+		{&sdk.JobRetry{MaxAttempts: 2}, true},
+		{&sdk.JobRetry{MaxAttempts: 2, On: []string{sdk.FailureWorkerLost}}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		pbJob := &sdk.PipelineBuildJob{ID: 1, Status: sdk.StatusBuilding.String()}
+		pbJob.Job.Timeout = 60
+		pbJob.Job.Retry = tt.retry
+
+		assert.NoError(t, setTimedOut(pbJob))
+		assert.Equal(t, sdk.FailureTimeout, pbJob.Job.FailureClass)
+		assert.Len(t, pbJob.SpawnInfos, 1)
+		assert.Equal(t, sdk.MsgSpawnInfoJobTimeout.ID, pbJob.SpawnInfos[0].Message.ID)
+
+		// The job is failed by UpdatePipelineBuildJobStatus, then its retry policy decides
+		pbJob.Status = sdk.StatusFail.String()
+		assert.Equal(t, tt.want, IsRetryPending(pbJob), "retry policy %+v", tt.retry)
+	}
+}
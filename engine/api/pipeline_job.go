@@ -34,6 +34,18 @@ func addJobToStageHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap
 		return err
 	}
 
+	if _, err := sdk.ExpandMatrix(job.Matrix); err != nil {
+		return err
+	}
+
+	if err := sdk.CheckJobOutputs(job.Outputs); err != nil {
+		return sdk.NewError(sdk.ErrInvalidJobOutput, err)
+	}
+
+	if err := sdk.CheckJobRetry(job.Retry); err != nil {
+		return sdk.NewError(sdk.ErrInvalidJobRetry, err)
+	}
+
 	pip, err := pipeline.LoadPipeline(db, projectKey, pipelineName, false)
 	if err != nil {
 		log.Warning("addJobToStageHandler> Cannot load pipeline %s for project %s: %s\n", pipelineName, projectKey, err)
@@ -127,6 +139,18 @@ func updateJobHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 		return err
 	}
 
+	if _, err := sdk.ExpandMatrix(job.Matrix); err != nil {
+		return err
+	}
+
+	if err := sdk.CheckJobOutputs(job.Outputs); err != nil {
+		return sdk.NewError(sdk.ErrInvalidJobOutput, err)
+	}
+
+	if err := sdk.CheckJobRetry(job.Retry); err != nil {
+		return sdk.NewError(sdk.ErrInvalidJobRetry, err)
+	}
+
 	if jobID != job.PipelineActionID {
 		log.Warning("updateJobHandler>Pipeline action does not match: %s\n", err)
 		return err
@@ -49,11 +49,11 @@ func DeleteAll(db gorp.SqlExecutor, appID int64) error {
 func Update(db gorp.SqlExecutor, poller *sdk.RepositoryPoller) error {
 	query := `
         UPDATE  poller 
-        SET enabled = $3, name = $4
+        SET enabled = $3, name = $4, pull_requests = $5
         WHERE application_id = $1
         AND pipeline_id  = $2
     `
-	if _, err := db.Exec(query, poller.Application.ID, poller.Pipeline.ID, poller.Enabled, poller.Name); err != nil {
+	if _, err := db.Exec(query, poller.Application.ID, poller.Pipeline.ID, poller.Enabled, poller.Name, poller.PullRequests); err != nil {
 		log.Warning("UpdatePoller> Error :%s", err)
 		return err
 	}
@@ -93,7 +93,7 @@ func LoadEnabled(db gorp.SqlExecutor) ([]sdk.RepositoryPoller, error) {
 //LoadEnabledByProject load all RepositoryPoller for a project
 func LoadEnabledByProject(db gorp.SqlExecutor, projKey string) ([]sdk.RepositoryPoller, error) {
 	query := `
-        SELECT poller.application_id, poller.pipeline_id, poller.name, poller.enabled, poller.date_creation
+        SELECT poller.application_id, poller.pipeline_id, poller.name, poller.enabled, poller.date_creation, poller.pull_requests
         FROM poller, application, project
         WHERE poller.application_id = application.id
 		AND application.project_id = project.id
@@ -116,7 +116,7 @@ func LoadEnabledByProject(db gorp.SqlExecutor, projKey string) ([]sdk.Repository
 //LoadByApplication loads all pollers for an application
 func LoadByApplication(db gorp.SqlExecutor, applicationID int64) ([]sdk.RepositoryPoller, error) {
 	query := `
-        SELECT application_id, pipeline_id, name, enabled, date_creation
+        SELECT application_id, pipeline_id, name, enabled, date_creation, pull_requests
         FROM poller
         WHERE application_id = $1
     `
@@ -136,7 +136,7 @@ func LoadByApplication(db gorp.SqlExecutor, applicationID int64) ([]sdk.Reposito
 //LoadByApplicationAndPipeline loads the poller for an application/pipeline
 func LoadByApplicationAndPipeline(db gorp.SqlExecutor, applicationID, pipelineID int64) (*sdk.RepositoryPoller, error) {
 	query := `
-        SELECT application_id, pipeline_id, name, enabled, date_creation
+        SELECT application_id, pipeline_id, name, enabled, date_creation, pull_requests
         FROM poller
         WHERE application_id = $1
 		AND pipeline_id = $2
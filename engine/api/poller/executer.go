@@ -3,6 +3,7 @@ package poller
 import (
 	"database/sql"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -153,7 +154,7 @@ func executerProcess(tx gorp.SqlExecutor, p *sdk.RepositoryPoller, e *sdk.Reposi
 	}
 
 	var pbs []sdk.PipelineBuild
-	if len(e.PushEvents) > 0 {
+	if len(e.PushEvents) > 0 || (p.PullRequests && len(e.PullRequestEvents) > 0) {
 		var err error
 		pbs, err = triggerPipelines(tx, projectKey, rm, p, e)
 		if err != nil {
@@ -188,7 +189,7 @@ func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.Repositori
 
 		if pb != nil {
 			log.Debug("Polling.triggerPipelines> Triggered %s/%s/%s : %s", projectKey, poller.Application.RepositoryFullname, event.Branch, event.Commit.Hash)
-			e.PipelineBuildVersions[event.Branch.ID+"/"+event.Commit.Hash[:7]] = pb.Version
+			e.PipelineBuildVersions[event.Branch.ID+"/"+shortHash(event.Commit.Hash)] = pb.Version
 			pbs = append(pbs, *pb)
 		}
 	}
@@ -202,7 +203,7 @@ func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.Repositori
 
 		if pb != nil {
 			log.Debug("Polling.triggerPipelines> Triggered %s/%s/%s : %s", projectKey, poller.Application.RepositoryFullname, event.Branch, event.Commit.Hash)
-			e.PipelineBuildVersions[event.Branch.ID+"/"+event.Commit.Hash[:7]] = pb.Version
+			e.PipelineBuildVersions[event.Branch.ID+"/"+shortHash(event.Commit.Hash)] = pb.Version
 			pbs = append(pbs, *pb)
 		}
 	}
@@ -216,27 +217,39 @@ func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.Repositori
 		}
 	}
 
+	if poller.PullRequests {
+		for _, event := range e.PullRequestEvents {
+			switch event.Action {
+			case "opened", "synchronize":
+				pb, err := triggerPullRequestPipeline(tx, poller, event, proj)
+				if err != nil {
+					log.Error("Polling.triggerPipelines> cannot trigger pipeline %d for pull request %d: %s\n", poller.Pipeline.ID, event.Number, err)
+					return nil, err
+				}
+
+				if pb != nil {
+					log.Debug("Polling.triggerPipelines> Triggered %s/%s/%s : %s", projectKey, poller.Application.RepositoryFullname, event.MergeRef, event.Head.Commit.Hash)
+					e.PipelineBuildVersions[event.MergeRef+"/"+shortHash(event.Head.Commit.Hash)] = pb.Version
+					pbs = append(pbs, *pb)
+				}
+			case "closed":
+				//Stop queued and building pipelines of the pull request, then remove its builds
+				if err := pipeline.DeleteBranchBuilds(tx, poller.Application.ID, event.MergeRef); err != nil {
+					if err != sql.ErrNoRows {
+						log.Error("Polling.triggerPipelines> cannot delete pipeline build for pull request %d: %s", event.Number, err)
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+
 	log.Debug("Polling.triggerPipelines> %d pipelines triggered", len(pbs))
 
 	return pbs, nil
 }
 
 func triggerPipeline(tx gorp.SqlExecutor, rm *sdk.RepositoriesManager, poller *sdk.RepositoryPoller, e sdk.VCSPushEvent, proj *sdk.Project) (*sdk.PipelineBuild, error) {
-	// Create pipeline args
-	var params []sdk.Parameter
-
-	// Load pipeline Argument
-	parameters, err := pipeline.GetAllParametersInPipeline(tx, poller.Pipeline.ID)
-	if err != nil {
-		return nil, err
-	}
-	poller.Pipeline.Parameter = parameters
-
-	applicationPipelineArgs, err := application.GetAllPipelineParam(tx, poller.Application.ID, poller.Pipeline.ID)
-	if err != nil {
-		return nil, err
-	}
-
 	trigger := sdk.PipelineBuildTrigger{
 		ManualTrigger:    false,
 		VCSChangesBranch: e.Branch.ID,
@@ -254,6 +267,48 @@ func triggerPipeline(tx gorp.SqlExecutor, rm *sdk.RepositoriesManager, poller *s
 		return nil, nil
 	}
 
+	return runPipeline(tx, poller, proj, nil, trigger)
+}
+
+//triggerPullRequestPipeline runs the pipeline on the merge ref of the pull request.
+//The build hash is the head of the pull request, so the status is reported on it
+func triggerPullRequestPipeline(tx gorp.SqlExecutor, poller *sdk.RepositoryPoller, e sdk.VCSPullRequestEvent, proj *sdk.Project) (*sdk.PipelineBuild, error) {
+	if e.MergeRef == "" || e.Head.Commit.Hash == "" {
+		log.Warning("polling> Skipping pull request %d of %s/%s: unknown ref\n", e.Number, proj.Key, poller.Application.Name)
+		return nil, nil
+	}
+
+	params := []sdk.Parameter{}
+	sdk.AddParameter(&params, "cds.pr.number", sdk.StringParameter, strconv.Itoa(e.Number))
+	sdk.AddParameter(&params, "cds.pr.url", sdk.StringParameter, e.URL)
+	sdk.AddParameter(&params, "cds.pr.author", sdk.StringParameter, e.User.Name)
+	sdk.AddParameter(&params, "cds.pr.source_branch", sdk.StringParameter, e.Head.Branch.DisplayID)
+	sdk.AddParameter(&params, "cds.pr.target_branch", sdk.StringParameter, e.Base.Branch.DisplayID)
+	sdk.AddParameter(&params, "cds.pr.head_hash", sdk.StringParameter, e.Head.Commit.Hash)
+
+	trigger := sdk.PipelineBuildTrigger{
+		ManualTrigger:    false,
+		VCSChangesBranch: e.MergeRef,
+		VCSChangesHash:   e.Head.Commit.Hash,
+		VCSChangesAuthor: e.User.Name,
+	}
+
+	return runPipeline(tx, poller, proj, params, trigger)
+}
+
+func runPipeline(tx gorp.SqlExecutor, poller *sdk.RepositoryPoller, proj *sdk.Project, params []sdk.Parameter, trigger sdk.PipelineBuildTrigger) (*sdk.PipelineBuild, error) {
+	// Load pipeline Argument
+	parameters, err := pipeline.GetAllParametersInPipeline(tx, poller.Pipeline.ID)
+	if err != nil {
+		return nil, err
+	}
+	poller.Pipeline.Parameter = parameters
+
+	applicationPipelineArgs, err := application.GetAllPipelineParam(tx, poller.Application.ID, poller.Pipeline.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	//Check if build exists
 	if b, err := pipeline.BuildExists(tx, poller.Application.ID, poller.Pipeline.ID, sdk.DefaultEnv.ID, &trigger); err != nil || b {
 		if err != nil {
@@ -270,3 +325,11 @@ func triggerPipeline(tx gorp.SqlExecutor, rm *sdk.RepositoriesManager, poller *s
 
 	return pb, nil
 }
+
+//shortHash returns the first 7 characters of a commit hash, the hash given by the repositories manager may be shorter or empty
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -14,3 +14,17 @@ func TestExecuterRun(t *testing.T) {
 	}
 	t.Logf("Has execute %v", exs)
 }
+
+func TestShortHash(t *testing.T) {
+	tests := map[string]string{
+		"":        "",
+		"abc":     "abc",
+		"abcdef1": "abcdef1",
+		"abcdef1234567890abcdef1234567890abcdef12": "abcdef1",
+	}
+	for hash, want := range tests {
+		if got := shortHash(hash); got != want {
+			t.Errorf("shortHash(%q) = %q, want %q", hash, got, want)
+		}
+	}
+}
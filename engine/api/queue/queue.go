@@ -39,6 +39,14 @@ func Pipelines() {
 
 		db := database.DBMap(database.DB())
 		if db != nil && !m {
+			pendingIDs, err := pipeline.LoadPendingPipelinesIDs(db)
+			if err != nil {
+				log.Warning("queue.Pipelines> Cannot load pending pipelines: %s\n", err)
+			}
+			for _, id := range pendingIDs {
+				startPendingPipeline(db, id)
+			}
+
 			ids, err := pipeline.LoadBuildingPipelinesIDs(db)
 			if err != nil {
 				log.Warning("queue.Pipelines> Cannot load building pipelines: %s\n", err)
@@ -100,6 +108,10 @@ func runPipeline(db *gorp.DbMap, pbID int64) {
 		stage := &pb.Stages[stageIndex]
 
 		if stage.Status == sdk.StatusWaiting {
+			if waitApproval(stage, pb) {
+				pbNewStatus = sdk.StatusWaitingApproval
+				break
+			}
 			if err := addJobsToQueue(tx, stage, pb); err != nil {
 				log.Warning("queue.RunActions> Cannot add job to queue: %s", err)
 				return
@@ -121,9 +133,9 @@ func runPipeline(db *gorp.DbMap, pbID int64) {
 					return
 				}
 
-				if stage.Status == sdk.StatusFail {
+				if stage.Status == sdk.StatusFail || stage.Status == sdk.StatusStopped {
 					pb.Done = time.Now()
-					pbNewStatus = sdk.StatusFail
+					pbNewStatus = stage.Status
 					break
 				}
 				if stageIndex == len(pb.Stages)-1 {
@@ -159,6 +171,70 @@ func runPipeline(db *gorp.DbMap, pbID int64) {
 	}
 }
 
+// startPendingPipeline starts a pipeline build held by its concurrency group
+// when the builds started before it in the group are done
+func startPendingPipeline(db *gorp.DbMap, pbID int64) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Warning("queue.startPendingPipeline> cannot start tx for pb %d: %s\n", pbID, err)
+		return
+	}
+	defer tx.Rollback()
+
+	if err := pipeline.SelectBuildPendingForUpdate(tx, pbID); err != nil {
+		// if ErrNoRows, pipeline has been started or stopped
+		if err == sql.ErrNoRows {
+			return
+		}
+		pqerr, ok := err.(*pq.Error)
+		// Cannot get lock (FOR UPDATE NOWAIT), someone else is on it
+		if ok && pqerr.Code == "55P03" {
+			return
+		}
+		log.Warning("queue.startPendingPipeline> Cannot load pb: %s\n", err)
+		return
+	}
+
+	pb, err := pipeline.LoadPipelineBuildByID(tx, pbID)
+	if err != nil {
+		log.Warning("queue.startPendingPipeline> Cannot load pb [%d]: %s\n", pbID, err)
+		return
+	}
+
+	nb, err := pipeline.CountBuildsBefore(tx, pb.ConcurrencyKey, pb.ID)
+	if err != nil {
+		log.Warning("queue.startPendingPipeline> Cannot count builds of concurrency group %s: %s\n", pb.ConcurrencyKey, err)
+		return
+	}
+	if nb > 0 {
+		return
+	}
+
+	if err := pipeline.StartPendingPipelineBuild(tx, pb); err != nil {
+		log.Warning("queue.startPendingPipeline> Cannot start pb %d: %s\n", pb.ID, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Warning("queue.startPendingPipeline> Cannot commit tx on pb %d: %s\n", pb.ID, err)
+	}
+}
+
+// waitApproval puts the stage in waiting approval if it has an approval gate not yet approved.
+// Disabled stages and stages with unmatched prerequisites are not gated, their jobs are not run anyway
+func waitApproval(stage *sdk.Stage, pb *sdk.PipelineBuild) bool {
+	if !stage.Enabled || stage.Approval == nil || stage.Approval.Approved() {
+		return false
+	}
+	prerequisitesOK, err := pipeline.CheckPrerequisites(*stage, pb)
+	if err != nil || !prerequisitesOK {
+		return false
+	}
+	stage.Status = sdk.StatusWaitingApproval
+	stage.Approval.Start = time.Now()
+	return true
+}
+
 func addJobsToQueue(tx gorp.SqlExecutor, stage *sdk.Stage, pb *sdk.PipelineBuild) error {
 	//Check stage prerequisites
 	prerequisitesOK, err := pipeline.CheckPrerequisites(*stage, pb)
@@ -173,34 +249,74 @@ func addJobsToQueue(tx gorp.SqlExecutor, stage *sdk.Stage, pb *sdk.PipelineBuild
 		if errParam != nil {
 			return errParam
 		}
-		pbJob := sdk.PipelineBuildJob{
-			PipelineBuildID: pb.ID,
-			Parameters:      pbJobParams,
-			Job: sdk.ExecutedJob{
-				Job: job,
-			},
-			Queued: time.Now(),
-			Status: sdk.StatusWaiting.String(),
-			Start:  time.Now(),
-		}
 
-		if !stage.Enabled || !pbJob.Job.Enabled {
-			pbJob.Status = sdk.StatusDisabled.String()
-		} else if !prerequisitesOK {
-			pbJob.Status = sdk.StatusSkipped.String()
-		}
-		if err := pipeline.InsertPipelineBuildJob(tx, &pbJob); err != nil {
-			log.Warning("addJobToQueue> Cannot insert job in queue for pipeline build %d: %s\n", pb.ID, err)
-			return err
+		executedJobs, errM := expandJob(job)
+		if errM != nil {
+			log.Warning("addJobsToQueue> Cannot expand matrix of job %s on pipeline %s(%d): %s\n", job.Action.Name, pb.Pipeline.Name, pb.ID, errM)
+			return errM
 		}
 
-		event.PublishActionBuild(pb, &pbJob)
-		stage.PipelineBuildJobs = append(stage.PipelineBuildJobs, pbJob)
+		for _, executedJob := range executedJobs {
+			pbJob := sdk.PipelineBuildJob{
+				PipelineBuildID: pb.ID,
+				Parameters:      pbJobParams,
+				Job:             executedJob,
+				Queued:          time.Now(),
+				Status:          sdk.StatusWaiting.String(),
+				Start:           time.Now(),
+			}
+			if executedJob.Matrix != nil {
+				pbJob.Parameters = append(append([]sdk.Parameter{}, pbJobParams...), executedJob.Matrix.Parameters()...)
+			}
+
+			if !stage.Enabled || !pbJob.Job.Enabled {
+				pbJob.Status = sdk.StatusDisabled.String()
+			} else if !prerequisitesOK {
+				pbJob.Status = sdk.StatusSkipped.String()
+			}
+			if err := pipeline.InsertPipelineBuildJob(tx, &pbJob); err != nil {
+				log.Warning("addJobToQueue> Cannot insert job in queue for pipeline build %d: %s\n", pb.ID, err)
+				return err
+			}
+
+			event.PublishActionBuild(pb, &pbJob)
+			stage.PipelineBuildJobs = append(stage.PipelineBuildJobs, pbJob)
+		}
 	}
 
 	return nil
 }
 
+// expandJob returns one executed job per cell of the job matrix, or the job itself if there is no matrix.
+// Each cell is named after its values, and runs on the worker model of the cell if any
+func expandJob(job sdk.Job) ([]sdk.ExecutedJob, error) {
+	cells, err := sdk.ExpandMatrix(job.Matrix)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) == 0 {
+		return []sdk.ExecutedJob{{Job: job}}, nil
+	}
+
+	jobs := make([]sdk.ExecutedJob, 0, len(cells))
+	for i := range cells {
+		cell := cells[i]
+		j := job
+		j.Action.Name = fmt.Sprintf("%s (%s)", job.Action.Name, cell)
+		if cell.Model != "" {
+			j.Action.Requirements = []sdk.Requirement{}
+			for _, r := range job.Action.Requirements {
+				if r.Type != sdk.ModelRequirement {
+					j.Action.Requirements = append(j.Action.Requirements, r)
+				}
+			}
+			j.Action.Requirements = append(j.Action.Requirements, sdk.Requirement{Name: cell.Model, Type: sdk.ModelRequirement, Value: cell.Model})
+		}
+		jobs = append(jobs, sdk.ExecutedJob{Job: j, Matrix: &cell})
+	}
+	return jobs, nil
+}
+
 func syncPipelineBuildJob(db gorp.SqlExecutor, stage *sdk.Stage) (bool, error) {
 	stageEnd := true
 	finalStatus := sdk.StatusBuilding
@@ -215,6 +331,18 @@ func syncPipelineBuildJob(db gorp.SqlExecutor, stage *sdk.Stage) (bool, error) {
 				return stageEnd, errJob
 			}
 
+			// A failed job with a retry policy is queued again once its backoff is elapsed
+			if pipeline.IsRetryPending(pbJobDB) {
+				stageEnd = false
+				if err := pipeline.RetryPipelineBuildJob(db, pbJobDB, time.Now()); err != nil {
+					return stageEnd, err
+				}
+				pbJob.Status = sdk.StatusWaiting.String()
+				pbJob.SpawnInfos = pbJobDB.SpawnInfos
+				pbJob.Job = pbJobDB.Job
+				continue
+			}
+
 			if pbJobDB.Status == sdk.StatusBuilding.String() || pbJobDB.Status == sdk.StatusWaiting.String() {
 				stageEnd = false
 			}
@@ -230,6 +358,7 @@ func syncPipelineBuildJob(db gorp.SqlExecutor, stage *sdk.Stage) (bool, error) {
 				pbJob.Done = pbJobDB.Done
 				pbJob.Model = pbJobDB.Model
 				pbJob.Job = pbJobDB.Job
+				pbJob.Outputs = pbJobDB.Outputs
 			}
 		}
 	}
@@ -253,8 +382,19 @@ func syncPipelineBuildJob(db gorp.SqlExecutor, stage *sdk.Stage) (bool, error) {
 					finalStatus = sdk.StatusSkipped
 				}
 			case sdk.StatusFail.String():
+				// Failures of matrix cells allowed to fail don't fail the stage, they don't override
+				// the status of disabled or skipped jobs either
+				if buildJob.Job.Matrix != nil && buildJob.Job.Matrix.AllowFailure {
+					if finalStatus == sdk.StatusBuilding {
+						finalStatus = sdk.StatusSuccess
+					}
+					continue
+				}
 				finalStatus = sdk.StatusFail
 				break finalStageLoop
+			case sdk.StatusStopped.String():
+				finalStatus = sdk.StatusStopped
+				break finalStageLoop
 			case sdk.StatusSuccess.String():
 				if finalStatus != sdk.StatusFail {
 					finalStatus = sdk.StatusSuccess
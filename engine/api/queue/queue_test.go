@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_waitApproval(t *testing.T) {
+	pb := &sdk.PipelineBuild{
+		Parameters: []sdk.Parameter{{Name: "git.branch", Value: "master"}},
+	}
+
+	tests := []struct {
+		name  string
+		stage sdk.Stage
+		wait  bool
+	}{
+		{
+			name:  "no approval",
+			stage: sdk.Stage{Enabled: true, Status: sdk.StatusWaiting},
+		},
+		{
+			name:  "approval not decided",
+			stage: sdk.Stage{Enabled: true, Status: sdk.StatusWaiting, Approval: &sdk.StageApproval{Groups: []string{"ops"}}},
+			wait:  true,
+		},
+		{
+			name: "approval granted",
+			stage: sdk.Stage{Enabled: true, Status: sdk.StatusWaiting, Approval: &sdk.StageApproval{
+				Groups:    []string{"ops"},
+				Decisions: []sdk.ApprovalDecision{{User: "john", Approved: true}},
+			}},
+		},
+		{
+			name:  "disabled stage",
+			stage: sdk.Stage{Enabled: false, Status: sdk.StatusWaiting, Approval: &sdk.StageApproval{Groups: []string{"ops"}}},
+		},
+		{
+			name: "unmatched prerequisites",
+			stage: sdk.Stage{Enabled: true, Status: sdk.StatusWaiting, Approval: &sdk.StageApproval{Groups: []string{"ops"}},
+				Prerequisites: []sdk.Prerequisite{{Parameter: "git.branch", ExpectedValue: "release"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		stage := tt.stage
+		assert.Equal(t, tt.wait, waitApproval(&stage, pb), tt.name)
+		if tt.wait {
+			assert.Equal(t, sdk.StatusWaitingApproval, stage.Status, tt.name)
+			assert.False(t, stage.Approval.Start.IsZero(), tt.name)
+		} else {
+			assert.Equal(t, sdk.StatusWaiting, stage.Status, tt.name)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/context"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/hatchery"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/worker"
+	"github.com/ovh/cds/sdk"
+)
+
+// getPipelineBuildJobExplainHandler evaluates every registered hatchery and model pair against a queued job,
+// as the hatcheries would do, and returns the reasons why they cannot spawn a worker for it
+func getPipelineBuildJobExplainHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	id, errc := requestVarInt(r, "id")
+	if errc != nil {
+		return sdk.WrapError(errc, "getPipelineBuildJobExplainHandler> invalid id")
+	}
+
+	// Only jobs of the queue of the user can be explained
+	if c.User == nil {
+		return sdk.WrapError(sdk.ErrForbidden, "getPipelineBuildJobExplainHandler> only users can explain a job")
+	}
+	queue, errq := pipeline.LoadUserWaitingQueue(db, c.User)
+	if errq != nil {
+		return sdk.WrapError(errq, "getPipelineBuildJobExplainHandler> Cannot load queue")
+	}
+	var job *sdk.PipelineBuildJob
+	for i := range queue {
+		if queue[i].ID == id {
+			job = &queue[i]
+			break
+		}
+	}
+	if job == nil {
+		return sdk.WrapError(sdk.ErrNotFound, "getPipelineBuildJobExplainHandler> Job %d is not in the queue", id)
+	}
+
+	hatcheries, errh := hatchery.LoadHatcheries(db)
+	if errh != nil {
+		return sdk.WrapError(errh, "getPipelineBuildJobExplainHandler> Cannot load hatcheries")
+	}
+
+	explanation := sdk.JobExplanation{
+		JobID:         job.ID,
+		JobName:       job.Job.Action.Name,
+		QueuedSeconds: job.QueuedSeconds,
+		BookedBy:      job.BookedBy.Name,
+		Spawns:        []sdk.SpawnExplanation{},
+	}
+
+	groupAccess := map[int64]bool{}
+	for _, h := range hatcheries {
+		access, ok := groupAccess[h.GroupID]
+		if !ok {
+			var err error
+			if access, err = groupCanTakeJob(db, h.GroupID, job.ID); err != nil {
+				return sdk.WrapError(err, "getPipelineBuildJobExplainHandler> Cannot load queue of group %d", h.GroupID)
+			}
+			groupAccess[h.GroupID] = access
+		}
+
+		models, err := loadHatcheryModels(db, h)
+		if err != nil {
+			return sdk.WrapError(err, "getPipelineBuildJobExplainHandler> Cannot load models of hatchery %s", h.Name)
+		}
+
+		for i := range models {
+			m := &models[i]
+			if version := sdk.PinnedModelVersion(job.Job.Action.Requirements, m.Name); version != 0 && version != m.Version {
+				v, err := worker.LoadWorkerModelVersion(db, m.ID, version)
+				if err != nil && err != sdk.ErrNoWorkerModelVersion {
+					return sdk.WrapError(err, "getPipelineBuildJobExplainHandler> Cannot load version %d of model %s", version, m.Name)
+				}
+				// Hatcheries spawn the version pinned by the job
+				if v != nil {
+					pinned := m.WithVersion(*v)
+					m = &pinned
+				}
+			}
+
+			e := sdk.SpawnExplanation{
+				HatcheryID:   h.ID,
+				HatcheryName: h.Name,
+				ModelID:      m.ID,
+				ModelName:    m.Name,
+				ModelType:    m.Type,
+			}
+			if !access {
+				e.Rejections = append(e.Rejections, "the group of the hatchery cannot access the pipeline of the job")
+			}
+			e.Rejections = append(e.Rejections, sdk.CheckModelRequirements(m, job, hatcheryHostname(h, job))...)
+			e.CanSpawn = len(e.Rejections) == 0
+			explanation.Spawns = append(explanation.Spawns, e)
+		}
+	}
+
+	return WriteJSON(w, r, explanation, http.StatusOK)
+}
+
+// groupCanTakeJob returns true if the job is in the queue of the group
+func groupCanTakeJob(db gorp.SqlExecutor, groupID, jobID int64) (bool, error) {
+	queue, err := pipeline.LoadGroupWaitingQueue(db, groupID)
+	if err != nil {
+		return false, err
+	}
+	for _, j := range queue {
+		if j.ID == jobID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// loadHatcheryModels loads the models a hatchery can spawn: the models it reports in its status,
+// or all the models usable by its group for hatcheries which do not report their status.
+// As on spawn, only the models of the type of the hatchery are kept
+func loadHatcheryModels(db gorp.SqlExecutor, h sdk.Hatchery) ([]sdk.Model, error) {
+	all, err := worker.LoadWorkerModelsUsableOnGroup(db, h.GroupID, group.SharedInfraGroup.ID)
+	if err != nil {
+		return nil, err
+	}
+	usable := []sdk.Model{}
+	for _, m := range all {
+		if h.ModelType == "" || m.Type == h.ModelType {
+			usable = append(usable, m)
+		}
+	}
+
+	ids, err := hatchery.LoadModelIDsByHatchery(db, h.ID)
+	if err != nil {
+		return nil, err
+	}
+	if h.Model.ID != 0 {
+		ids = append(ids, h.Model.ID)
+	}
+	if len(ids) == 0 {
+		return usable, nil
+	}
+
+	models := []sdk.Model{}
+	for _, m := range usable {
+		if sdk.IsInArray(m.ID, ids) {
+			models = append(models, m)
+		}
+	}
+	return models, nil
+}
+
+// hatcheryHostname returns the hostname against which the hostname requirements of a job are checked.
+// The host is unknown to the API, but hatcheries are named after their host unless their name is set
+func hatcheryHostname(h sdk.Hatchery, job *sdk.PipelineBuildJob) string {
+	for _, r := range job.Job.Action.Requirements {
+		if r.Type == sdk.HostnameRequirement && (h.Name == r.Value || strings.HasPrefix(h.Name, r.Value+"-")) {
+			return r.Value
+		}
+	}
+	return h.Name
+}
@@ -0,0 +1,344 @@
+package repogitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// GiteaClient is a gitea wrapper for CDS RepositoriesManagerClient interface
+type GiteaClient struct {
+	URL              string
+	Token            string
+	DisableSetStatus bool
+	DisableStatusURL bool
+}
+
+func (g *GiteaClient) user() (User, error) {
+	u := User{}
+	status, body, _, err := g.get("/user")
+	if err != nil {
+		return u, err
+	}
+	if status >= 400 {
+		return u, sdk.NewError(sdk.ErrNoReposManagerClientAuth, ErrorAPI(body))
+	}
+	if err := json.Unmarshal(body, &u); err != nil {
+		return u, err
+	}
+	return u, nil
+}
+
+// Repos list repositories that are accessible to the authenticated user
+func (g *GiteaClient) Repos() ([]sdk.VCSRepo, error) {
+	repos := []Repository{}
+	err := g.getAll("/user/repos", func(body []byte) (int, error) {
+		nextRepos := []Repository{}
+		if err := json.Unmarshal(body, &nextRepos); err != nil {
+			log.Warning("GiteaClient.Repos> Unable to parse gitea repositories: %s", err)
+			return 0, err
+		}
+		repos = append(repos, nextRepos...)
+		return len(nextRepos), nil
+	})
+	if err != nil {
+		log.Warning("GiteaClient.Repos> Error %s", err)
+		return nil, err
+	}
+
+	responseRepos := []sdk.VCSRepo{}
+	for _, r := range repos {
+		responseRepos = append(responseRepos, r.toVCSRepo())
+	}
+	return responseRepos, nil
+}
+
+func (r Repository) toVCSRepo() sdk.VCSRepo {
+	return sdk.VCSRepo{
+		ID:           strconv.Itoa(r.ID),
+		Name:         r.Name,
+		Slug:         r.Name,
+		Fullname:     r.FullName,
+		URL:          r.HTMLURL,
+		HTTPCloneURL: r.CloneURL,
+		SSHCloneURL:  r.SSHURL,
+	}
+}
+
+// RepoByFullname Get only one repo
+func (g *GiteaClient) RepoByFullname(fullname string) (sdk.VCSRepo, error) {
+	r, err := g.repo(fullname)
+	if err != nil {
+		return sdk.VCSRepo{}, err
+	}
+	return r.toVCSRepo(), nil
+}
+
+func (g *GiteaClient) repo(fullname string) (Repository, error) {
+	r := Repository{}
+	cacheKey := cache.Key("reposmanager", "gitea", "repo", g.Token, fullname)
+	if cache.Get(cacheKey, &r) && r.ID != 0 {
+		return r, nil
+	}
+
+	status, body, _, err := g.get("/repos/" + fullname)
+	if err != nil {
+		log.Warning("GiteaClient.repo> Error %s", err)
+		return r, err
+	}
+	if status >= 400 {
+		return r, sdk.NewError(sdk.ErrRepoNotFound, ErrorAPI(body))
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		log.Warning("GiteaClient.repo> Unable to parse gitea repository: %s", err)
+		return r, err
+	}
+
+	//Put the repository on cache for one hour and one minute
+	cache.SetWithTTL(cacheKey, r, 61*60)
+	return r, nil
+}
+
+// Branches returns list of branches for a repo
+func (g *GiteaClient) Branches(fullname string) ([]sdk.VCSBranch, error) {
+	r, err := g.repo(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	branches, err := g.branches(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	branchesResult := []sdk.VCSBranch{}
+	for _, b := range branches {
+		branchesResult = append(branchesResult, b.toVCSBranch(r.DefaultBranch))
+	}
+	return branchesResult, nil
+}
+
+func (g *GiteaClient) branches(fullname string) ([]Branch, error) {
+	branches := []Branch{}
+	err := g.getAll("/repos/"+fullname+"/branches", func(body []byte) (int, error) {
+		nextBranches := []Branch{}
+		if err := json.Unmarshal(body, &nextBranches); err != nil {
+			log.Warning("GiteaClient.Branches> Unable to parse gitea branches: %s", err)
+			return 0, err
+		}
+		branches = append(branches, nextBranches...)
+		return len(nextBranches), nil
+	})
+	if err != nil {
+		log.Warning("GiteaClient.Branches> Error %s", err)
+		return nil, err
+	}
+	return branches, nil
+}
+
+func (b Branch) toVCSBranch(defaultBranch string) sdk.VCSBranch {
+	return sdk.VCSBranch{
+		ID:           b.Name,
+		DisplayID:    b.Name,
+		LatestCommit: b.Commit.ID,
+		Default:      b.Name == defaultBranch,
+	}
+}
+
+// Branch returns only detail of a branch
+func (g *GiteaClient) Branch(fullname, branch string) (sdk.VCSBranch, error) {
+	r, err := g.repo(fullname)
+	if err != nil {
+		return sdk.VCSBranch{}, err
+	}
+
+	status, body, _, err := g.get("/repos/" + fullname + "/branches/" + url.QueryEscape(branch))
+	if err != nil {
+		log.Warning("GiteaClient.Branch> Error %s", err)
+		return sdk.VCSBranch{}, err
+	}
+	if status == http.StatusNotFound {
+		return sdk.VCSBranch{}, sdk.ErrNoBranch
+	}
+	if status >= 400 {
+		return sdk.VCSBranch{}, sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+	}
+
+	b := Branch{}
+	if err := json.Unmarshal(body, &b); err != nil {
+		log.Warning("GiteaClient.Branch> Unable to parse gitea branch: %s", err)
+		return sdk.VCSBranch{}, err
+	}
+	return b.toVCSBranch(r.DefaultBranch), nil
+}
+
+// Commits returns the commits list on a branch between a commit SHA (since) until another commit SHA (until).
+// If since is empty, it returns all the commits of the branch until the commit SHA (until)
+func (g *GiteaClient) Commits(repo, theBranch, since, until string) ([]sdk.VCSCommit, error) {
+	var commitsResult []sdk.VCSCommit
+
+	log.Debug("GiteaClient.Commits> Looking for commits on repo %s since = %s until = %s", repo, since, until)
+	cacheKey := cache.Key("reposmanager", "gitea", "commits", g.URL, repo, "since="+since, "until="+until)
+	if cache.Get(cacheKey, &commitsResult) {
+		return commitsResult, nil
+	}
+
+	ref := until
+	if ref == "" {
+		ref = theBranch
+	}
+
+	var found bool
+	err := g.getAll("/repos/"+repo+"/commits?sha="+url.QueryEscape(ref), func(body []byte) (int, error) {
+		nextCommits := []Commit{}
+		if err := json.Unmarshal(body, &nextCommits); err != nil {
+			log.Warning("GiteaClient.Commits> Unable to parse gitea commits: %s", err)
+			return 0, err
+		}
+		for _, c := range nextCommits {
+			if since != "" && c.SHA == since {
+				found = true
+				return 0, nil
+			}
+			commitsResult = append(commitsResult, c.toVCSCommit())
+		}
+		return len(nextCommits), nil
+	})
+	if err != nil {
+		log.Warning("GiteaClient.Commits> Error %s", err)
+		return nil, err
+	}
+
+	if since != "" && !found {
+		log.Debug("GiteaClient.Commits> %s not found in %s history", since, ref)
+	}
+
+	cache.SetWithTTL(cacheKey, commitsResult, 3*60*60)
+
+	return commitsResult, nil
+}
+
+func (c Commit) toVCSCommit() sdk.VCSCommit {
+	commit := sdk.VCSCommit{
+		Hash:      c.SHA,
+		Message:   c.Commit.Message,
+		Timestamp: c.Commit.Author.Date.Unix() * 1000,
+		URL:       c.HTMLURL,
+		Author: sdk.VCSAuthor{
+			Name:        c.Commit.Author.Name,
+			DisplayName: c.Commit.Author.Name,
+			Email:       c.Commit.Author.Email,
+		},
+	}
+	if c.Author != nil {
+		commit.Author.Name = c.Author.Login
+		commit.Author.Avatar = c.Author.AvatarURL
+	}
+	return commit
+}
+
+// Commit Get a single commit
+func (g *GiteaClient) Commit(repo, hash string) (sdk.VCSCommit, error) {
+	c := Commit{}
+	cacheKey := cache.Key("reposmanager", "gitea", "commit", g.URL, repo, hash)
+	if !cache.Get(cacheKey, &c) || c.SHA == "" {
+		status, body, _, err := g.get("/repos/" + repo + "/git/commits/" + url.QueryEscape(hash))
+		if err != nil {
+			log.Warning("GiteaClient.Commit> Error %s", err)
+			return sdk.VCSCommit{}, err
+		}
+		if status >= 400 {
+			return sdk.VCSCommit{}, sdk.NewError(sdk.ErrRepoNotFound, ErrorAPI(body))
+		}
+		if err := json.Unmarshal(body, &c); err != nil {
+			log.Warning("GiteaClient.Commit> Unable to parse gitea commit: %s", err)
+			return sdk.VCSCommit{}, err
+		}
+		//A commit never changes, keep it for one day
+		cache.SetWithTTL(cacheKey, c, 24*60*60)
+	}
+	return c.toVCSCommit(), nil
+}
+
+func (g *GiteaClient) hooks(repo string) ([]Hook, error) {
+	status, body, _, err := g.get("/repos/" + repo + "/hooks")
+	if err != nil {
+		return nil, err
+	}
+	if status >= 400 {
+		return nil, sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+	}
+	hooks := []Hook{}
+	if err := json.Unmarshal(body, &hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+//CreateHook creates a gitea webhook sending push and delete events as json.
+//Gitea sends a push event when a branch is created, so create events are not needed
+func (g *GiteaClient) CreateHook(repo, url string) error {
+	hooks, err := g.hooks(repo)
+	if err != nil {
+		if err == ErrorUnauthorized {
+			return sdk.ErrNoReposManagerClientAuth
+		}
+		return err
+	}
+	for _, h := range hooks {
+		if h.Config["url"] == url {
+			log.Info("CreateHook> Hook already exists on %s", repo)
+			return nil
+		}
+	}
+
+	h := Hook{
+		Type: "gitea",
+		Config: map[string]string{
+			"url":          url,
+			"content_type": "json",
+		},
+		Events: []string{"push", "delete"},
+		Active: true,
+	}
+	log.Info("CreateHook> Ask Gitea to create Hook on %s: %s", repo, url)
+	status, body, _, err := g.post("/repos/"+repo+"/hooks", h)
+	if err != nil {
+		return err
+	}
+	if status >= 400 {
+		return sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+	}
+	return nil
+}
+
+//DeleteHook deletes the gitea webhook matching the url
+func (g *GiteaClient) DeleteHook(repo, url string) error {
+	hooks, err := g.hooks(repo)
+	if err != nil {
+		if err == ErrorUnauthorized {
+			return sdk.ErrNoReposManagerClientAuth
+		}
+		return err
+	}
+	for _, h := range hooks {
+		if h.Config["url"] != url {
+			continue
+		}
+		log.Info("DeleteHook> Ask Gitea to delete Hook %d on %s", h.ID, repo)
+		status, body, _, err := g.delete(fmt.Sprintf("/repos/%s/hooks/%d", repo, h.ID))
+		if err != nil {
+			return err
+		}
+		if status >= 400 {
+			return sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package repogitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (g *GiteaClient) pullRequests(fullname string) ([]PullRequest, error) {
+	prs := []PullRequest{}
+	status, body, _, err := g.get("/repos/" + fullname + "/pulls?state=all")
+	if err != nil {
+		return nil, err
+	}
+	//Gogs doesn't provide pull requests API
+	if status == http.StatusNotFound {
+		return prs, nil
+	}
+	if status >= 400 {
+		return nil, sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+	}
+	if err := json.Unmarshal(body, &prs); err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+//GetEvents computes events from the difference between the current state of the repository
+//and the state saved in cache on the previous call, because Gitea doesn't provide an events API.
+//dateRef is the poller creation date, it is part of the cache key so each poller has its own state
+func (g *GiteaClient) GetEvents(fullname string, dateRef time.Time) ([]interface{}, time.Duration, error) {
+	log.Debug("GiteaClient.GetEvents> loading events for %s after %v", fullname, dateRef)
+	interval := 60 * time.Second
+
+	branches, err := g.branches(fullname)
+	if err != nil {
+		log.Warning("GiteaClient.GetEvents> Error %s", err)
+		return nil, interval, err
+	}
+
+	prs, err := g.pullRequests(fullname)
+	if err != nil {
+		log.Warning("GiteaClient.GetEvents> Error %s", err)
+		return nil, interval, err
+	}
+
+	cacheKey := cache.Key("reposmanager", "gitea", "snapshot", g.URL, fullname, strconv.FormatInt(dateRef.Unix(), 10))
+	previous := snapshot{}
+	hasPrevious := cache.Get(cacheKey, &previous)
+
+	current := snapshot{
+		Branches:     map[string]string{},
+		PullRequests: map[int]string{},
+	}
+
+	events := []interface{}{}
+	for _, b := range branches {
+		current.Branches[b.Name] = b.Commit.ID
+		latest, known := previous.Branches[b.Name]
+		switch {
+		case !hasPrevious:
+			if b.Commit.Timestamp.After(dateRef) {
+				events = append(events, Event{Type: "PushEvent", Branch: b})
+			}
+		case !known:
+			events = append(events, Event{Type: "CreateEvent", Branch: b})
+		case latest != b.Commit.ID:
+			events = append(events, Event{Type: "PushEvent", Branch: b})
+		}
+	}
+
+	for name := range previous.Branches {
+		if _, ok := current.Branches[name]; !ok {
+			events = append(events, Event{Type: "DeleteEvent", Branch: Branch{Name: name}})
+		}
+	}
+
+	for _, pr := range prs {
+		current.PullRequests[pr.Number] = pr.State + "@" + pr.Head.Sha
+		var action string
+		prev, known := previous.PullRequests[pr.Number]
+		switch {
+		case !hasPrevious:
+			if pr.State == "open" && pr.CreatedAt != nil && pr.CreatedAt.After(dateRef) {
+				action = "opened"
+			}
+		case !known:
+			if pr.State == "open" {
+				action = "opened"
+			}
+		default:
+			t := strings.SplitN(prev, "@", 2)
+			prevState, prevSha := t[0], ""
+			if len(t) == 2 {
+				prevSha = t[1]
+			}
+			switch {
+			case prevState == "open" && pr.State == "closed":
+				action = "closed"
+			case prevState == "closed" && pr.State == "open":
+				action = "opened"
+			case pr.State == "open" && prevSha != pr.Head.Sha:
+				action = "synchronize"
+			}
+		}
+		if action != "" {
+			events = append(events, Event{Type: "PullRequestEvent", Action: action, PullRequest: pr})
+		}
+	}
+
+	cache.SetWithTTL(cacheKey, current, 24*60*60)
+
+	if len(events) == 0 {
+		return nil, interval, fmt.Errorf("No new events")
+	}
+
+	return events, interval, nil
+}
+
+func filterEvents(iEvents []interface{}, t string) []Event {
+	events := []Event{}
+	for _, i := range iEvents {
+		e := i.(Event)
+		if e.Type == t {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+//PushEvents returns push events as commits
+func (g *GiteaClient) PushEvents(fullname string, iEvents []interface{}) ([]sdk.VCSPushEvent, error) {
+	r, err := g.repo(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []sdk.VCSPushEvent{}
+	for _, e := range filterEvents(iEvents, "PushEvent") {
+		c, err := g.Commit(fullname, e.Branch.Commit.ID)
+		if err != nil {
+			log.Warning("GiteaClient.PushEvents> Unable to find commit %s in %s : %s", e.Branch.Commit.ID, fullname, err)
+			continue
+		}
+		res = append(res, sdk.VCSPushEvent{
+			Branch: e.Branch.toVCSBranch(r.DefaultBranch),
+			Commit: c,
+		})
+	}
+	return res, nil
+}
+
+//CreateEvents checks create events from a event list
+func (g *GiteaClient) CreateEvents(fullname string, iEvents []interface{}) ([]sdk.VCSCreateEvent, error) {
+	r, err := g.repo(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []sdk.VCSCreateEvent{}
+	for _, e := range filterEvents(iEvents, "CreateEvent") {
+		c, err := g.Commit(fullname, e.Branch.Commit.ID)
+		if err != nil {
+			log.Warning("GiteaClient.CreateEvents> Unable to find commit %s in %s : %s", e.Branch.Commit.ID, fullname, err)
+			continue
+		}
+		res = append(res, sdk.VCSCreateEvent{
+			Branch: e.Branch.toVCSBranch(r.DefaultBranch),
+			Commit: c,
+		})
+	}
+
+	log.Debug("GiteaClient.CreateEvents> found %d create events : %#v", len(res), res)
+	return res, nil
+}
+
+//DeleteEvents checks delete events from a event list
+func (g *GiteaClient) DeleteEvents(fullname string, iEvents []interface{}) ([]sdk.VCSDeleteEvent, error) {
+	res := []sdk.VCSDeleteEvent{}
+	for _, e := range filterEvents(iEvents, "DeleteEvent") {
+		res = append(res, sdk.VCSDeleteEvent{
+			Branch: sdk.VCSBranch{
+				DisplayID: e.Branch.Name,
+			},
+		})
+	}
+
+	log.Debug("GiteaClient.DeleteEvents> found %d delete events : %#v", len(res), res)
+	return res, nil
+}
+
+//PullRequestEvents checks pull request events from a event list
+func (g *GiteaClient) PullRequestEvents(fullname string, iEvents []interface{}) ([]sdk.VCSPullRequestEvent, error) {
+	res := []sdk.VCSPullRequestEvent{}
+	for _, e := range filterEvents(iEvents, "PullRequestEvent") {
+		pr := e.PullRequest
+		head := sdk.VCSPushEvent{
+			Branch: sdk.VCSBranch{
+				ID:           pr.Head.Ref,
+				DisplayID:    pr.Head.Ref,
+				LatestCommit: pr.Head.Sha,
+			},
+			Commit: sdk.VCSCommit{
+				Hash: pr.Head.Sha,
+			},
+		}
+		base := sdk.VCSPushEvent{
+			Branch: sdk.VCSBranch{
+				ID:           pr.Base.Ref,
+				DisplayID:    pr.Base.Ref,
+				LatestCommit: pr.Base.Sha,
+			},
+			Commit: sdk.VCSCommit{
+				Hash: pr.Base.Sha,
+			},
+		}
+		res = append(res, sdk.VCSPullRequestEvent{
+			Action: e.Action,
+			Number: pr.Number,
+			URL:    pr.HTMLURL,
+			//Gitea does not compute merge refs, so pull requests are built on their head
+			MergeRef: fmt.Sprintf("refs/pull/%d/head", pr.Number),
+			User: sdk.VCSAuthor{
+				Name:        pr.User.Login,
+				DisplayName: pr.User.FullName,
+				Email:       pr.User.Email,
+				Avatar:      pr.User.AvatarURL,
+			},
+			Head:   head,
+			Base:   base,
+			Branch: head.Branch,
+		})
+	}
+
+	log.Debug("GiteaClient.PullRequestEvents> found %d pull request events : %#v", len(res), res)
+	return res, nil
+}
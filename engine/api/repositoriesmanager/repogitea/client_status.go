@@ -0,0 +1,102 @@
+package repogitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//SetStatus Users with push access can create commit statuses for a given sha:
+//https://try.gitea.io/api/swagger#/repository/repoCreateStatus
+func (g *GiteaClient) SetStatus(event sdk.Event) error {
+	log.Debug("gitea.SetStatus> receive: type:%s all: %+v", event.EventType, event)
+	var eventpb sdk.EventPipelineBuild
+
+	if event.EventType != fmt.Sprintf("%T", sdk.EventPipelineBuild{}) {
+		return nil
+	}
+
+	if g.DisableSetStatus {
+		log.Warning("⚠ Gitea statuses are disabled")
+		return nil
+	}
+
+	if err := mapstructure.Decode(event.Payload, &eventpb); err != nil {
+		log.Warning("Error during consumption: %s", err)
+		return err
+	}
+
+	var status string
+	switch eventpb.Status {
+	case sdk.StatusBuilding:
+		status = "pending"
+	case sdk.StatusFail:
+		status = "failure"
+	case sdk.StatusSuccess:
+		status = "success"
+	case sdk.StatusWaiting:
+		status = "pending"
+	default:
+		return nil
+	}
+
+	var desc string
+	switch eventpb.PipelineType {
+	case sdk.BuildPipeline:
+		desc = fmt.Sprintf("Build pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
+	case sdk.TestingPipeline:
+		desc = fmt.Sprintf("Testing pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
+	case sdk.DeploymentPipeline:
+		desc = fmt.Sprintf("Deployment pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
+	default:
+		log.Warning("Unrecognized pipeline type : %v", eventpb.PipelineType)
+		return nil
+	}
+
+	targetURL := fmt.Sprintf("%s#/project/%s/application/%s/pipeline/%s/build/%d?env=%s",
+		uiURL,
+		eventpb.ProjectKey,
+		eventpb.ApplicationName,
+		eventpb.PipelineName,
+		eventpb.BuildNumber,
+		url.QueryEscape(eventpb.EnvironmentName),
+	)
+
+	//CDS can avoid sending gitea target url in status, if it's disable
+	if g.DisableStatusURL {
+		targetURL = ""
+	}
+
+	gtStatus := CommitStatus{
+		Context:     fmt.Sprintf("continuous-delivery/CDS/%s", eventpb.PipelineName),
+		State:       status,
+		TargetURL:   targetURL,
+		Description: desc,
+	}
+
+	path := fmt.Sprintf("/repos/%s/statuses/%s", eventpb.RepositoryFullname, eventpb.Hash)
+	code, body, _, err := g.post(path, gtStatus)
+	if err != nil {
+		return err
+	}
+
+	if code != 201 && code != 200 {
+		err := fmt.Errorf("Unable to create status on gitea. Status code : %d - Body: %s", code, body)
+		log.Warning("SetStatus> %s", err)
+		return err
+	}
+
+	s := &CommitStatus{}
+	if err := json.Unmarshal(body, s); err != nil {
+		return err
+	}
+
+	log.Debug("SetStatus> Status %s %s created", s.Context, s.State)
+
+	return nil
+}
@@ -0,0 +1,345 @@
+package repogitea
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+const fakeToken = "my-token"
+
+type fakeGitea struct {
+	*httptest.Server
+	branches     []Branch
+	pullRequests []PullRequest
+	hooks        []Hook
+	statuses     []CommitStatus
+}
+
+func newFakeGitea(t *testing.T) *fakeGitea {
+	f := &fakeGitea{}
+	mux := http.NewServeMux()
+
+	writeJSON := func(w http.ResponseWriter, code int, i interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(i)
+	}
+
+	checkAuth := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "token "+fakeToken {
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Unauthorized"})
+				return
+			}
+			h(w, r)
+		}
+	}
+
+	repo := Repository{
+		ID:            42,
+		Owner:         User{Login: "my-org"},
+		Name:          "my-repo",
+		FullName:      "my-org/my-repo",
+		HTMLURL:       "http://gitea.local/my-org/my-repo",
+		CloneURL:      "http://gitea.local/my-org/my-repo.git",
+		SSHURL:        "git@gitea.local:my-org/my-repo.git",
+		DefaultBranch: "master",
+	}
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+
+	f.branches = []Branch{
+		{Name: "master", Commit: PayloadCommit{ID: "aaaa", Timestamp: old}},
+		{Name: "feat", Commit: PayloadCommit{ID: "bbbb", Timestamp: now}},
+	}
+	f.pullRequests = []PullRequest{
+		{Number: 3, State: "open", HTMLURL: "http://gitea.local/my-org/my-repo/pulls/3", User: User{Login: "jane"}, Head: PRBranchInfo{Ref: "feat", Sha: "bbbb"}, Base: PRBranchInfo{Ref: "master", Sha: "aaaa"}, CreatedAt: &now},
+	}
+
+	commit := func(sha, msg string, parent string) Commit {
+		c := Commit{
+			SHA:     sha,
+			HTMLURL: "http://gitea.local/my-org/my-repo/commit/" + sha,
+			Commit:  RepoCommit{Message: msg, Author: CommitUser{Name: "Jane", Email: "jane@localhost", Date: now}},
+			Author:  &User{Login: "jane"},
+		}
+		if parent != "" {
+			c.Parents = []CommitMeta{{SHA: parent}}
+		}
+		return c
+	}
+
+	mux.HandleFunc("/api/v1/user", checkAuth(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, User{ID: 1, Login: "jane"})
+	}))
+
+	mux.HandleFunc("/api/v1/user/repos", checkAuth(func(w http.ResponseWriter, r *http.Request) {
+		other := repo
+		other.ID = 43
+		other.FullName = "my-org/other-repo"
+		writeJSON(w, http.StatusOK, []Repository{repo, other})
+	}))
+
+	mux.HandleFunc("/api/v1/repos/", checkAuth(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v1/repos/")
+		if !strings.HasPrefix(path, "my-org/my-repo") {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		path = strings.TrimPrefix(path, "my-org/my-repo")
+
+		switch {
+		case path == "":
+			writeJSON(w, http.StatusOK, repo)
+		case path == "/branches":
+			writeJSON(w, http.StatusOK, f.branches)
+		case strings.HasPrefix(path, "/branches/"):
+			name := strings.TrimPrefix(path, "/branches/")
+			for _, b := range f.branches {
+				if b.Name == name {
+					writeJSON(w, http.StatusOK, b)
+					return
+				}
+			}
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		case path == "/commits":
+			if r.URL.Query().Get("page") != "1" {
+				writeJSON(w, http.StatusOK, []Commit{})
+				return
+			}
+			switch r.URL.Query().Get("sha") {
+			case "master", "aaaa":
+				writeJSON(w, http.StatusOK, []Commit{commit("aaaa", "second", "0000"), commit("0000", "first", "")})
+			default:
+				writeJSON(w, http.StatusOK, []Commit{commit("cccc", "c", "bbbb"), commit("bbbb", "b", "0000"), commit("0000", "first", "")})
+			}
+		case strings.HasPrefix(path, "/git/commits/"):
+			sha := strings.TrimPrefix(path, "/git/commits/")
+			writeJSON(w, http.StatusOK, commit(sha, "feature", ""))
+		case path == "/pulls":
+			assert.Equal(t, "all", r.URL.Query().Get("state"))
+			writeJSON(w, http.StatusOK, f.pullRequests)
+		case path == "/hooks" && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, f.hooks)
+		case path == "/hooks" && r.Method == http.MethodPost:
+			h := Hook{}
+			json.NewDecoder(r.Body).Decode(&h)
+			h.ID = len(f.hooks) + 1
+			f.hooks = append(f.hooks, h)
+			writeJSON(w, http.StatusCreated, h)
+		case strings.HasPrefix(path, "/hooks/") && r.Method == http.MethodDelete:
+			f.hooks = nil
+			w.WriteHeader(http.StatusNoContent)
+		case strings.HasPrefix(path, "/statuses/"):
+			s := CommitStatus{}
+			json.NewDecoder(r.Body).Decode(&s)
+			s.ID = 1
+			f.statuses = append(f.statuses, s)
+			writeJSON(w, http.StatusCreated, s)
+		default:
+			t.Errorf("Unexpected call %s %s", r.Method, r.URL)
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		}
+	}))
+
+	f.Server = httptest.NewServer(mux)
+	return f
+}
+
+func TestGiteaConsumerAuthorize(t *testing.T) {
+	f := newFakeGitea(t)
+	defer f.Close()
+
+	consumer := New(f.URL + "/")
+
+	requestToken, URL, err := consumer.AuthorizeRedirect()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, requestToken)
+	assert.Equal(t, f.URL+"/user/settings/applications", URL)
+
+	token, secret, err := consumer.AuthorizeToken(requestToken, fakeToken)
+	assert.NoError(t, err)
+	assert.Equal(t, fakeToken, token)
+	assert.Equal(t, requestToken, secret)
+
+	_, _, err = consumer.AuthorizeToken(requestToken, "bad-token")
+	assert.Error(t, err)
+}
+
+func TestGiteaClientRepos(t *testing.T) {
+	f := newFakeGitea(t)
+	defer f.Close()
+
+	client, err := New(f.URL).GetAuthorized(fakeToken, "")
+	assert.NoError(t, err)
+
+	repos, err := client.Repos()
+	assert.NoError(t, err)
+	assert.Len(t, repos, 2)
+	assert.Equal(t, "my-org/my-repo", repos[0].Fullname)
+	assert.Equal(t, "my-org/other-repo", repos[1].Fullname)
+
+	repo, err := client.RepoByFullname("my-org/my-repo")
+	assert.NoError(t, err)
+	assert.Equal(t, "git@gitea.local:my-org/my-repo.git", repo.SSHCloneURL)
+
+	_, err = client.RepoByFullname("my-org/unknown")
+	assert.Error(t, err)
+}
+
+func TestGiteaClientBranchesAndCommits(t *testing.T) {
+	f := newFakeGitea(t)
+	defer f.Close()
+
+	client, _ := New(f.URL).GetAuthorized(fakeToken, "")
+
+	branches, err := client.Branches("my-org/my-repo")
+	assert.NoError(t, err)
+	assert.Len(t, branches, 2)
+	assert.True(t, branches[0].Default)
+	assert.False(t, branches[1].Default)
+	assert.Equal(t, "bbbb", branches[1].LatestCommit)
+
+	b, err := client.Branch("my-org/my-repo", "feat")
+	assert.NoError(t, err)
+	assert.Equal(t, "feat", b.DisplayID)
+
+	_, err = client.Branch("my-org/my-repo", "unknown")
+	assert.Equal(t, sdk.ErrNoBranch, err)
+
+	commits, err := client.Commits("my-org/my-repo", "master", "", "")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "aaaa", commits[0].Hash)
+
+	commits, err = client.Commits("my-org/my-repo", "feat", "0000", "cccc")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "cccc", commits[0].Hash)
+	assert.Equal(t, "bbbb", commits[1].Hash)
+
+	c, err := client.Commit("my-org/my-repo", "bbbb")
+	assert.NoError(t, err)
+	assert.Equal(t, "jane", c.Author.Name)
+	assert.Equal(t, "jane@localhost", c.Author.Email)
+}
+
+func TestGiteaClientHooks(t *testing.T) {
+	f := newFakeGitea(t)
+	defer f.Close()
+
+	client, _ := New(f.URL).GetAuthorized(fakeToken, "")
+
+	assert.NoError(t, client.CreateHook("my-org/my-repo", "http://cds.local/hook?uid=1"))
+	assert.NoError(t, client.CreateHook("my-org/my-repo", "http://cds.local/hook?uid=1"))
+	assert.Len(t, f.hooks, 1)
+	assert.Equal(t, "json", f.hooks[0].Config["content_type"])
+
+	assert.NoError(t, client.DeleteHook("my-org/my-repo", "http://cds.local/hook?uid=1"))
+	assert.Len(t, f.hooks, 0)
+}
+
+func TestGiteaClientEvents(t *testing.T) {
+	cache.Initialize("local", "", "", 60)
+
+	f := newFakeGitea(t)
+	defer f.Close()
+
+	client, _ := New(f.URL).GetAuthorized(fakeToken, "")
+	dateRef := time.Now().Add(-1 * time.Hour)
+
+	//First call: without previous state, only recent changes are events
+	events, _, err := client.GetEvents("my-org/my-repo", dateRef)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	pushs, err := client.PushEvents("my-org/my-repo", events)
+	assert.NoError(t, err)
+	assert.Len(t, pushs, 1)
+	assert.Equal(t, "feat", pushs[0].Branch.DisplayID)
+	assert.Equal(t, "bbbb", pushs[0].Commit.Hash)
+
+	prs, err := client.PullRequestEvents("my-org/my-repo", events)
+	assert.NoError(t, err)
+	assert.Len(t, prs, 1)
+	assert.Equal(t, "opened", prs[0].Action)
+	assert.Equal(t, 3, prs[0].Number)
+	assert.Equal(t, "refs/pull/3/head", prs[0].MergeRef)
+	assert.Equal(t, "feat", prs[0].Head.Branch.DisplayID)
+	assert.Equal(t, "master", prs[0].Base.Branch.DisplayID)
+
+	//Nothing changed
+	_, _, err = client.GetEvents("my-org/my-repo", dateRef)
+	assert.Error(t, err)
+
+	//Push on feat, create new, delete master and close the pull request
+	f.branches = []Branch{
+		{Name: "feat", Commit: PayloadCommit{ID: "cccc"}},
+		{Name: "new", Commit: PayloadCommit{ID: "dddd"}},
+	}
+	f.pullRequests[0].State = "closed"
+
+	events, _, err = client.GetEvents("my-org/my-repo", dateRef)
+	assert.NoError(t, err)
+	assert.Len(t, events, 4)
+
+	pushs, err = client.PushEvents("my-org/my-repo", events)
+	assert.NoError(t, err)
+	assert.Len(t, pushs, 1)
+	assert.Equal(t, "cccc", pushs[0].Commit.Hash)
+
+	creates, err := client.CreateEvents("my-org/my-repo", events)
+	assert.NoError(t, err)
+	assert.Len(t, creates, 1)
+	assert.Equal(t, "new", creates[0].Branch.DisplayID)
+	assert.Equal(t, "feature", creates[0].Commit.Message)
+
+	deletes, err := client.DeleteEvents("my-org/my-repo", events)
+	assert.NoError(t, err)
+	assert.Len(t, deletes, 1)
+	assert.Equal(t, "master", deletes[0].Branch.DisplayID)
+
+	prs, err = client.PullRequestEvents("my-org/my-repo", events)
+	assert.NoError(t, err)
+	assert.Len(t, prs, 1)
+	assert.Equal(t, "closed", prs[0].Action)
+}
+
+func TestGiteaClientSetStatus(t *testing.T) {
+	f := newFakeGitea(t)
+	defer f.Close()
+
+	client, _ := New(f.URL).GetAuthorized(fakeToken, "")
+
+	e := sdk.Event{
+		EventType: "sdk.EventPipelineBuild",
+		Payload: map[string]interface{}{
+			"Status":             sdk.StatusFail,
+			"PipelineName":       "build",
+			"PipelineType":       sdk.BuildPipeline,
+			"ProjectKey":         "KEY",
+			"ApplicationName":    "app",
+			"BranchName":         "master",
+			"Hash":               "aaaa",
+			"RepositoryFullname": "my-org/my-repo",
+		},
+	}
+	assert.NoError(t, client.SetStatus(e))
+	assert.Len(t, f.statuses, 1)
+	assert.Equal(t, "failure", f.statuses[0].State)
+	assert.Equal(t, "continuous-delivery/CDS/build", f.statuses[0].Context)
+
+	//Checking status is not sent
+	e.Payload["Status"] = sdk.StatusChecking
+	assert.NoError(t, client.SetStatus(e))
+	assert.Len(t, f.statuses, 1)
+}
@@ -0,0 +1,91 @@
+package repogitea
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func generateHash() (string, error) {
+	size := 128
+	bs := make([]byte, size)
+	if _, err := rand.Read(bs); err != nil {
+		log.Error("generateID: rand.Read failed: %s\n", err)
+		return "", err
+	}
+	str := hex.EncodeToString(bs)
+	token := []byte(str)[0:size]
+
+	log.Debug("generateID: new generated id: %s\n", token)
+	return string(token), nil
+}
+
+//GiteaConsumer is a gitea (or gogs) instance. Gitea doesn't provide an OAuth flow,
+//users authorize CDS with a personal access token
+type GiteaConsumer struct {
+	URL              string `json:"-"`
+	WithHooks        bool   `json:"with-hooks"`
+	WithPolling      bool   `json:"with-polling"`
+	DisableSetStatus bool   `json:"-"`
+	DisableStatusURL bool   `json:"-"`
+}
+
+//New creates a new GiteaConsumer
+func New(URL string) *GiteaConsumer {
+	return &GiteaConsumer{
+		URL: strings.TrimSuffix(URL, "/"),
+	}
+}
+
+//Data returns a serilized version of specific data
+func (g *GiteaConsumer) Data() string {
+	b, _ := json.Marshal(g)
+	return string(b)
+}
+
+//AuthorizeRedirect returns a request token and the URL where users generate their access token
+func (g *GiteaConsumer) AuthorizeRedirect() (string, string, error) {
+	requestToken, err := generateHash()
+	if err != nil {
+		return "", "", err
+	}
+	return requestToken, fmt.Sprintf("%s/user/settings/applications", g.URL), nil
+}
+
+//AuthorizeToken checks the access token given by the user as verifier
+//and returns it with the request token as secret
+func (g *GiteaConsumer) AuthorizeToken(requestToken, accessToken string) (string, string, error) {
+	c := &GiteaClient{URL: g.URL, Token: accessToken}
+	u, err := c.user()
+	if err != nil {
+		return "", "", err
+	}
+	log.Debug("AuthorizeToken> Gitea token belongs to %s", u.Login)
+	return accessToken, requestToken, nil
+}
+
+//GetAuthorized returns an authorized client
+func (g *GiteaConsumer) GetAuthorized(accessToken, accessTokenSecret string) (sdk.RepositoriesManagerClient, error) {
+	c := &GiteaClient{
+		URL:              g.URL,
+		Token:            accessToken,
+		DisableSetStatus: g.DisableSetStatus,
+		DisableStatusURL: g.DisableStatusURL,
+	}
+	return c, nil
+}
+
+//HooksSupported returns true if the driver technically support hook
+func (g *GiteaConsumer) HooksSupported() bool {
+	return true
+}
+
+//PollingSupported returns true if the driver technically support polling
+func (g *GiteaConsumer) PollingSupported() bool {
+	return true
+}
@@ -0,0 +1,37 @@
+package repogitea
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+//Error wraps gitea error format
+type Error struct {
+	ID   string `json:"-"`
+	Desc string `json:"message"`
+	URL  string `json:"url"`
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("(gitea_%s) %s", e.ID, e.Desc)
+}
+
+func (e Error) String() string {
+	return e.Error()
+}
+
+//Gitea errors
+var (
+	ErrorUnauthorized = &Error{
+		ID:   "unauthorized",
+		Desc: "Bad credentials",
+	}
+)
+
+//ErrorAPI creates a new error from a gitea API response body
+func ErrorAPI(body []byte) Error {
+	e := Error{}
+	json.Unmarshal(body, &e)
+	e.ID = "api_error"
+	return e
+}
@@ -0,0 +1,120 @@
+package repogitea
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/facebookgo/httpcontrol"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//Gitea http var
+var (
+	httpClient = &http.Client{
+		Transport: &httpcontrol.Transport{
+			RequestTimeout: time.Second * 30,
+			MaxTries:       5,
+		},
+	}
+)
+
+const apiPath = "/api/v1"
+
+func (c *GiteaClient) do(method, path string, in interface{}) (int, []byte, http.Header, error) {
+	if !strings.HasPrefix(path, c.URL) {
+		path = c.URL + apiPath + path
+	}
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		body = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequest(method, path, body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	req.Header.Set("User-Agent", "CDS")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("token %s", c.Token))
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Debug("Gitea API>> Request %s %s", method, req.URL.String())
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusUnauthorized {
+		return res.StatusCode, nil, nil, ErrorUnauthorized
+	}
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, nil, nil, err
+	}
+
+	return res.StatusCode, resBody, res.Header, nil
+}
+
+func (c *GiteaClient) get(path string) (int, []byte, http.Header, error) {
+	return c.do(http.MethodGet, path, nil)
+}
+
+func (c *GiteaClient) post(path string, in interface{}) (int, []byte, http.Header, error) {
+	return c.do(http.MethodPost, path, in)
+}
+
+func (c *GiteaClient) delete(path string) (int, []byte, http.Header, error) {
+	return c.do(http.MethodDelete, path, nil)
+}
+
+const pageLimit = 50
+
+//getAll calls fn with the body of each page until the last page.
+//Gogs doesn't send Link headers and ignores pagination on some endpoints,
+//so we stop on an incomplete page or when a page is returned twice
+func (c *GiteaClient) getAll(path string, fn func(body []byte) (int, error)) error {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	var previous []byte
+	for page := 1; ; page++ {
+		status, body, _, err := c.get(fmt.Sprintf("%s%spage=%d&limit=%d", path, sep, page, pageLimit))
+		if err != nil {
+			return err
+		}
+		if status >= 400 {
+			return sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+		}
+		if bytes.Equal(previous, body) {
+			return nil
+		}
+		n, err := fn(body)
+		if err != nil {
+			return err
+		}
+		if n < pageLimit {
+			return nil
+		}
+		previous = body
+	}
+}
@@ -0,0 +1,12 @@
+package repogitea
+
+var (
+	apiURL string
+	uiURL  string
+)
+
+// Init initializes repogitea package
+func Init(apiurl, uiurl string) {
+	apiURL = apiurl
+	uiURL = uiurl
+}
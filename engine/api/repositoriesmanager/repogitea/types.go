@@ -0,0 +1,129 @@
+package repogitea
+
+import "time"
+
+// User represents a Gitea user
+// https://try.gitea.io/api/swagger#/user
+type User struct {
+	ID        int    `json:"id"`
+	Login     string `json:"login"`
+	Username  string `json:"username"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// Repository represents a Gitea repository
+type Repository struct {
+	ID            int    `json:"id"`
+	Owner         User   `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	HTMLURL       string `json:"html_url"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// PayloadUser is the author or the committer of a commit
+type PayloadUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// PayloadCommit is the commit embedded in a branch
+type PayloadCommit struct {
+	ID        string       `json:"id"`
+	Message   string       `json:"message"`
+	URL       string       `json:"url"`
+	Author    *PayloadUser `json:"author"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// Branch represents a Gitea repository branch
+type Branch struct {
+	Name   string        `json:"name"`
+	Commit PayloadCommit `json:"commit"`
+}
+
+// CommitUser is the git author of a commit
+type CommitUser struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// RepoCommit is the git part of a commit
+type RepoCommit struct {
+	Message string     `json:"message"`
+	Author  CommitUser `json:"author"`
+}
+
+// CommitMeta is a reference to a commit
+type CommitMeta struct {
+	SHA string `json:"sha"`
+}
+
+// Commit represents a Gitea commit
+type Commit struct {
+	SHA     string       `json:"sha"`
+	HTMLURL string       `json:"html_url"`
+	Commit  RepoCommit   `json:"commit"`
+	Author  *User        `json:"author"`
+	Parents []CommitMeta `json:"parents"`
+}
+
+// PRBranchInfo is the head or the base of a pull request
+type PRBranchInfo struct {
+	Ref string `json:"ref"`
+	Sha string `json:"sha"`
+}
+
+// PullRequest represents a Gitea pull request
+type PullRequest struct {
+	ID        int          `json:"id"`
+	Number    int          `json:"number"`
+	Title     string       `json:"title"`
+	State     string       `json:"state"`
+	HTMLURL   string       `json:"html_url"`
+	User      User         `json:"user"`
+	Head      PRBranchInfo `json:"head"`
+	Base      PRBranchInfo `json:"base"`
+	CreatedAt *time.Time   `json:"created_at"`
+	UpdatedAt *time.Time   `json:"updated_at"`
+	ClosedAt  *time.Time   `json:"closed_at"`
+}
+
+// Hook represents a Gitea repository webhook
+type Hook struct {
+	ID     int               `json:"id,omitempty"`
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+// CommitStatus represents a commit status
+type CommitStatus struct {
+	ID          int    `json:"id,omitempty"`
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+}
+
+// Event is computed by CDS from the difference between two states of a repository,
+// because Gitea doesn't provide an events API on repositories
+type Event struct {
+	Type        string // PushEvent | CreateEvent | DeleteEvent | PullRequestEvent
+	Action      string // only for PullRequestEvent: opened | synchronize | closed
+	Branch      Branch
+	PullRequest PullRequest
+}
+
+// snapshot is the state of a repository stored in cache between two polls
+type snapshot struct {
+	Branches     map[string]string // branch name -> latest commit
+	PullRequests map[int]string    // pull request number -> state@head sha
+}
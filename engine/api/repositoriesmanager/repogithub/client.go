@@ -584,7 +584,113 @@ func (g *GithubClient) DeleteEvents(fullname string, iEvents []interface{}) ([]s
 	return res, nil
 }
 
-//PullRequestEvents checks pull request events from a event list
+//PullRequestEvents checks pull request events from a event list.
+//Github events API does not send an event when a pull request is updated, so pushes on the head branch
+//of an opened pull request are returned as synchronize events
 func (g *GithubClient) PullRequestEvents(fullname string, iEvents []interface{}) ([]sdk.VCSPullRequestEvent, error) {
-	return nil, nil
+	res := []sdk.VCSPullRequestEvent{}
+	seen := map[int]bool{}
+	pushedBranches := map[string]bool{}
+	for _, i := range iEvents {
+		e := i.(Event)
+		if e.Type == "PushEvent" {
+			pushedBranches[strings.Replace(e.Payload.Ref, "refs/heads/", "", 1)] = true
+			continue
+		}
+		if e.Type != "PullRequestEvent" || e.Payload.PullRequest == nil {
+			continue
+		}
+
+		var action string
+		switch e.Payload.Action {
+		case "opened", "reopened":
+			action = "opened"
+		case "closed":
+			action = "closed"
+		default:
+			continue
+		}
+
+		seen[e.Payload.PullRequest.Number] = true
+		res = append(res, pullRequestEvent(*e.Payload.PullRequest, action))
+	}
+
+	if len(pushedBranches) > 0 {
+		prs, err := g.openedPullRequests(fullname)
+		if err != nil {
+			log.Warning("GithubClient.PullRequestEvents> Unable to list pull requests of %s : %s", fullname, err)
+			return res, nil
+		}
+
+		for _, pr := range prs {
+			//Pull requests from forks are not updated by pushes on this repository
+			if seen[pr.Number] || pr.Head.Repo.FullName != fullname || !pushedBranches[pr.Head.Ref] {
+				continue
+			}
+			res = append(res, pullRequestEvent(pr, "synchronize"))
+		}
+	}
+
+	log.Debug("GithubClient.PullRequestEvents> found %d pull request events : %#v", len(res), res)
+	return res, nil
+}
+
+func pullRequestEvent(pr PullRequest, action string) sdk.VCSPullRequestEvent {
+	head := sdk.VCSPushEvent{
+		Branch: sdk.VCSBranch{
+			ID:           pr.Head.Ref,
+			DisplayID:    pr.Head.Ref,
+			LatestCommit: pr.Head.Sha,
+		},
+		Commit: sdk.VCSCommit{
+			Hash: pr.Head.Sha,
+		},
+	}
+	base := sdk.VCSPushEvent{
+		Branch: sdk.VCSBranch{
+			ID:           pr.Base.Ref,
+			DisplayID:    pr.Base.Ref,
+			LatestCommit: pr.Base.Sha,
+		},
+		Commit: sdk.VCSCommit{
+			Hash: pr.Base.Sha,
+		},
+	}
+
+	return sdk.VCSPullRequestEvent{
+		Action:   action,
+		Number:   pr.Number,
+		URL:      pr.HTMLURL,
+		MergeRef: fmt.Sprintf("refs/pull/%d/merge", pr.Number),
+		User: sdk.VCSAuthor{
+			Name:        pr.User.Login,
+			DisplayName: pr.User.Login,
+			Avatar:      pr.User.AvatarURL,
+		},
+		Head:   head,
+		Base:   base,
+		Branch: head.Branch,
+	}
+}
+
+// https://developer.github.com/v3/pulls/#list-pull-requests
+func (g *GithubClient) openedPullRequests(fullname string) ([]PullRequest, error) {
+	prs := []PullRequest{}
+	nextPage := "/repos/" + fullname + "/pulls?state=open&per_page=100"
+	for nextPage != "" {
+		status, body, headers, err := g.get(nextPage, withoutETag)
+		if err != nil {
+			return nil, err
+		}
+		if status >= 400 {
+			return nil, sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+		}
+		page := []PullRequest{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		prs = append(prs, page...)
+		nextPage = getNextPage(headers)
+	}
+	return prs, nil
 }
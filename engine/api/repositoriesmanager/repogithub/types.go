@@ -346,6 +346,9 @@ type Event struct {
 			Distinct bool   `json:"distinct"`
 			URL      string `json:"url"`
 		} `json:"commits"`
+		Action      string       `json:"action"`
+		Number      int          `json:"number"`
+		PullRequest *PullRequest `json:"pull_request"`
 	} `json:"payload"`
 	Public    bool      `json:"public"`
 	CreatedAt Timestamp `json:"created_at"`
@@ -358,6 +361,35 @@ type Event struct {
 	} `json:"org"`
 }
 
+// PullRequest represents a GitHub pull request
+type PullRequest struct {
+	ID      int                `json:"id"`
+	Number  int                `json:"number"`
+	State   string             `json:"state"`
+	Title   string             `json:"title"`
+	HTMLURL string             `json:"html_url"`
+	Merged  bool               `json:"merged"`
+	User    PullRequestUser    `json:"user"`
+	Head    PullRequestRefInfo `json:"head"`
+	Base    PullRequestRefInfo `json:"base"`
+}
+
+// PullRequestUser represents the author of a GitHub pull request
+type PullRequestUser struct {
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// PullRequestRefInfo represents the head or the base of a GitHub pull request
+type PullRequestRefInfo struct {
+	Label string `json:"label"`
+	Ref   string `json:"ref"`
+	Sha   string `json:"sha"`
+	Repo  struct {
+		FullName string `json:"full_name"`
+	} `json:"repo"`
+}
+
 //CreateStatus represents create a Status API Payload
 type CreateStatus struct {
 	State       string `json:"state"`
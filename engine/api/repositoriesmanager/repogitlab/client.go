@@ -0,0 +1,338 @@
+package repogitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// GitlabClient is a gitlab wrapper for CDS RepositoriesManagerClient interface
+type GitlabClient struct {
+	URL              string
+	OAuthToken       string
+	DisableSetStatus bool
+	DisableStatusURL bool
+}
+
+// Repos list projects the authenticated user is member of
+// https://docs.gitlab.com/ce/api/projects.html#list-projects
+func (g *GitlabClient) Repos() ([]sdk.VCSRepo, error) {
+	var projects = []Project{}
+	err := g.getAll("/projects?membership=true", func(body []byte) error {
+		nextProjects := []Project{}
+		if err := json.Unmarshal(body, &nextProjects); err != nil {
+			log.Warning("GitlabClient.Repos> Unable to parse gitlab projects: %s", err)
+			return err
+		}
+		projects = append(projects, nextProjects...)
+		return nil
+	})
+	if err != nil {
+		log.Warning("GitlabClient.Repos> Error %s", err)
+		return nil, err
+	}
+
+	responseRepos := []sdk.VCSRepo{}
+	for _, p := range projects {
+		responseRepos = append(responseRepos, p.toVCSRepo())
+	}
+
+	return responseRepos, nil
+}
+
+func (p Project) toVCSRepo() sdk.VCSRepo {
+	return sdk.VCSRepo{
+		ID:           strconv.Itoa(p.ID),
+		Name:         p.Name,
+		Slug:         p.Path,
+		Fullname:     p.PathWithNamespace,
+		URL:          p.WebURL,
+		HTTPCloneURL: p.HTTPURLToRepo,
+		SSHCloneURL:  p.SSHURLToRepo,
+	}
+}
+
+// RepoByFullname Get only one repo
+// https://docs.gitlab.com/ce/api/projects.html#get-single-project
+func (g *GitlabClient) RepoByFullname(fullname string) (sdk.VCSRepo, error) {
+	p, err := g.project(fullname)
+	if err != nil {
+		return sdk.VCSRepo{}, err
+	}
+	return p.toVCSRepo(), nil
+}
+
+func (g *GitlabClient) project(fullname string) (Project, error) {
+	var p Project
+	cacheKey := cache.Key("reposmanager", "gitlab", "project", g.OAuthToken, fullname)
+	if cache.Get(cacheKey, &p) && p.ID != 0 {
+		return p, nil
+	}
+
+	status, body, _, err := g.get(projectPath(fullname))
+	if err != nil {
+		log.Warning("GitlabClient.project> Error %s", err)
+		return p, err
+	}
+	if status >= 400 {
+		return p, sdk.NewError(sdk.ErrRepoNotFound, ErrorAPI(body))
+	}
+	if err := json.Unmarshal(body, &p); err != nil {
+		log.Warning("GitlabClient.project> Unable to parse gitlab project: %s", err)
+		return p, err
+	}
+
+	//Put the project on cache for one hour and one minute
+	cache.SetWithTTL(cacheKey, p, 61*60)
+	return p, nil
+}
+
+// Branches returns list of branches for a repo
+// https://docs.gitlab.com/ce/api/branches.html#list-repository-branches
+func (g *GitlabClient) Branches(fullname string) ([]sdk.VCSBranch, error) {
+	p, err := g.project(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	var branches = []Branch{}
+	err = g.getAll(projectPath(fullname)+"/repository/branches", func(body []byte) error {
+		nextBranches := []Branch{}
+		if err := json.Unmarshal(body, &nextBranches); err != nil {
+			log.Warning("GitlabClient.Branches> Unable to parse gitlab branches: %s", err)
+			return err
+		}
+		branches = append(branches, nextBranches...)
+		return nil
+	})
+	if err != nil {
+		log.Warning("GitlabClient.Branches> Error %s", err)
+		return nil, err
+	}
+
+	branchesResult := []sdk.VCSBranch{}
+	for _, b := range branches {
+		branchesResult = append(branchesResult, b.toVCSBranch(p.DefaultBranch))
+	}
+	return branchesResult, nil
+}
+
+func (b Branch) toVCSBranch(defaultBranch string) sdk.VCSBranch {
+	return sdk.VCSBranch{
+		ID:           b.Name,
+		DisplayID:    b.Name,
+		LatestCommit: b.Commit.ID,
+		Default:      b.Default || b.Name == defaultBranch,
+	}
+}
+
+// Branch returns only detail of a branch
+// https://docs.gitlab.com/ce/api/branches.html#get-single-repository-branch
+func (g *GitlabClient) Branch(fullname, branch string) (sdk.VCSBranch, error) {
+	p, err := g.project(fullname)
+	if err != nil {
+		return sdk.VCSBranch{}, err
+	}
+
+	status, body, _, err := g.get(projectPath(fullname) + "/repository/branches/" + url.QueryEscape(branch))
+	if err != nil {
+		log.Warning("GitlabClient.Branch> Error %s", err)
+		return sdk.VCSBranch{}, err
+	}
+	if status == http.StatusNotFound {
+		return sdk.VCSBranch{}, sdk.ErrNoBranch
+	}
+	if status >= 400 {
+		return sdk.VCSBranch{}, sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+	}
+
+	b := Branch{}
+	if err := json.Unmarshal(body, &b); err != nil {
+		log.Warning("GitlabClient.Branch> Unable to parse gitlab branch: %s", err)
+		return sdk.VCSBranch{}, err
+	}
+	return b.toVCSBranch(p.DefaultBranch), nil
+}
+
+// Commits returns the commits list on a branch between a commit SHA (since) until another commit SHA (until).
+// If since is empty, it returns all the commits of the branch until the commit SHA (until)
+func (g *GitlabClient) Commits(repo, theBranch, since, until string) ([]sdk.VCSCommit, error) {
+	var commitsResult []sdk.VCSCommit
+
+	log.Debug("GitlabClient.Commits> Looking for commits on repo %s since = %s until = %s", repo, since, until)
+	cacheKey := cache.Key("reposmanager", "gitlab", "commits", g.URL, repo, "since="+since, "until="+until)
+	if cache.Get(cacheKey, &commitsResult) {
+		return commitsResult, nil
+	}
+
+	var theCommits []Commit
+	if since == "" {
+		ref := until
+		if ref == "" {
+			ref = theBranch
+		}
+		err := g.getAll(projectPath(repo)+"/repository/commits?ref_name="+url.QueryEscape(ref), func(body []byte) error {
+			nextCommits := []Commit{}
+			if err := json.Unmarshal(body, &nextCommits); err != nil {
+				log.Warning("GitlabClient.Commits> Unable to parse gitlab commits: %s", err)
+				return err
+			}
+			theCommits = append(theCommits, nextCommits...)
+			return nil
+		})
+		if err != nil {
+			log.Warning("GitlabClient.Commits> Error %s", err)
+			return nil, err
+		}
+	} else {
+		//https://docs.gitlab.com/ce/api/repositories.html#compare-branches-tags-or-commits
+		v := url.Values{}
+		v.Add("from", since)
+		v.Add("to", until)
+		status, body, _, err := g.get(projectPath(repo) + "/repository/compare?" + v.Encode())
+		if err != nil {
+			log.Warning("GitlabClient.Commits> Error %s", err)
+			return nil, err
+		}
+		if status >= 400 {
+			return nil, sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+		}
+		compare := Compare{}
+		if err := json.Unmarshal(body, &compare); err != nil {
+			log.Warning("GitlabClient.Commits> Unable to parse gitlab compare: %s", err)
+			return nil, err
+		}
+		//Gitlab returns the commits from the oldest to the newest
+		for i := len(compare.Commits) - 1; i >= 0; i-- {
+			theCommits = append(theCommits, compare.Commits[i])
+		}
+	}
+
+	for _, c := range theCommits {
+		commitsResult = append(commitsResult, g.toVCSCommit(repo, c))
+	}
+
+	cache.SetWithTTL(cacheKey, commitsResult, 3*60*60)
+
+	return commitsResult, nil
+}
+
+func (g *GitlabClient) toVCSCommit(repo string, c Commit) sdk.VCSCommit {
+	commit := sdk.VCSCommit{
+		Hash:    c.ID,
+		Message: c.Message,
+		URL:     fmt.Sprintf("%s/%s/commit/%s", g.URL, repo, c.ID),
+		Author: sdk.VCSAuthor{
+			Name:        c.AuthorName,
+			DisplayName: c.AuthorName,
+			Email:       c.AuthorEmail,
+		},
+	}
+	if c.CreatedAt != nil {
+		commit.Timestamp = c.CreatedAt.Unix() * 1000
+	}
+	return commit
+}
+
+// Commit Get a single commit
+// https://docs.gitlab.com/ce/api/commits.html#get-a-single-commit
+func (g *GitlabClient) Commit(repo, hash string) (sdk.VCSCommit, error) {
+	c := Commit{}
+	cacheKey := cache.Key("reposmanager", "gitlab", "commit", g.URL, repo, hash)
+	if !cache.Get(cacheKey, &c) || c.ID == "" {
+		status, body, _, err := g.get(projectPath(repo) + "/repository/commits/" + url.QueryEscape(hash))
+		if err != nil {
+			log.Warning("GitlabClient.Commit> Error %s", err)
+			return sdk.VCSCommit{}, err
+		}
+		if status >= 400 {
+			return sdk.VCSCommit{}, sdk.NewError(sdk.ErrRepoNotFound, ErrorAPI(body))
+		}
+		if err := json.Unmarshal(body, &c); err != nil {
+			log.Warning("GitlabClient.Commit> Unable to parse gitlab commit: %s", err)
+			return sdk.VCSCommit{}, err
+		}
+		//A commit never changes, keep it for one day
+		cache.SetWithTTL(cacheKey, c, 24*60*60)
+	}
+
+	return g.toVCSCommit(repo, c), nil
+}
+
+func (g *GitlabClient) hooks(repo string) ([]Hook, error) {
+	hooks := []Hook{}
+	err := g.getAll(projectPath(repo)+"/hooks", func(body []byte) error {
+		nextHooks := []Hook{}
+		if err := json.Unmarshal(body, &nextHooks); err != nil {
+			return err
+		}
+		hooks = append(hooks, nextHooks...)
+		return nil
+	})
+	return hooks, err
+}
+
+//CreateHook creates a project hook on gitlab
+//https://docs.gitlab.com/ce/api/projects.html#add-project-hook
+func (g *GitlabClient) CreateHook(repo, url string) error {
+	hooks, err := g.hooks(repo)
+	if err != nil {
+		if err == ErrorUnauthorized {
+			return sdk.ErrNoReposManagerClientAuth
+		}
+		return err
+	}
+	for _, h := range hooks {
+		if h.URL == url {
+			log.Info("CreateHook> Hook already exists on %s", repo)
+			return nil
+		}
+	}
+
+	h := Hook{
+		URL:                   url,
+		PushEvents:            true,
+		EnableSSLVerification: true,
+	}
+	log.Info("CreateHook> Ask Gitlab to create Hook on %s: %s", repo, url)
+	status, body, _, err := g.post(projectPath(repo)+"/hooks", h)
+	if err != nil {
+		return err
+	}
+	if status >= 400 {
+		return sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+	}
+	return nil
+}
+
+//DeleteHook deletes the project hook matching the url
+//https://docs.gitlab.com/ce/api/projects.html#delete-project-hook
+func (g *GitlabClient) DeleteHook(repo, url string) error {
+	hooks, err := g.hooks(repo)
+	if err != nil {
+		if err == ErrorUnauthorized {
+			return sdk.ErrNoReposManagerClientAuth
+		}
+		return err
+	}
+	for _, h := range hooks {
+		if h.URL != url {
+			continue
+		}
+		log.Info("DeleteHook> Ask Gitlab to delete Hook %d on %s", h.ID, repo)
+		status, body, _, err := g.delete(fmt.Sprintf("%s/hooks/%d", projectPath(repo), h.ID))
+		if err != nil {
+			return err
+		}
+		if status >= 400 {
+			return sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+		}
+	}
+	return nil
+}
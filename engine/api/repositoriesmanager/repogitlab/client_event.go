@@ -0,0 +1,280 @@
+package repogitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//GetEvents calls Gitlab and returns gitlab Events as []interface{}
+//https://docs.gitlab.com/ce/api/events.html#list-a-project-s-visible-events
+func (g *GitlabClient) GetEvents(fullname string, dateRef time.Time) ([]interface{}, time.Duration, error) {
+	log.Debug("GitlabClient.GetEvents> loading events for %s after %v", fullname, dateRef)
+	var events = []interface{}{}
+
+	interval := 60 * time.Second
+
+	//Gitlab filters events by day, so we have to filter on the exact date ourself
+	v := url.Values{}
+	v.Add("after", dateRef.AddDate(0, 0, -1).Format("2006-01-02"))
+	v.Add("per_page", "100")
+
+	status, body, _, err := g.get(projectPath(fullname) + "/events?" + v.Encode())
+	if err != nil {
+		log.Warning("GitlabClient.GetEvents> Error %s", err)
+		return nil, interval, err
+	}
+
+	if status >= 400 {
+		err := sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+		log.Warning("GitlabClient.GetEvents> Error http %s", err)
+		return nil, interval, err
+	}
+
+	nextEvents := []Event{}
+	if err := json.Unmarshal(body, &nextEvents); err != nil {
+		log.Warning("GitlabClient.GetEvents> Unable to parse gitlab events: %s", err)
+		return nil, interval, fmt.Errorf("Unable to parse gitlab events %s: %s", string(body), err)
+	}
+
+	for _, e := range nextEvents {
+		if e.CreatedAt.After(dateRef) {
+			events = append(events, e)
+		}
+	}
+
+	if len(events) == 0 {
+		return nil, interval, fmt.Errorf("No new events")
+	}
+
+	return events, interval, nil
+}
+
+func filterPushEvents(iEvents []interface{}, action string) Events {
+	events := Events{}
+	for _, i := range iEvents {
+		e := i.(Event)
+		if e.PushData != nil && e.PushData.RefType == "branch" && e.PushData.Action == action {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+//PushEvents returns push events as commits
+func (g *GitlabClient) PushEvents(fullname string, iEvents []interface{}) ([]sdk.VCSPushEvent, error) {
+	events := filterPushEvents(iEvents, "pushed")
+
+	lastCommitPerBranch := map[string]sdk.VCSCommit{}
+	for _, e := range events {
+		commit := sdk.VCSCommit{
+			Hash:      e.PushData.CommitTo,
+			Message:   e.PushData.CommitTitle,
+			Timestamp: e.CreatedAt.Unix() * 1000,
+			URL:       fmt.Sprintf("%s/%s/commit/%s", g.URL, fullname, e.PushData.CommitTo),
+			Author: sdk.VCSAuthor{
+				DisplayName: e.Author.Name,
+				Name:        e.Author.Username,
+				Avatar:      e.Author.AvatarURL,
+			},
+		}
+		l, b := lastCommitPerBranch[e.PushData.Ref]
+		if !b || l.Timestamp < commit.Timestamp {
+			lastCommitPerBranch[e.PushData.Ref] = commit
+		}
+	}
+
+	res := []sdk.VCSPushEvent{}
+	for b, c := range lastCommitPerBranch {
+		branch, err := g.Branch(fullname, b)
+		if err != nil {
+			log.Warning("GitlabClient.PushEvents> Unable to find branch %s in %s : %s", b, fullname, err)
+			continue
+		}
+		res = append(res, sdk.VCSPushEvent{
+			Branch: branch,
+			Commit: c,
+		})
+	}
+
+	return res, nil
+}
+
+//CreateEvents checks create events from a event list
+func (g *GitlabClient) CreateEvents(fullname string, iEvents []interface{}) ([]sdk.VCSCreateEvent, error) {
+	events := filterPushEvents(iEvents, "created")
+
+	res := []sdk.VCSCreateEvent{}
+	for _, e := range events {
+		b := e.PushData.Ref
+		branch, err := g.Branch(fullname, b)
+		if err != nil {
+			log.Warning("GitlabClient.CreateEvents> Unable to find branch %s in %s : %s", b, fullname, err)
+			continue
+		}
+		event := sdk.VCSCreateEvent{
+			Branch: branch,
+		}
+
+		c, err := g.Commit(fullname, branch.LatestCommit)
+		if err != nil {
+			log.Warning("GitlabClient.CreateEvents> Unable to find commit %s in %s : %s", branch.LatestCommit, fullname, err)
+			continue
+		}
+		event.Commit = c
+
+		res = append(res, event)
+	}
+
+	log.Debug("GitlabClient.CreateEvents> found %d create events : %#v", len(res), res)
+
+	return res, nil
+}
+
+//DeleteEvents checks delete events from a event list
+func (g *GitlabClient) DeleteEvents(fullname string, iEvents []interface{}) ([]sdk.VCSDeleteEvent, error) {
+	events := filterPushEvents(iEvents, "removed")
+
+	res := []sdk.VCSDeleteEvent{}
+	for _, e := range events {
+		event := sdk.VCSDeleteEvent{
+			Branch: sdk.VCSBranch{
+				DisplayID: e.PushData.Ref,
+			},
+		}
+		res = append(res, event)
+	}
+
+	log.Debug("GitlabClient.DeleteEvents> found %d delete events : %#v", len(res), res)
+	return res, nil
+}
+
+//PullRequestEvents checks merge request events from a event list.
+//Gitlab does not send an event when a merge request is updated, so pushes on the source branch
+//of an opened merge request are returned as synchronize events
+func (g *GitlabClient) PullRequestEvents(fullname string, iEvents []interface{}) ([]sdk.VCSPullRequestEvent, error) {
+	res := []sdk.VCSPullRequestEvent{}
+	seen := map[int]bool{}
+	for _, i := range iEvents {
+		e := i.(Event)
+		if e.TargetType != "MergeRequest" {
+			continue
+		}
+
+		var action string
+		switch e.ActionName {
+		case "opened", "reopened":
+			action = "opened"
+		case "closed", "accepted":
+			action = "closed"
+		default:
+			continue
+		}
+
+		mr, err := g.mergeRequest(fullname, e.TargetIID)
+		if err != nil {
+			log.Warning("GitlabClient.PullRequestEvents> Unable to find merge request %d in %s : %s", e.TargetIID, fullname, err)
+			continue
+		}
+
+		seen[mr.IID] = true
+		res = append(res, mr.pullRequestEvent(action))
+	}
+
+	pushes := filterPushEvents(iEvents, "pushed")
+	if len(pushes) > 0 {
+		branches := map[string]bool{}
+		for _, e := range pushes {
+			branches[e.PushData.Ref] = true
+		}
+
+		mrs, err := g.openedMergeRequests(fullname)
+		if err != nil {
+			log.Warning("GitlabClient.PullRequestEvents> Unable to list merge requests of %s : %s", fullname, err)
+			return res, nil
+		}
+
+		for _, mr := range mrs {
+			//Merge requests from forks are not updated by pushes on this repository
+			if seen[mr.IID] || mr.SourceProjectID != mr.TargetProjectID || !branches[mr.SourceBranch] {
+				continue
+			}
+			res = append(res, mr.pullRequestEvent("synchronize"))
+		}
+	}
+
+	log.Debug("GitlabClient.PullRequestEvents> found %d merge request events : %#v", len(res), res)
+	return res, nil
+}
+
+func (mr MergeRequest) pullRequestEvent(action string) sdk.VCSPullRequestEvent {
+	head := sdk.VCSPushEvent{
+		Branch: sdk.VCSBranch{
+			ID:           mr.SourceBranch,
+			DisplayID:    mr.SourceBranch,
+			LatestCommit: mr.SHA,
+		},
+		Commit: sdk.VCSCommit{
+			Hash: mr.SHA,
+		},
+	}
+	base := sdk.VCSPushEvent{
+		Branch: sdk.VCSBranch{
+			ID:           mr.TargetBranch,
+			DisplayID:    mr.TargetBranch,
+			LatestCommit: mr.DiffRefs.BaseSHA,
+		},
+		Commit: sdk.VCSCommit{
+			Hash: mr.DiffRefs.BaseSHA,
+		},
+	}
+
+	return sdk.VCSPullRequestEvent{
+		Action:   action,
+		Number:   mr.IID,
+		URL:      mr.WebURL,
+		MergeRef: fmt.Sprintf("refs/merge-requests/%d/merge", mr.IID),
+		User: sdk.VCSAuthor{
+			Name:        mr.Author.Username,
+			DisplayName: mr.Author.Name,
+			Avatar:      mr.Author.AvatarURL,
+		},
+		Head:   head,
+		Base:   base,
+		Branch: head.Branch,
+	}
+}
+
+// https://docs.gitlab.com/ce/api/merge_requests.html#list-project-merge-requests
+func (g *GitlabClient) openedMergeRequests(fullname string) ([]MergeRequest, error) {
+	mrs := []MergeRequest{}
+	err := g.getAll(projectPath(fullname)+"/merge_requests?state=opened", func(body []byte) error {
+		page := []MergeRequest{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		mrs = append(mrs, page...)
+		return nil
+	})
+	return mrs, err
+}
+
+// https://docs.gitlab.com/ce/api/merge_requests.html#get-single-mr
+func (g *GitlabClient) mergeRequest(fullname string, iid int) (MergeRequest, error) {
+	mr := MergeRequest{}
+	status, body, _, err := g.get(fmt.Sprintf("%s/merge_requests/%d", projectPath(fullname), iid))
+	if err != nil {
+		return mr, err
+	}
+	if status >= 400 {
+		return mr, sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+	}
+	if err := json.Unmarshal(body, &mr); err != nil {
+		return mr, err
+	}
+	return mr, nil
+}
@@ -0,0 +1,110 @@
+package repogitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//SetStatus Users with push access can create commit statuses for a given sha:
+//https://docs.gitlab.com/ce/api/commits.html#post-the-build-status-to-a-commit
+func (g *GitlabClient) SetStatus(event sdk.Event) error {
+	log.Debug("gitlab.SetStatus> receive: type:%s all: %+v", event.EventType, event)
+	var eventpb sdk.EventPipelineBuild
+
+	if event.EventType != fmt.Sprintf("%T", sdk.EventPipelineBuild{}) {
+		return nil
+	}
+
+	if g.DisableSetStatus {
+		log.Warning("⚠ Gitlab statuses are disabled")
+		return nil
+	}
+
+	if err := mapstructure.Decode(event.Payload, &eventpb); err != nil {
+		log.Warning("Error during consumption: %s", err)
+		return err
+	}
+
+	var status string
+	switch eventpb.Status {
+	case sdk.StatusBuilding:
+		status = "running"
+	case sdk.StatusFail:
+		status = "failed"
+	case sdk.StatusSuccess:
+		status = "success"
+	case sdk.StatusWaiting:
+		status = "pending"
+	default:
+		return nil
+	}
+
+	var desc string
+	switch eventpb.PipelineType {
+	case sdk.BuildPipeline:
+		desc = fmt.Sprintf("Build pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
+	case sdk.TestingPipeline:
+		desc = fmt.Sprintf("Testing pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
+	case sdk.DeploymentPipeline:
+		desc = fmt.Sprintf("Deployment pipeline %s: %s", eventpb.PipelineName, eventpb.Status.String())
+	default:
+		log.Warning("Unrecognized pipeline type : %v", eventpb.PipelineType)
+		return nil
+	}
+
+	targetURL := fmt.Sprintf("%s#/project/%s/application/%s/pipeline/%s/build/%d?env=%s",
+		uiURL,
+		eventpb.ProjectKey,
+		eventpb.ApplicationName,
+		eventpb.PipelineName,
+		eventpb.BuildNumber,
+		url.QueryEscape(eventpb.EnvironmentName),
+	)
+
+	//CDS can avoid sending gitlab target url in status, if it's disable
+	if g.DisableStatusURL {
+		targetURL = ""
+	}
+
+	glStatus := CommitStatus{
+		Name:        fmt.Sprintf("continuous-delivery/CDS/%s", eventpb.PipelineName),
+		Ref:         eventpb.BranchName,
+		State:       status,
+		TargetURL:   targetURL,
+		Description: desc,
+	}
+
+	//Merge request builds run on refs/merge-requests/<iid>/merge which is not a branch:
+	//the status is only set on the head commit
+	if strings.HasPrefix(eventpb.BranchName, "refs/") {
+		glStatus.Ref = ""
+	}
+
+	path := fmt.Sprintf("%s/statuses/%s", projectPath(eventpb.RepositoryFullname), eventpb.Hash)
+	code, body, _, err := g.post(path, glStatus)
+	if err != nil {
+		return err
+	}
+
+	if code != 201 && code != 200 {
+		err := fmt.Errorf("Unable to create status on gitlab. Status code : %d - Body: %s", code, body)
+		log.Warning("SetStatus> %s", err)
+		return err
+	}
+
+	s := &CommitStatus{}
+	if err := json.Unmarshal(body, s); err != nil {
+		return err
+	}
+
+	log.Debug("SetStatus> Status %d %s created", s.ID, s.State)
+
+	return nil
+}
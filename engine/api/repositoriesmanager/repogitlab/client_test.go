@@ -0,0 +1,338 @@
+package repogitlab
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const fakeToken = "my-token"
+
+type fakeGitlab struct {
+	*httptest.Server
+	hooks    []Hook
+	statuses []CommitStatus
+}
+
+func newFakeGitlab(t *testing.T) *fakeGitlab {
+	f := &fakeGitlab{}
+	mux := http.NewServeMux()
+
+	writeJSON := func(w http.ResponseWriter, code int, i interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(i)
+	}
+
+	checkAuth := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+fakeToken {
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "401 Unauthorized"})
+				return
+			}
+			h(w, r)
+		}
+	}
+
+	project := Project{
+		ID:                42,
+		Name:              "my-repo",
+		Path:              "my-repo",
+		PathWithNamespace: "my-group/my-repo",
+		DefaultBranch:     "master",
+		WebURL:            "http://gitlab.local/my-group/my-repo",
+		HTTPURLToRepo:     "http://gitlab.local/my-group/my-repo.git",
+		SSHURLToRepo:      "git@gitlab.local:my-group/my-repo.git",
+	}
+	created := time.Now()
+
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.FormValue("code") != "my-code" || r.FormValue("client_secret") != "my-secret" || r.FormValue("grant_type") != "authorization_code" {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"access_token": fakeToken, "token_type": "bearer"})
+	})
+
+	mux.HandleFunc("/api/v4/projects", checkAuth(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("X-Next-Page", "2")
+			writeJSON(w, http.StatusOK, []Project{project})
+			return
+		}
+		other := project
+		other.ID = 43
+		other.PathWithNamespace = "my-group/other-repo"
+		writeJSON(w, http.StatusOK, []Project{other})
+	}))
+
+	mux.HandleFunc("/api/v4/projects/", checkAuth(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/projects/")
+		if !strings.HasPrefix(path, "my-group%2Fmy-repo") {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "404 Project Not Found"})
+			return
+		}
+		path = strings.TrimPrefix(path, "my-group%2Fmy-repo")
+
+		switch {
+		case path == "":
+			writeJSON(w, http.StatusOK, project)
+		case path == "/repository/branches":
+			writeJSON(w, http.StatusOK, []Branch{
+				{Name: "master", Commit: Commit{ID: "aaaa"}},
+				{Name: "feat", Commit: Commit{ID: "bbbb"}},
+			})
+		case path == "/repository/branches/feat":
+			writeJSON(w, http.StatusOK, Branch{Name: "feat", Commit: Commit{ID: "bbbb"}})
+		case path == "/repository/branches/master":
+			writeJSON(w, http.StatusOK, Branch{Name: "master", Commit: Commit{ID: "aaaa"}})
+		case strings.HasPrefix(path, "/repository/branches/"):
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "404 Branch Not Found"})
+		case path == "/repository/commits":
+			writeJSON(w, http.StatusOK, []Commit{
+				{ID: "aaaa", Message: "second", AuthorName: "john", CreatedAt: &created},
+				{ID: "0000", Message: "first", AuthorName: "john", CreatedAt: &created},
+			})
+		case path == "/repository/commits/bbbb":
+			writeJSON(w, http.StatusOK, Commit{ID: "bbbb", Message: "feature", AuthorName: "jane", AuthorEmail: "jane@localhost", CreatedAt: &created})
+		case path == "/repository/compare":
+			assert.Equal(t, "0000", r.URL.Query().Get("from"))
+			assert.Equal(t, "cccc", r.URL.Query().Get("to"))
+			writeJSON(w, http.StatusOK, Compare{Commits: []Commit{
+				{ID: "bbbb", Message: "b", CreatedAt: &created},
+				{ID: "cccc", Message: "c", CreatedAt: &created},
+			}})
+		case path == "/hooks" && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, f.hooks)
+		case path == "/hooks" && r.Method == http.MethodPost:
+			h := Hook{}
+			json.NewDecoder(r.Body).Decode(&h)
+			h.ID = len(f.hooks) + 1
+			f.hooks = append(f.hooks, h)
+			writeJSON(w, http.StatusCreated, h)
+		case strings.HasPrefix(path, "/hooks/") && r.Method == http.MethodDelete:
+			f.hooks = nil
+			w.WriteHeader(http.StatusNoContent)
+		case path == "/events":
+			writeJSON(w, http.StatusOK, []Event{
+				{ActionName: "pushed to", CreatedAt: created, Author: User{Username: "jane", Name: "Jane"}, PushData: &PushData{Action: "pushed", RefType: "branch", Ref: "feat", CommitTo: "bbbb", CommitTitle: "feature"}},
+				{ActionName: "pushed new", CreatedAt: created, PushData: &PushData{Action: "created", RefType: "branch", Ref: "feat", CommitTo: "bbbb"}},
+				{ActionName: "deleted", CreatedAt: created, PushData: &PushData{Action: "removed", RefType: "branch", Ref: "old"}},
+				{ActionName: "opened", CreatedAt: created, TargetType: "MergeRequest", TargetIID: 3},
+				{ActionName: "pushed to", CreatedAt: created.Add(-48 * time.Hour), PushData: &PushData{Action: "pushed", RefType: "branch", Ref: "master", CommitTo: "aaaa"}},
+			})
+		case path == "/merge_requests":
+			writeJSON(w, http.StatusOK, []MergeRequest{
+				{IID: 3, SourceBranch: "feat", TargetBranch: "master", SourceProjectID: 1, TargetProjectID: 1, SHA: "bbbb"},
+				{IID: 5, SourceBranch: "feat", TargetBranch: "release", SourceProjectID: 1, TargetProjectID: 1, SHA: "bbbb", Author: User{Username: "john"}, DiffRefs: DiffRefs{BaseSHA: "cccc"}},
+				{IID: 6, SourceBranch: "feat", TargetBranch: "master", SourceProjectID: 2, TargetProjectID: 1, SHA: "dddd"},
+			})
+		case path == "/merge_requests/3":
+			writeJSON(w, http.StatusOK, MergeRequest{IID: 3, SourceBranch: "feat", TargetBranch: "master", SHA: "bbbb", WebURL: "http://gitlab.local/my-group/my-repo/merge_requests/3", Author: User{Username: "jane"}, DiffRefs: DiffRefs{BaseSHA: "aaaa"}})
+		case strings.HasPrefix(path, "/statuses/"):
+			s := CommitStatus{}
+			json.NewDecoder(r.Body).Decode(&s)
+			s.ID = 1
+			f.statuses = append(f.statuses, s)
+			writeJSON(w, http.StatusCreated, s)
+		default:
+			t.Errorf("Unexpected call %s %s", r.Method, r.URL)
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "404 Not found"})
+		}
+	}))
+
+	f.Server = httptest.NewServer(mux)
+	return f
+}
+
+func newTestConsumer(t *testing.T, URL string) *GitlabConsumer {
+	tmpfile, err := ioutil.TempFile("", "gitlab-secret")
+	assert.NoError(t, err)
+	tmpfile.Write([]byte("my-secret\n"))
+	tmpfile.Close()
+	return New(URL+"/", "my-client-id", tmpfile.Name(), "http://cds.local/repositories_manager/oauth2/callback")
+}
+
+func TestGitlabConsumerOAuth(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	consumer := newTestConsumer(t, f.URL)
+	defer os.Remove(consumer.ClientSecret)
+
+	state, authorizeURL, err := consumer.AuthorizeRedirect()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, state)
+	assert.True(t, strings.HasPrefix(authorizeURL, f.URL+"/oauth/authorize?"))
+	assert.Contains(t, authorizeURL, "client_id=my-client-id")
+	assert.Contains(t, authorizeURL, "response_type=code")
+
+	token, secret, err := consumer.AuthorizeToken(state, "my-code")
+	assert.NoError(t, err)
+	assert.Equal(t, fakeToken, token)
+	assert.Equal(t, state, secret)
+
+	_, _, err = consumer.AuthorizeToken(state, "bad-code")
+	assert.Error(t, err)
+}
+
+func TestGitlabClientRepos(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	client, err := New(f.URL, "id", "secret", "").GetAuthorized(fakeToken, "")
+	assert.NoError(t, err)
+
+	repos, err := client.Repos()
+	assert.NoError(t, err)
+	assert.Len(t, repos, 2)
+	assert.Equal(t, "my-group/my-repo", repos[0].Fullname)
+	assert.Equal(t, "42", repos[0].ID)
+	assert.Equal(t, "my-group/other-repo", repos[1].Fullname)
+
+	repo, err := client.RepoByFullname("my-group/my-repo")
+	assert.NoError(t, err)
+	assert.Equal(t, "git@gitlab.local:my-group/my-repo.git", repo.SSHCloneURL)
+
+	_, err = client.RepoByFullname("my-group/unknown")
+	assert.Error(t, err)
+
+	unauthorized, _ := New(f.URL, "id", "secret", "").GetAuthorized("bad-token", "")
+	_, err = unauthorized.Repos()
+	assert.Error(t, err)
+}
+
+func TestGitlabClientBranchesAndCommits(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	client, _ := New(f.URL, "id", "secret", "").GetAuthorized(fakeToken, "")
+
+	branches, err := client.Branches("my-group/my-repo")
+	assert.NoError(t, err)
+	assert.Len(t, branches, 2)
+	assert.True(t, branches[0].Default)
+	assert.False(t, branches[1].Default)
+	assert.Equal(t, "bbbb", branches[1].LatestCommit)
+
+	b, err := client.Branch("my-group/my-repo", "feat")
+	assert.NoError(t, err)
+	assert.Equal(t, "feat", b.DisplayID)
+
+	_, err = client.Branch("my-group/my-repo", "unknown")
+	assert.Equal(t, sdk.ErrNoBranch, err)
+
+	commits, err := client.Commits("my-group/my-repo", "master", "", "aaaa")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "aaaa", commits[0].Hash)
+
+	commits, err = client.Commits("my-group/my-repo", "feat", "0000", "cccc")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "cccc", commits[0].Hash)
+	assert.Equal(t, "bbbb", commits[1].Hash)
+
+	c, err := client.Commit("my-group/my-repo", "bbbb")
+	assert.NoError(t, err)
+	assert.Equal(t, "jane@localhost", c.Author.Email)
+	assert.Equal(t, f.URL+"/my-group/my-repo/commit/bbbb", c.URL)
+}
+
+func TestGitlabClientHooks(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	client, _ := New(f.URL, "id", "secret", "").GetAuthorized(fakeToken, "")
+
+	assert.NoError(t, client.CreateHook("my-group/my-repo", "http://cds.local/hook?uid=1"))
+	assert.NoError(t, client.CreateHook("my-group/my-repo", "http://cds.local/hook?uid=1"))
+	assert.Len(t, f.hooks, 1)
+	assert.True(t, f.hooks[0].PushEvents)
+
+	assert.NoError(t, client.DeleteHook("my-group/my-repo", "http://cds.local/hook?uid=1"))
+	assert.Len(t, f.hooks, 0)
+}
+
+func TestGitlabClientEvents(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	client, _ := New(f.URL, "id", "secret", "").GetAuthorized(fakeToken, "")
+
+	events, _, err := client.GetEvents("my-group/my-repo", time.Now().Add(-1*time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, events, 4)
+
+	pushs, err := client.PushEvents("my-group/my-repo", events)
+	assert.NoError(t, err)
+	assert.Len(t, pushs, 1)
+	assert.Equal(t, "feat", pushs[0].Branch.DisplayID)
+	assert.Equal(t, "bbbb", pushs[0].Commit.Hash)
+	assert.Equal(t, "jane", pushs[0].Commit.Author.Name)
+
+	creates, err := client.CreateEvents("my-group/my-repo", events)
+	assert.NoError(t, err)
+	assert.Len(t, creates, 1)
+	assert.Equal(t, "feature", creates[0].Commit.Message)
+
+	deletes, err := client.DeleteEvents("my-group/my-repo", events)
+	assert.NoError(t, err)
+	assert.Len(t, deletes, 1)
+	assert.Equal(t, "old", deletes[0].Branch.DisplayID)
+
+	prs, err := client.PullRequestEvents("my-group/my-repo", events)
+	assert.NoError(t, err)
+	assert.Len(t, prs, 2)
+	assert.Equal(t, "opened", prs[0].Action)
+	assert.Equal(t, 3, prs[0].Number)
+	assert.Equal(t, "refs/merge-requests/3/merge", prs[0].MergeRef)
+	assert.Equal(t, "feat", prs[0].Head.Branch.DisplayID)
+	assert.Equal(t, "master", prs[0].Base.Branch.DisplayID)
+	assert.Equal(t, "bbbb", prs[0].Head.Commit.Hash)
+
+	//Push on feat synchronizes the other merge request, but not the one from a fork
+	assert.Equal(t, "synchronize", prs[1].Action)
+	assert.Equal(t, 5, prs[1].Number)
+	assert.Equal(t, "release", prs[1].Base.Branch.DisplayID)
+}
+
+func TestGitlabClientSetStatus(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	client, _ := New(f.URL, "id", "secret", "").GetAuthorized(fakeToken, "")
+
+	e := sdk.Event{
+		EventType: "sdk.EventPipelineBuild",
+		Payload: map[string]interface{}{
+			"Status":             sdk.StatusSuccess,
+			"PipelineName":       "build",
+			"PipelineType":       sdk.BuildPipeline,
+			"ProjectKey":         "KEY",
+			"ApplicationName":    "app",
+			"BranchName":         "master",
+			"Hash":               "aaaa",
+			"RepositoryFullname": "my-group/my-repo",
+		},
+	}
+	assert.NoError(t, client.SetStatus(e))
+	assert.Len(t, f.statuses, 1)
+	assert.Equal(t, "success", f.statuses[0].State)
+	assert.Equal(t, "continuous-delivery/CDS/build", f.statuses[0].Name)
+
+	//Checking status is not sent
+	e.Payload["Status"] = sdk.StatusChecking
+	assert.NoError(t, client.SetStatus(e))
+	assert.Len(t, f.statuses, 1)
+}
@@ -0,0 +1,48 @@
+package repogitlab
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+//Error wraps gitlab error format
+type Error struct {
+	ID   string `json:"error"`
+	Desc string `json:"error_description"`
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("(gl_%s) %s", e.ID, e.Desc)
+}
+
+func (e Error) String() string {
+	return e.Error()
+}
+
+//Gitlab errors
+var (
+	ErrorUnauthorized = &Error{
+		ID:   "unauthorized",
+		Desc: "Bad credentials",
+	}
+)
+
+//ErrorAPI creates a new error from a gitlab API response body
+//Gitlab API returns either {"message": "..."} or {"error": "..."}
+func ErrorAPI(body []byte) Error {
+	res := map[string]interface{}{}
+	json.Unmarshal(body, &res)
+	e := Error{ID: "api_error"}
+	switch m := res["message"].(type) {
+	case string:
+		e.Desc = m
+	case nil:
+		if s, ok := res["error"].(string); ok {
+			e.Desc = s
+		}
+	default:
+		b, _ := json.Marshal(m)
+		e.Desc = string(b)
+	}
+	return e
+}
@@ -0,0 +1,148 @@
+package repogitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/facebookgo/httpcontrol"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//Gitlab http var
+var (
+	httpClient = &http.Client{
+		Transport: &httpcontrol.Transport{
+			RequestTimeout: time.Second * 30,
+			MaxTries:       5,
+		},
+	}
+)
+
+const apiPath = "/api/v4"
+
+func projectPath(fullname string) string {
+	return "/projects/" + url.QueryEscape(fullname)
+}
+
+func (g *GitlabConsumer) postForm(path string, data url.Values) (int, []byte, error) {
+	body := strings.NewReader(data.Encode())
+
+	req, err := http.NewRequest(http.MethodPost, g.URL+path, body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "CDS-gl_client_id="+g.ClientID)
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, nil, err
+	}
+
+	if res.StatusCode >= 400 {
+		glErr := &Error{}
+		if err := json.Unmarshal(resBody, glErr); err == nil && glErr.ID != "" {
+			return res.StatusCode, resBody, glErr
+		}
+	}
+
+	return res.StatusCode, resBody, nil
+}
+
+func (c *GitlabClient) do(method, path string, in interface{}) (int, []byte, http.Header, error) {
+	if !strings.HasPrefix(path, c.URL) {
+		path = c.URL + apiPath + path
+	}
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		body = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequest(method, path, body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	req.Header.Set("User-Agent", "CDS")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.OAuthToken))
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Debug("Gitlab API>> Request %s %s", method, req.URL.String())
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusUnauthorized {
+		return res.StatusCode, nil, nil, ErrorUnauthorized
+	}
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, nil, nil, err
+	}
+
+	return res.StatusCode, resBody, res.Header, nil
+}
+
+func (c *GitlabClient) get(path string) (int, []byte, http.Header, error) {
+	return c.do(http.MethodGet, path, nil)
+}
+
+func (c *GitlabClient) post(path string, in interface{}) (int, []byte, http.Header, error) {
+	return c.do(http.MethodPost, path, in)
+}
+
+func (c *GitlabClient) delete(path string) (int, []byte, http.Header, error) {
+	return c.do(http.MethodDelete, path, nil)
+}
+
+//getAll follows gitlab pagination and calls fn with the body of each page
+//https://docs.gitlab.com/ce/api/README.html#pagination
+func (c *GitlabClient) getAll(path string, fn func(body []byte) error) error {
+	var page = "1"
+	for page != "" {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		status, body, headers, err := c.get(path + sep + "per_page=100&page=" + page)
+		if err != nil {
+			return err
+		}
+		if status >= 400 {
+			return sdk.NewError(sdk.ErrUnknownError, ErrorAPI(body))
+		}
+		if err := fn(body); err != nil {
+			return err
+		}
+		page = headers.Get("X-Next-Page")
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package repogitlab
+
+var (
+	apiURL string
+	uiURL  string
+)
+
+// Init initializes repogitlab package
+func Init(apiurl, uiurl string) {
+	apiURL = apiurl
+	uiURL = uiurl
+}
@@ -0,0 +1,124 @@
+package repogitlab
+
+import "time"
+
+// Project represents a Gitlab project
+// https://docs.gitlab.com/ce/api/projects.html
+type Project struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	Path              string `json:"path"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+	WebURL            string `json:"web_url"`
+	HTTPURLToRepo     string `json:"http_url_to_repo"`
+	SSHURLToRepo      string `json:"ssh_url_to_repo"`
+}
+
+// Branch represents a Gitlab repository branch
+// https://docs.gitlab.com/ce/api/branches.html
+type Branch struct {
+	Name    string `json:"name"`
+	Default bool   `json:"default"`
+	Commit  Commit `json:"commit"`
+}
+
+// Commit represents a Gitlab commit
+// https://docs.gitlab.com/ce/api/commits.html
+type Commit struct {
+	ID          string     `json:"id"`
+	ShortID     string     `json:"short_id"`
+	Title       string     `json:"title"`
+	Message     string     `json:"message"`
+	AuthorName  string     `json:"author_name"`
+	AuthorEmail string     `json:"author_email"`
+	CreatedAt   *time.Time `json:"created_at"`
+	ParentIDs   []string   `json:"parent_ids"`
+}
+
+// Compare represents the result of a comparison between two refs
+type Compare struct {
+	Commits []Commit `json:"commits"`
+}
+
+// User represents a Gitlab user
+type User struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// Event represents a Gitlab project event
+// https://docs.gitlab.com/ce/api/events.html
+type Event struct {
+	ID         int       `json:"id"`
+	ProjectID  int       `json:"project_id"`
+	ActionName string    `json:"action_name"`
+	TargetID   int       `json:"target_id"`
+	TargetIID  int       `json:"target_iid"`
+	TargetType string    `json:"target_type"`
+	CreatedAt  time.Time `json:"created_at"`
+	Author     User      `json:"author"`
+	PushData   *PushData `json:"push_data"`
+}
+
+// PushData is the payload of push events
+type PushData struct {
+	CommitCount int    `json:"commit_count"`
+	Action      string `json:"action"` // pushed | created | removed
+	RefType     string `json:"ref_type"`
+	CommitFrom  string `json:"commit_from"`
+	CommitTo    string `json:"commit_to"`
+	Ref         string `json:"ref"`
+	CommitTitle string `json:"commit_title"`
+}
+
+// Events is a list of Event
+type Events []Event
+
+// MergeRequest represents a Gitlab merge request
+// https://docs.gitlab.com/ce/api/merge_requests.html
+type MergeRequest struct {
+	ID              int      `json:"id"`
+	IID             int      `json:"iid"`
+	Title           string   `json:"title"`
+	State           string   `json:"state"`
+	SourceBranch    string   `json:"source_branch"`
+	TargetBranch    string   `json:"target_branch"`
+	SourceProjectID int      `json:"source_project_id"`
+	TargetProjectID int      `json:"target_project_id"`
+	SHA             string   `json:"sha"`
+	WebURL          string   `json:"web_url"`
+	Author          User     `json:"author"`
+	DiffRefs        DiffRefs `json:"diff_refs"`
+}
+
+// DiffRefs are the references of a merge request diff
+type DiffRefs struct {
+	BaseSHA  string `json:"base_sha"`
+	HeadSHA  string `json:"head_sha"`
+	StartSHA string `json:"start_sha"`
+}
+
+// Hook represents a Gitlab project hook
+// https://docs.gitlab.com/ce/api/projects.html#hooks
+type Hook struct {
+	ID                    int    `json:"id,omitempty"`
+	URL                   string `json:"url"`
+	PushEvents            bool   `json:"push_events"`
+	TagPushEvents         bool   `json:"tag_push_events"`
+	MergeRequestsEvents   bool   `json:"merge_requests_events"`
+	EnableSSLVerification bool   `json:"enable_ssl_verification"`
+}
+
+// CommitStatus represents a commit status
+// https://docs.gitlab.com/ce/api/commits.html#post-the-build-status-to-a-commit
+type CommitStatus struct {
+	ID          int    `json:"id,omitempty"`
+	State       string `json:"state"`
+	Ref         string `json:"ref,omitempty"`
+	Name        string `json:"name"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description"`
+}
@@ -13,6 +13,8 @@ import (
 
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/api/repositoriesmanager/repogithub"
+	"github.com/ovh/cds/engine/api/repositoriesmanager/repogitea"
+	"github.com/ovh/cds/engine/api/repositoriesmanager/repogitlab"
 	"github.com/ovh/cds/engine/api/repositoriesmanager/repostash"
 	"github.com/ovh/cds/engine/api/secret/secretbackend"
 	"github.com/ovh/cds/sdk"
@@ -33,7 +35,12 @@ type InitializeOpts struct {
 	DisableStashSetStatus  bool
 	DisableGithubSetStatus bool
 	DisableGithubStatusURL bool
+	DisableGitlabSetStatus bool
+	DisableGitlabStatusURL bool
+	DisableGiteaSetStatus  bool
+	DisableGiteaStatusURL  bool
 	GithubSecret           string
+	GitlabSecret           string
 	StashPrivateKey        string
 }
 
@@ -45,6 +52,8 @@ func Initialize(o InitializeOpts) error {
 	options = o
 	repogithub.Init(o.APIBaseURL, o.UIBaseURL)
 	repostash.Init(o.APIBaseURL, o.UIBaseURL)
+	repogitlab.Init(o.APIBaseURL, o.UIBaseURL)
+	repogitea.Init(o.APIBaseURL, o.UIBaseURL)
 
 	_db := database.DB()
 	if _db == nil {
@@ -61,6 +70,10 @@ func Initialize(o InitializeOpts) error {
 			return err
 		}
 		for _, rm := range repositoriesManager {
+			//Gitea uses personal access tokens, there is no secret to load
+			if rm.Type == sdk.Gitea {
+				continue
+			}
 			var found bool
 			log.Info("RepositoriesManager> Searching key for %s", rm.Name)
 			s := fmt.Sprintf("cds/repositoriesmanager-secrets-%s-", rm.Name)
@@ -91,6 +104,12 @@ func Initialize(o InitializeOpts) error {
 						rmSecrets["client-secret"] = o.GithubSecret
 						found = true
 					}
+				case sdk.Gitlab:
+					if o.GitlabSecret != "" {
+						log.Info("RepositoriesManager> Found a key for %s", rm.Name)
+						rmSecrets["client-secret"] = o.GitlabSecret
+						found = true
+					}
 				}
 			}
 			if found {
@@ -222,6 +241,149 @@ func New(t sdk.RepositoriesManagerType, id int64, name, URL string, args map[str
 			PollingSupported: *withPolling && github.PollingSupported(),
 		}
 
+		return &rm, nil
+	case sdk.Gitlab:
+		var gitlab *repogitlab.GitlabConsumer
+		var withHook, withPolling *bool
+		//Check if it isn't comming from the DB
+		if id == 0 || consumerData == "" {
+			//Check args
+			if len(args) < 2 || args["client-id"] == "" || args["client-secret"] == "" {
+				return nil, fmt.Errorf("client-id args and client-secret are mandatory to connect to gitlab : %v", args)
+			}
+
+			gitlab = repogitlab.New(URL, args["client-id"], args["client-secret"], options.APIBaseURL+"/repositories_manager/oauth2/callback")
+			if args["with-hooks"] != "" {
+				b, err := strconv.ParseBool(args["with-hooks"])
+				if err == nil {
+					withHook = &b
+				}
+			}
+
+			if args["with-polling"] != "" {
+				b, err := strconv.ParseBool(args["with-polling"])
+				if err == nil {
+					withPolling = &b
+				}
+			}
+		} else {
+			//It's coming from the database, we just have to unmarshal data from the DB to get consumerData
+			var data map[string]interface{}
+			if err := json.Unmarshal([]byte(consumerData), &data); err != nil {
+				log.Warning("New> Error %s", err)
+				return nil, err
+			}
+
+			gitlab = repogitlab.New(URL, data["client-id"].(string), data["client-secret"].(string), options.APIBaseURL+"/repositories_manager/oauth2/callback")
+			if data["with-hooks"] != nil {
+				b, ok := data["with-hooks"].(bool)
+				if !ok {
+					b = gitlab.HooksSupported()
+				}
+				withHook = &b
+			}
+
+			if data["with-polling"] != nil {
+				b, ok := data["with-polling"].(bool)
+				if !ok {
+					b = gitlab.PollingSupported()
+				}
+				withPolling = &b
+			}
+		}
+
+		gitlab.DisableSetStatus = options.DisableGitlabSetStatus
+		gitlab.DisableStatusURL = options.DisableGitlabStatusURL
+
+		if withHook == nil {
+			b := gitlab.HooksSupported()
+			withHook = &b
+		}
+		gitlab.WithHooks = *withHook
+		if withPolling == nil {
+			b := gitlab.PollingSupported()
+			withPolling = &b
+		}
+		gitlab.WithPolling = *withPolling
+
+		rm := sdk.RepositoriesManager{
+			ID:               id,
+			Consumer:         gitlab,
+			Name:             name,
+			URL:              gitlab.URL,
+			Type:             sdk.Gitlab,
+			HooksSupported:   *withHook && gitlab.HooksSupported(),
+			PollingSupported: *withPolling && gitlab.PollingSupported(),
+		}
+
+		return &rm, nil
+	case sdk.Gitea:
+		var withHook, withPolling *bool
+		gitea := repogitea.New(URL)
+		//Check if it isn't comming from the DB
+		if id == 0 || consumerData == "" {
+			if args["with-hooks"] != "" {
+				b, err := strconv.ParseBool(args["with-hooks"])
+				if err == nil {
+					withHook = &b
+				}
+			}
+
+			if args["with-polling"] != "" {
+				b, err := strconv.ParseBool(args["with-polling"])
+				if err == nil {
+					withPolling = &b
+				}
+			}
+		} else {
+			//It's coming from the database, we just have to unmarshal data from the DB to get consumerData
+			var data map[string]interface{}
+			if err := json.Unmarshal([]byte(consumerData), &data); err != nil {
+				log.Warning("New> Error %s", err)
+				return nil, err
+			}
+
+			if data["with-hooks"] != nil {
+				b, ok := data["with-hooks"].(bool)
+				if !ok {
+					b = gitea.HooksSupported()
+				}
+				withHook = &b
+			}
+
+			if data["with-polling"] != nil {
+				b, ok := data["with-polling"].(bool)
+				if !ok {
+					b = gitea.PollingSupported()
+				}
+				withPolling = &b
+			}
+		}
+
+		gitea.DisableSetStatus = options.DisableGiteaSetStatus
+		gitea.DisableStatusURL = options.DisableGiteaStatusURL
+
+		if withHook == nil {
+			b := gitea.HooksSupported()
+			withHook = &b
+		}
+		gitea.WithHooks = *withHook
+		if withPolling == nil {
+			b := gitea.PollingSupported()
+			withPolling = &b
+		}
+		gitea.WithPolling = *withPolling
+
+		rm := sdk.RepositoriesManager{
+			ID:               id,
+			Consumer:         gitea,
+			Name:             name,
+			URL:              gitea.URL,
+			Type:             sdk.Gitea,
+			HooksSupported:   *withHook && gitea.HooksSupported(),
+			PollingSupported: *withPolling && gitea.PollingSupported(),
+		}
+
 		return &rm, nil
 	}
 	return nil, fmt.Errorf("Unknown type %s. Cannot instanciate repositories manager t=%s id=%d name=%s url=%s args=%s consumerData=%s", t, t, id, name, URL, args, consumerData)
@@ -266,5 +428,23 @@ func initRepositoriesManager(db gorp.SqlExecutor, rm *sdk.RepositoriesManager, d
 		}
 		return nil
 	}
+	if rm.Type == sdk.Gitlab {
+		clientSecret := secrets["client-secret"]
+		if clientSecret == "" {
+			return fmt.Errorf("Cannot init %s. Missing client secret", rm.Name)
+		}
+		path := filepath.Join(directory, fmt.Sprintf("%s.%s", rm.Name, "clientSecret"))
+		log.Info("RepositoriesManager> Writing gitlab client secret %s", path)
+		if err := ioutil.WriteFile(path, []byte(clientSecret), 0600); err != nil {
+			log.Warning("RepositoriesManager> Unable to write gitlab client secret %s : %s", path, err)
+			return err
+		}
+		gl := rm.Consumer.(*repogitlab.GitlabConsumer)
+		gl.ClientSecret = path
+		if err := Update(db, rm); err != nil {
+			return err
+		}
+		return nil
+	}
 	return fmt.Errorf("Unsupported repositories manager : %s: %s", rm.Name, rm.Type)
 }
@@ -19,11 +19,14 @@ const (
 	EnvironmentVariableUsedInApplicationDoesNotExist
 	InvalidVariableFormatUsedInApplication
 	MissingEnvironment
+	DeprecatedWorkerModel
+	DisabledWorkerModel
+	IncompatiblePlatformAndModelRequirements
 )
 
 var messageAmericanEnglish = map[int64]string{
 	MultipleWorkerModelWarning:                       `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}} has multiple Worker Model as requirement. It will never start building.`,
-	NoWorkerModelMatchRequirement:                    `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}}: No worker model matches all required binaries`,
+	NoWorkerModelMatchRequirement:                    `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}}: No worker model matches all required binaries, os and arch`,
 	InvalidVariableFormat:                            `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}}: Invalid variable format '{{index . "VarName"}}'`,
 	ProjectVariableDoesNotExist:                      `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}}: Project variable '{{index . "VarName"}}' used but doesn't exist`,
 	ApplicationVariableDoesNotExist:                  `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}}: Application variable '{{index . "VarName"}}' used but doesn't exist in application '{{index . "AppName"}}'`,
@@ -37,4 +40,7 @@ var messageAmericanEnglish = map[int64]string{
 	GitURLWithoutKey:                                 `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}} is used but no ssh key were found. Git clone will failed`,
 	MissingEnvironment:                               `Application {{index . "ApplicationName"}}: At least one environment with one variable should be defined`,
 	EnvironmentVariableUsedInApplicationDoesNotExist: `Application {{index . "ApplicationName"}}: Environment variable {{index . "VarName"}} used but doesn't exist in all environments`,
-	InvalidVariableFormatUsedInApplication:           `Application {{index . "ApplicationName"}}: Invalid variable format '{{index . "VarName"}}'`}
+	InvalidVariableFormatUsedInApplication:           `Application {{index . "ApplicationName"}}: Invalid variable format '{{index . "VarName"}}'`,
+	DeprecatedWorkerModel:                            `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}}: Model {{index . "ModelName"}} is deprecated`,
+	DisabledWorkerModel:                              `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}}: Model {{index . "ModelName"}} is disabled. It will never start building.`,
+	IncompatiblePlatformAndModelRequirements:         `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}}: Model {{index . "ModelName"}} does not have the {{index . "PlatformType"}} '{{index . "PlatformRequirement"}}' capability`}
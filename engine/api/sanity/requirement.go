@@ -50,6 +50,18 @@ func checkActionRequirements(a *sdk.Action, proj string, pip string, wms []sdk.M
 			return nil, err
 		}
 		warns = append(warns, w...)
+
+		w, err = checkIncompatiblePlatformWithModelRequirement(proj, pip, a, wms, modelName)
+		if err != nil {
+			return nil, err
+		}
+		warns = append(warns, w...)
+
+		w, err = checkWorkerModelState(proj, pip, a, wms, modelName)
+		if err != nil {
+			return nil, err
+		}
+		warns = append(warns, w...)
 	}
 
 	return warns, nil
@@ -65,10 +77,9 @@ func checkMultipleWorkerModelWarning(proj string, pip string, a *sdk.Action) ([]
 	for _, r := range areqs {
 		if r.Type == sdk.ModelRequirement {
 			modelReq++
-			modelName = r.Value
+			modelName, _ = sdk.ParseModelRequirement(r.Value)
 		}
 		if modelReq > 1 {
-			modelName = r.Value
 			w := sdk.Warning{
 				Action: sdk.Action{
 					ID: a.ID,
@@ -134,6 +145,15 @@ func checkNoWorkerModelMatchRequirement(proj string, pip string, a *sdk.Action,
 				break
 			}
 
+			// Os and arch requirements must match the os and arch capabilities of the model
+			if ar.Type == sdk.OSRequirement || ar.Type == sdk.ArchRequirement {
+				if !sdk.MatchPlatformRequirement(ar, wm.Capabilities) {
+					ok = false
+					break
+				}
+				continue
+			}
+
 			// We are only checkins binary requirement matching with binary capabilities
 			// so let's skip this other types of requirements
 			if ar.Type != sdk.BinaryRequirement {
@@ -329,3 +349,93 @@ func checkIncompatibleMemoryWithModelRequirement(proj string, pip string, a *sdk
 
 	return warns, nil
 }
+
+func checkWorkerModelState(proj string, pip string, a *sdk.Action, wms []sdk.Model, modelName string) ([]sdk.Warning, error) {
+	var warns []sdk.Warning
+	var m sdk.Model
+
+	// find worker model
+	for _, wm := range wms {
+		if wm.Name == modelName {
+			m = wm
+			break
+		}
+	}
+
+	if m.Name == "" {
+		log.Warning("checkWorkerModelState> Model '%s' not found\n", modelName)
+		return nil, sdk.ErrNoWorkerModel
+	}
+
+	var id int64
+	switch m.State {
+	case sdk.ModelStateDeprecated:
+		id = DeprecatedWorkerModel
+	case sdk.ModelStateDisabled:
+		id = DisabledWorkerModel
+	default:
+		return nil, nil
+	}
+
+	w := sdk.Warning{
+		Action: sdk.Action{
+			ID: a.ID,
+		},
+		ID: id,
+		MessageParam: map[string]string{
+			"ActionName":   a.Name,
+			"PipelineName": pip,
+			"ProjectKey":   proj,
+			"ModelName":    modelName,
+		},
+	}
+	warns = append(warns, w)
+
+	return warns, nil
+}
+
+func checkIncompatiblePlatformWithModelRequirement(proj string, pip string, a *sdk.Action, wms []sdk.Model, modelName string) ([]sdk.Warning, error) {
+	var warns []sdk.Warning
+	var m sdk.Model
+	areqs := a.Requirements
+
+	// find worker model
+	for _, wm := range wms {
+		if wm.Name == modelName {
+			m = wm
+			break
+		}
+	}
+
+	if m.Name == "" {
+		log.Warning("checkIncompatiblePlatformWithModelRequirement> Model '%s' not found\n", modelName)
+		return nil, sdk.ErrNoWorkerModel
+	}
+
+	// now for each os and arch requirement in areqs, check it matches model capas
+	for _, r := range areqs {
+		if r.Type != sdk.OSRequirement && r.Type != sdk.ArchRequirement {
+			continue
+		}
+		if sdk.MatchPlatformRequirement(r, m.Capabilities) {
+			continue
+		}
+		w := sdk.Warning{
+			Action: sdk.Action{
+				ID: a.ID,
+			},
+			ID: IncompatiblePlatformAndModelRequirements,
+			MessageParam: map[string]string{
+				"ActionName":          a.Name,
+				"PipelineName":        pip,
+				"ProjectKey":          proj,
+				"ModelName":           modelName,
+				"PlatformType":        r.Type,
+				"PlatformRequirement": r.Value,
+			},
+		}
+		warns = append(warns, w)
+	}
+
+	return warns, nil
+}
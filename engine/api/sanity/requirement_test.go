@@ -251,6 +251,49 @@ func Test_checkNoWorkerModelMatchRequirement(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "With no model of the required arch it should return 1 warning",
+			args: args{
+				proj: "proj",
+				pip:  "pipeline",
+				a: &sdk.Action{
+					ID:   1,
+					Name: "Action Name 1",
+					Requirements: []sdk.Requirement{
+						{
+							Name:  "arch",
+							Type:  sdk.ArchRequirement,
+							Value: "arm64",
+						},
+					},
+				},
+				wms: []sdk.Model{
+					{
+						Capabilities: []sdk.Requirement{
+							{
+								Name:  "arch",
+								Type:  sdk.ArchRequirement,
+								Value: "amd64",
+							},
+						},
+					},
+				},
+			},
+			want: []sdk.Warning{
+				{
+					Action: sdk.Action{
+						ID: 1,
+					},
+					ID: NoWorkerModelMatchRequirement,
+					MessageParam: map[string]string{
+						"ActionName":   "Action Name 1",
+						"PipelineName": "pipeline",
+						"ProjectKey":   "proj",
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "With no missing capa it should not return warning",
 			args: args{
@@ -701,3 +744,220 @@ func Test_checkIncompatibleMemoryWithModelRequirement(t *testing.T) {
 		assert.EqualValues(t, tt.want, got)
 	}
 }
+
+func Test_checkWorkerModelState(t *testing.T) {
+	type args struct {
+		proj      string
+		pip       string
+		a         *sdk.Action
+		wms       []sdk.Model
+		modelName string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []sdk.Warning
+		wantErr bool
+	}{
+		{
+			name: "With an active model it should not return warning",
+			args: args{
+				proj:      "proj",
+				pip:       "pipeline",
+				a:         &sdk.Action{ID: 1, Name: "Action Name 1"},
+				modelName: "model",
+				wms: []sdk.Model{
+					sdk.Model{
+						Name:  "model",
+						State: sdk.ModelStateActive,
+					},
+				},
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "With a deprecated model it should return 1 warning",
+			args: args{
+				proj:      "proj",
+				pip:       "pipeline",
+				a:         &sdk.Action{ID: 1, Name: "Action Name 1"},
+				modelName: "model",
+				wms: []sdk.Model{
+					sdk.Model{
+						Name:  "model",
+						State: sdk.ModelStateDeprecated,
+					},
+				},
+			},
+			want: []sdk.Warning{
+				{
+					Action: sdk.Action{
+						ID: 1,
+					},
+					ID: DeprecatedWorkerModel,
+					MessageParam: map[string]string{
+						"ActionName":   "Action Name 1",
+						"PipelineName": "pipeline",
+						"ProjectKey":   "proj",
+						"ModelName":    "model",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "With a disabled model it should return 1 warning",
+			args: args{
+				proj:      "proj",
+				pip:       "pipeline",
+				a:         &sdk.Action{ID: 1, Name: "Action Name 1"},
+				modelName: "model",
+				wms: []sdk.Model{
+					sdk.Model{
+						Name:  "model",
+						State: sdk.ModelStateDisabled,
+					},
+				},
+			},
+			want: []sdk.Warning{
+				{
+					Action: sdk.Action{
+						ID: 1,
+					},
+					ID: DisabledWorkerModel,
+					MessageParam: map[string]string{
+						"ActionName":   "Action Name 1",
+						"PipelineName": "pipeline",
+						"ProjectKey":   "proj",
+						"ModelName":    "model",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "With an unknown model it should return an error",
+			args: args{
+				proj:      "proj",
+				pip:       "pipeline",
+				a:         &sdk.Action{ID: 1, Name: "Action Name 1"},
+				modelName: "unknown",
+				wms: []sdk.Model{
+					sdk.Model{
+						Name: "model",
+					},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := checkWorkerModelState(tt.args.proj, tt.args.pip, tt.args.a, tt.args.wms, tt.args.modelName)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q. checkWorkerModelState() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		assert.EqualValues(t, tt.want, got)
+	}
+}
+
+func Test_checkIncompatiblePlatformWithModelRequirement(t *testing.T) {
+	type args struct {
+		proj      string
+		pip       string
+		a         *sdk.Action
+		wms       []sdk.Model
+		modelName string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []sdk.Warning
+		wantErr bool
+	}{
+		{
+			name: "With a model of the required os and arch it should not return warning",
+			args: args{
+				proj: "proj",
+				pip:  "pipeline",
+				a: &sdk.Action{
+					ID:   1,
+					Name: "Action Name 1",
+					Requirements: []sdk.Requirement{
+						{
+							Name:  "os",
+							Type:  sdk.OSRequirement,
+							Value: "linux",
+						},
+						{
+							Name:  "arch",
+							Type:  sdk.ArchRequirement,
+							Value: "x86_64",
+						},
+					},
+				},
+				modelName: "model",
+				wms: []sdk.Model{
+					sdk.Model{
+						Name:         "model",
+						Capabilities: sdk.PlatformCapabilities("linux", "amd64"),
+					},
+				},
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "With a model of another arch it should return 1 warning",
+			args: args{
+				proj: "proj",
+				pip:  "pipeline",
+				a: &sdk.Action{
+					ID:   1,
+					Name: "Action Name 1",
+					Requirements: []sdk.Requirement{
+						{
+							Name:  "arch",
+							Type:  sdk.ArchRequirement,
+							Value: "arm64",
+						},
+					},
+				},
+				modelName: "model",
+				wms: []sdk.Model{
+					sdk.Model{
+						Name:         "model",
+						Capabilities: sdk.PlatformCapabilities("linux", "amd64"),
+					},
+				},
+			},
+			want: []sdk.Warning{
+				{
+					Action: sdk.Action{
+						ID: 1,
+					},
+					ID: IncompatiblePlatformAndModelRequirements,
+					MessageParam: map[string]string{
+						"ActionName":          "Action Name 1",
+						"PipelineName":        "pipeline",
+						"ProjectKey":          "proj",
+						"ModelName":           "model",
+						"PlatformType":        sdk.ArchRequirement,
+						"PlatformRequirement": "arm64",
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		got, err := checkIncompatiblePlatformWithModelRequirement(tt.args.proj, tt.args.pip, tt.args.a, tt.args.wms, tt.args.modelName)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q. checkIncompatiblePlatformWithModelRequirement() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		assert.EqualValues(t, tt.want, got)
+	}
+}
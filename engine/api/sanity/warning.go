@@ -6,6 +6,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -30,6 +31,34 @@ func CheckProjectPipelines(db *gorp.DbMap, project *sdk.Project) error {
 	return nil
 }
 
+// CheckModelPipelines checks all pipelines with a job requiring the worker model, pinned to a version or not
+func CheckModelPipelines(db *gorp.DbMap, modelName string) error {
+	query := `SELECT DISTINCT pipeline_stage.pipeline_id
+	FROM action_requirement
+	JOIN pipeline_action ON pipeline_action.action_id = action_requirement.action_id
+	JOIN pipeline_stage ON pipeline_stage.id = pipeline_action.pipeline_stage_id
+	WHERE action_requirement.type = $1 AND (action_requirement.value = $2 OR action_requirement.value LIKE $2 || '@%')`
+	var ids []int64
+	if _, err := db.Select(&ids, query, sdk.ModelRequirement, modelName); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		pip, err := pipeline.LoadPipelineByID(db, id, true)
+		if err != nil {
+			return err
+		}
+		proj, err := project.LoadByPipelineID(db, &sdk.User{Admin: true}, id, project.LoadOptions.WithApplications)
+		if err != nil {
+			return err
+		}
+		if err := CheckPipeline(db, proj, pip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CheckPipeline loads all PipelineAction and checks them all
 func CheckPipeline(db *gorp.DbMap, project *sdk.Project, pip *sdk.Pipeline) error {
 	tx, err := db.Begin()
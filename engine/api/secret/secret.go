@@ -244,3 +244,15 @@ func EncryptS(ptype string, value string) (sql.NullString, []byte, error) {
 	d, err := Encrypt([]byte(value))
 	return n, d, err
 }
+
+// Sign returns the hmac-sha256 of data with the secret key, to authenticate data sent to clients
+// Init() must be called before any signature
+func Sign(data []byte) ([]byte, error) {
+	if key == nil {
+		log.Error("Missing key, init failed?")
+		return nil, sdk.ErrSecretKeyFetchFailed
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
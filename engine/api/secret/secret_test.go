@@ -99,3 +99,20 @@ func TestDecryptS(t *testing.T) {
 	}
 
 }
+
+func TestSign(t *testing.T) {
+	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
+
+	s1, err := Sign([]byte("42:1500000000"))
+	if err != nil {
+		t.Fatalf("Sign failed: %s", err)
+	}
+	s2, _ := Sign([]byte("42:1500000000"))
+	if !bytes.Equal(s1, s2) {
+		t.Fatalf("Sign should be deterministic")
+	}
+	s3, _ := Sign([]byte("43:1500000000"))
+	if bytes.Equal(s1, s3) {
+		t.Fatalf("Sign should depend on data")
+	}
+}
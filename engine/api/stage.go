@@ -9,11 +9,30 @@ import (
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/context"
+	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/condition"
 	"github.com/ovh/cds/sdk/log"
 )
 
+// checkStageApproval verifies that the approver groups of a stage gate exist
+func checkStageApproval(db gorp.SqlExecutor, a *sdk.StageApproval) error {
+	if a == nil {
+		return nil
+	}
+	if len(a.Groups) == 0 || a.Required < 0 || a.Timeout < 0 {
+		return sdk.ErrWrongRequest
+	}
+	for _, name := range a.Groups {
+		if _, err := group.LoadGroup(db, name); err != nil {
+			log.Warning("checkStageApproval> Cannot load approver group %s: %s", name, err)
+			return err
+		}
+	}
+	return nil
+}
+
 func addStageHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
 	// Get project name in URL
 	vars := mux.Vars(r)
@@ -25,6 +44,14 @@ func addStageHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *
 		return err
 	}
 
+	if err := condition.Validate(stageData.Condition); err != nil {
+		return sdk.NewError(sdk.ErrInvalidCondition, err)
+	}
+
+	if err := checkStageApproval(db, stageData.Approval); err != nil {
+		return err
+	}
+
 	// Check if pipeline exist
 	pipelineData, err := pipeline.LoadPipeline(db, projectKey, pipelineKey, false)
 	if err != nil {
@@ -168,6 +195,14 @@ func updateStageHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap,
 		return err
 	}
 
+	if err := condition.Validate(stageData.Condition); err != nil {
+		return sdk.NewError(sdk.ErrInvalidCondition, err)
+	}
+
+	if err := checkStageApproval(db, stageData.Approval); err != nil {
+		return err
+	}
+
 	stageID, err := strconv.ParseInt(stageIDString, 10, 60)
 	if err != nil {
 		log.Warning("addStageHandler> Stage ID must be an int: %s", err)
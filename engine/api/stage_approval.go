@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/approval"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/context"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/sdk"
+)
+
+func decideStageApprovalHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	stageID, err := requestVarInt(r, "stageID")
+	if err != nil {
+		return sdk.WrapError(err, "decideStageApprovalHandler> invalid stage id")
+	}
+
+	var d sdk.ApprovalDecision
+	if err := UnmarshalBody(r, &d); err != nil {
+		return err
+	}
+
+	pb, err := loadPipelineBuildFromRequest(db, r, c, permission.PermissionReadExecute)
+	if err != nil {
+		return err
+	}
+
+	pbDecided, err := approval.Decide(db, pb.ID, stageID, c.User, d.Approved, d.Comment)
+	if err != nil {
+		return sdk.WrapError(err, "decideStageApprovalHandler> Cannot decide on stage %d of pipeline build %d", stageID, pb.ID)
+	}
+
+	cache.DeleteAll(cache.Key("application", mux.Vars(r)["key"], "builds", "*"))
+
+	return WriteJSON(w, r, pbDecided, http.StatusOK)
+}
+
+func getPipelineBuildApprovalsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	pb, err := loadPipelineBuildFromRequest(db, r, c, permission.PermissionRead)
+	if err != nil {
+		return err
+	}
+
+	records, err := approval.LoadRecords(db, pb.ID)
+	if err != nil {
+		return sdk.WrapError(err, "getPipelineBuildApprovalsHandler> Cannot load approvals")
+	}
+
+	return WriteJSON(w, r, records, http.StatusOK)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/context"
 	"github.com/ovh/cds/engine/api/database"
@@ -75,6 +76,10 @@ func statusHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *co
 	output = append(output, fmt.Sprintf("Object-Store: %s", objectstore.Status()))
 	log.Debug("Status> Object-Store: %s", objectstore.Status())
 
+	// Check artifact cleaner
+	output = append(output, fmt.Sprintf("Artifact GC: %s", artifact.GCStatus()))
+	log.Debug("Status> Artifact GC: %s", artifact.GCStatus())
+
 	// Check mail
 	mailStatus := mail.Status()
 	output = append(output, fmt.Sprintf("SMTP: %s", mailStatus))
@@ -15,6 +15,7 @@ import (
 	"github.com/ovh/cds/engine/api/trigger"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/condition"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -28,6 +29,10 @@ func addTriggerHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 		return err
 	}
 
+	if err := condition.Validate(t.Condition); err != nil {
+		return sdk.NewError(sdk.ErrInvalidCondition, err)
+	}
+
 	// load source ids
 	if t.SrcApplication.ID == 0 {
 		a, errSrcApp := application.LoadByName(db, project, t.SrcApplication.Name, c.User)
@@ -117,6 +122,10 @@ func addTriggerHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 
 	}
 
+	if err := pipeline.CheckJobOutputReferences(db, t.SrcPipeline.ID, t.Parameters); err != nil {
+		return sdk.NewError(sdk.ErrInvalidJobOutput, err)
+	}
+
 	tx, errBegin := db.Begin()
 	if errBegin != nil {
 		return errBegin
@@ -287,6 +296,10 @@ func updateTriggerHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap
 		return err
 	}
 
+	if err := condition.Validate(t.Condition); err != nil {
+		return sdk.NewError(sdk.ErrInvalidCondition, err)
+	}
+
 	if t.SrcApplication.ID == 0 || t.DestApplication.ID == 0 ||
 		t.SrcPipeline.ID == 0 || t.DestPipeline.ID == 0 {
 		log.Warning("updateTriggerHandler> IDs should not be zero\n")
@@ -294,6 +307,10 @@ func updateTriggerHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap
 
 	}
 
+	if err := pipeline.CheckJobOutputReferences(db, t.SrcPipeline.ID, t.Parameters); err != nil {
+		return sdk.NewError(sdk.ErrInvalidJobOutput, err)
+	}
+
 	tx, errBegin := db.Begin()
 	if errBegin != nil {
 		log.Warning("updateTriggerHandler> cannot start transaction: %s\n", errBegin)
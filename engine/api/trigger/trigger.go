@@ -9,6 +9,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/condition"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -26,7 +27,7 @@ func InsertTriggerParameter(db gorp.SqlExecutor, triggerID int64, p sdk.Paramete
 // InsertTrigger adds a new trigger in database
 func InsertTrigger(tx gorp.SqlExecutor, t *sdk.PipelineTrigger) error {
 	query := `INSERT INTO pipeline_trigger (src_application_id, src_pipeline_id, src_environment_id,
-	dest_application_id, dest_pipeline_id, dest_environment_id, manual) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	dest_application_id, dest_pipeline_id, dest_environment_id, manual, condition_expression) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
 
 	var srcEnvID sql.NullInt64
 	if t.SrcEnvironment.ID != 0 {
@@ -58,7 +59,7 @@ func InsertTrigger(tx gorp.SqlExecutor, t *sdk.PipelineTrigger) error {
 
 	// Insert trigger
 	err = tx.QueryRow(query, t.SrcApplication.ID, t.SrcPipeline.ID, srcEnvID,
-		t.DestApplication.ID, t.DestPipeline.ID, dstEnvID, t.Manual).Scan(&t.ID)
+		t.DestApplication.ID, t.DestPipeline.ID, dstEnvID, t.Manual, t.Condition).Scan(&t.ID)
 	if err != nil {
 		return err
 	}
@@ -165,9 +166,9 @@ func UpdateTrigger(db gorp.SqlExecutor, t *sdk.PipelineTrigger) error {
 	query := `UPDATE pipeline_trigger SET 
 	src_application_id = $1, src_pipeline_id = $2, src_environment_id = $3,
 	dest_application_id = $4, dest_pipeline_id = $5, dest_environment_id = $6,
-	manual = $7
-	WHERE id = $8`
-	if _, err := db.Exec(query, t.SrcApplication.ID, t.SrcPipeline.ID, srcEnvID, t.DestApplication.ID, t.DestPipeline.ID, destEnvID, t.Manual, t.ID); err != nil {
+	manual = $7, condition_expression = $8
+	WHERE id = $9`
+	if _, err := db.Exec(query, t.SrcApplication.ID, t.SrcPipeline.ID, srcEnvID, t.DestApplication.ID, t.DestPipeline.ID, destEnvID, t.Manual, t.Condition, t.ID); err != nil {
 		return err
 	}
 
@@ -209,7 +210,7 @@ func LoadTriggersAsSource(db gorp.SqlExecutor, appID, pipelineID, envID int64) (
 	dest_pipeline_id, dest_pip.name, dest_pip.type,
 	dest_environment_id, dest_env.name,
 	dest_project.id, dest_project.projectkey, dest_project.name,
-	manual
+	manual, condition_expression
 	FROM pipeline_trigger
 	JOIN pipeline as src_pip ON src_pip.id = src_pipeline_id
 	JOIN application AS src_app ON src_app.id = src_application_id
@@ -275,7 +276,7 @@ func LoadAutomaticTriggersAsSource(db gorp.SqlExecutor, appID, pipelineID, envID
 	dest_pipeline_id, dest_pip.name, dest_pip.type,
 	dest_environment_id, dest_env.name,
 	dest_project.id, dest_project.projectkey, dest_project.name,
-	manual
+	manual, condition_expression
 	FROM pipeline_trigger
 	JOIN pipeline as src_pip ON src_pip.id = src_pipeline_id
 	JOIN application AS src_app ON src_app.id = src_application_id
@@ -340,7 +341,7 @@ func LoadTriggersByAppAndPipeline(db gorp.SqlExecutor, appID int64, pipID int64)
 	dest_pipeline_id, dest_pip.name, dest_pip.type,
 	dest_environment_id, dest_env.name,
 	dest_project.id, dest_project.projectkey, dest_project.name,
-	manual
+	manual, condition_expression
 	FROM pipeline_trigger
 	JOIN pipeline as src_pip ON src_pip.id = src_pipeline_id
 	JOIN application AS src_app ON src_app.id = src_application_id
@@ -394,7 +395,7 @@ func LoadTriggerByApp(db gorp.SqlExecutor, appID int64) ([]sdk.PipelineTrigger,
 	dest_pipeline_id, dest_pip.name, dest_pip.type,
 	dest_environment_id, dest_env.name,
 	dest_project.id, dest_project.projectkey, dest_project.name,
-	manual
+	manual, condition_expression
 	FROM pipeline_trigger
 	JOIN pipeline as src_pip ON src_pip.id = src_pipeline_id
 	JOIN application AS src_app ON src_app.id = src_application_id
@@ -448,7 +449,7 @@ func LoadTrigger(db gorp.SqlExecutor, triggerID int64) (*sdk.PipelineTrigger, er
 	dest_pipeline_id, dest_pip.name, dest_pip.type,
 	dest_environment_id, dest_env.name,
 	dest_project.id, dest_project.projectkey, dest_project.name,
-	manual
+	manual, condition_expression
 	FROM pipeline_trigger
 	JOIN pipeline as src_pip ON src_pip.id = src_pipeline_id
 	JOIN application AS src_app ON src_app.id = src_application_id
@@ -499,7 +500,7 @@ func LoadTriggers(db gorp.SqlExecutor, appID, pipelineID, envID int64) ([]sdk.Pi
 	dest_pipeline_id, dest_pip.name, dest_pip.type,
 	dest_environment_id, dest_env.name,
 	dest_project.id, dest_project.projectkey, dest_project.name,
-	manual
+	manual, condition_expression
 	FROM pipeline_trigger
 	JOIN pipeline as src_pip ON src_pip.id = src_pipeline_id
 	JOIN application AS src_app ON src_app.id = src_application_id
@@ -553,7 +554,7 @@ func loadTrigger(db gorp.SqlExecutor, s *sql.Rows, subqueries bool) (sdk.Pipelin
 		&t.DestPipeline.ID, &t.DestPipeline.Name, &t.DestPipeline.Type,
 		&destEnvID, &destEnvName,
 		&t.DestProject.ID, &t.DestProject.Key, &t.DestProject.Name,
-		&t.Manual,
+		&t.Manual, &t.Condition,
 	)
 	if err != nil {
 		return t, err
@@ -885,8 +886,27 @@ func CheckPrerequisites(t sdk.PipelineTrigger, pb *sdk.PipelineBuild) (bool, err
 			break
 		}
 	}
+	if !prerequisitesOK {
+		return false, nil
+	}
 
-	return prerequisitesOK, nil
+	// Check condition expression, trigger parameters take precedence over build parameters
+	vars := make(map[string]string, len(pb.Parameters)+len(parameters))
+	for _, pbp := range pb.Parameters {
+		vars[pbp.Name] = pbp.Value
+	}
+	for _, p := range parameters {
+		vars[p.Name] = p.Value
+	}
+	ok, err := condition.Eval(t.Condition, vars)
+	if err != nil {
+		log.Warning("CheckPrerequisites> Cannot eval condition '%s': %s", t.Condition, err)
+		return false, fmt.Errorf("CheckPrerequisites> %s", err)
+	}
+	if !ok {
+		log.Debug("CheckPrerequisites> Condition '%s' is false\n", t.Condition)
+	}
+	return ok, nil
 }
 
 //Exists checks if trigger exists
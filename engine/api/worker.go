@@ -39,7 +39,7 @@ func registerWorkerHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 	}
 
 	// Try to register worker
-	worker, err := worker.RegisterWorker(db, params.Name, params.UserKey, params.Model, h, params.BinaryCapabilities)
+	worker, err := worker.RegisterWorker(db, params.Name, params.UserKey, params.Model, params.ModelVersion, h, params.BinaryCapabilities, params.OS, params.Arch)
 	if err != nil {
 		err = sdk.NewError(sdk.ErrUnauthorized, err)
 		return sdk.WrapError(err, "registerWorkerHandler> [%s] Registering failed", params.Name)
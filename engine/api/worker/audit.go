@@ -0,0 +1,37 @@
+package worker
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertWorkerModelAudit records a change made by username on a worker model
+func InsertWorkerModelAudit(db gorp.SqlExecutor, modelID int64, username, change, detail string) error {
+	query := `INSERT INTO worker_model_audit (worker_model_id, created, username, change, detail) VALUES ($1, NOW(), $2, $3, $4)`
+	if _, err := db.Exec(query, modelID, username, change, detail); err != nil {
+		return sdk.WrapError(err, "InsertWorkerModelAudit> Unable to insert audit of model %d", modelID)
+	}
+	return nil
+}
+
+// LoadWorkerModelAudits loads the changes made on a worker model, latest first
+func LoadWorkerModelAudits(db gorp.SqlExecutor, modelID int64) ([]sdk.ModelAudit, error) {
+	query := `SELECT id, worker_model_id, created, username, change, detail
+		FROM worker_model_audit WHERE worker_model_id = $1 ORDER BY created DESC, id DESC`
+	rows, err := db.Query(query, modelID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadWorkerModelAudits> Unable to load audits of model %d", modelID)
+	}
+	defer rows.Close()
+
+	as := []sdk.ModelAudit{}
+	for rows.Next() {
+		var a sdk.ModelAudit
+		if err := rows.Scan(&a.ID, &a.ModelID, &a.Created, &a.Username, &a.Change, &a.Detail); err != nil {
+			return nil, sdk.WrapError(err, "LoadWorkerModelAudits> Unable to scan audit of model %d", modelID)
+		}
+		as = append(as, a)
+	}
+	return as, nil
+}
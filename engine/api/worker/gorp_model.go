@@ -52,7 +52,7 @@ func (m *WorkerModel) PreDelete(s gorp.SqlExecutor) error {
 	return nil
 }
 
-//PostSelect load capabilitites and createdBy user
+//PostSelect load capabilitites, scaling policy and createdBy user
 func (m *WorkerModel) PostSelect(s gorp.SqlExecutor) error {
 	//Load capabilities
 	var capabilities = []struct {
@@ -74,6 +74,20 @@ func (m *WorkerModel) PostSelect(s gorp.SqlExecutor) error {
 		})
 	}
 
+	//Load scaling policy
+	scaling, errScaling := LoadScalingPolicy(s, m.ID)
+	if errScaling != nil && errScaling != sdk.ErrNoScalingPolicy {
+		return errScaling
+	}
+	m.Scaling = scaling
+
+	//Load pending introspection
+	introspection, errIntrospection := HasPendingIntrospection(s, m.ID)
+	if errIntrospection != nil {
+		return errIntrospection
+	}
+	m.IntrospectionRequested = introspection
+
 	//Load created_by
 	m.CreatedBy = sdk.User{}
 	str, errSelect := s.SelectNullStr("select created_by from worker_model where id = $1", &m.ID)
@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// RequestIntrospection requests the introspection of a worker model, the pending request is returned if any
+func RequestIntrospection(db gorp.SqlExecutor, modelID int64, user string) (*sdk.ModelIntrospection, error) {
+	i := sdk.ModelIntrospection{ModelID: modelID, Status: sdk.IntrospectionPending, RequestedBy: user}
+
+	query := `SELECT id, requested, requested_by FROM worker_model_introspection WHERE worker_model_id = $1 AND status = $2`
+	err := db.QueryRow(query, modelID, sdk.IntrospectionPending).Scan(&i.ID, &i.Requested, &i.RequestedBy)
+	if err == nil {
+		return &i, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "RequestIntrospection> Unable to load pending introspection of model %d", modelID)
+	}
+
+	query = `INSERT INTO worker_model_introspection (worker_model_id, status, requested, requested_by) VALUES ($1, $2, NOW(), $3) RETURNING id, requested`
+	if err := db.QueryRow(query, modelID, sdk.IntrospectionPending, user).Scan(&i.ID, &i.Requested); err != nil {
+		return nil, sdk.WrapError(err, "RequestIntrospection> Unable to insert introspection of model %d", modelID)
+	}
+	return &i, nil
+}
+
+// HasPendingIntrospection returns true if the introspection of a worker model is requested
+func HasPendingIntrospection(db gorp.SqlExecutor, modelID int64) (bool, error) {
+	query := `SELECT COUNT(1) FROM worker_model_introspection WHERE worker_model_id = $1 AND status = $2`
+	n, err := db.SelectInt(query, modelID, sdk.IntrospectionPending)
+	if err != nil {
+		return false, sdk.WrapError(err, "HasPendingIntrospection> Unable to count pending introspections of model %d", modelID)
+	}
+	return n > 0, nil
+}
+
+// CompleteIntrospection updates the capabilities of a worker model with the result of its pending introspection,
+// creating a new version of the model if they changed, and records the capabilities added and removed
+func CompleteIntrospection(db gorp.SqlExecutor, modelID int64, workerName string, res sdk.IntrospectionResult) (*sdk.ModelIntrospection, error) {
+	i := sdk.ModelIntrospection{ModelID: modelID, Status: sdk.IntrospectionDone, WorkerName: workerName, Result: &res}
+
+	query := `SELECT id, requested, requested_by FROM worker_model_introspection WHERE worker_model_id = $1 AND status = $2 FOR UPDATE`
+	if err := db.QueryRow(query, modelID, sdk.IntrospectionPending).Scan(&i.ID, &i.Requested, &i.RequestedBy); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WrapError(sdk.ErrNotFound, "CompleteIntrospection> No introspection requested for model %d", modelID)
+		}
+		return nil, sdk.WrapError(err, "CompleteIntrospection> Unable to load pending introspection of model %d", modelID)
+	}
+
+	m, err := LoadWorkerModelByID(db, modelID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "CompleteIntrospection> Unable to load model %d", modelID)
+	}
+
+	updated := *m
+	updated.Capabilities = sdk.MergeCapabilities(m.Capabilities, res)
+	i.Added, i.Removed = sdk.DiffCapabilities(m.Capabilities, updated.Capabilities)
+	if err := UpdateWorkerModelWithVersion(db, m, &updated, workerName); err != nil {
+		return nil, sdk.WrapError(err, "CompleteIntrospection> Unable to update capabilities of model %d", modelID)
+	}
+
+	result, errr := json.Marshal(res)
+	added, erra := json.Marshal(i.Added)
+	removed, errd := json.Marshal(i.Removed)
+	if errr != nil || erra != nil || errd != nil {
+		return nil, sdk.WrapError(sdk.ErrUnknownError, "CompleteIntrospection> Unable to marshal result of model %d", modelID)
+	}
+
+	query = `UPDATE worker_model_introspection SET status = $2, done = NOW(), worker_name = $3, result = $4, added = $5, removed = $6
+		WHERE id = $1 RETURNING done`
+	if err := db.QueryRow(query, i.ID, sdk.IntrospectionDone, workerName, result, added, removed).Scan(&i.Done); err != nil {
+		return nil, sdk.WrapError(err, "CompleteIntrospection> Unable to update introspection %d", i.ID)
+	}
+	return &i, nil
+}
+
+// LoadIntrospections loads the history of the introspections of a worker model, latest first
+func LoadIntrospections(db gorp.SqlExecutor, modelID int64) ([]sdk.ModelIntrospection, error) {
+	query := `SELECT id, status, requested, requested_by, done, worker_name, result, added, removed
+		FROM worker_model_introspection WHERE worker_model_id = $1 ORDER BY requested DESC`
+	rows, err := db.Query(query, modelID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadIntrospections> Unable to load introspections of model %d", modelID)
+	}
+	defer rows.Close()
+
+	is := []sdk.ModelIntrospection{}
+	for rows.Next() {
+		i := sdk.ModelIntrospection{ModelID: modelID}
+		var done pq.NullTime
+		var result, added, removed []byte
+		if err := rows.Scan(&i.ID, &i.Status, &i.Requested, &i.RequestedBy, &done, &i.WorkerName, &result, &added, &removed); err != nil {
+			return nil, sdk.WrapError(err, "LoadIntrospections> Unable to scan introspection")
+		}
+		if done.Valid {
+			i.Done = done.Time
+		}
+		if len(result) > 0 {
+			i.Result = &sdk.IntrospectionResult{}
+			if err := json.Unmarshal(result, i.Result); err != nil {
+				return nil, sdk.WrapError(err, "LoadIntrospections> Unable to unmarshal result of introspection %d", i.ID)
+			}
+		}
+		if len(added) > 0 {
+			if err := json.Unmarshal(added, &i.Added); err != nil {
+				return nil, sdk.WrapError(err, "LoadIntrospections> Unable to unmarshal added capabilities of introspection %d", i.ID)
+			}
+		}
+		if len(removed) > 0 {
+			if err := json.Unmarshal(removed, &i.Removed); err != nil {
+				return nil, sdk.WrapError(err, "LoadIntrospections> Unable to unmarshal removed capabilities of introspection %d", i.ID)
+			}
+		}
+		is = append(is, i)
+	}
+	return is, nil
+}
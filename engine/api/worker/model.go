@@ -10,14 +10,26 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-// InsertWorkerModel insert a new worker model in database
+// InsertWorkerModel insert a new worker model in database, with its first version
 func InsertWorkerModel(db gorp.SqlExecutor, model *sdk.Model) error {
+	model.Version = 1
+	if model.State == "" {
+		model.State = sdk.ModelStateActive
+	}
+	if !sdk.IsValidModelState(model.State) {
+		return sdk.ErrInvalidModelState
+	}
+
 	dbmodel := WorkerModel(*model)
 	if err := db.Insert(&dbmodel); err != nil {
 		return err
 	}
 	*model = sdk.Model(dbmodel)
-	return nil
+
+	if err := InsertWorkerModelVersion(db, model, model.CreatedBy.Username); err != nil {
+		return err
+	}
+	return InsertWorkerModelAudit(db, model.ID, model.CreatedBy.Username, sdk.ModelAuditCreated, "")
 }
 
 // UpdateWorkerModel update a worker model
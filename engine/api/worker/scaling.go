@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadScalingPolicy loads the scaling policy of a worker model
+func LoadScalingPolicy(db gorp.SqlExecutor, modelID int64) (*sdk.ModelScalingPolicy, error) {
+	query := `SELECT min_warm, max_warm, scale_up_step, job_age_seconds, idle_minutes, timezone, schedules
+		FROM worker_model_scaling WHERE worker_model_id = $1`
+
+	p := sdk.ModelScalingPolicy{ModelID: modelID}
+	var schedules sql.NullString
+	if err := db.QueryRow(query, modelID).Scan(&p.MinWarm, &p.MaxWarm, &p.ScaleUpStep, &p.JobAgeSeconds, &p.IdleMinutes, &p.Timezone, &schedules); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrNoScalingPolicy
+		}
+		return nil, sdk.WrapError(err, "LoadScalingPolicy> Unable to load scaling policy of model %d", modelID)
+	}
+
+	if schedules.Valid && schedules.String != "" {
+		if err := json.Unmarshal([]byte(schedules.String), &p.Schedules); err != nil {
+			return nil, sdk.WrapError(err, "LoadScalingPolicy> Unable to unmarshal schedules of model %d", modelID)
+		}
+	}
+	return &p, nil
+}
+
+// SaveScalingPolicy inserts or replaces the scaling policy of a worker model
+func SaveScalingPolicy(db gorp.SqlExecutor, p *sdk.ModelScalingPolicy) error {
+	if err := sdk.CheckModelScalingPolicy(p); err != nil {
+		return sdk.NewError(sdk.ErrInvalidScalingPolicy, err)
+	}
+
+	schedules, err := json.Marshal(p.Schedules)
+	if err != nil {
+		return sdk.WrapError(err, "SaveScalingPolicy> Unable to marshal schedules")
+	}
+
+	if err := DeleteScalingPolicy(db, p.ModelID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO worker_model_scaling (worker_model_id, min_warm, max_warm, scale_up_step, job_age_seconds, idle_minutes, timezone, schedules)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	if _, err := db.Exec(query, p.ModelID, p.MinWarm, p.MaxWarm, p.ScaleUpStep, p.JobAgeSeconds, p.IdleMinutes, p.Timezone, schedules); err != nil {
+		return sdk.WrapError(err, "SaveScalingPolicy> Unable to insert scaling policy of model %d", p.ModelID)
+	}
+	return nil
+}
+
+// DeleteScalingPolicy removes the scaling policy of a worker model
+func DeleteScalingPolicy(db gorp.SqlExecutor, modelID int64) error {
+	if _, err := db.Exec(`DELETE FROM worker_model_scaling WHERE worker_model_id = $1`, modelID); err != nil {
+		return sdk.WrapError(err, "DeleteScalingPolicy> Unable to delete scaling policy of model %d", modelID)
+	}
+	return nil
+}
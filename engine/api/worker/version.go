@@ -0,0 +1,150 @@
+package worker
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertWorkerModelVersion records the type, image and capabilities of a worker model as its version m.Version
+func InsertWorkerModelVersion(db gorp.SqlExecutor, m *sdk.Model, username string) error {
+	capabilities, err := json.Marshal(m.Capabilities)
+	if err != nil {
+		return sdk.WrapError(err, "InsertWorkerModelVersion> Unable to marshal capabilities of model %d", m.ID)
+	}
+
+	query := `INSERT INTO worker_model_version (worker_model_id, version, type, image, capabilities, created, created_by) VALUES ($1, $2, $3, $4, $5, NOW(), $6)`
+	if _, err := db.Exec(query, m.ID, m.Version, m.Type, m.Image, capabilities, username); err != nil {
+		return sdk.WrapError(err, "InsertWorkerModelVersion> Unable to insert version %d of model %d", m.Version, m.ID)
+	}
+	return nil
+}
+
+// LoadWorkerModelVersions loads all the versions of a worker model, latest first
+func LoadWorkerModelVersions(db gorp.SqlExecutor, modelID int64) ([]sdk.ModelVersion, error) {
+	query := `SELECT worker_model_id, version, type, image, capabilities, created, created_by
+		FROM worker_model_version WHERE worker_model_id = $1 ORDER BY version DESC`
+	rows, err := db.Query(query, modelID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadWorkerModelVersions> Unable to load versions of model %d", modelID)
+	}
+	defer rows.Close()
+
+	vs := []sdk.ModelVersion{}
+	for rows.Next() {
+		v, err := scanWorkerModelVersion(rows)
+		if err != nil {
+			return nil, sdk.WrapError(err, "LoadWorkerModelVersions> Unable to scan version of model %d", modelID)
+		}
+		vs = append(vs, *v)
+	}
+	return vs, nil
+}
+
+// LoadWorkerModelVersion loads a version of a worker model
+func LoadWorkerModelVersion(db gorp.SqlExecutor, modelID, version int64) (*sdk.ModelVersion, error) {
+	query := `SELECT worker_model_id, version, type, image, capabilities, created, created_by
+		FROM worker_model_version WHERE worker_model_id = $1 AND version = $2`
+	v, err := scanWorkerModelVersion(db.QueryRow(query, modelID, version))
+	if err == sql.ErrNoRows {
+		return nil, sdk.ErrNoWorkerModelVersion
+	}
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadWorkerModelVersion> Unable to load version %d of model %d", version, modelID)
+	}
+	return v, nil
+}
+
+func scanWorkerModelVersion(row interface {
+	Scan(dest ...interface{}) error
+}) (*sdk.ModelVersion, error) {
+	var v sdk.ModelVersion
+	var capabilities []byte
+	if err := row.Scan(&v.ModelID, &v.Version, &v.Type, &v.Image, &capabilities, &v.Created, &v.CreatedBy); err != nil {
+		return nil, err
+	}
+	if len(capabilities) > 0 {
+		if err := json.Unmarshal(capabilities, &v.Capabilities); err != nil {
+			return nil, err
+		}
+	}
+	return &v, nil
+}
+
+// UpdateWorkerModelWithVersion updates a worker model, its version and state are kept.
+// A new version of the model is created, and becomes the current one, if its type, image or capabilities changed
+func UpdateWorkerModelWithVersion(db gorp.SqlExecutor, old *sdk.Model, m *sdk.Model, username string) error {
+	m.Version = old.Version
+	m.State = old.State
+
+	newVersion := old.IsVersionChanged(*m)
+	if newVersion {
+		last, err := db.SelectInt(`SELECT COALESCE(MAX(version), 0) FROM worker_model_version WHERE worker_model_id = $1`, m.ID)
+		if err != nil {
+			return sdk.WrapError(err, "UpdateWorkerModelWithVersion> Unable to load last version of model %d", m.ID)
+		}
+		m.Version = last + 1
+	}
+
+	if err := UpdateWorkerModel(db, *m); err != nil {
+		return sdk.WrapError(err, "UpdateWorkerModelWithVersion> Unable to update model %d", m.ID)
+	}
+
+	if !newVersion {
+		return InsertWorkerModelAudit(db, m.ID, username, sdk.ModelAuditUpdated, fmt.Sprintf("name: %s, group: %d", m.Name, m.GroupID))
+	}
+
+	if err := InsertWorkerModelVersion(db, m, username); err != nil {
+		return err
+	}
+
+	detail := fmt.Sprintf("version %d -> %d", old.Version, m.Version)
+	if old.Image != m.Image {
+		detail += fmt.Sprintf(", image: %s -> %s", old.Image, m.Image)
+	}
+	if old.Type != m.Type {
+		detail += fmt.Sprintf(", type: %s -> %s", old.Type, m.Type)
+	}
+	added, removed := sdk.DiffCapabilities(old.Capabilities, m.Capabilities)
+	for _, c := range added {
+		detail += fmt.Sprintf(", +%s", c.Name)
+	}
+	for _, c := range removed {
+		detail += fmt.Sprintf(", -%s", c.Name)
+	}
+	return InsertWorkerModelAudit(db, m.ID, username, sdk.ModelAuditNewVersion, detail)
+}
+
+// SetCurrentWorkerModelVersion moves the current version of a worker model to one of its versions:
+// the type, image and capabilities of the model are the ones of the version
+func SetCurrentWorkerModelVersion(db gorp.SqlExecutor, m *sdk.Model, version int64, username string) error {
+	v, err := LoadWorkerModelVersion(db, m.ID, version)
+	if err != nil {
+		return err
+	}
+
+	previous := m.Version
+	*m = m.WithVersion(*v)
+	if err := UpdateWorkerModel(db, *m); err != nil {
+		return sdk.WrapError(err, "SetCurrentWorkerModelVersion> Unable to update model %d", m.ID)
+	}
+	return InsertWorkerModelAudit(db, m.ID, username, sdk.ModelAuditCurrentVersion, fmt.Sprintf("version %d -> %d", previous, m.Version))
+}
+
+// UpdateWorkerModelState sets the state of a worker model
+func UpdateWorkerModelState(db gorp.SqlExecutor, m *sdk.Model, state string, username string) error {
+	if !sdk.IsValidModelState(state) {
+		return sdk.NewError(sdk.ErrInvalidModelState, fmt.Errorf("state must be one of %v", sdk.AvailableModelStates))
+	}
+
+	previous := m.State
+	if _, err := db.Exec(`UPDATE worker_model SET state = $2 WHERE id = $1`, m.ID, state); err != nil {
+		return sdk.WrapError(err, "UpdateWorkerModelState> Unable to update state of model %d", m.ID)
+	}
+	m.State = state
+	return InsertWorkerModelAudit(db, m.ID, username, sdk.ModelAuditState, fmt.Sprintf("%s -> %s", previous, state))
+}
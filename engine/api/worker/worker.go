@@ -51,7 +51,7 @@ func DeleteWorker(db *gorp.DbMap, id string) error {
 		}
 
 		log.Info("Worker %s crashed while building %d !\n", name, pbJobID.Int64)
-		if err := pipeline.RestartPipelineBuildJob(tx, pbJobID.Int64); err != nil {
+		if err := pipeline.WorkerLostPipelineBuildJob(tx, pbJobID.Int64, name); err != nil {
 			log.Error("DeleteWorker[%d]> Cannot restart pipeline build job: %s\n", id, err)
 		} else {
 			log.Info("DeleteWorker[%d]> PipelineBuildJob %d restarted after crash\n", id, pbJobID.Int64)
@@ -247,9 +247,12 @@ type RegistrationForm struct {
 	Name               string
 	UserKey            string
 	Model              int64
+	ModelVersion       int64
 	Hatchery           int64
 	BinaryCapabilities []string
 	Version            string
+	OS                 string
+	Arch               string
 }
 
 // TakeForm contains booked JobID if exists
@@ -259,7 +262,7 @@ type TakeForm struct {
 }
 
 // RegisterWorker  Register new worker
-func RegisterWorker(db *gorp.DbMap, name string, key string, modelID int64, h *sdk.Hatchery, binaryCapabilities []string) (*sdk.Worker, error) {
+func RegisterWorker(db *gorp.DbMap, name string, key string, modelID, modelVersion int64, h *sdk.Hatchery, binaryCapabilities []string, goos, goarch string) (*sdk.Worker, error) {
 	if name == "" {
 		return nil, fmt.Errorf("cannot register worker with empty name")
 	}
@@ -338,8 +341,14 @@ func RegisterWorker(db *gorp.DbMap, name string, key string, modelID int64, h *s
 		return nil, err
 	}
 
-	//If the worker is registered for a model and it gave us BinaryCapabilities...
-	if len(binaryCapabilities) > 0 && modelID != 0 {
+	//If the introspection of the model is requested, the worker must discover its capabilities
+	if m != nil {
+		w.Introspect = m.IntrospectionRequested
+	}
+
+	//If the worker is registered for a model and it gave us BinaryCapabilities, os or arch...
+	platformCapabilities := sdk.PlatformCapabilities(goos, goarch)
+	if (len(binaryCapabilities) > 0 || len(platformCapabilities) > 0) && modelID != 0 {
 		go func() {
 			//Start a new tx for this goroutine
 			ntx, err := db.Begin()
@@ -349,36 +358,10 @@ func RegisterWorker(db *gorp.DbMap, name string, key string, modelID int64, h *s
 			}
 			defer ntx.Rollback()
 
-			existingCapas, err := LoadWorkerModelCapabilities(ntx, modelID)
-			if err != nil {
-				log.Warning("RegisterWorker> Unable to load worker model capabilities : %s", err)
+			if err := addWorkerCapabilities(ntx, modelID, modelVersion, name, binaryCapabilities, platformCapabilities); err != nil {
+				log.Warning("RegisterWorker> Unable to update worker model capabilities : %s", err)
 				return
 			}
-
-			var newCapas []string
-			for _, b := range binaryCapabilities {
-				var found bool
-				for _, c := range existingCapas {
-					if b == c.Value {
-						found = true
-						break
-					}
-				}
-				if !found {
-					newCapas = append(newCapas, b)
-				}
-			}
-			if len(newCapas) > 0 {
-				log.Debug("Updating model %d binary capabilities with %d capabilities", modelID, len(newCapas))
-				for _, b := range newCapas {
-					query := `insert into worker_capability (worker_model_id, name, argument, type) values ($1, $2, $3, $4)`
-					if _, err := ntx.Exec(query, modelID, b, b, string(sdk.BinaryRequirement)); err != nil {
-						//Ignore errors because we let the database to check constraints...
-						log.Debug("registerWorker> Cannot insert into worker_capability: %s\n", err)
-						return
-					}
-				}
-			}
 			if err := ntx.Commit(); err != nil {
 				log.Warning("RegisterWorker> Unable to commit transaction : %s", err)
 			}
@@ -387,6 +370,59 @@ func RegisterWorker(db *gorp.DbMap, name string, key string, modelID int64, h *s
 	return w, tx.Commit()
 }
 
+// addWorkerCapabilities adds to a model the binaries a worker of the model found and the os and arch of the worker,
+// as a new version of the model. Os and arch are only set on models which do not declare them.
+// Only the workers spawned with the current version of the model update it, the capabilities of a worker
+// of a pinned or rolled back version do not belong to the current version
+func addWorkerCapabilities(db gorp.SqlExecutor, modelID, modelVersion int64, workerName string, binaryCapabilities []string, platformCapabilities []sdk.Requirement) error {
+	// Workers of a model register together, lock the model to create a single version
+	if _, err := db.Exec(`SELECT id FROM worker_model WHERE id = $1 FOR UPDATE`, modelID); err != nil {
+		return sdk.WrapError(err, "addWorkerCapabilities> Unable to lock model %d", modelID)
+	}
+	m, err := LoadWorkerModelByID(db, modelID)
+	if err != nil {
+		return sdk.WrapError(err, "addWorkerCapabilities> Unable to load model %d", modelID)
+	}
+	if m.Version != modelVersion {
+		log.Debug("addWorkerCapabilities> Worker %s runs version %d of model %d, current version is %d", workerName, modelVersion, modelID, m.Version)
+		return nil
+	}
+
+	var newCapas []sdk.Requirement
+	for _, b := range binaryCapabilities {
+		var found bool
+		for _, c := range m.Capabilities {
+			if b == c.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			newCapas = append(newCapas, sdk.Requirement{Name: b, Type: sdk.BinaryRequirement, Value: b})
+		}
+	}
+	for _, p := range platformCapabilities {
+		var found bool
+		for _, c := range m.Capabilities {
+			if p.Type == c.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			newCapas = append(newCapas, p)
+		}
+	}
+	if len(newCapas) == 0 {
+		return nil
+	}
+
+	log.Debug("addWorkerCapabilities> Updating model %d capabilities with %d capabilities", modelID, len(newCapas))
+	updated := *m
+	updated.Capabilities = append(append([]sdk.Requirement{}, m.Capabilities...), newCapas...)
+	return UpdateWorkerModelWithVersion(db, m, &updated, workerName)
+}
+
 // SetStatus sets action_build_id and status to building on given worker
 func SetStatus(db gorp.SqlExecutor, workerID string, status sdk.Status) error {
 	query := `UPDATE worker SET status = $1 WHERE id = $2`
@@ -3,6 +3,8 @@ package worker
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/sdk"
 )
@@ -98,3 +100,35 @@ func TestLoadWorkers(t *testing.T) {
 		}
 	}
 }
+
+func TestAddWorkerCapabilities(t *testing.T) {
+	db := test.SetupPG(t)
+	deleteAllWorkerModel(t, db)
+
+	g := insertGroup(t, db)
+	m := insertWorkerModel(t, db, "Foo", g.ID)
+
+	test.NoError(t, addWorkerCapabilities(db, m.ID, m.Version, "foo.bar.io", []string{"capa_1", "git"}, sdk.PlatformCapabilities("linux", "amd64")))
+
+	updated, err := LoadWorkerModelByID(db, m.ID)
+	test.NoError(t, err)
+	assert.Equal(t, m.Version+1, updated.Version)
+	assert.Len(t, updated.Capabilities, 4)
+
+	audits, err := LoadWorkerModelAudits(db, m.ID)
+	test.NoError(t, err)
+	assert.NotEmpty(t, audits)
+
+	// Nothing new, the version is kept
+	test.NoError(t, addWorkerCapabilities(db, m.ID, updated.Version, "foo.bar.io", []string{"git"}, sdk.PlatformCapabilities("linux", "arm64")))
+	updated, err = LoadWorkerModelByID(db, m.ID)
+	test.NoError(t, err)
+	assert.Equal(t, m.Version+1, updated.Version)
+
+	// A worker of a previous version of the model does not update the current version
+	test.NoError(t, addWorkerCapabilities(db, m.ID, m.Version, "foo.bar.io", []string{"docker"}, nil))
+	updated, err = LoadWorkerModelByID(db, m.ID)
+	test.NoError(t, err)
+	assert.Equal(t, m.Version+1, updated.Version)
+	assert.Len(t, updated.Capabilities, 4)
+}
@@ -61,11 +61,21 @@ func addWorkerModel(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *c
 		Origin:   c.User.Origin,
 	}
 
+	tx, errb := db.Begin()
+	if errb != nil {
+		return sdk.WrapError(errb, "addWorkerModel> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
 	// Insert model in db
-	if err := worker.InsertWorkerModel(db, &model); err != nil {
+	if err := worker.InsertWorkerModel(tx, &model); err != nil {
 		return sdk.WrapError(err, "addWorkerModel> cannot add worker model")
 	}
 
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "addWorkerModel> Cannot commit transaction")
+	}
+
 	return WriteJSON(w, r, model, http.StatusOK)
 }
 
@@ -137,11 +147,21 @@ func updateWorkerModel(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 		return sdk.WrapError(sdk.ErrInvalidID, "updateWorkerModel> wrong ID")
 	}
 
-	// update model in db
-	if err := worker.UpdateWorkerModel(db, model); err != nil {
+	tx, errb := db.Begin()
+	if errb != nil {
+		return sdk.WrapError(errb, "updateWorkerModel> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	// update model in db, a new version is created if its image, type or capabilities changed
+	if err := worker.UpdateWorkerModelWithVersion(tx, old, &model, c.User.Username); err != nil {
 		return sdk.WrapError(err, "updateWorkerModel> cannot update worker model")
 	}
 
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "updateWorkerModel> Cannot commit transaction")
+	}
+
 	// Recompute warnings
 	go func() {
 		warnings, err := sanity.LoadAllWarnings(db, "")
@@ -233,9 +253,10 @@ func addWorkerModelCapa(w http.ResponseWriter, r *http.Request, db *gorp.DbMap,
 	if err := UnmarshalBody(r, &capa); err != nil {
 		return sdk.WrapError(err, "addWorkerModelCapa> cannot unmashal body")
 	}
-	workerModel.Capabilities = append(workerModel.Capabilities, capa)
+	updated := *workerModel
+	updated.Capabilities = append(append([]sdk.Requirement{}, workerModel.Capabilities...), capa)
 
-	if err := worker.UpdateWorkerModel(db, *workerModel); err != nil {
+	if err := updateWorkerModelCapabilities(db, workerModel, &updated, c.User.Username); err != nil {
 		return sdk.WrapError(err, "addWorkerModelCapa> cannot insert new worker model capa")
 	}
 
@@ -281,7 +302,26 @@ func updateWorkerModelCapa(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 		return sdk.WrapError(sdk.ErrWrongRequest, "updateWorkerModelCapa> Wrong capability name %s != %s", capaName, capa.Name)
 	}
 
-	if err := worker.UpdateWorkerModelCapability(db, capa, workerModelID); err != nil {
+	workerModel, errLoad := worker.LoadWorkerModelByID(db, workerModelID)
+	if errLoad != nil {
+		return sdk.WrapError(errLoad, "updateWorkerModelCapa> cannot load worker model by id")
+	}
+
+	updated := *workerModel
+	updated.Capabilities = []sdk.Requirement{}
+	var found bool
+	for _, capability := range workerModel.Capabilities {
+		if capability.Name == capa.Name {
+			capability.Type, capability.Value = capa.Type, capa.Value
+			found = true
+		}
+		updated.Capabilities = append(updated.Capabilities, capability)
+	}
+	if !found {
+		return sdk.WrapError(sdk.ErrNoWorkerModelCapa, "updateWorkerModelCapa> capability %s not found", capa.Name)
+	}
+
+	if err := updateWorkerModelCapabilities(db, workerModel, &updated, c.User.Username); err != nil {
 		return sdk.WrapError(err, "updateWorkerModelCapa> cannot update worker model")
 	}
 
@@ -309,7 +349,23 @@ func deleteWorkerModelCapa(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 		return sdk.WrapError(errr, "deleteWorkerModelCapa> Invalid permModelID")
 	}
 
-	if err := worker.DeleteWorkerModelCapability(db, workerModelID, capaName); err != nil {
+	workerModel, errLoad := worker.LoadWorkerModelByID(db, workerModelID)
+	if errLoad != nil {
+		return sdk.WrapError(errLoad, "deleteWorkerModelCapa> cannot load worker model by id")
+	}
+
+	updated := *workerModel
+	updated.Capabilities = []sdk.Requirement{}
+	for _, capability := range workerModel.Capabilities {
+		if capability.Name != capaName {
+			updated.Capabilities = append(updated.Capabilities, capability)
+		}
+	}
+	if len(updated.Capabilities) == len(workerModel.Capabilities) {
+		return sdk.WrapError(sdk.ErrNoWorkerModelCapa, "deleteWorkerModelCapa> capability %s not found", capaName)
+	}
+
+	if err := updateWorkerModelCapabilities(db, workerModel, &updated, c.User.Username); err != nil {
 		return sdk.WrapError(err, "updateWorkerModelCapa> cannot remove worker model capa")
 	}
 
@@ -328,6 +384,21 @@ func deleteWorkerModelCapa(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 	return nil
 }
 
+// updateWorkerModelCapabilities updates the capabilities of a worker model in a new version of the model
+func updateWorkerModelCapabilities(db *gorp.DbMap, old *sdk.Model, m *sdk.Model, username string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "updateWorkerModelCapabilities> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	if err := worker.UpdateWorkerModelWithVersion(tx, old, m, username); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func getWorkerModelsStatsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
 	res := []struct {
 		Model string
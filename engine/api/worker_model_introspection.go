@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/context"
+	"github.com/ovh/cds/engine/api/sanity"
+	"github.com/ovh/cds/engine/api/worker"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func requestWorkerModelIntrospectionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	workerModelID, errr := requestVarInt(r, "permModelID")
+	if errr != nil {
+		return sdk.WrapError(errr, "requestWorkerModelIntrospectionHandler> Invalid permModelID")
+	}
+
+	i, err := worker.RequestIntrospection(db, workerModelID, c.User.Username)
+	if err != nil {
+		return sdk.WrapError(err, "requestWorkerModelIntrospectionHandler> cannot request introspection")
+	}
+	return WriteJSON(w, r, i, http.StatusAccepted)
+}
+
+func getWorkerModelIntrospectionsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	workerModelID, errr := requestVarInt(r, "permModelID")
+	if errr != nil {
+		return sdk.WrapError(errr, "getWorkerModelIntrospectionsHandler> Invalid permModelID")
+	}
+
+	is, err := worker.LoadIntrospections(db, workerModelID)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkerModelIntrospectionsHandler> cannot load introspections")
+	}
+	return WriteJSON(w, r, is, http.StatusOK)
+}
+
+func postWorkerIntrospectionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	if c.Worker == nil || c.Worker.ID == "" || c.Worker.Model == 0 {
+		return sdk.WrapError(sdk.ErrForbidden, "postWorkerIntrospectionHandler> only workers of a model can post an introspection")
+	}
+
+	var res sdk.IntrospectionResult
+	if err := UnmarshalBody(r, &res); err != nil {
+		return sdk.WrapError(err, "postWorkerIntrospectionHandler> cannot unmarshal body")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "postWorkerIntrospectionHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	i, err := worker.CompleteIntrospection(tx, c.Worker.Model, c.Worker.Name, res)
+	if err != nil {
+		return sdk.WrapError(err, "postWorkerIntrospectionHandler> cannot complete introspection of model %d", c.Worker.Model)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "postWorkerIntrospectionHandler> Cannot commit transaction")
+	}
+
+	// Recompute warnings
+	go func() {
+		warnings, err := sanity.LoadAllWarnings(db, "")
+		if err != nil {
+			log.Warning("postWorkerIntrospectionHandler> cannot load warnings: %s", err)
+		}
+
+		for _, warning := range warnings {
+			sanity.CheckPipeline(db, &warning.Project, &warning.Pipeline)
+		}
+	}()
+
+	return WriteJSON(w, r, i, http.StatusOK)
+}
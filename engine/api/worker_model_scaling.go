@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/context"
+	"github.com/ovh/cds/engine/api/hatchery"
+	"github.com/ovh/cds/engine/api/worker"
+	"github.com/ovh/cds/sdk"
+)
+
+func getWorkerModelScalingHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	workerModelID, errr := requestVarInt(r, "permModelID")
+	if errr != nil {
+		return sdk.WrapError(errr, "getWorkerModelScalingHandler> Invalid permModelID")
+	}
+
+	p, err := worker.LoadScalingPolicy(db, workerModelID)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkerModelScalingHandler> cannot load scaling policy")
+	}
+	return WriteJSON(w, r, p, http.StatusOK)
+}
+
+func updateWorkerModelScalingHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	workerModelID, errr := requestVarInt(r, "permModelID")
+	if errr != nil {
+		return sdk.WrapError(errr, "updateWorkerModelScalingHandler> Invalid permModelID")
+	}
+
+	var p sdk.ModelScalingPolicy
+	if err := UnmarshalBody(r, &p); err != nil {
+		return sdk.WrapError(err, "updateWorkerModelScalingHandler> cannot unmarshal body")
+	}
+	p.ModelID = workerModelID
+
+	if _, err := worker.LoadWorkerModelByID(db, workerModelID); err != nil {
+		return sdk.WrapError(err, "updateWorkerModelScalingHandler> cannot load worker model")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "updateWorkerModelScalingHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	if err := worker.SaveScalingPolicy(tx, &p); err != nil {
+		return sdk.WrapError(err, "updateWorkerModelScalingHandler> cannot save scaling policy")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "updateWorkerModelScalingHandler> Cannot commit transaction")
+	}
+	return WriteJSON(w, r, p, http.StatusOK)
+}
+
+func deleteWorkerModelScalingHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	workerModelID, errr := requestVarInt(r, "permModelID")
+	if errr != nil {
+		return sdk.WrapError(errr, "deleteWorkerModelScalingHandler> Invalid permModelID")
+	}
+
+	if err := worker.DeleteScalingPolicy(db, workerModelID); err != nil {
+		return sdk.WrapError(err, "deleteWorkerModelScalingHandler> cannot delete scaling policy")
+	}
+	return nil
+}
+
+func getWorkerModelStatusHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	status, err := hatchery.LoadModelStatus(db)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkerModelStatusHandler> cannot load status")
+	}
+
+	// Only the status of the models usable by the user are returned
+	res := []sdk.ModelStatus{}
+	if c.User == nil || c.User.ID == 0 {
+		return WriteJSON(w, r, res, http.StatusOK)
+	}
+
+	models, err := worker.LoadWorkerModelsByUser(db, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkerModelStatusHandler> cannot load worker models for user id %d", c.User.ID)
+	}
+
+	for _, s := range status {
+		for _, m := range models {
+			if m.ID == s.ModelID {
+				res = append(res, s)
+				break
+			}
+		}
+	}
+	return WriteJSON(w, r, res, http.StatusOK)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/context"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/sanity"
+	"github.com/ovh/cds/engine/api/worker"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func getWorkerModelVersionsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	workerModelID, errr := requestVarInt(r, "permModelID")
+	if errr != nil {
+		return sdk.WrapError(errr, "getWorkerModelVersionsHandler> Invalid permModelID")
+	}
+
+	vs, err := worker.LoadWorkerModelVersions(db, workerModelID)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkerModelVersionsHandler> cannot load versions")
+	}
+	return WriteJSON(w, r, vs, http.StatusOK)
+}
+
+// getWorkerModelVersionHandler returns a version of a model, hatcheries load the versions pinned by jobs to spawn them.
+// Models of the shared infra group are usable by every group, so they are readable by everyone
+func getWorkerModelVersionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	workerModelID, errr := requestVarInt(r, "modelID")
+	if errr != nil {
+		return sdk.WrapError(errr, "getWorkerModelVersionHandler> Invalid modelID")
+	}
+	version, errv := requestVarInt(r, "version")
+	if errv != nil {
+		return sdk.WrapError(errv, "getWorkerModelVersionHandler> Invalid version")
+	}
+
+	m, errLoad := worker.LoadWorkerModelByID(db, workerModelID)
+	if errLoad != nil {
+		return sdk.WrapError(errLoad, "getWorkerModelVersionHandler> cannot load worker model by id")
+	}
+	if m.GroupID != group.SharedInfraGroup.ID && !checkWorkerModelPermissionsByUser(m, c.User, permission.PermissionRead) {
+		return sdk.WrapError(sdk.ErrForbidden, "getWorkerModelVersionHandler> cannot read model %d", workerModelID)
+	}
+
+	v, err := worker.LoadWorkerModelVersion(db, workerModelID, version)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkerModelVersionHandler> cannot load version %d", version)
+	}
+	return WriteJSON(w, r, v, http.StatusOK)
+}
+
+func setWorkerModelCurrentVersionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	workerModelID, errr := requestVarInt(r, "permModelID")
+	if errr != nil {
+		return sdk.WrapError(errr, "setWorkerModelCurrentVersionHandler> Invalid permModelID")
+	}
+	version, errv := requestVarInt(r, "version")
+	if errv != nil {
+		return sdk.WrapError(errv, "setWorkerModelCurrentVersionHandler> Invalid version")
+	}
+
+	tx, errb := db.Begin()
+	if errb != nil {
+		return sdk.WrapError(errb, "setWorkerModelCurrentVersionHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	m, errLoad := worker.LoadWorkerModelByID(tx, workerModelID)
+	if errLoad != nil {
+		return sdk.WrapError(errLoad, "setWorkerModelCurrentVersionHandler> cannot load worker model by id")
+	}
+
+	if err := worker.SetCurrentWorkerModelVersion(tx, m, version, c.User.Username); err != nil {
+		return sdk.WrapError(err, "setWorkerModelCurrentVersionHandler> cannot set current version %d", version)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "setWorkerModelCurrentVersionHandler> Cannot commit transaction")
+	}
+
+	// Recompute warnings
+	go func() {
+		if err := sanity.CheckModelPipelines(db, m.Name); err != nil {
+			log.Warning("setWorkerModelCurrentVersionHandler> cannot check pipelines using model %s: %s", m.Name, err)
+		}
+	}()
+
+	return WriteJSON(w, r, m, http.StatusOK)
+}
+
+func updateWorkerModelStateHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	workerModelID, errr := requestVarInt(r, "permModelID")
+	if errr != nil {
+		return sdk.WrapError(errr, "updateWorkerModelStateHandler> Invalid permModelID")
+	}
+
+	var body struct {
+		State string `json:"state"`
+	}
+	if err := UnmarshalBody(r, &body); err != nil {
+		return sdk.WrapError(err, "updateWorkerModelStateHandler> cannot unmarshal body")
+	}
+
+	tx, errb := db.Begin()
+	if errb != nil {
+		return sdk.WrapError(errb, "updateWorkerModelStateHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	m, errLoad := worker.LoadWorkerModelByID(tx, workerModelID)
+	if errLoad != nil {
+		return sdk.WrapError(errLoad, "updateWorkerModelStateHandler> cannot load worker model by id")
+	}
+
+	if err := worker.UpdateWorkerModelState(tx, m, body.State, c.User.Username); err != nil {
+		return sdk.WrapError(err, "updateWorkerModelStateHandler> cannot update state of model %d", workerModelID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "updateWorkerModelStateHandler> Cannot commit transaction")
+	}
+
+	// Recompute warnings of the pipelines using the model
+	go func() {
+		if err := sanity.CheckModelPipelines(db, m.Name); err != nil {
+			log.Warning("updateWorkerModelStateHandler> cannot check pipelines using model %s: %s", m.Name, err)
+		}
+	}()
+
+	return WriteJSON(w, r, m, http.StatusOK)
+}
+
+func getWorkerModelAuditsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *context.Ctx) error {
+	workerModelID, errr := requestVarInt(r, "permModelID")
+	if errr != nil {
+		return sdk.WrapError(errr, "getWorkerModelAuditsHandler> Invalid permModelID")
+	}
+
+	as, err := worker.LoadWorkerModelAudits(db, workerModelID)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkerModelAuditsHandler> cannot load audits")
+	}
+	return WriteJSON(w, r, as, http.StatusOK)
+}
@@ -82,7 +82,7 @@ func Test_workerCheckingHandler(t *testing.T) {
 		t.Fatalf("Error inserting token : %s", err)
 	}
 
-	workr, err := worker.RegisterWorker(db, "test-worker", "test-key", model.ID, &h, nil)
+	workr, err := worker.RegisterWorker(db, "test-worker", "test-key", model.ID, model.Version, &h, nil, "", "")
 	if err != nil {
 		t.Fatalf("Error Registering worker : %s", err)
 	}
@@ -177,7 +177,7 @@ func Test_workerWaitingHandler(t *testing.T) {
 		t.Fatalf("Error inserting token : %s", err)
 	}
 
-	workr, err := worker.RegisterWorker(db, "test-worker", "test-key", model.ID, &h, nil)
+	workr, err := worker.RegisterWorker(db, "test-worker", "test-key", model.ID, model.Version, &h, nil, "", "")
 	if err != nil {
 		t.Fatalf("Error Registering worker : %s", err)
 	}
@@ -57,6 +57,7 @@ $ hatchery docker --api=https://<api.domain> --token=<token>
 			viper.GetInt("spawn-threshold-warning"),
 			viper.GetInt("spawn-threshold-critical"),
 			viper.GetInt("grace-time-queued"),
+			viper.GetBool("dry-run"),
 		)
 	},
 }
@@ -70,8 +70,9 @@ func (hd *HatcheryDocker) Init() error {
 	}
 
 	hd.hatch = &sdk.Hatchery{
-		Name: hatchery.GenerateName("docker", viper.GetString("name")),
-		UID:  viper.GetString("token"),
+		Name:      hatchery.GenerateName("docker", viper.GetString("name")),
+		UID:       viper.GetString("token"),
+		ModelType: hd.ModelType(),
 	}
 
 	if err := hatchery.Register(hd.hatch, viper.GetString("token")); err != nil {
@@ -190,6 +191,7 @@ func (hd *HatcheryDocker) SpawnWorker(wm *sdk.Model, job *sdk.PipelineBuildJob)
 	args = append(args, "-e", fmt.Sprintf("CDS_NAME=%s", name))
 	args = append(args, "-e", fmt.Sprintf("CDS_KEY=%s", viper.GetString("token")))
 	args = append(args, "-e", fmt.Sprintf("CDS_MODEL=%d", wm.ID))
+	args = append(args, "-e", fmt.Sprintf("CDS_MODEL_VERSION=%d", wm.Version))
 	args = append(args, "-e", fmt.Sprintf("CDS_HATCHERY=%d", hd.hatch.ID))
 
 	if viper.GetString("graylog_host") != "" {
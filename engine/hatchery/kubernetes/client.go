@@ -0,0 +1,173 @@
+package kubernetes
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Files mounted in pods by kubernetes for the service account
+const (
+	inClusterTokenFile     = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	inClusterCAFile        = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+	inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// PodInterface manages the pods of a namespace
+type PodInterface interface {
+	Create(pod *Pod) (*Pod, error)
+	List(labelSelector string) ([]Pod, error)
+	Delete(name string) error
+}
+
+// restClient implements PodInterface with the kubernetes REST API
+type restClient struct {
+	host       string
+	token      string
+	namespace  string
+	httpClient *http.Client
+}
+
+// NewClient returns a PodInterface for the namespace of the given kubernetes API server.
+// If host is empty, the in-cluster service account configuration is used
+func NewClient(host, token, caFile, namespace string, insecure bool) (PodInterface, error) {
+	if host == "" {
+		h, p := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if h == "" || p == "" {
+			return nil, fmt.Errorf("kubernetes API server not provided and hatchery is not running in a cluster")
+		}
+		host = "https://" + h + ":" + p
+
+		if token == "" {
+			b, err := ioutil.ReadFile(inClusterTokenFile)
+			if err != nil {
+				return nil, err
+			}
+			token = strings.TrimSpace(string(b))
+		}
+		if caFile == "" {
+			caFile = inClusterCAFile
+		}
+		if namespace == "" {
+			if b, err := ioutil.ReadFile(inClusterNamespaceFile); err == nil {
+				namespace = strings.TrimSpace(string(b))
+			}
+		}
+	}
+
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecure}
+	if caFile != "" {
+		ca, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("unable to load certificates from %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &restClient{
+		host:      strings.TrimSuffix(host, "/"),
+		token:     token,
+		namespace: namespace,
+		httpClient: &http.Client{
+			Timeout:   time.Minute,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+func (c *restClient) podsPath() string {
+	return fmt.Sprintf("%s/api/v1/namespaces/%s/pods", c.host, c.namespace)
+}
+
+func (c *restClient) do(method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode >= 400 {
+		s := Status{}
+		if err := json.Unmarshal(b, &s); err == nil && s.Message != "" {
+			return fmt.Errorf("kubernetes: %s (%d)", s.Message, res.StatusCode)
+		}
+		return fmt.Errorf("kubernetes: %s (%d)", string(b), res.StatusCode)
+	}
+
+	if out != nil {
+		return json.Unmarshal(b, out)
+	}
+	return nil
+}
+
+// Create creates a pod
+func (c *restClient) Create(pod *Pod) (*Pod, error) {
+	pod.APIVersion = "v1"
+	pod.Kind = "Pod"
+	res := &Pod{}
+	if err := c.do(http.MethodPost, c.podsPath(), pod, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// List lists pods matching the label selector
+func (c *restClient) List(labelSelector string) ([]Pod, error) {
+	path := c.podsPath()
+	if labelSelector != "" {
+		path += "?labelSelector=" + url.QueryEscape(labelSelector)
+	}
+	res := PodList{}
+	if err := c.do(http.MethodGet, path, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// Delete deletes a pod and its containers immediately
+func (c *restClient) Delete(name string) error {
+	return c.do(http.MethodDelete, c.podsPath()+"/"+name+"?gracePeriodSeconds=0", nil, nil)
+}
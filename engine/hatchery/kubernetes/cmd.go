@@ -0,0 +1,102 @@
+package kubernetes
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+func init() {
+	hatcheryKubernetes = &HatcheryKubernetes{}
+
+	Cmd.Flags().StringVar(&hatcheryKubernetes.kubernetesHost, "kubernetes-master-url", "", "Kubernetes API server url. If not set, in-cluster configuration is used")
+	viper.BindPFlag("kubernetes-master-url", Cmd.Flags().Lookup("kubernetes-master-url"))
+
+	Cmd.Flags().StringVar(&hatcheryKubernetes.kubernetesToken, "kubernetes-token", "", "Kubernetes bearer token")
+	viper.BindPFlag("kubernetes-token", Cmd.Flags().Lookup("kubernetes-token"))
+
+	Cmd.Flags().StringVar(&hatcheryKubernetes.kubernetesCAFile, "kubernetes-ca-file", "", "Kubernetes API server certificate authority file")
+	viper.BindPFlag("kubernetes-ca-file", Cmd.Flags().Lookup("kubernetes-ca-file"))
+
+	Cmd.Flags().BoolVar(&hatcheryKubernetes.kubernetesInsecure, "kubernetes-insecure", false, "Skip Kubernetes API server certificate verification")
+	viper.BindPFlag("kubernetes-insecure", Cmd.Flags().Lookup("kubernetes-insecure"))
+
+	Cmd.Flags().StringVar(&hatcheryKubernetes.kubernetesNamespace, "kubernetes-namespace", "", "Kubernetes namespace of the workers. Default: namespace of the hatchery in-cluster, default otherwise")
+	viper.BindPFlag("kubernetes-namespace", Cmd.Flags().Lookup("kubernetes-namespace"))
+
+	Cmd.Flags().IntVar(&hatcheryKubernetes.defaultMemory, "worker-memory", 1024, "Worker default memory")
+	viper.BindPFlag("worker-memory", Cmd.Flags().Lookup("worker-memory"))
+
+	Cmd.Flags().IntVar(&hatcheryKubernetes.workerTTL, "worker-ttl", 10, "Worker TTL (minutes)")
+	viper.BindPFlag("worker-ttl", Cmd.Flags().Lookup("worker-ttl"))
+
+	Cmd.Flags().IntVar(&hatcheryKubernetes.workerSpawnTimeout, "worker-spawn-timeout", 120, "Worker Timeout Spawning (seconds), pending pods are deleted after this timeout")
+	viper.BindPFlag("worker-spawn-timeout", Cmd.Flags().Lookup("worker-spawn-timeout"))
+
+	Cmd.Flags().Int("spawn-threshold-critical", 10, "log critical if spawn take more than this value (in seconds)")
+	viper.BindPFlag("spawn-threshold-critical", Cmd.Flags().Lookup("spawn-threshold-critical"))
+
+	Cmd.Flags().Int("spawn-threshold-warning", 4, "log warning if spawn take more than this value (in seconds)")
+	viper.BindPFlag("spawn-threshold-warning", Cmd.Flags().Lookup("spawn-threshold-warning"))
+}
+
+// Cmd configures comamnd for HatcheryKubernetes
+var Cmd = &cobra.Command{
+	Use:   "kubernetes",
+	Short: "Hatchery kubernetes commands: hatchery kubernetes --help",
+	Long: `Hatchery kubernetes commands: hatchery kubernetes <command>
+Start worker model instances as pods on a kubernetes cluster
+
+$ cds generate token --group shared.infra --expiration persistent
+2706bda13748877c57029598b915d46236988c7c57ea0d3808524a1e1a3adef4
+
+Inside the cluster, the service account of the hatchery pod is used:
+$ hatchery kubernetes --api=https://<api.domain> --token=<token>
+
+Outside the cluster:
+$ hatchery kubernetes --api=https://<api.domain> --token=<token> --kubernetes-master-url=https://<kubernetes.domain> --kubernetes-token=<kubernetes token> --kubernetes-namespace=cds
+
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		hatchery.Create(hatcheryKubernetes,
+			viper.GetString("api"),
+			viper.GetString("token"),
+			viper.GetInt("max-worker"),
+			viper.GetInt("provision"),
+			viper.GetInt("request-api-timeout"),
+			viper.GetInt("max-failures-heartbeat"),
+			viper.GetBool("insecure"),
+			viper.GetInt("provision-seconds"),
+			viper.GetInt("spawn-threshold-warning"),
+			viper.GetInt("spawn-threshold-critical"),
+			viper.GetInt("grace-time-queued"),
+			viper.GetBool("dry-run"),
+		)
+	},
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if viper.GetInt("worker-ttl") <= 0 {
+			sdk.Exit("worker-ttl must be > 0\n")
+		}
+		if viper.GetInt("worker-memory") <= 1 {
+			sdk.Exit("worker-memory must be > 1\n")
+		}
+
+		hatcheryKubernetes.token = viper.GetString("token")
+		hatcheryKubernetes.defaultMemory = viper.GetInt("worker-memory")
+		hatcheryKubernetes.workerTTL = viper.GetInt("worker-ttl")
+		hatcheryKubernetes.workerSpawnTimeout = viper.GetInt("worker-spawn-timeout")
+		hatcheryKubernetes.kubernetesHost = viper.GetString("kubernetes-master-url")
+		hatcheryKubernetes.kubernetesToken = viper.GetString("kubernetes-token")
+		hatcheryKubernetes.kubernetesCAFile = viper.GetString("kubernetes-ca-file")
+		hatcheryKubernetes.kubernetesInsecure = viper.GetBool("kubernetes-insecure")
+		hatcheryKubernetes.kubernetesNamespace = viper.GetString("kubernetes-namespace")
+
+		client, err := NewClient(hatcheryKubernetes.kubernetesHost, hatcheryKubernetes.kubernetesToken, hatcheryKubernetes.kubernetesCAFile, hatcheryKubernetes.kubernetesNamespace, hatcheryKubernetes.kubernetesInsecure)
+		if err != nil {
+			sdk.Exit("Unable to configure kubernetes client: %s\n", err)
+		}
+		hatcheryKubernetes.client = client
+	},
+}
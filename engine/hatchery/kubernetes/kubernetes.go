@@ -0,0 +1,379 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/namesgenerator"
+	"github.com/spf13/viper"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Labels set on pods, they are used to find the workers spawned by an hatchery
+const (
+	LabelHatchery    = "cds-hatchery"
+	LabelWorker      = "cds-worker"
+	LabelWorkerModel = "cds-worker-model"
+)
+
+var hatcheryKubernetes *HatcheryKubernetes
+
+// HatcheryKubernetes implements HatcheryMode interface for kubernetes mode
+type HatcheryKubernetes struct {
+	hatch *sdk.Hatchery
+	token string
+
+	client PodInterface
+
+	kubernetesHost      string
+	kubernetesToken     string
+	kubernetesCAFile    string
+	kubernetesNamespace string
+	kubernetesInsecure  bool
+
+	defaultMemory      int
+	workerTTL          int
+	workerSpawnTimeout int
+}
+
+// ID must returns hatchery id
+func (h *HatcheryKubernetes) ID() int64 {
+	if h.hatch == nil {
+		return 0
+	}
+	return h.hatch.ID
+}
+
+//Hatchery returns hatchery instance
+func (h *HatcheryKubernetes) Hatchery() *sdk.Hatchery {
+	return h.hatch
+}
+
+// ModelType returns type of hatchery
+func (*HatcheryKubernetes) ModelType() string {
+	return sdk.Docker
+}
+
+// Init registers the hatchery and starts killing routine of worker not registered
+func (h *HatcheryKubernetes) Init() error {
+	h.hatch = &sdk.Hatchery{
+		Name:      hatchery.GenerateName("kubernetes", viper.GetString("name")),
+		UID:       viper.GetString("token"),
+		ModelType: h.ModelType(),
+	}
+
+	if err := hatchery.Register(h.hatch, viper.GetString("token")); err != nil {
+		log.Warning("Cannot register hatchery: %s", err)
+	}
+
+	go h.killAwolWorkerRoutine()
+	return nil
+}
+
+func (h *HatcheryKubernetes) selector(labels ...string) string {
+	return strings.Join(append([]string{LabelHatchery + "=" + strconv.FormatInt(h.ID(), 10)}, labels...), ",")
+}
+
+// pods returns the pods of the workers spawned by this hatchery which are not terminated
+func (h *HatcheryKubernetes) pods(labels ...string) ([]Pod, error) {
+	pods, err := h.client.List(h.selector(labels...))
+	if err != nil {
+		return nil, err
+	}
+
+	res := []Pod{}
+	for _, p := range pods {
+		if p.Status.Phase == PodSucceeded || p.Status.Phase == PodFailed {
+			continue
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
+
+// KillWorker deletes the pod of the worker
+func (h *HatcheryKubernetes) KillWorker(worker sdk.Worker) error {
+	pods, err := h.client.List(h.selector(LabelWorker + "=" + worker.Name))
+	if err != nil {
+		return sdk.WrapError(err, "KillWorker> Cannot list pods")
+	}
+
+	for _, p := range pods {
+		log.Info("KillWorker> Killing %s", p.Metadata.Name)
+		if err := h.client.Delete(p.Metadata.Name); err != nil {
+			return sdk.WrapError(err, "KillWorker> Cannot delete pod %s", p.Metadata.Name)
+		}
+	}
+	return nil
+}
+
+// CanSpawn return wether or not hatchery can spawn model
+func (h *HatcheryKubernetes) CanSpawn(model *sdk.Model, job *sdk.PipelineBuildJob) bool {
+	pods, err := h.pods()
+	if err != nil {
+		log.Info("CanSpawn> Error on listing pods: %s", err)
+		return false
+	}
+	if len(pods) >= viper.GetInt("max-worker") {
+		log.Info("CanSpawn> max number of pods reached, aborting. Current: %d. Max: %d", len(pods), viper.GetInt("max-worker"))
+		return false
+	}
+	return true
+}
+
+// SpawnWorker creates a pod running the worker, and a container for each service requirement of the job
+func (h *HatcheryKubernetes) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJob) error {
+	if job != nil {
+		log.Info("SpawnWorker> spawning worker %s (%s) for job %d", model.Name, model.Image, job.ID)
+	} else {
+		log.Info("SpawnWorker> spawning worker %s (%s)", model.Name, model.Image)
+	}
+
+	pod, err := h.newPod(model, job)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.client.Create(pod); err != nil {
+		return sdk.WrapError(err, "SpawnWorker> Cannot create pod %s", pod.Metadata.Name)
+	}
+	return nil
+}
+
+var invalidNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// dnsName returns a valid DNS-1123 label, as required by kubernetes for pod and container names
+func dnsName(s string) string {
+	s = invalidNameChars.ReplaceAllString(strings.ToLower(s), "-")
+	if len(s) > 63 {
+		s = s[:63]
+	}
+	return strings.Trim(s, "-")
+}
+
+//newPod builds the pod of a worker.
+//The model requirement is resolved by the hatchery routine: the image of the model is the image of the worker container.
+//Memory requirement sets the worker memory limit, and service requirements are run as containers of the pod:
+//they are reachable on localhost and by their requirement name
+func (h *HatcheryKubernetes) newPod(model *sdk.Model, job *sdk.PipelineBuildJob) (*Pod, error) {
+	workerName := dnsName(fmt.Sprintf("%s-%s", model.Name, namesgenerator.GetRandomName(0)))
+	memory := h.defaultMemory
+
+	env := map[string]string{
+		"CDS_API":           sdk.Host,
+		"CDS_KEY":           h.token,
+		"CDS_NAME":          workerName,
+		"CDS_MODEL":         fmt.Sprintf("%d", model.ID),
+		"CDS_MODEL_VERSION": fmt.Sprintf("%d", model.Version),
+		"CDS_HATCHERY":      fmt.Sprintf("%d", h.ID()),
+		"CDS_SINGLE_USE":    "1",
+		"CDS_TTL":           fmt.Sprintf("%d", h.workerTTL),
+	}
+
+	if viper.GetString("graylog_host") != "" {
+		env["CDS_GRAYLOG_HOST"] = viper.GetString("graylog_host")
+	}
+	if viper.GetString("graylog_port") != "" {
+		env["CDS_GRAYLOG_PORT"] = viper.GetString("graylog_port")
+	}
+	if viper.GetString("graylog_extra_key") != "" {
+		env["CDS_GRAYLOG_EXTRA_KEY"] = viper.GetString("graylog_extra_key")
+	}
+	if viper.GetString("graylog_extra_value") != "" {
+		env["CDS_GRAYLOG_EXTRA_VALUE"] = viper.GetString("graylog_extra_value")
+	}
+
+	services := []Container{}
+	aliases := []string{}
+	if job != nil {
+		env["CDS_BOOKED_JOB_ID"] = fmt.Sprintf("%d", job.ID)
+
+		for _, r := range job.Job.Action.Requirements {
+			switch r.Type {
+			case sdk.MemoryRequirement:
+				var err error
+				memory, err = strconv.Atoi(r.Value)
+				if err != nil {
+					log.Warning("SpawnWorker> Unable to parse memory requirement %s: %s", r.Value, err)
+					return nil, err
+				}
+			case sdk.ServiceRequirement:
+				services = append(services, serviceContainer(r))
+				aliases = append(aliases, r.Name)
+			}
+		}
+	}
+
+	cmd := "rm -f worker && curl ${CDS_API}/download/worker/$(uname -m) -o worker && chmod +x worker && exec ./worker"
+	worker := Container{
+		Name:    "worker",
+		Image:   model.Image,
+		Command: []string{"sh", "-c", cmd},
+		Env:     envVars(env),
+		Resources: ResourceRequirements{
+			Limits:   map[string]string{"memory": fmt.Sprintf("%dMi", memory)},
+			Requests: map[string]string{"memory": fmt.Sprintf("%dMi", memory)},
+		},
+	}
+	if strings.HasSuffix(model.Image, ":latest") {
+		worker.ImagePullPolicy = "Always"
+	}
+
+	pod := &Pod{
+		Metadata: ObjectMeta{
+			Name: workerName,
+			Labels: map[string]string{
+				LabelHatchery:    strconv.FormatInt(h.ID(), 10),
+				LabelWorker:      workerName,
+				LabelWorkerModel: strconv.FormatInt(model.ID, 10),
+			},
+		},
+		Spec: PodSpec{
+			Containers:    append([]Container{worker}, services...),
+			RestartPolicy: "Never",
+		},
+	}
+
+	if len(aliases) > 0 {
+		pod.Spec.HostAliases = []HostAlias{{IP: "127.0.0.1", Hostnames: aliases}}
+	}
+
+	return pod, nil
+}
+
+//serviceContainer builds the container of a service requirement
+//value= "postgres:latest env_1=blabla env_2=blabla" => we can add env variables in requirement value
+//option for power user : set the service memory with CDS_SERVICE_MEMORY=1024
+func serviceContainer(r sdk.Requirement) Container {
+	tuple := strings.Split(r.Value, " ")
+	c := Container{
+		Name:  dnsName("service-" + r.Name),
+		Image: tuple[0],
+	}
+
+	env := map[string]string{}
+	for _, e := range tuple[1:] {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if kv[0] == "CDS_SERVICE_MEMORY" {
+			if _, err := strconv.Atoi(kv[1]); err != nil {
+				log.Warning("SpawnWorker> Unable to parse service option %s : %s", e, err)
+				continue
+			}
+			c.Resources.Limits = map[string]string{"memory": kv[1] + "Mi"}
+			continue
+		}
+		env[kv[0]] = kv[1]
+	}
+	c.Env = envVars(env)
+
+	return c
+}
+
+func envVars(env map[string]string) []EnvVar {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := make([]EnvVar, 0, len(env))
+	for _, k := range keys {
+		res = append(res, EnvVar{Name: k, Value: env[k]})
+	}
+	return res
+}
+
+// WorkersStarted returns the number of instances started but
+// not necessarily register on CDS yet
+func (h *HatcheryKubernetes) WorkersStarted() int {
+	pods, err := h.pods()
+	if err != nil {
+		log.Warning("WorkersStarted> Unable to list pods: %s", err)
+		return 0
+	}
+	return len(pods)
+}
+
+// WorkersStartedByModel returns the number of instances of given model started but
+// not necessarily register on CDS yet
+func (h *HatcheryKubernetes) WorkersStartedByModel(model *sdk.Model) int {
+	pods, err := h.pods(LabelWorkerModel + "=" + strconv.FormatInt(model.ID, 10))
+	if err != nil {
+		log.Warning("WorkersStartedByModel> Unable to list pods: %s", err)
+		return 0
+	}
+	return len(pods)
+}
+
+func (h *HatcheryKubernetes) killAwolWorkerRoutine() {
+	for {
+		time.Sleep(30 * time.Second)
+		if err := h.killAwolWorkers(); err != nil {
+			log.Warning("killAwolWorkerRoutine> Cannot kill awol workers: %s", err)
+		}
+	}
+}
+
+//killAwolWorkers deletes terminated pods, pods of disabled workers,
+//pods running for more than a minute without registered worker and
+//pods pending for more than the spawn timeout
+func (h *HatcheryKubernetes) killAwolWorkers() error {
+	workers, err := sdk.GetWorkers()
+	if err != nil {
+		return err
+	}
+	return h.killAwolPods(workers)
+}
+
+func (h *HatcheryKubernetes) killAwolPods(workers []sdk.Worker) error {
+	pods, err := h.client.List(h.selector())
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pods {
+		var kill bool
+		switch {
+		case p.Status.Phase == PodSucceeded || p.Status.Phase == PodFailed:
+			kill = true
+		case p.Status.Phase == PodRunning:
+			var found bool
+			for _, w := range workers {
+				if w.Name == p.Metadata.Labels[LabelWorker] {
+					found = true
+					kill = w.Status == sdk.StatusDisabled
+					break
+				}
+			}
+			if !found && p.Metadata.CreationTimestamp != nil && time.Since(*p.Metadata.CreationTimestamp) > time.Minute {
+				kill = true
+			}
+		case p.Status.Phase == PodPending:
+			//The pod cannot be scheduled or its image cannot be pulled, it would be counted as a started worker forever
+			if p.Metadata.CreationTimestamp != nil && time.Since(*p.Metadata.CreationTimestamp) > time.Duration(h.workerSpawnTimeout)*time.Second {
+				kill = true
+			}
+		}
+
+		if !kill {
+			continue
+		}
+
+		log.Info("killAwolWorkers> Delete pod %s", p.Metadata.Name)
+		if err := h.client.Delete(p.Metadata.Name); err != nil {
+			log.Warning("killAwolWorkers> Cannot delete pod %s: %s", p.Metadata.Name, err)
+		}
+	}
+
+	return nil
+}
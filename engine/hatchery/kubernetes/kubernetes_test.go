@@ -0,0 +1,219 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// fakeClient is an in memory PodInterface
+type fakeClient struct {
+	pods map[string]Pod
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{pods: map[string]Pod{}}
+}
+
+func (f *fakeClient) Create(pod *Pod) (*Pod, error) {
+	p := *pod
+	p.Status.Phase = PodPending
+	f.pods[p.Metadata.Name] = p
+	return &p, nil
+}
+
+func (f *fakeClient) List(labelSelector string) ([]Pod, error) {
+	res := []Pod{}
+	for _, p := range f.pods {
+		match := true
+		for _, s := range strings.Split(labelSelector, ",") {
+			kv := strings.SplitN(s, "=", 2)
+			if len(kv) == 2 && p.Metadata.Labels[kv[0]] != kv[1] {
+				match = false
+			}
+		}
+		if match {
+			res = append(res, p)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeClient) Delete(name string) error {
+	delete(f.pods, name)
+	return nil
+}
+
+func newTestHatchery() (*HatcheryKubernetes, *fakeClient) {
+	client := newFakeClient()
+	h := &HatcheryKubernetes{
+		hatch:              &sdk.Hatchery{ID: 1, Name: "test-kubernetes"},
+		token:              "token",
+		client:             client,
+		defaultMemory:      1024,
+		workerTTL:          10,
+		workerSpawnTimeout: 120,
+	}
+	return h, client
+}
+
+func TestSpawnWorker(t *testing.T) {
+	h, client := newTestHatchery()
+
+	model := &sdk.Model{ID: 42, Name: "Go_1.8", Image: "golang:1.8"}
+	job := &sdk.PipelineBuildJob{ID: 7}
+	job.Job.Action.Requirements = []sdk.Requirement{
+		{Name: "Go_1.8", Type: sdk.ModelRequirement, Value: "Go_1.8"},
+		{Name: "2048", Type: sdk.MemoryRequirement, Value: "2048"},
+		{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres:9.6 POSTGRES_USER=cds CDS_SERVICE_MEMORY=512"},
+	}
+
+	assert.NoError(t, h.SpawnWorker(model, job))
+	assert.Len(t, client.pods, 1)
+
+	var pod Pod
+	for _, p := range client.pods {
+		pod = p
+	}
+
+	assert.True(t, strings.HasPrefix(pod.Metadata.Name, "go-1-8-"))
+	assert.Equal(t, "1", pod.Metadata.Labels[LabelHatchery])
+	assert.Equal(t, "42", pod.Metadata.Labels[LabelWorkerModel])
+	assert.Equal(t, pod.Metadata.Name, pod.Metadata.Labels[LabelWorker])
+	assert.Equal(t, "Never", pod.Spec.RestartPolicy)
+
+	assert.Len(t, pod.Spec.Containers, 2)
+	worker := pod.Spec.Containers[0]
+	assert.Equal(t, "golang:1.8", worker.Image)
+	assert.Equal(t, "2048Mi", worker.Resources.Limits["memory"])
+	assert.Contains(t, worker.Env, EnvVar{Name: "CDS_BOOKED_JOB_ID", Value: "7"})
+	assert.Contains(t, worker.Env, EnvVar{Name: "CDS_NAME", Value: pod.Metadata.Name})
+
+	service := pod.Spec.Containers[1]
+	assert.Equal(t, "service-pg", service.Name)
+	assert.Equal(t, "postgres:9.6", service.Image)
+	assert.Equal(t, "512Mi", service.Resources.Limits["memory"])
+	assert.Equal(t, []EnvVar{{Name: "POSTGRES_USER", Value: "cds"}}, service.Env)
+	assert.Equal(t, []HostAlias{{IP: "127.0.0.1", Hostnames: []string{"pg"}}}, pod.Spec.HostAliases)
+}
+
+func TestWorkersStarted(t *testing.T) {
+	h, client := newTestHatchery()
+	viper.Set("max-worker", 2)
+
+	goModel := &sdk.Model{ID: 1, Name: "go", Image: "golang:1.8"}
+	nodeModel := &sdk.Model{ID: 2, Name: "node", Image: "node:8"}
+
+	assert.True(t, h.CanSpawn(goModel, nil))
+	assert.NoError(t, h.SpawnWorker(goModel, nil))
+	assert.NoError(t, h.SpawnWorker(nodeModel, nil))
+
+	//Pods from another hatchery are ignored
+	client.pods["other"] = Pod{Metadata: ObjectMeta{Name: "other", Labels: map[string]string{LabelHatchery: "2", LabelWorkerModel: "1"}}}
+
+	assert.Equal(t, 2, h.WorkersStarted())
+	assert.Equal(t, 1, h.WorkersStartedByModel(goModel))
+	assert.False(t, h.CanSpawn(goModel, nil))
+
+	//Terminated pods are not counted
+	for name, p := range client.pods {
+		if p.Metadata.Labels[LabelWorkerModel] == "2" {
+			p.Status.Phase = PodSucceeded
+			client.pods[name] = p
+		}
+	}
+	assert.Equal(t, 1, h.WorkersStarted())
+	assert.Equal(t, 0, h.WorkersStartedByModel(nodeModel))
+	assert.True(t, h.CanSpawn(goModel, nil))
+}
+
+func TestKillWorker(t *testing.T) {
+	h, client := newTestHatchery()
+
+	model := &sdk.Model{ID: 1, Name: "go", Image: "golang:1.8"}
+	assert.NoError(t, h.SpawnWorker(model, nil))
+	assert.NoError(t, h.SpawnWorker(model, nil))
+	assert.Len(t, client.pods, 2)
+
+	var name string
+	for n := range client.pods {
+		name = n
+	}
+
+	assert.NoError(t, h.KillWorker(sdk.Worker{Name: name}))
+	assert.Len(t, client.pods, 1)
+	_, found := client.pods[name]
+	assert.False(t, found)
+}
+
+func TestKillAwolPods(t *testing.T) {
+	h, client := newTestHatchery()
+	viper.Set("max-worker", 2)
+
+	model := &sdk.Model{ID: 1, Name: "go", Image: "golang:1.8"}
+	assert.NoError(t, h.SpawnWorker(model, nil))
+	assert.NoError(t, h.SpawnWorker(model, nil))
+	assert.False(t, h.CanSpawn(model, nil))
+
+	//Both pods are pending, one of them for longer than the spawn timeout
+	var stuck string
+	for name, p := range client.pods {
+		created := time.Now()
+		if stuck == "" {
+			stuck = name
+			created = created.Add(-3 * time.Minute)
+		}
+		p.Metadata.CreationTimestamp = &created
+		client.pods[name] = p
+	}
+
+	assert.NoError(t, h.killAwolPods(nil))
+	assert.Len(t, client.pods, 1)
+	_, found := client.pods[stuck]
+	assert.False(t, found)
+	assert.True(t, h.CanSpawn(model, nil))
+}
+
+func TestRestClient(t *testing.T) {
+	var created Pod
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/namespaces/cds/pods":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(created)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/cds/pods":
+			assert.Equal(t, "cds-hatchery=1", r.URL.Query().Get("labelSelector"))
+			json.NewEncoder(w).Encode(PodList{Items: []Pod{created}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/namespaces/cds/pods/worker":
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(Status{Message: `pods "worker" not found`, Code: 404})
+		}
+	}))
+	defer s.Close()
+
+	client, err := NewClient(s.URL, "secret", "", "cds", false)
+	assert.NoError(t, err)
+
+	_, err = client.Create(&Pod{Metadata: ObjectMeta{Name: "worker", Labels: map[string]string{LabelHatchery: "1"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Pod", created.Kind)
+
+	pods, err := client.List("cds-hatchery=1")
+	assert.NoError(t, err)
+	assert.Len(t, pods, 1)
+	assert.Equal(t, "worker", pods[0].Metadata.Name)
+
+	err = client.Delete("worker")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -45,6 +45,7 @@ $ hatchery docker --api=https://<api.domain> --token=<token> --basedir=/tmp
 			viper.GetInt("spawn-threshold-warning"),
 			viper.GetInt("spawn-threshold-critical"),
 			viper.GetInt("grace-time-queued"),
+			viper.GetBool("dry-run"),
 		)
 	},
 	PreRun: func(cmd *cobra.Command, args []string) {
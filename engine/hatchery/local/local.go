@@ -200,8 +200,9 @@ func (h *HatcheryLocal) Init() error {
 	name := hatchery.GenerateName("local", viper.GetString("name"))
 
 	h.hatch = &sdk.Hatchery{
-		Name: name,
-		UID:  viper.GetString("token"),
+		Name:      name,
+		UID:       viper.GetString("token"),
+		ModelType: h.ModelType(),
 		Model: sdk.Model{
 			Name:         name,
 			Image:        name,
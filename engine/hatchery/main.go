@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/ovh/cds/engine/hatchery/docker"
+	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
 	"github.com/ovh/cds/engine/hatchery/openstack"
@@ -65,6 +66,7 @@ func addCommands() {
 	rootCmd.AddCommand(marathon.Cmd)
 	rootCmd.AddCommand(swarm.Cmd)
 	rootCmd.AddCommand(openstack.Cmd)
+	rootCmd.AddCommand(kubernetes.Cmd)
 }
 
 // Cannot rely on viper.AutomaticEnv here because of the presence of hyphen '-'
@@ -113,6 +115,9 @@ func addFlags() {
 	rootCmd.PersistentFlags().Int64("grace-time-queued", 4, "if worker is queued less than this value (seconds), hatchery does not take care of it")
 	viper.BindPFlag("grace-time-queued", rootCmd.PersistentFlags().Lookup("grace-time-queued"))
 
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Only log the workers the hatchery would spawn and kill, without spawning nor killing them")
+	viper.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run"))
+
 	rootCmd.PersistentFlags().String("graylog-protocol", "", "Ex: --graylog-protocol=xxxx-yyyy")
 	viper.BindPFlag("graylog_protocol", rootCmd.PersistentFlags().Lookup("graylog-protocol"))
 
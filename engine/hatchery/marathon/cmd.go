@@ -75,6 +75,7 @@ $ hatchery marathon --api=https://<api.domain> --token=<token>
 			viper.GetInt("spawn-threshold-warning"),
 			viper.GetInt("spawn-threshold-critical"),
 			viper.GetInt("grace-time-queued"),
+			viper.GetBool("dry-run"),
 		)
 	},
 	PreRun: func(cmd *cobra.Command, args []string) {
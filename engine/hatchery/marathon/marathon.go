@@ -123,13 +123,14 @@ func (m *HatcheryMarathon) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJ
 	forcePull := strings.HasSuffix(model.Image, ":latest")
 
 	env := map[string]string{
-		"CDS_API":        sdk.Host,
-		"CDS_KEY":        m.token,
-		"CDS_NAME":       workerName,
-		"CDS_MODEL":      fmt.Sprintf("%d", model.ID),
-		"CDS_HATCHERY":   fmt.Sprintf("%d", m.hatch.ID),
-		"CDS_SINGLE_USE": "1",
-		"CDS_TTL":        fmt.Sprintf("%d", m.workerTTL),
+		"CDS_API":           sdk.Host,
+		"CDS_KEY":           m.token,
+		"CDS_NAME":          workerName,
+		"CDS_MODEL":         fmt.Sprintf("%d", model.ID),
+		"CDS_MODEL_VERSION": fmt.Sprintf("%d", model.Version),
+		"CDS_HATCHERY":      fmt.Sprintf("%d", m.hatch.ID),
+		"CDS_SINGLE_USE":    "1",
+		"CDS_TTL":           fmt.Sprintf("%d", m.workerTTL),
 	}
 
 	if viper.GetString("graylog_host") != "" {
@@ -309,8 +310,9 @@ func (m *HatcheryMarathon) WorkersStartedByModel(model *sdk.Model) int {
 func (m *HatcheryMarathon) Init() error {
 	// Register without declaring model
 	m.hatch = &sdk.Hatchery{
-		Name: hatchery.GenerateName("marathon", viper.GetString("name")),
-		UID:  viper.GetString("token"),
+		Name:      hatchery.GenerateName("marathon", viper.GetString("name")),
+		UID:       viper.GetString("token"),
+		ModelType: m.ModelType(),
 	}
 
 	if err := hatchery.Register(m.hatch, viper.GetString("token")); err != nil {
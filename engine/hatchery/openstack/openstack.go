@@ -86,8 +86,9 @@ const serverStatusActive = "ACTIVE"
 func (h *HatcheryCloud) Init() error {
 	// Register without declaring model
 	h.hatch = &sdk.Hatchery{
-		Name: hatchery.GenerateName("openstack", viper.GetString("name")),
-		UID:  viper.GetString("uk"),
+		Name:      hatchery.GenerateName("openstack", viper.GetString("name")),
+		UID:       viper.GetString("uk"),
+		ModelType: h.ModelType(),
 	}
 
 	if errRegistrer := hatchery.Register(h.hatch, viper.GetString("token")); errRegistrer != nil {
@@ -400,7 +401,7 @@ cd $HOME
 # Download and start worker with curl
 curl  "{{.API}}/download/worker/$(uname -m)" -o worker --retry 10 --retry-max-time 120 -C - >> /tmp/user_data 2>&1
 chmod +x worker
-CDS_SINGLE_USE=1 ./worker --api={{.API}} --key={{.Key}} --name={{.Name}} --model={{.Model}} --hatchery={{.Hatchery}} --booked-job-id={{.JobID}} --single-use --ttl={{.TTL}} {{.Graylog}} && exit 0
+CDS_SINGLE_USE=1 ./worker --api={{.API}} --key={{.Key}} --name={{.Name}} --model={{.Model}} --model-version={{.ModelVersion}} --hatchery={{.Hatchery}} --booked-job-id={{.JobID}} --single-use --ttl={{.TTL}} {{.Graylog}} && exit 0
 `
 	var udata = udataBegin + string(udataModel) + udataEnd
 
@@ -414,23 +415,25 @@ CDS_SINGLE_USE=1 ./worker --api={{.API}} --key={{.Key}} --name={{.Name}} --model
 		return errt
 	}
 	udataParam := struct {
-		API      string
-		Name     string
-		Key      string
-		Model    int64
-		Hatchery int64
-		JobID    int64
-		TTL      int
-		Graylog  string
+		API          string
+		Name         string
+		Key          string
+		Model        int64
+		ModelVersion int64
+		Hatchery     int64
+		JobID        int64
+		TTL          int
+		Graylog      string
 	}{
-		API:      viper.GetString("api"),
-		Name:     name,
-		Key:      viper.GetString("token"),
-		Model:    model.ID,
-		Hatchery: h.hatch.ID,
-		JobID:    jobID,
-		TTL:      h.workerTTL,
-		Graylog:  graylog,
+		API:          viper.GetString("api"),
+		Name:         name,
+		Key:          viper.GetString("token"),
+		Model:        model.ID,
+		ModelVersion: model.Version,
+		Hatchery:     h.hatch.ID,
+		JobID:        jobID,
+		TTL:          h.workerTTL,
+		Graylog:      graylog,
 	}
 	var buffer bytes.Buffer
 	if err = tmpl.Execute(&buffer, udataParam); err != nil {
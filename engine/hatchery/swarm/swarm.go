@@ -43,7 +43,8 @@ func (h *HatcherySwarm) Init() error {
 	}
 
 	h.hatch = &sdk.Hatchery{
-		Name: hatchery.GenerateName("swarm", viper.GetString("name")),
+		Name:      hatchery.GenerateName("swarm", viper.GetString("name")),
+		ModelType: h.ModelType(),
 	}
 
 	if err := hatchery.Register(h.hatch, viper.GetString("token")); err != nil {
@@ -256,6 +257,7 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJob)
 		"CDS_NAME" + "=" + name,
 		"CDS_KEY" + "=" + viper.GetString("token"),
 		"CDS_MODEL" + "=" + strconv.FormatInt(model.ID, 10),
+		"CDS_MODEL_VERSION" + "=" + strconv.FormatInt(model.Version, 10),
 		"CDS_HATCHERY" + "=" + strconv.FormatInt(h.hatch.ID, 10),
 		"CDS_TTL" + "=" + strconv.Itoa(h.workerTTL),
 		"CDS_SINGLE_USE=1",
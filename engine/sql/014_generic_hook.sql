@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE hook ADD COLUMN secret TEXT DEFAULT '';
+ALTER TABLE hook ADD COLUMN environment TEXT DEFAULT '';
+ALTER TABLE hook ADD COLUMN params_mapping JSONB;
+ALTER TABLE received_hook ADD COLUMN uid TEXT DEFAULT '';
+ALTER TABLE received_hook ADD COLUMN created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP;
+select create_index('received_hook', 'IDX_RECEIVED_HOOK_UID', 'uid');
+
+-- +migrate Down
+drop index IDX_RECEIVED_HOOK_UID;
+ALTER TABLE received_hook DROP COLUMN created;
+ALTER TABLE received_hook DROP COLUMN uid;
+ALTER TABLE hook DROP COLUMN params_mapping;
+ALTER TABLE hook DROP COLUMN environment;
+ALTER TABLE hook DROP COLUMN secret;
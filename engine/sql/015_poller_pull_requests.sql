@@ -0,0 +1,6 @@
+-- +migrate Up
+ALTER TABLE poller ADD COLUMN pull_requests BOOLEAN DEFAULT false;
+UPDATE poller set pull_requests = false;
+
+-- +migrate Down
+ALTER TABLE poller DROP COLUMN pull_requests;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE pipeline_action ADD COLUMN matrix TEXT;
+
+-- +migrate Down
+ALTER TABLE pipeline_action DROP COLUMN matrix;
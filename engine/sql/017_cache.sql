@@ -0,0 +1,7 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "cache" (id BIGSERIAL PRIMARY KEY, project_id BIGINT, cache_key TEXT, size BIGINT DEFAULT 0, created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP, last_access TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP);
+ALTER TABLE cache ADD CONSTRAINT FK_CACHE_PROJECT FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE;
+select create_index('cache','IDX_CACHE_PROJECT_KEY','project_id,cache_key');
+
+-- +migrate Down
+DROP TABLE cache;
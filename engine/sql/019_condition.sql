@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE pipeline_stage ADD COLUMN condition_expression TEXT NOT NULL DEFAULT '';
+ALTER TABLE pipeline_trigger ADD COLUMN condition_expression TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE pipeline_stage DROP COLUMN condition_expression;
+ALTER TABLE pipeline_trigger DROP COLUMN condition_expression;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE pipeline_action ADD COLUMN outputs TEXT;
+ALTER TABLE pipeline_build_job ADD COLUMN outputs JSONB;
+
+-- +migrate Down
+ALTER TABLE pipeline_action DROP COLUMN outputs;
+ALTER TABLE pipeline_build_job DROP COLUMN outputs;
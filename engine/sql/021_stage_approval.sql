@@ -0,0 +1,22 @@
+-- +migrate Up
+ALTER TABLE pipeline_stage ADD COLUMN approval TEXT;
+
+CREATE TABLE IF NOT EXISTS "pipeline_build_approval" (
+    id BIGSERIAL PRIMARY KEY,
+    pipeline_build_id BIGINT NOT NULL,
+    stage_id BIGINT NOT NULL,
+    user_id BIGINT,
+    username TEXT NOT NULL,
+    approved BOOLEAN NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+CREATE INDEX idx_pipeline_build_approval_build ON pipeline_build_approval (pipeline_build_id);
+ALTER TABLE pipeline_build_approval ADD CONSTRAINT FK_PIPELINE_BUILD_APPROVAL_PIPELINE_BUILD FOREIGN KEY (pipeline_build_id) REFERENCES pipeline_build(id) ON DELETE CASCADE;
+-- user_id is null for the rejections of CDS when the approval timeout is reached, and once the approver is deleted:
+-- the record is kept with the username of the approver
+ALTER TABLE pipeline_build_approval ADD CONSTRAINT FK_PIPELINE_BUILD_APPROVAL_USER FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE SET NULL;
+
+-- +migrate Down
+DROP TABLE pipeline_build_approval;
+ALTER TABLE pipeline_stage DROP COLUMN approval;
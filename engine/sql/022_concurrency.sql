@@ -0,0 +1,11 @@
+-- +migrate Up
+ALTER TABLE pipeline ADD COLUMN concurrency TEXT;
+ALTER TABLE pipeline_build ADD COLUMN concurrency_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE pipeline_build ADD COLUMN waiting_reason TEXT NOT NULL DEFAULT '';
+CREATE INDEX idx_pipeline_build_concurrency ON pipeline_build (concurrency_key, status);
+
+-- +migrate Down
+DROP INDEX idx_pipeline_build_concurrency;
+ALTER TABLE pipeline_build DROP COLUMN waiting_reason;
+ALTER TABLE pipeline_build DROP COLUMN concurrency_key;
+ALTER TABLE pipeline DROP COLUMN concurrency;
@@ -0,0 +1,16 @@
+-- +migrate Up
+ALTER TABLE pipeline_action ADD COLUMN retry TEXT;
+
+CREATE TABLE IF NOT EXISTS "pipeline_build_job_attempt" (
+    id BIGSERIAL PRIMARY KEY,
+    pipeline_build_id BIGINT NOT NULL,
+    pipeline_build_job_id BIGINT NOT NULL,
+    attempt INT NOT NULL,
+    data JSONB
+);
+CREATE INDEX idx_pipeline_build_job_attempt_job ON pipeline_build_job_attempt (pipeline_build_job_id);
+CREATE INDEX idx_pipeline_build_job_attempt_build ON pipeline_build_job_attempt (pipeline_build_id);
+
+-- +migrate Down
+DROP TABLE pipeline_build_job_attempt;
+ALTER TABLE pipeline_action DROP COLUMN retry;
@@ -0,0 +1,20 @@
+-- +migrate Up
+ALTER TABLE artifact ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS "artifact_retention" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    application_id BIGINT,
+    pipeline_id BIGINT,
+    keep_last INT NOT NULL DEFAULT 0,
+    keep_days INT NOT NULL DEFAULT 0,
+    keep_deployed BOOLEAN NOT NULL DEFAULT FALSE
+);
+ALTER TABLE artifact_retention ADD CONSTRAINT FK_ARTIFACT_RETENTION_PROJECT FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE;
+ALTER TABLE artifact_retention ADD CONSTRAINT FK_ARTIFACT_RETENTION_APPLICATION FOREIGN KEY (application_id) REFERENCES application(id) ON DELETE CASCADE;
+ALTER TABLE artifact_retention ADD CONSTRAINT FK_ARTIFACT_RETENTION_PIPELINE FOREIGN KEY (pipeline_id) REFERENCES pipeline(id) ON DELETE CASCADE;
+CREATE UNIQUE INDEX idx_artifact_retention_level ON artifact_retention (project_id, COALESCE(application_id, 0), COALESCE(pipeline_id, 0));
+
+-- +migrate Down
+DROP TABLE artifact_retention;
+ALTER TABLE artifact DROP COLUMN pinned;
@@ -0,0 +1,27 @@
+-- +migrate Up
+ALTER TABLE artifact ADD COLUMN sha256sum TEXT;
+
+CREATE TABLE IF NOT EXISTS "artifact_upload" (
+    id TEXT PRIMARY KEY,
+    pipeline_id BIGINT NOT NULL,
+    application_id BIGINT NOT NULL,
+    environment_id BIGINT NOT NULL,
+    build_number BIGINT NOT NULL,
+    tag TEXT NOT NULL,
+    name TEXT NOT NULL,
+    environment_name TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    perm INT NOT NULL DEFAULT 0,
+    md5sum TEXT NOT NULL,
+    sha256sum TEXT NOT NULL DEFAULT '',
+    received BIGINT NOT NULL DEFAULT 0,
+    chunks INT NOT NULL DEFAULT 0,
+    completing BOOLEAN NOT NULL DEFAULT false,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+ALTER TABLE artifact_upload ADD CONSTRAINT FK_ARTIFACT_UPLOAD_PIPELINE FOREIGN KEY (pipeline_id) REFERENCES pipeline(id) ON DELETE CASCADE;
+ALTER TABLE artifact_upload ADD CONSTRAINT FK_ARTIFACT_UPLOAD_APPLICATION FOREIGN KEY (application_id) REFERENCES application(id) ON DELETE CASCADE;
+
+-- +migrate Down
+DROP TABLE artifact_upload;
+ALTER TABLE artifact DROP COLUMN sha256sum;
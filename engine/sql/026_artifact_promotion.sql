@@ -0,0 +1,26 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "artifact_promotion" (
+    id BIGSERIAL PRIMARY KEY,
+    channel TEXT NOT NULL,
+    application_id BIGINT NOT NULL,
+    pipeline_id BIGINT NOT NULL,
+    environment_id BIGINT NOT NULL,
+    build_number BIGINT NOT NULL,
+    artifact_id BIGINT NOT NULL,
+    promoted TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    promoted_by TEXT NOT NULL DEFAULT ''
+);
+ALTER TABLE artifact_promotion ADD CONSTRAINT FK_ARTIFACT_PROMOTION_APPLICATION FOREIGN KEY (application_id) REFERENCES application(id) ON DELETE CASCADE;
+ALTER TABLE artifact_promotion ADD CONSTRAINT FK_ARTIFACT_PROMOTION_PIPELINE FOREIGN KEY (pipeline_id) REFERENCES pipeline(id) ON DELETE CASCADE;
+ALTER TABLE artifact_promotion ADD CONSTRAINT FK_ARTIFACT_PROMOTION_ARTIFACT FOREIGN KEY (artifact_id) REFERENCES artifact(id) ON DELETE CASCADE;
+CREATE UNIQUE INDEX idx_artifact_promotion ON artifact_promotion (application_id, pipeline_id, channel, artifact_id);
+
+INSERT INTO action_parameter (action_id, name, type, value, description)
+SELECT action.id, 'reference', 'string', '', 'Reference of artifacts of another application or pipeline: project/application/pipeline/environment/build[/name], build being a build number, a tag or @channel. Replaces tag, pipeline and application'
+FROM action WHERE action.name = 'Artifact Download' AND NOT EXISTS (
+    SELECT 1 FROM action_parameter WHERE action_parameter.action_id = action.id AND action_parameter.name = 'reference'
+);
+
+-- +migrate Down
+DELETE FROM action_parameter WHERE name = 'reference' AND action_id IN (SELECT id FROM action WHERE name = 'Artifact Download');
+DROP TABLE artifact_promotion;
@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "worker_model_scaling" (
+    worker_model_id BIGINT PRIMARY KEY,
+    min_warm INT NOT NULL DEFAULT 0,
+    max_warm INT NOT NULL,
+    scale_up_step INT NOT NULL DEFAULT 0,
+    job_age_seconds BIGINT NOT NULL DEFAULT 0,
+    idle_minutes INT NOT NULL DEFAULT 0,
+    timezone TEXT NOT NULL DEFAULT '',
+    schedules JSONB
+);
+ALTER TABLE worker_model_scaling ADD CONSTRAINT FK_WORKER_MODEL_SCALING_MODEL FOREIGN KEY (worker_model_id) REFERENCES worker_model(id) ON DELETE CASCADE;
+
+CREATE TABLE IF NOT EXISTS "hatchery_model_status" (
+    hatchery_id BIGINT NOT NULL,
+    worker_model_id BIGINT NOT NULL,
+    current_count BIGINT NOT NULL DEFAULT 0,
+    wanted_count BIGINT NOT NULL DEFAULT 0,
+    building_count BIGINT NOT NULL DEFAULT 0,
+    updated TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    PRIMARY KEY (hatchery_id, worker_model_id)
+);
+ALTER TABLE hatchery_model_status ADD CONSTRAINT FK_HATCHERY_MODEL_STATUS_HATCHERY FOREIGN KEY (hatchery_id) REFERENCES hatchery(id) ON DELETE CASCADE;
+ALTER TABLE hatchery_model_status ADD CONSTRAINT FK_HATCHERY_MODEL_STATUS_MODEL FOREIGN KEY (worker_model_id) REFERENCES worker_model(id) ON DELETE CASCADE;
+
+-- +migrate Down
+DROP TABLE hatchery_model_status;
+DROP TABLE worker_model_scaling;
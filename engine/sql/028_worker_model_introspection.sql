@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "worker_model_introspection" (
+    id BIGSERIAL PRIMARY KEY,
+    worker_model_id BIGINT NOT NULL,
+    status TEXT NOT NULL,
+    requested TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    requested_by TEXT NOT NULL DEFAULT '',
+    done TIMESTAMP WITH TIME ZONE,
+    worker_name TEXT NOT NULL DEFAULT '',
+    result JSONB,
+    added JSONB,
+    removed JSONB
+);
+ALTER TABLE worker_model_introspection ADD CONSTRAINT FK_WORKER_MODEL_INTROSPECTION_MODEL FOREIGN KEY (worker_model_id) REFERENCES worker_model(id) ON DELETE CASCADE;
+CREATE INDEX idx_worker_model_introspection_model ON worker_model_introspection (worker_model_id, status);
+
+-- +migrate Down
+DROP TABLE worker_model_introspection;
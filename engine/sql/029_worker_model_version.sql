@@ -0,0 +1,38 @@
+-- +migrate Up
+ALTER TABLE worker_model ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE worker_model ADD COLUMN state TEXT NOT NULL DEFAULT 'active';
+
+CREATE TABLE IF NOT EXISTS "worker_model_version" (
+    worker_model_id BIGINT NOT NULL,
+    version BIGINT NOT NULL,
+    type TEXT NOT NULL,
+    image TEXT NOT NULL,
+    capabilities JSONB,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    created_by TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (worker_model_id, version)
+);
+ALTER TABLE worker_model_version ADD CONSTRAINT FK_WORKER_MODEL_VERSION_MODEL FOREIGN KEY (worker_model_id) REFERENCES worker_model(id) ON DELETE CASCADE;
+
+INSERT INTO worker_model_version (worker_model_id, version, type, image, capabilities, created_by)
+SELECT worker_model.id, 1, worker_model.type, worker_model.image,
+    COALESCE((SELECT json_agg(json_build_object('name', name, 'type', type, 'value', argument)) FROM worker_capability WHERE worker_model_id = worker_model.id), '[]')::JSONB,
+    COALESCE(worker_model.created_by->>'username', '')
+FROM worker_model;
+
+CREATE TABLE IF NOT EXISTS "worker_model_audit" (
+    id BIGSERIAL PRIMARY KEY,
+    worker_model_id BIGINT NOT NULL,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    username TEXT NOT NULL DEFAULT '',
+    change TEXT NOT NULL,
+    detail TEXT NOT NULL DEFAULT ''
+);
+ALTER TABLE worker_model_audit ADD CONSTRAINT FK_WORKER_MODEL_AUDIT_MODEL FOREIGN KEY (worker_model_id) REFERENCES worker_model(id) ON DELETE CASCADE;
+CREATE INDEX idx_worker_model_audit_model ON worker_model_audit (worker_model_id);
+
+-- +migrate Down
+DROP TABLE worker_model_audit;
+DROP TABLE worker_model_version;
+ALTER TABLE worker_model DROP COLUMN state;
+ALTER TABLE worker_model DROP COLUMN version;
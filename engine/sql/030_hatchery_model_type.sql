@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE hatchery ADD COLUMN model_type TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE hatchery DROP COLUMN model_type;
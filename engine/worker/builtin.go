@@ -23,6 +23,10 @@ func runBuiltin(a *sdk.Action, pbJob sdk.PipelineBuildJob, stepOrder int) sdk.Re
 		return runParseJunitTestResultAction(a, pbJob, stepOrder)
 	case sdk.GitCloneAction:
 		return runGitClone(a, pbJob, stepOrder)
+	case sdk.CacheSaveAction:
+		return runCacheSave(a, pbJob, stepOrder)
+	case sdk.CacheRestoreAction:
+		return runCacheRestore(a, pbJob, stepOrder)
 	}
 
 	res.Reason = fmt.Sprintf("Unknown builtin step: %s\n", name)
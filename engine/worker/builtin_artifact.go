@@ -107,7 +107,7 @@ func runArtifactUpload(filePattern, tag string, pbJob sdk.PipelineBuildJob, step
 
 func runArtifactDownload(a *sdk.Action, pbJob sdk.PipelineBuildJob, stepOrder int) sdk.Result {
 	res := sdk.Result{Status: sdk.StatusSuccess}
-	var project, pipeline, application, environment, tag, filePath string
+	var project, pipeline, application, environment, tag, filePath, reference string
 	enabled := true
 
 	for _, p := range pbJob.Parameters {
@@ -152,6 +152,9 @@ func runArtifactDownload(a *sdk.Action, pbJob sdk.PipelineBuildJob, stepOrder in
 		case "application":
 			fmt.Printf("runArtifactDownload: application=%s\n", p.Value)
 			application = p.Value
+		case "reference":
+			fmt.Printf("runArtifactDownload: reference=%s\n", p.Value)
+			reference = p.Value
 		}
 	}
 
@@ -160,6 +163,18 @@ func runArtifactDownload(a *sdk.Action, pbJob sdk.PipelineBuildJob, stepOrder in
 		return res
 	}
 
+	// A reference fetches the artifacts of any application pipeline build, the API checks the permissions on it
+	if reference != "" {
+		sendLog(pbJob.ID, fmt.Sprintf("Downloading artifacts from %s into '%s'...\n", reference, filePath), pbJob.PipelineBuildID, stepOrder, false)
+		if err := sdk.DownloadArtifactsByRef(reference, filePath); err != nil {
+			res.Status = sdk.StatusFail
+			res.Reason = fmt.Sprintf("%s\n", err)
+			log.Warning("Cannot download artifacts: %s\n", err)
+			sendLog(pbJob.ID, res.Reason, pbJob.PipelineBuildID, stepOrder, false)
+		}
+		return res
+	}
+
 	if tag == "" {
 		res.Status = sdk.StatusFail
 		res.Reason = fmt.Sprintf("tag variable is empty. aborting\n")
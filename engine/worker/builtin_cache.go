@@ -0,0 +1,343 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// hashFilesRegexp matches {{hashFiles "pattern" ...}} in cache keys
+var hashFilesRegexp = regexp.MustCompile(`{{\s*hashFiles((?:\s+"[^"]*")+)\s*}}`)
+
+var quotedRegexp = regexp.MustCompile(`"([^"]*)"`)
+
+// cacheKey replaces all {{hashFiles "pattern"}} of the key by the sha256 of the matching files
+func cacheKey(key string) (string, error) {
+	var errHash error
+	k := hashFilesRegexp.ReplaceAllStringFunc(key, func(m string) string {
+		patterns := []string{}
+		for _, q := range quotedRegexp.FindAllStringSubmatch(m, -1) {
+			patterns = append(patterns, q[1])
+		}
+		h, err := hashFiles(patterns)
+		if err != nil {
+			errHash = err
+		}
+		return h
+	})
+	return strings.TrimSpace(k), errHash
+}
+
+// hashFiles returns the hex sha256 of the names and contents of the files matching the patterns
+func hashFiles(patterns []string) (string, error) {
+	files := []string{}
+	for _, p := range patterns {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return "", fmt.Errorf("cannot perform globbing of pattern '%s': %s", p, err)
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("patterns %s matched no file", strings.Join(patterns, ", "))
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, f := range files {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return "", err
+		}
+		if fi.IsDir() {
+			continue
+		}
+		file, err := os.Open(f)
+		if err != nil {
+			return "", err
+		}
+		io.WriteString(h, f)
+		_, err = io.Copy(h, file)
+		file.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// splitLines returns the non empty lines of s, commas are also accepted as separators
+func splitLines(s string) []string {
+	lines := []string{}
+	for _, l := range strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == ',' }) {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+func getCacheParams(a *sdk.Action, pbJob sdk.PipelineBuildJob) (project, application, key string, values map[string]string) {
+	for _, p := range pbJob.Parameters {
+		switch p.Name {
+		case "cds.project":
+			project = p.Value
+		case "cds.application":
+			application = p.Value
+		}
+	}
+	values = map[string]string{}
+	for _, p := range a.Parameters {
+		values[p.Name] = p.Value
+	}
+	key = values["key"]
+	return
+}
+
+func runCacheSave(a *sdk.Action, pbJob sdk.PipelineBuildJob, stepOrder int) sdk.Result {
+	res := sdk.Result{Status: sdk.StatusSuccess}
+	project, application, key, values := getCacheParams(a, pbJob)
+
+	paths := splitLines(values["path"])
+	if len(paths) == 0 {
+		res.Status = sdk.StatusFail
+		res.Reason = fmt.Sprintf("path variable is empty. aborting\n")
+		sendLog(pbJob.ID, res.Reason, pbJob.PipelineBuildID, stepOrder, false)
+		return res
+	}
+
+	key, err := cacheKey(key)
+	if err != nil || key == "" {
+		res.Status = sdk.StatusFail
+		res.Reason = fmt.Sprintf("Invalid cache key: %v\n", err)
+		sendLog(pbJob.ID, res.Reason, pbJob.PipelineBuildID, stepOrder, false)
+		return res
+	}
+
+	tmp, err := ioutil.TempFile("", "cds-cache")
+	if err != nil {
+		res.Status = sdk.StatusFail
+		res.Reason = fmt.Sprintf("Cannot create cache tarball: %s\n", err)
+		sendLog(pbJob.ID, res.Reason, pbJob.PipelineBuildID, stepOrder, false)
+		return res
+	}
+	defer os.RemoveAll(tmp.Name())
+
+	if err := writeTarball(tmp, paths); err != nil {
+		tmp.Close()
+		res.Status = sdk.StatusFail
+		res.Reason = fmt.Sprintf("Cannot create cache tarball: %s\n", err)
+		sendLog(pbJob.ID, res.Reason, pbJob.PipelineBuildID, stepOrder, false)
+		return res
+	}
+	if _, err := tmp.Seek(0, 0); err != nil {
+		tmp.Close()
+		res.Status = sdk.StatusFail
+		res.Reason = fmt.Sprintf("Cannot read cache tarball: %s\n", err)
+		sendLog(pbJob.ID, res.Reason, pbJob.PipelineBuildID, stepOrder, false)
+		return res
+	}
+
+	sendLog(pbJob.ID, fmt.Sprintf("Saving %s into cache %s...\n", strings.Join(paths, ", "), key), pbJob.PipelineBuildID, stepOrder, false)
+	// A cache which cannot be saved only slows down next builds, so it does not fail the step
+	if err := sdk.UploadCache(project, application, key, tmp); err != nil {
+		sendLog(pbJob.ID, fmt.Sprintf("Cannot save cache %s: %s\n", key, err), pbJob.PipelineBuildID, stepOrder, false)
+	}
+	return res
+}
+
+func runCacheRestore(a *sdk.Action, pbJob sdk.PipelineBuildJob, stepOrder int) sdk.Result {
+	res := sdk.Result{Status: sdk.StatusSuccess}
+	project, application, key, values := getCacheParams(a, pbJob)
+
+	// A missing file for hashFiles only means that there is nothing to restore for this key
+	key, err := cacheKey(key)
+	if err != nil {
+		sendLog(pbJob.ID, fmt.Sprintf("Cannot compute cache key: %s\n", err), pbJob.PipelineBuildID, stepOrder, false)
+	}
+	prefixes := splitLines(values["prefixes"])
+
+	c, err := sdk.GetCache(project, application, key, prefixes)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrCacheNotFound) {
+			sendLog(pbJob.ID, fmt.Sprintf("No cache found for key %s\n", key), pbJob.PipelineBuildID, stepOrder, false)
+		} else {
+			sendLog(pbJob.ID, fmt.Sprintf("Cannot get cache %s: %s\n", key, err), pbJob.PipelineBuildID, stepOrder, false)
+		}
+		return res
+	}
+
+	sendLog(pbJob.ID, fmt.Sprintf("Restoring cache %s...\n", c.Key), pbJob.PipelineBuildID, stepOrder, false)
+	reader, err := sdk.DownloadCache(project, application, c)
+	if err != nil {
+		sendLog(pbJob.ID, fmt.Sprintf("Cannot download cache %s: %s\n", c.Key, err), pbJob.PipelineBuildID, stepOrder, false)
+		return res
+	}
+	defer reader.Close()
+
+	if err := extractTarball(reader, "."); err != nil {
+		res.Status = sdk.StatusFail
+		res.Reason = fmt.Sprintf("Cannot extract cache %s: %s\n", c.Key, err)
+		sendLog(pbJob.ID, res.Reason, pbJob.PipelineBuildID, stepOrder, false)
+	}
+	return res
+}
+
+// writeTarball writes a tar.gz of the paths, keeping their names relative to the workspace.
+// Paths outside of the workspace are refused, they could not be restored
+func writeTarball(w io.Writer, paths []string) error {
+	for _, root := range paths {
+		if !isRelativeInside(filepath.Clean(root)) {
+			return fmt.Errorf("invalid path %s: paths must be relative to the workspace", root)
+		}
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, root := range paths {
+		err := filepath.Walk(filepath.Clean(root), func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			var link string
+			if fi.Mode()&os.ModeSymlink != 0 {
+				if link, err = os.Readlink(path); err != nil {
+					return err
+				}
+			}
+			hdr, err := tar.FileInfoHeader(fi, link)
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(path)
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if !fi.Mode().IsRegular() {
+				return nil
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(tw, f)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// isRelativeInside returns true if the cleaned path is relative and does not go up its base directory
+func isRelativeInside(name string) bool {
+	return !filepath.IsAbs(name) && name != ".." && !strings.HasPrefix(name, ".."+string(filepath.Separator))
+}
+
+// isInsideDir returns true if path is dir or a path under dir
+func isInsideDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && isRelativeInside(rel)
+}
+
+// extractTarball extracts a tar.gz into dir. Entries outside of dir are refused, as well as entries written
+// through a symlink leading outside of dir, and symlinks pointing outside of dir
+func extractTarball(r io.Reader, dir string) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return err
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		target := filepath.Join(root, name)
+		if !isRelativeInside(name) || !isInsideDir(root, target) {
+			return fmt.Errorf("invalid path %s", hdr.Name)
+		}
+		if name == "." {
+			continue
+		}
+
+		// the parent directory may be a symlink extracted before: it must stay inside dir
+		parent := filepath.Dir(target)
+		if err := os.MkdirAll(parent, 0755); err != nil {
+			return err
+		}
+		realParent, err := filepath.EvalSymlinks(parent)
+		if err != nil {
+			return err
+		}
+		if !isInsideDir(root, realParent) {
+			return fmt.Errorf("invalid path %s: outside of %s through a symlink", hdr.Name, dir)
+		}
+		target = filepath.Join(realParent, filepath.Base(target))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(hdr.Mode)|0700); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			link := filepath.FromSlash(hdr.Linkname)
+			if filepath.IsAbs(link) || !isInsideDir(root, filepath.Join(realParent, link)) {
+				return fmt.Errorf("invalid symlink %s: %s is outside of %s", hdr.Name, hdr.Linkname, dir)
+			}
+			os.Remove(target)
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			// never write through an existing symlink
+			if fi, err := os.Lstat(target); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+				os.Remove(target)
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(hdr.Mode))
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
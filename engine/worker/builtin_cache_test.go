@@ -0,0 +1,124 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_cacheKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cds-cache-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sum := filepath.Join(dir, "go.sum")
+	assert.NoError(t, ioutil.WriteFile(sum, []byte("v1"), 0644))
+
+	k1, err := cacheKey(`app-{{hashFiles "` + sum + `"}}`)
+	assert.NoError(t, err)
+	assert.Len(t, k1, len("app-")+64)
+
+	k2, err := cacheKey(`app-{{ hashFiles "` + sum + `" "` + filepath.Join(dir, "*.lock") + `" }}`)
+	assert.NoError(t, err)
+	assert.Equal(t, k1, k2)
+
+	assert.NoError(t, ioutil.WriteFile(sum, []byte("v2"), 0644))
+	k3, err := cacheKey(`app-{{hashFiles "` + sum + `"}}`)
+	assert.NoError(t, err)
+	assert.NotEqual(t, k1, k3)
+
+	k4, err := cacheKey(`{{.cds.application}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "{{.cds.application}}", k4)
+
+	_, err = cacheKey(`{{hashFiles "` + filepath.Join(dir, "none") + `"}}`)
+	assert.Error(t, err)
+}
+
+func Test_tarballRoundTrip(t *testing.T) {
+	src, err := ioutil.TempDir("", "cds-cache-src")
+	assert.NoError(t, err)
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "cds-cache-dst")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dst)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(src, "vendor", "lib"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(src, "vendor", "lib", "a.go"), []byte("package lib"), 0644))
+
+	wd, _ := os.Getwd()
+	assert.NoError(t, os.Chdir(src))
+	defer os.Chdir(wd)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, writeTarball(buf, []string{"vendor"}))
+	assert.NoError(t, extractTarball(buf, dst))
+
+	b, err := ioutil.ReadFile(filepath.Join(dst, "vendor", "lib", "a.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "package lib", string(b))
+}
+
+func Test_writeTarballOutsideWorkspace(t *testing.T) {
+	for _, p := range []string{"/etc", "../vendor", "vendor/../.."} {
+		assert.Error(t, writeTarball(new(bytes.Buffer), []string{p}), p)
+	}
+}
+
+func Test_extractTarballSymlinkOutside(t *testing.T) {
+	dst, err := ioutil.TempDir("", "cds-cache-dst")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dst)
+
+	tarball := func(entries ...tar.Header) *bytes.Buffer {
+		buf := new(bytes.Buffer)
+		gz := gzip.NewWriter(buf)
+		tw := tar.NewWriter(gz)
+		for i := range entries {
+			assert.NoError(t, tw.WriteHeader(&entries[i]))
+			if entries[i].Typeflag == tar.TypeReg {
+				tw.Write([]byte("owned"))
+			}
+		}
+		tw.Close()
+		gz.Close()
+		return buf
+	}
+
+	// a symlink leading outside of the workspace is refused
+	buf := tarball(
+		tar.Header{Name: "out", Typeflag: tar.TypeSymlink, Linkname: "..", Mode: 0777},
+		tar.Header{Name: "out/owned", Typeflag: tar.TypeReg, Size: 5, Mode: 0644},
+	)
+	assert.Error(t, extractTarball(buf, dst))
+	_, err = os.Stat(filepath.Join(filepath.Dir(dst), "owned"))
+	assert.True(t, os.IsNotExist(err))
+
+	// files are not written through a symlink leading outside of the workspace
+	assert.NoError(t, os.Symlink(filepath.Dir(dst), filepath.Join(dst, "parent")))
+	buf = tarball(tar.Header{Name: "parent/owned", Typeflag: tar.TypeReg, Size: 5, Mode: 0644})
+	assert.Error(t, extractTarball(buf, dst))
+	_, err = os.Stat(filepath.Join(filepath.Dir(dst), "owned"))
+	assert.True(t, os.IsNotExist(err))
+
+	// symlinks inside the workspace are restored
+	buf = tarball(
+		tar.Header{Name: "lib", Typeflag: tar.TypeDir, Mode: 0755},
+		tar.Header{Name: "lib/a.go", Typeflag: tar.TypeReg, Size: 5, Mode: 0644},
+		tar.Header{Name: "current", Typeflag: tar.TypeSymlink, Linkname: "lib", Mode: 0777},
+	)
+	assert.NoError(t, extractTarball(buf, dst))
+	b, err := ioutil.ReadFile(filepath.Join(dst, "current", "a.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "owned", string(b))
+}
+
+func Test_splitLines(t *testing.T) {
+	assert.Equal(t, []string{"vendor", "node_modules", ".m2"}, splitLines("vendor\n node_modules ,.m2\n\n"))
+}
@@ -9,6 +9,7 @@ import (
 	"path"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/kardianos/osext"
 
@@ -218,6 +219,7 @@ func runScriptAction(a *sdk.Action, pbJob sdk.PipelineBuildJob, stepOrder int) s
 		}
 	}()
 
+	setProcessGroup(cmd)
 	err = cmd.Start()
 	if err != nil {
 		res.Reason = fmt.Sprintf("%s\n", err)
@@ -226,6 +228,20 @@ func runScriptAction(a *sdk.Action, pbJob sdk.PipelineBuildJob, stepOrder int) s
 		return res
 	}
 
+	// Terminate the script and all its children when the job is stopped
+	stopDone := terminateOnStop(cmd)
+	defer stopDone()
+
+	// Kill the script and all its children when the step deadline is reached
+	if !deadline.IsZero() {
+		timer := time.AfterFunc(deadline.Sub(time.Now()), func() {
+			if err := killProcessGroup(cmd); err != nil {
+				log.Warning("runScriptAction> Cannot kill script: %s\n", err)
+			}
+		})
+		defer timer.Stop()
+	}
+
 	_ = <-outchan
 	_ = <-errchan
 	err = cmd.Wait()
@@ -38,6 +38,9 @@ func cmdMain() *cobra.Command {
 	pflags.Int("model", 0, "Model of worker")
 	viper.BindPFlag("model", pflags.Lookup("model"))
 
+	pflags.Int("model-version", 0, "Version of the model of worker")
+	viper.BindPFlag("model_version", pflags.Lookup("model-version"))
+
 	pflags.Int("hatchery", 0, "Hatchery spawing worker")
 	viper.BindPFlag("hatchery", pflags.Lookup("hatchery"))
 
@@ -52,6 +55,9 @@ func cmdMain() *cobra.Command {
 	flags.Int("ttl", 30, "Worker time to live (minutes)")
 	viper.BindPFlag("ttl", flags.Lookup("ttl"))
 
+	flags.Int("stop-check-interval", 3, "Delay between two checks of a stop of the running job (seconds)")
+	viper.BindPFlag("stop_check_interval", flags.Lookup("stop-check-interval"))
+
 	flags.Int64("booked-job-id", 0, "Booked job id")
 	viper.BindPFlag("booked_job_id", flags.Lookup("booked-job-id"))
 
@@ -92,6 +98,10 @@ func mainCommandRun(cmd *cobra.Command, args []string) {
 
 	bookedJobID = viper.GetInt64("booked_job_id")
 
+	if i := viper.GetInt("stop_check_interval"); i > 0 {
+		stopCheckInterval = time.Duration(i) * time.Second
+	}
+
 	initServer()
 
 	// Gracefully shutdown connections
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var cmdOutput = &cobra.Command{
+	Use:   "output",
+	Short: "worker output <name> <value>",
+	Long:  "Set the value of an output declared by the job, the value must match the type of the output",
+	Run:   outputCmd,
+}
+
+func outputCmd(cmd *cobra.Command, args []string) {
+	portS := os.Getenv(WorkerServerPort)
+	if portS == "" {
+		sdk.Exit("%s not found, are you running inside a CDS worker job?\n", WorkerServerPort)
+	}
+
+	port, err := strconv.Atoi(portS)
+	if err != nil {
+		sdk.Exit("cannot parse '%s' as a port number", portS)
+	}
+
+	if len(args) != 2 {
+		sdk.Exit("Wrong usage: See '%s'\n", cmd.Short)
+	}
+
+	o := sdk.JobOutput{
+		Name:  args[0],
+		Value: args[1],
+	}
+
+	data, err := json.Marshal(o)
+	if err != nil {
+		sdk.Exit("internal error (%s)\n", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/output", port), bytes.NewReader(data))
+	if err != nil {
+		sdk.Exit("cannot set output: %s\n", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		sdk.Exit("cannot set output: %s\n", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		sdk.Exit("cannot set output: %s\n", msg)
+	}
+}
+
+func setOutputHandler(w http.ResponseWriter, r *http.Request) {
+	// Get body
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var o sdk.JobOutput
+	if err := json.Unmarshal(data, &o); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := setJobOutput(pbJob.Job.Outputs, o.Name, o.Value); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+}
+
+// setJobOutput checks the output is declared by the job and the value matches its type, then stores it
+func setJobOutput(declared []sdk.JobOutput, name, value string) error {
+	for _, d := range declared {
+		if d.Name != name {
+			continue
+		}
+		if err := d.CheckValue(value); err != nil {
+			return err
+		}
+
+		for i := range jobOutputs {
+			if jobOutputs[i].Name == name {
+				jobOutputs[i].Value = value
+				return nil
+			}
+		}
+		jobOutputs = append(jobOutputs, sdk.JobOutput{Name: name, Type: d.Type, Value: value})
+		return nil
+	}
+	return fmt.Errorf("output %s is not declared by the job", name)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_setJobOutput(t *testing.T) {
+	jobOutputs = nil
+	defer func() { jobOutputs = nil }()
+
+	declared := []sdk.JobOutput{{Name: "version", Type: sdk.OutputString}, {Name: "coverage", Type: sdk.OutputNumber}}
+
+	assert.NoError(t, setJobOutput(declared, "version", "1.0.0"))
+	assert.NoError(t, setJobOutput(declared, "version", "1.1.0"))
+	assert.NoError(t, setJobOutput(declared, "coverage", "42"))
+	assert.Error(t, setJobOutput(declared, "coverage", "unknown"))
+	assert.Error(t, setJobOutput(declared, "foo", "bar"))
+
+	assert.Equal(t, []sdk.JobOutput{
+		{Name: "version", Type: sdk.OutputString, Value: "1.1.0"},
+		{Name: "coverage", Type: sdk.OutputNumber, Value: "42"},
+	}, jobOutputs)
+}
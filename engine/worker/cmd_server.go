@@ -33,6 +33,7 @@ func server() (int, error) {
 	log.Info("Export variable HTTP server: %s\n", listener.Addr().String())
 	r := mux.NewRouter()
 	r.HandleFunc("/var", addBuildVarHandler)
+	r.HandleFunc("/output", setOutputHandler)
 	r.HandleFunc("/upload", uploadHandler)
 
 	srv := &http.Server{
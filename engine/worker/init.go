@@ -58,6 +58,7 @@ func initViper() {
 	status.Name = name
 
 	model = int64(viper.GetInt("model"))
+	modelVersion = int64(viper.GetInt("model_version"))
 	status.Model = model
 }
 
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const introspectionVersionTimeout = 3 * time.Second
+
+// introspect discovers the binaries installed among the binary requirements known by the api,
+// their versions, the memory, os and arch of the worker, and sends them to the api
+// to update the capabilities of the model of the worker
+func introspect(reqs []sdk.Requirement) error {
+	res := sdk.IntrospectionResult{
+		Versions: map[string]string{},
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+	}
+
+	var found int
+	checked := map[string]bool{}
+	for _, r := range reqs {
+		if r.Type != sdk.BinaryRequirement || checked[r.Value] {
+			continue
+		}
+		checked[r.Value] = true
+		res.Checked = append(res.Checked, r.Value)
+
+		path, err := exec.LookPath(r.Value)
+		if err != nil {
+			continue
+		}
+		found++
+		res.Capabilities = append(res.Capabilities, sdk.Requirement{Name: r.Value, Type: sdk.BinaryRequirement, Value: r.Value})
+		if v := binaryVersion(path); v != "" {
+			res.Versions[r.Value] = v
+		}
+	}
+
+	if totalMemory, err := systemTotalMemory(); err != nil {
+		log.Warning("introspect> unable to get memory: %s", err)
+	} else {
+		res.Memory = int64(totalMemory / 1024 / 1024)
+		res.Capabilities = append(res.Capabilities, sdk.Requirement{Name: "memory", Type: sdk.MemoryRequirement, Value: fmt.Sprintf("%d", res.Memory)})
+	}
+
+	res.Capabilities = append(res.Capabilities, sdk.PlatformCapabilities(res.OS, res.Arch)...)
+
+	log.Info("introspect> found %d binaries out of %d, %d MB of memory on %s/%s", found, len(res.Checked), res.Memory, res.OS, res.Arch)
+	return sdk.PostWorkerModelIntrospection(res)
+}
+
+// binaryVersion returns the first line printed by a binary called with --version, if any
+func binaryVersion(path string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), introspectionVersionTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if err != nil {
+		return ""
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(out))
+	if !s.Scan() {
+		return ""
+	}
+	v := s.Text()
+	if len(v) > 100 {
+		v = v[:100]
+	}
+	return v
+}
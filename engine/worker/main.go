@@ -14,20 +14,22 @@ var (
 	// WorkerID is a unique identifier for this worker
 	WorkerID string
 	// key is the token generated by the user owning the worker
-	key         string
-	name        string
-	api         string
-	model       int64
-	hatchery    int64
-	basedir     string
-	bookedJobID int64
-	logChan     chan sdk.Log
+	key          string
+	name         string
+	api          string
+	model        int64
+	modelVersion int64
+	hatchery     int64
+	basedir      string
+	bookedJobID  int64
+	logChan      chan sdk.Log
 	// port of variable exporter HTTP server
 	exportport int
 	// current actionBuild is here to allow var export
 	pbJob          sdk.PipelineBuildJob
 	currentStep    int
 	buildVariables []sdk.Variable
+	jobOutputs     []sdk.JobOutput
 	// Git ssh configuration
 	pkey          string
 	gitsshPath    string
@@ -48,6 +50,7 @@ func main() {
 
 	cmd := cmdMain()
 	cmd.AddCommand(cmdExport)
+	cmd.AddCommand(cmdOutput)
 	cmd.AddCommand(cmdUpload)
 	cmd.AddCommand(cmdVersion)
 	cmd.AddCommand(cmdRegister())
@@ -115,8 +115,9 @@ func takeJob(b sdk.PipelineBuildJob, isBooked bool) {
 	}
 
 	pbJob = pbji.PipelineBuildJob
-	// Reset build variables
+	// Reset build variables and outputs
 	buildVariables = nil
+	jobOutputs = nil
 	start := time.Now()
 	res := run(&pbji)
 	res.RemoteTime = time.Now()
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"runtime"
 	"time"
 
 	"github.com/ovh/cds/engine/api/worker"
@@ -32,9 +33,12 @@ func register(cdsURI string, name string, uk string) error {
 		Name:               name,
 		UserKey:            uk,
 		Model:              model,
+		ModelVersion:       modelVersion,
 		Hatchery:           hatchery,
 		BinaryCapabilities: binaryCapabilities,
 		Version:            VERSION,
+		OS:                 runtime.GOOS,
+		Arch:               runtime.GOARCH,
 	}
 
 	body, errM := json.Marshal(in)
@@ -70,6 +74,12 @@ func register(cdsURI string, name string, uk string) error {
 		log.Warning("-=-=-=-=- Please update your worker binary -=-=-=-=-")
 	}
 
+	if w.Introspect {
+		if err := introspect(requirements); err != nil {
+			log.Warning("register> unable to send introspection of model: %s", err)
+		}
+	}
+
 	return nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"runtime"
 	"strconv"
 	"time"
 
@@ -22,6 +23,8 @@ var requirementCheckFuncs = map[string]func(r sdk.Requirement) (bool, error){
 	sdk.PluginRequirement:        checkPluginRequirement,
 	sdk.ServiceRequirement:       checkServiceRequirement,
 	sdk.MemoryRequirement:        checkMemoryRequirement,
+	sdk.OSRequirement:            checkOSRequirement,
+	sdk.ArchRequirement:          checkArchRequirement,
 }
 
 func checkRequirement(r sdk.Requirement) (bool, error) {
@@ -82,17 +85,35 @@ func checkBinaryRequirement(r sdk.Requirement) (bool, error) {
 	return true, nil
 }
 
+func checkOSRequirement(r sdk.Requirement) (bool, error) {
+	return sdk.NormalizeOS(r.Value) == runtime.GOOS, nil
+}
+
+func checkArchRequirement(r sdk.Requirement) (bool, error) {
+	return sdk.NormalizeArch(r.Value) == runtime.GOARCH, nil
+}
+
 func checkModelRequirement(r sdk.Requirement) (bool, error) {
-	wm, err := sdk.GetWorkerModel(r.Value)
+	name, version := sdk.ParseModelRequirement(r.Value)
+	wm, err := sdk.GetWorkerModel(name)
 	if err != nil {
 		return false, nil
 	}
 
-	if wm.ID == model {
-		return true, nil
+	if wm.ID != model {
+		return false, nil
 	}
 
-	return false, nil
+	// Workers spawned without their version run the current version of the model
+	if version != 0 {
+		current := modelVersion
+		if current == 0 {
+			current = wm.Version
+		}
+		return current == version, nil
+	}
+
+	return true, nil
 }
 
 func checkNetworkAccessRequirement(r sdk.Requirement) (bool, error) {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"runtime"
 	"testing"
 
 	"github.com/ovh/cds/sdk"
@@ -95,3 +96,32 @@ func TestNetworkAccessRequirement(t *testing.T) {
 	}
 
 }
+
+func TestCheckPlatformRequirement(t *testing.T) {
+
+	r := sdk.Requirement{
+		Type:  sdk.OSRequirement,
+		Value: runtime.GOOS,
+	}
+
+	ok, err := checkRequirement(r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if !ok {
+		t.Fatalf("Requirement os %s should be ok", runtime.GOOS)
+	}
+
+	r = sdk.Requirement{
+		Type:  sdk.ArchRequirement,
+		Value: "foo",
+	}
+	ok, err = checkRequirement(r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if ok {
+		t.Fatalf("Requirement arch foo should not be ok")
+	}
+
+}
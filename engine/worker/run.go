@@ -156,7 +156,7 @@ func runJob(a *sdk.Action, pipBuildJob sdk.PipelineBuildJob, stepOrder int, step
 
 	rFinal, _ := runSteps(finalActions, a, pipBuildJob, stepOrder, stepName, len(noFinalActions))
 
-	if r.Status == sdk.StatusFail {
+	if r.Status == sdk.StatusFail || r.Status == sdk.StatusStopped {
 		return r
 	}
 	return rFinal
@@ -205,7 +205,29 @@ func runSteps(steps []sdk.Action, a *sdk.Action, pipBuildJob sdk.PipelineBuildJo
 			}
 			sendLog(pipBuildJob.ID, fmt.Sprintf("Starting step %s", childName), pipBuildJob.PipelineBuildID, currentStep, false)
 
-			r = startAction(&child, pipBuildJob, currentStep, childName)
+			parentDeadline := deadline
+			deadline = deadlineFor(parentDeadline, child.Timeout, time.Now())
+			if !deadlineExceeded(deadline) && !isJobStopped() {
+				r = startAction(&child, pipBuildJob, currentStep, childName)
+			}
+			if isJobStopped() {
+				r = sdk.Result{
+					Status:  sdk.StatusStopped,
+					BuildID: pipBuildJob.ID,
+					Reason:  fmt.Sprintf("Job has been stopped, step %s has been stopped\n", childName),
+				}
+				sendLog(pipBuildJob.ID, r.Reason, pipBuildJob.PipelineBuildID, currentStep, false)
+			} else if deadlineExceeded(deadline) {
+				r = sdk.Result{
+					Status:       sdk.StatusFail,
+					BuildID:      pipBuildJob.ID,
+					Reason:       fmt.Sprintf("Timeout reached, step %s has been stopped\n", childName),
+					FailureClass: sdk.FailureTimeout,
+				}
+				sendLog(pipBuildJob.ID, r.Reason, pipBuildJob.PipelineBuildID, currentStep, false)
+			}
+			deadline = parentDeadline
+
 			if r.Status != sdk.StatusSuccess {
 				log.Debug("Stopping %s at step %s", a.Name, childName)
 				doNotRunChildrenAnymore = true
@@ -375,7 +397,33 @@ func run(pbji *worker.PipelineBuildJobInfo) sdk.Result {
 	// add cds.worker on parameters available
 	pbji.PipelineBuildJob.Parameters = append(pbji.PipelineBuildJob.Parameters, sdk.Parameter{Name: "cds.worker", Value: pbji.PipelineBuildJob.Job.WorkerName, Type: sdk.StringParameter})
 
+	// Check the job status while it runs, to stop it if it is stopped from the API
+	jobStopped = make(chan struct{})
+	watchDone := make(chan bool)
+	go watchJobStatus(pbji.PipelineBuildJob.ID, jobStopped, watchDone)
+
+	deadline = deadlineFor(time.Time{}, pbji.PipelineBuildJob.Job.Timeout, time.Now())
 	res := startAction(&pbji.PipelineBuildJob.Job.Action, pbji.PipelineBuildJob, -1, "")
+	deadline = time.Time{}
+
+	close(watchDone)
+	if isJobStopped() {
+		res.Status = sdk.StatusStopped
+		res.Reason = "Job has been stopped\n"
+	}
+	jobStopped = nil
+
+	// A successful job must set all the outputs it declares
+	if res.Status == sdk.StatusSuccess && len(pbji.PipelineBuildJob.Job.Outputs) > 0 {
+		outputs, err := sdk.ResolveJobOutputs(pbji.PipelineBuildJob.Job.Outputs, jobOutputs)
+		if err != nil {
+			res.Status = sdk.StatusFail
+			res.Reason = fmt.Sprintf("Error: %s\n", err)
+			res.FailureClass = sdk.FailureMissingOutputs
+			sendLog(pbji.PipelineBuildJob.ID, res.Reason, pbji.PipelineBuildJob.PipelineBuildID, currentStep, false)
+		}
+		res.Outputs = outputs
+	}
 	close(doneChan)
 	logsecrets = nil
 
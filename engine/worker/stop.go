@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os/exec"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+var (
+	// stopCheckInterval is the delay between two checks of the running job status, set by --stop-check-interval
+	stopCheckInterval = 3 * time.Second
+	// stopGracePeriod is the delay given to a step to exit after SIGTERM, before it is killed
+	stopGracePeriod = 10 * time.Second
+	// jobStopped is closed when the running job has been stopped from the API
+	jobStopped chan struct{}
+)
+
+// watchJobStatus checks the status of the running job until done is closed, and closes stopped if the job has been stopped
+func watchJobStatus(pbJobID int64, stopped chan struct{}, done chan bool) {
+	ticker := time.NewTicker(stopCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			status, err := sdk.GetPipelineBuildJobStatus(pbJobID)
+			if err != nil {
+				log.Warning("watchJobStatus> Cannot get status of job %d: %s", pbJobID, err)
+				continue
+			}
+			if status == sdk.StatusStopped {
+				log.Info("watchJobStatus> Job %d has been stopped", pbJobID)
+				close(stopped)
+				return
+			}
+		}
+	}
+}
+
+// isJobStopped returns true if the running job has been stopped
+func isJobStopped() bool {
+	select {
+	case <-jobStopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// terminateOnStop sends SIGTERM to the process group of the command when the running job is stopped,
+// then kills it if it is still running after stopGracePeriod. The returned func must be called when the command exited
+func terminateOnStop(cmd *exec.Cmd) func() {
+	done := make(chan struct{})
+	stopped := jobStopped
+
+	go func() {
+		select {
+		case <-done:
+			return
+		case <-stopped:
+		}
+
+		if err := terminateProcessGroup(cmd); err != nil {
+			log.Warning("terminateOnStop> Cannot terminate process: %s", err)
+		}
+
+		select {
+		case <-done:
+		case <-time.After(stopGracePeriod):
+			if err := killProcessGroup(cmd); err != nil {
+				log.Warning("terminateOnStop> Cannot kill process: %s", err)
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
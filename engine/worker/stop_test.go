@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_terminateOnStop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.SkipNow()
+	}
+
+	jobStopped = make(chan struct{})
+	defer func() { jobStopped = nil }()
+
+	cmd := exec.Command("/bin/sh", "-c", "sleep 30 & sleep 30")
+	setProcessGroup(cmd)
+	assert.NoError(t, cmd.Start())
+	done := terminateOnStop(cmd)
+	defer done()
+
+	assert.False(t, isJobStopped())
+	start := time.Now()
+	close(jobStopped)
+	assert.True(t, isJobStopped())
+	assert.Error(t, cmd.Wait())
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
+
+func Test_terminateOnStopKillsAfterGracePeriod(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.SkipNow()
+	}
+
+	jobStopped = make(chan struct{})
+	defer func() { jobStopped = nil }()
+	gracePeriod := stopGracePeriod
+	stopGracePeriod = 500 * time.Millisecond
+	defer func() { stopGracePeriod = gracePeriod }()
+
+	// The script ignores SIGTERM, it must be killed at the end of the grace period
+	cmd := exec.Command("/bin/sh", "-c", "trap '' TERM; sleep 30")
+	setProcessGroup(cmd)
+	assert.NoError(t, cmd.Start())
+	done := terminateOnStop(cmd)
+	defer done()
+
+	time.Sleep(100 * time.Millisecond)
+	start := time.Now()
+	close(jobStopped)
+	assert.Error(t, cmd.Wait())
+	assert.True(t, time.Since(start) >= stopGracePeriod)
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"time"
+)
+
+// deadline is the time at which the running step must be stopped, zero if it is not bounded.
+// It is the earliest of the job deadline and the timeouts of the running step and of its parents
+var deadline time.Time
+
+// deadlineFor returns the deadline of a step with the given timeout in seconds, started now under the parent deadline
+func deadlineFor(parent time.Time, timeout int64, now time.Time) time.Time {
+	if timeout <= 0 {
+		return parent
+	}
+	d := now.Add(time.Duration(timeout) * time.Second)
+	if !parent.IsZero() && parent.Before(d) {
+		return parent
+	}
+	return d
+}
+
+// deadlineExceeded returns true if the deadline is set and reached
+func deadlineExceeded(d time.Time) bool {
+	return !d.IsZero() && !time.Now().Before(d)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_deadlineFor(t *testing.T) {
+	now := time.Now()
+
+	assert.True(t, deadlineFor(time.Time{}, 0, now).IsZero())
+	assert.Equal(t, now.Add(time.Minute), deadlineFor(time.Time{}, 60, now))
+
+	parent := now.Add(30 * time.Second)
+	assert.Equal(t, parent, deadlineFor(parent, 0, now))
+	assert.Equal(t, parent, deadlineFor(parent, 60, now))
+	assert.Equal(t, now.Add(10*time.Second), deadlineFor(parent, 10, now))
+}
+
+func Test_killProcessGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.SkipNow()
+	}
+
+	cmd := exec.Command("/bin/sh", "-c", "sleep 30 & sleep 30")
+	setProcessGroup(cmd)
+	assert.NoError(t, cmd.Start())
+
+	start := time.Now()
+	time.AfterFunc(100*time.Millisecond, func() { killProcessGroup(cmd) })
+	assert.Error(t, cmd.Wait())
+	assert.True(t, time.Since(start) < 10*time.Second)
+}
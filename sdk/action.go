@@ -18,6 +18,7 @@ type Action struct {
 	Actions      []Action      `json:"actions" yaml:"actions,omitempty"`
 	Enabled      bool          `json:"enabled" yaml:"-"`
 	Final        bool          `json:"final" yaml:"-"`
+	Timeout      int64         `json:"timeout,omitempty" yaml:"-"` // Timeout of the step in seconds, 0 means no timeout
 	LastModified int64         `json:"last_modified"`
 }
 
@@ -65,10 +66,12 @@ const (
 
 // Builtin Action
 const (
-	ScriptAction   = "Script"
-	NotifAction    = "Notif"
-	JUnitAction    = "JUnit"
-	GitCloneAction = "GitClone"
+	ScriptAction       = "Script"
+	NotifAction        = "Notif"
+	JUnitAction        = "JUnit"
+	GitCloneAction     = "GitClone"
+	CacheSaveAction    = "CacheSave"
+	CacheRestoreAction = "CacheRestore"
 )
 
 const (
@@ -86,6 +89,10 @@ const (
 	ServiceRequirement = "service"
 	//MemoryRequirement set memory limit on a container
 	MemoryRequirement = "memory"
+	//OSRequirement checks the operating system of the worker
+	OSRequirement = "os"
+	//ArchRequirement checks the CPU architecture of the worker
+	ArchRequirement = "arch"
 )
 
 var (
@@ -98,6 +105,8 @@ var (
 		PluginRequirement,
 		ServiceRequirement,
 		MemoryRequirement,
+		OSRequirement,
+		ArchRequirement,
 	}
 )
 
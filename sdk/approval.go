@@ -0,0 +1,122 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// StageApproval is a manual gate on a stage: the build waits until enough members of the approver groups approve it
+type StageApproval struct {
+	Groups    []string           `json:"groups"`
+	Required  int                `json:"required,omitempty"`
+	Timeout   int64              `json:"timeout,omitempty"` // in seconds, 0 means no timeout
+	Start     time.Time          `json:"start,omitempty"`
+	Decisions []ApprovalDecision `json:"decisions,omitempty"`
+}
+
+// ApprovalDecision is the approval or rejection of a stage by a user
+type ApprovalDecision struct {
+	User     string    `json:"user"`
+	Approved bool      `json:"approved"`
+	Comment  string    `json:"comment,omitempty"`
+	Date     time.Time `json:"date"`
+}
+
+// RequiredApprovals returns the number of approvals needed to open the gate
+func (a *StageApproval) RequiredApprovals() int {
+	if a.Required < 1 {
+		return 1
+	}
+	return a.Required
+}
+
+// Approved returns true if enough users approved the stage
+func (a *StageApproval) Approved() bool {
+	n := 0
+	for _, d := range a.Decisions {
+		if d.Approved {
+			n++
+		}
+	}
+	return n >= a.RequiredApprovals()
+}
+
+// Rejected returns true if one user rejected the stage
+func (a *StageApproval) Rejected() bool {
+	for _, d := range a.Decisions {
+		if !d.Approved {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired returns true if the approval timeout is reached
+func (a *StageApproval) Expired(now time.Time) bool {
+	if a.Timeout <= 0 || a.Start.IsZero() {
+		return false
+	}
+	return now.Sub(a.Start) > time.Duration(a.Timeout)*time.Second
+}
+
+// CanApprove returns true if the user is a member of one of the approver groups
+func (a *StageApproval) CanApprove(u *User) bool {
+	if u == nil {
+		return false
+	}
+	if u.Admin {
+		return true
+	}
+	for _, g := range u.Groups {
+		for _, name := range a.Groups {
+			if g.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasDecided returns true if the user already approved or rejected the stage
+func (a *StageApproval) HasDecided(username string) bool {
+	for _, d := range a.Decisions {
+		if d.User == username {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset removes all decisions, used when a stage is restarted
+func (a *StageApproval) Reset() {
+	a.Start = time.Time{}
+	a.Decisions = nil
+}
+
+// DecideStageApproval approves or rejects a stage waiting for approval
+func DecideStageApproval(key, app, pip, env string, bn int, stageID int64, approved bool, comment string) error {
+	d := ApprovalDecision{
+		Approved: approved,
+		Comment:  comment,
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/build/%d/stage/%d/approval?envName=%s", key, app, pip, bn, stageID, url.QueryEscape(env))
+	data, code, err := Request("POST", uri, data)
+	if err != nil {
+		return err
+	}
+	if e := DecodeError(data); e != nil {
+		return e
+	}
+	if code >= http.StatusMultipleChoices {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStageApprovalDecisions(t *testing.T) {
+	a := &StageApproval{Groups: []string{"release"}, Required: 2}
+
+	if a.Approved() || a.Rejected() {
+		t.Fatalf("approval without decision must be neither approved nor rejected")
+	}
+
+	a.Decisions = append(a.Decisions, ApprovalDecision{User: "alice", Approved: true})
+	if a.Approved() {
+		t.Errorf("approval must wait for %d approvals", a.RequiredApprovals())
+	}
+	if !a.HasDecided("alice") || a.HasDecided("bob") {
+		t.Errorf("HasDecided does not match decisions")
+	}
+
+	a.Decisions = append(a.Decisions, ApprovalDecision{User: "bob", Approved: true})
+	if !a.Approved() {
+		t.Errorf("approval must be approved after %d approvals", a.RequiredApprovals())
+	}
+
+	a.Decisions = append(a.Decisions, ApprovalDecision{User: "carol", Approved: false})
+	if !a.Rejected() {
+		t.Errorf("approval must be rejected after one rejection")
+	}
+
+	a.Reset()
+	if len(a.Decisions) != 0 || !a.Start.IsZero() {
+		t.Errorf("Reset must remove decisions")
+	}
+}
+
+func TestStageApprovalCanApprove(t *testing.T) {
+	a := &StageApproval{Groups: []string{"release"}}
+
+	tests := []struct {
+		user *User
+		want bool
+	}{
+		{nil, false},
+		{&User{Username: "alice", Groups: []Group{{Name: "release"}}}, true},
+		{&User{Username: "bob", Groups: []Group{{Name: "dev"}}}, false},
+		{&User{Username: "root", Admin: true}, true},
+	}
+	for _, tt := range tests {
+		if got := a.CanApprove(tt.user); got != tt.want {
+			t.Errorf("CanApprove(%+v) = %v, want %v", tt.user, got, tt.want)
+		}
+	}
+}
+
+func TestStageApprovalExpired(t *testing.T) {
+	now := time.Now()
+	a := &StageApproval{Groups: []string{"release"}, Timeout: 60, Start: now.Add(-2 * time.Minute)}
+	if !a.Expired(now) {
+		t.Errorf("approval started 2 minutes ago with a 60s timeout must be expired")
+	}
+
+	a.Start = now.Add(-30 * time.Second)
+	if a.Expired(now) {
+		t.Errorf("approval started 30s ago with a 60s timeout must not be expired")
+	}
+
+	a.Timeout = 0
+	a.Start = now.Add(-24 * time.Hour)
+	if a.Expired(now) {
+		t.Errorf("approval without timeout must never expire")
+	}
+}
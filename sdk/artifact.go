@@ -33,7 +33,9 @@ type Artifact struct {
 	Size         int64  `json:"size,omitempty"`
 	Perm         uint32 `json:"perm,omitempty"`
 	MD5sum       string `json:"md5sum,omitempty"`
+	SHA256sum    string `json:"sha256sum,omitempty"`
 	ObjectPath   string `json:"object_path,omitempty"`
+	Pinned       bool   `json:"pinned,omitempty"`
 }
 
 //GetName returns the name the artifact
@@ -80,41 +82,73 @@ func DownloadArtifacts(project string, application string, pipeline string, tag
 }
 
 func download(project, app, pip string, a Artifact, destdir string) error {
+	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/artifact/download/%d", project, app, pip, a.ID)
+	return downloadURI(uri, a, destdir)
+}
+
+// downloadURI downloads an artifact from uri into destdir, resuming the download after a failure
+func downloadURI(uri string, a Artifact, destdir string) error {
 	var lasterr error
+	destPath := path.Join(destdir, a.Name)
 
+	mode := os.FileMode(0644)
+	if a.Perm != uint32(0) {
+		mode = os.FileMode(a.Perm)
+	}
+
+	// offset is the number of bytes already downloaded, a failed download is resumed from there
+	var offset int64
 	for retry := 5; retry >= 0; retry-- {
-		uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/artifact/download/%d", project, app, pip, a.ID)
-		reader, code, err := Stream("GET", uri, nil)
+		var mods []RequestModifier
+		if offset > 0 {
+			mods = append(mods, SetHeader("Range", fmt.Sprintf("bytes=%d-", offset)))
+		}
+		reader, code, err := Stream("GET", uri, nil, mods...)
 		if err != nil {
 			lasterr = err
 			continue
 		}
+		if code == http.StatusRequestedRangeNotSatisfiable {
+			reader.Close()
+			lasterr = fmt.Errorf("HTTP %d", code)
+			offset = 0
+			continue
+		}
 		if code >= 300 {
+			reader.Close()
 			lasterr = fmt.Errorf("HTTP %d", code)
 			continue
 		}
-		destPath := path.Join(destdir, a.Name)
 
-		mode := os.FileMode(0644)
-		if a.Perm != uint32(0) {
-			mode = os.FileMode(a.Perm)
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if code == http.StatusPartialContent {
+			flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		} else {
+			offset = 0
 		}
 
-		f, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY, mode)
+		f, err := os.OpenFile(destPath, flags, mode)
 		if err != nil {
+			reader.Close()
 			lasterr = err
 			continue
 		}
 
-		_, err = io.Copy(f, reader)
+		n, err := io.Copy(f, reader)
+		offset += n
+		reader.Close()
+		f.Close()
 		if err != nil {
 			lasterr = err
+			continue
 		}
 
-		f.Close()
-		if err == nil {
-			return nil
+		if err := checkArtifactChecksums(destPath, a); err != nil {
+			lasterr = err
+			offset = 0
+			continue
 		}
+		return nil
 	}
 
 	return fmt.Errorf("x5: %s", lasterr)
@@ -172,7 +206,12 @@ func UploadArtifact(project string, pipeline string, application string, tag str
 	tag = url.QueryEscape(tag)
 	tag = strings.Replace(tag, "/", "-", -1)
 
-	var err error
+	// Upload by chunks, resumable from the last chunk received by the API
+	err := uploadArtifactByChunks(project, pipeline, application, tag, filePath, buildNumber, env)
+	if err != errChunkedUploadUnsupported {
+		return err
+	}
+
 	for i := 0; i < 5; i++ {
 		err = uploadArtifact(project, pipeline, application, tag, filePath, buildNumber, env)
 		if err == nil {
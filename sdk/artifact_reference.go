@@ -0,0 +1,172 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ArtifactChannelPattern is the pattern of the names of the channels where artifacts are promoted
+const ArtifactChannelPattern = "^[a-zA-Z0-9._-]+$"
+
+var artifactChannelRegexp = regexp.MustCompile(ArtifactChannelPattern)
+
+// ArtifactRef references the artifacts of a build of any application and pipeline:
+// project/application/pipeline/environment/build/name where build is a build number, a tag
+// or @channel for the build promoted in a channel. The name is optional, all artifacts are referenced without it
+type ArtifactRef struct {
+	Project     string `json:"project"`
+	Application string `json:"application"`
+	Pipeline    string `json:"pipeline"`
+	Environment string `json:"environment"`
+	BuildNumber int64  `json:"build_number,omitempty"`
+	Tag         string `json:"tag,omitempty"`
+	Channel     string `json:"channel,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// ParseArtifactRef parses a reference project/application/pipeline/environment/build[/name]
+func ParseArtifactRef(s string) (*ArtifactRef, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) < 5 || len(parts) > 6 {
+		return nil, ErrInvalidArtifactReference
+	}
+	for _, p := range parts[:5] {
+		if p == "" {
+			return nil, ErrInvalidArtifactReference
+		}
+	}
+
+	ref := &ArtifactRef{
+		Project:     parts[0],
+		Application: parts[1],
+		Pipeline:    parts[2],
+		Environment: parts[3],
+	}
+	if len(parts) == 6 {
+		ref.Name = parts[5]
+	}
+
+	build := parts[4]
+	switch {
+	case strings.HasPrefix(build, "@"):
+		ref.Channel = strings.TrimPrefix(build, "@")
+		if !artifactChannelRegexp.MatchString(ref.Channel) {
+			return nil, ErrInvalidArtifactReference
+		}
+	default:
+		if n, err := strconv.ParseInt(build, 10, 64); err == nil {
+			if n <= 0 {
+				return nil, ErrInvalidArtifactReference
+			}
+			ref.BuildNumber = n
+		} else {
+			ref.Tag = build
+		}
+	}
+	return ref, nil
+}
+
+// String returns the reference as parsed by ParseArtifactRef
+func (r ArtifactRef) String() string {
+	build := r.Tag
+	if r.Channel != "" {
+		build = "@" + r.Channel
+	} else if r.BuildNumber > 0 {
+		build = strconv.FormatInt(r.BuildNumber, 10)
+	}
+	s := strings.Join([]string{r.Project, r.Application, r.Pipeline, r.Environment, build}, "/")
+	if r.Name != "" {
+		s += "/" + r.Name
+	}
+	return s
+}
+
+// ArtifactPromotion is a build whose artifacts are promoted in a channel such as staging or prod
+type ArtifactPromotion struct {
+	Channel     string    `json:"channel"`
+	Application string    `json:"application"`
+	Pipeline    string    `json:"pipeline"`
+	Environment string    `json:"environment"`
+	BuildNumber int64     `json:"build_number"`
+	Promoted    time.Time `json:"promoted"`
+	PromotedBy  string    `json:"promoted_by"`
+}
+
+// IsValidArtifactChannel returns an error if the name of a channel is invalid
+func IsValidArtifactChannel(channel string) error {
+	if !artifactChannelRegexp.MatchString(channel) {
+		return ErrInvalidArtifactChannel
+	}
+	return nil
+}
+
+// ListArtifactsByRef returns the artifacts of a reference
+func ListArtifactsByRef(ref string) ([]Artifact, error) {
+	data, code, err := Request("GET", "/artifact/reference?ref="+url.QueryEscape(ref), nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("cds: cannot list artifacts of %s: %d", ref, code)
+	}
+
+	var arts []Artifact
+	if err := json.Unmarshal(data, &arts); err != nil {
+		return nil, err
+	}
+	return arts, nil
+}
+
+// DownloadArtifactsByRef downloads the artifacts of a reference into destdir
+func DownloadArtifactsByRef(ref string, destdir string) error {
+	arts, err := ListArtifactsByRef(ref)
+	if err != nil {
+		return err
+	}
+	if len(arts) == 0 {
+		return fmt.Errorf("cds: no artifact found for %s", ref)
+	}
+
+	for _, a := range arts {
+		if err := downloadURI("/artifact/"+a.DownloadHash, a, destdir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PromoteArtifacts promotes the artifacts of a build in a channel, replacing the build previously promoted in this channel
+func PromoteArtifacts(project, application, pipeline, env string, buildNumber int64, channel string) error {
+	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/build/%d/artifact/promote/%s?envName=%s", project, application, pipeline, buildNumber, channel, url.QueryEscape(env))
+	_, code, err := Request("POST", uri, nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("cds: cannot promote build %d in %s: %d", buildNumber, channel, code)
+	}
+	return nil
+}
+
+// ListArtifactPromotions returns the channels where builds of an application pipeline are promoted
+func ListArtifactPromotions(project, application, pipeline string) ([]ArtifactPromotion, error) {
+	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/promotion", project, application, pipeline)
+	data, code, err := Request("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("cds: cannot list promotions of %s-%s-%s: %d", project, application, pipeline, code)
+	}
+
+	var promotions []ArtifactPromotion
+	if err := json.Unmarshal(data, &promotions); err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
@@ -0,0 +1,41 @@
+package sdk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseArtifactRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want *ArtifactRef
+	}{
+		{"PRJ/app/build/NoEnv/42", &ArtifactRef{Project: "PRJ", Application: "app", Pipeline: "build", Environment: "NoEnv", BuildNumber: 42}},
+		{"PRJ/app/build/NoEnv/1.2.0/app.tar.gz", &ArtifactRef{Project: "PRJ", Application: "app", Pipeline: "build", Environment: "NoEnv", Tag: "1.2.0", Name: "app.tar.gz"}},
+		{"PRJ/app/build/NoEnv/@prod", &ArtifactRef{Project: "PRJ", Application: "app", Pipeline: "build", Environment: "NoEnv", Channel: "prod"}},
+		{"PRJ/app/build/NoEnv", nil},
+		{"PRJ/app/build/NoEnv/42/a/b", nil},
+		{"PRJ//build/NoEnv/42", nil},
+		{"PRJ/app/build/NoEnv/0", nil},
+		{"PRJ/app/build/NoEnv/@pr od", nil},
+	}
+	for _, tt := range tests {
+		got, err := ParseArtifactRef(tt.ref)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("ParseArtifactRef(%s) should fail, got %+v", tt.ref, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseArtifactRef(%s) failed: %s", tt.ref, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseArtifactRef(%s) = %+v, want %+v", tt.ref, got, tt.want)
+		}
+		if got.String() != tt.ref {
+			t.Errorf("String() = %s, want %s", got.String(), tt.ref)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package sdk
+
+import (
+	"time"
+)
+
+// ArtifactRetention is a retention policy of the artifacts of a project, an application or a pipeline.
+// The most specific policy applies to a build: pipeline, then application, then project.
+// Artifacts without policy are kept forever, as well as pinned builds
+type ArtifactRetention struct {
+	ID            int64 `json:"id" db:"id"`
+	ProjectID     int64 `json:"project_id" db:"project_id"`
+	ApplicationID int64 `json:"application_id,omitempty" db:"application_id"`
+	PipelineID    int64 `json:"pipeline_id,omitempty" db:"pipeline_id"`
+	KeepLast      int   `json:"keep_last" db:"keep_last"` // number of builds, 0 means no limit
+	KeepDays      int   `json:"keep_days" db:"keep_days"` // 0 means no limit
+	KeepDeployed  bool  `json:"keep_deployed" db:"keep_deployed"`
+}
+
+// IsValid checks the retention policy, at least one of keep last or keep days must be set
+func (r *ArtifactRetention) IsValid() error {
+	if r.KeepLast < 0 || r.KeepDays < 0 || (r.KeepLast == 0 && r.KeepDays == 0) {
+		return ErrInvalidArtifactRetention
+	}
+	return nil
+}
+
+// Keeps returns true if the policy keeps a build, given its rank from the most recent build of its
+// application, pipeline and environment and the date of its artifacts
+func (r *ArtifactRetention) Keeps(rank int, created, now time.Time) bool {
+	if r.KeepLast > 0 && rank < r.KeepLast {
+		return true
+	}
+	if r.KeepDays > 0 && created.After(now.AddDate(0, 0, -r.KeepDays)) {
+		return true
+	}
+	return false
+}
+
+// ArtifactGCReport lists the builds whose artifacts have been, or would be with a dry run, deleted by the retention policies
+type ArtifactGCReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Builds    []ArtifactGCBuild `json:"builds"`
+	Artifacts int               `json:"artifacts"`
+	Size      int64             `json:"size"`
+}
+
+// ArtifactGCBuild is a build whose artifacts are expired
+type ArtifactGCBuild struct {
+	Project     string    `json:"project"`
+	Application string    `json:"application"`
+	Pipeline    string    `json:"pipeline"`
+	Environment string    `json:"environment"`
+	BuildNumber int64     `json:"build_number"`
+	Created     time.Time `json:"created"`
+	Artifacts   int       `json:"artifacts"`
+	Size        int64     `json:"size"`
+}
@@ -0,0 +1,49 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArtifactRetentionIsValid(t *testing.T) {
+	tests := []struct {
+		retention ArtifactRetention
+		wantErr   bool
+	}{
+		{ArtifactRetention{KeepLast: 10}, false},
+		{ArtifactRetention{KeepDays: 30, KeepDeployed: true}, false},
+		{ArtifactRetention{KeepDeployed: true}, true},
+		{ArtifactRetention{KeepLast: -1, KeepDays: 30}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.retention.IsValid(); (err != nil) != tt.wantErr {
+			t.Errorf("IsValid(%+v) = %v, want error: %v", tt.retention, err, tt.wantErr)
+		}
+	}
+}
+
+func TestArtifactRetentionKeeps(t *testing.T) {
+	now := time.Date(2017, 6, 15, 12, 0, 0, 0, time.UTC)
+	r := ArtifactRetention{KeepLast: 2, KeepDays: 7}
+
+	tests := []struct {
+		rank    int
+		created time.Time
+		want    bool
+	}{
+		{0, now.AddDate(0, -1, 0), true},
+		{1, now.AddDate(0, -1, 0), true},
+		{2, now.AddDate(0, 0, -1), true},
+		{2, now.AddDate(0, 0, -8), false},
+	}
+	for _, tt := range tests {
+		if got := r.Keeps(tt.rank, tt.created, now); got != tt.want {
+			t.Errorf("Keeps(%d, %s) = %v, want %v", tt.rank, tt.created, got, tt.want)
+		}
+	}
+
+	lastOnly := ArtifactRetention{KeepLast: 1}
+	if lastOnly.Keeps(1, now, now) {
+		t.Errorf("a policy without keep days must not keep recent builds beyond the last ones")
+	}
+}
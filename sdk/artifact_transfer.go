@@ -0,0 +1,178 @@
+package sdk
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ArtifactChunkSize is the size of the chunks of a resumable artifact upload
+const ArtifactChunkSize = 16 * 1024 * 1024
+
+// ArtifactChunkedUpload is a resumable upload of an artifact, sent by chunks.
+// Offset is the number of bytes received by the API, the next chunk must start at this offset
+type ArtifactChunkedUpload struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Environment string `json:"environment"`
+	Size        int64  `json:"size"`
+	Perm        uint32 `json:"perm"`
+	MD5sum      string `json:"md5sum"`
+	SHA256sum   string `json:"sha256sum,omitempty"`
+	Offset      int64  `json:"offset"`
+	Chunks      int    `json:"chunks"`
+}
+
+// ArtifactChunk is a chunk of a resumable upload, stored until the upload is complete
+type ArtifactChunk struct {
+	UploadID string
+	Index    int
+}
+
+//GetName returns the name of the chunk
+func (c *ArtifactChunk) GetName() string {
+	return fmt.Sprintf("%06d", c.Index)
+}
+
+//GetPath returns the path of the chunks of the upload
+func (c *ArtifactChunk) GetPath() string {
+	return fmt.Sprintf("artifact-upload-%s", c.UploadID)
+}
+
+// ArtifactURL is a temporary URL to download an artifact without authentication
+type ArtifactURL struct {
+	URL     string    `json:"url"`
+	Expires time.Time `json:"expires"`
+}
+
+// fileChecksums returns the md5 and sha256 hexadecimal sums of a file
+func fileChecksums(filePath string) (string, string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	md5Hash := md5.New()
+	sha256Hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(md5Hash, sha256Hash), f); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(md5Hash.Sum(nil)), hex.EncodeToString(sha256Hash.Sum(nil)), nil
+}
+
+// checkArtifactChecksums checks a downloaded artifact against its sums
+func checkArtifactChecksums(filePath string, a Artifact) error {
+	if a.MD5sum == "" && a.SHA256sum == "" {
+		return nil
+	}
+	md5sum, sha256sum, err := fileChecksums(filePath)
+	if err != nil {
+		return err
+	}
+	if a.MD5sum != "" && a.MD5sum != md5sum {
+		return fmt.Errorf("md5sum of %s is %s, expected %s", a.Name, md5sum, a.MD5sum)
+	}
+	if a.SHA256sum != "" && a.SHA256sum != sha256sum {
+		return fmt.Errorf("sha256sum of %s is %s, expected %s", a.Name, sha256sum, a.SHA256sum)
+	}
+	return nil
+}
+
+// errChunkedUploadUnsupported is returned by an API without resumable uploads
+var errChunkedUploadUnsupported = fmt.Errorf("chunked upload not supported")
+
+// uploadArtifactByChunks uploads an artifact by chunks, resuming from the offset received by the API after a failure.
+// The API verifies the checksums of the artifact once all chunks are received
+func uploadArtifactByChunks(project, pipeline, application, tag, filePath string, buildNumber int, env string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	md5sum, sha256sum, err := fileChecksums(filePath)
+	if err != nil {
+		return err
+	}
+
+	upload := &ArtifactChunkedUpload{
+		Name:        stat.Name(),
+		Environment: env,
+		Size:        stat.Size(),
+		Perm:        uint32(stat.Mode().Perm()),
+		MD5sum:      md5sum,
+		SHA256sum:   sha256sum,
+	}
+	body, _ := json.Marshal(upload)
+	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/%d/artifact/%s/upload", project, application, pipeline, buildNumber, tag)
+	data, code, err := Request("POST", uri, body)
+	if err != nil {
+		return err
+	}
+	if code == http.StatusNotFound {
+		return errChunkedUploadUnsupported
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP Error %d", code)
+	}
+	if err := json.Unmarshal(data, upload); err != nil {
+		return err
+	}
+
+	uploadURI := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/artifact/upload/%s", project, application, pipeline, upload.ID)
+	buf := make([]byte, ArtifactChunkSize)
+	var lasterr error
+	for retry := 0; upload.Offset < upload.Size; {
+		if retry == 5 {
+			return fmt.Errorf("x5: %s", lasterr)
+		}
+
+		n, err := f.ReadAt(buf, upload.Offset)
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		data, code, err := Request("POST", fmt.Sprintf("%s?offset=%d", uploadURI, upload.Offset), buf[:n])
+		if err == nil && code < 300 {
+			if err := json.Unmarshal(data, upload); err != nil {
+				return err
+			}
+			continue
+		}
+		lasterr = err
+		if err == nil {
+			lasterr = fmt.Errorf("HTTP Error %d", code)
+		}
+		retry++
+		time.Sleep(1 * time.Second)
+
+		// Resume from the offset received by the API
+		data, code, err = Request("GET", uploadURI, nil)
+		if err == nil && code < 300 {
+			if err := json.Unmarshal(data, upload); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, code, err = Request("POST", uploadURI+"/complete", nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP Error %d", code)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ type PipelineBuildJob struct {
 	PipelineBuildID int64       `json:"pipeline_build_id,omitempty" db:"pipeline_build_id"`
 	BookedBy        Hatchery    `json:"bookedby" db:"-"`
 	SpawnInfos      []SpawnInfo `json:"spawninfos" db:"-"`
+	Outputs         []JobOutput `json:"outputs,omitempty" db:"-"`
 }
 
 // SpawnInfo contains an information about spawning
@@ -43,6 +44,10 @@ type ExecutedJob struct {
 	StepStatus []StepStatus `json:"step_status" db:"-"`
 	Reason     string       `json:"reason" db:"-"`
 	WorkerName string       `json:"worker_name" db:"-"`
+	Matrix     *MatrixCell  `json:"matrix,omitempty" db:"-"`
+	// Retries is the number of failed attempts already retried, FailureClass the class of the failure of the job
+	Retries      int    `json:"retries,omitempty" db:"-"`
+	FailureClass string `json:"failure_class,omitempty" db:"-"`
 }
 
 // StepStatus Represent a step and his status
@@ -57,6 +62,8 @@ type BuildState struct {
 	Logs     []Log   `json:"logs"`
 	StepLogs Log     `json:"step_logs"`
 	Status   Status  `json:"status"`
+	// Attempts are the previous failed attempts of the job, when it has been retried
+	Attempts []JobAttempt `json:"attempts,omitempty"`
 }
 
 // Status reprensents a Build Action or Build Pipeline Status
@@ -81,6 +88,12 @@ func StatusFromString(in string) Status {
 		return StatusDisabled
 	case StatusSkipped.String():
 		return StatusSkipped
+	case StatusWaitingApproval.String():
+		return StatusWaitingApproval
+	case StatusStopped.String():
+		return StatusStopped
+	case StatusPending.String():
+		return StatusPending
 	default:
 		return StatusUnknown
 	}
@@ -101,6 +114,11 @@ const (
 	StatusNeverBuilt Status = "Never Built"
 	StatusUnknown    Status = "Unknown"
 	StatusSkipped    Status = "Skipped"
+
+	StatusWaitingApproval Status = "Waiting approval"
+	StatusStopped         Status = "Stopped"
+	// StatusPending is the status of a pipeline build held by its concurrency group
+	StatusPending Status = "Pending"
 )
 
 // GetBuildQueue retrieves current CDS build in queue
@@ -124,6 +142,25 @@ func GetBuildQueue() ([]PipelineBuildJob, error) {
 	return q, nil
 }
 
+// GetPipelineBuildJobStatus returns the current status of a job from the queue
+func GetPipelineBuildJobStatus(id int64) (Status, error) {
+	var pbJob PipelineBuildJob
+
+	path := fmt.Sprintf("/queue/%d/status", id)
+	data, code, err := Request("GET", path, nil)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	if code >= 300 {
+		return StatusUnknown, fmt.Errorf("HTTP %d", code)
+	}
+
+	if err := json.Unmarshal(data, &pbJob); err != nil {
+		return StatusUnknown, err
+	}
+	return StatusFromString(pbJob.Status), nil
+}
+
 // GetBuildState Get the state of given build
 func GetBuildState(projectKey, appName, pipelineName, env, buildID string) (PipelineBuild, error) {
 	var buildState PipelineBuild
@@ -0,0 +1,128 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"time"
+)
+
+// Cache is a tarball of directories saved by a job, to be restored by the next builds of the project
+type Cache struct {
+	ID         int64     `json:"id" db:"id"`
+	ProjectID  int64     `json:"-" db:"project_id"`
+	ProjectKey string    `json:"project_key" db:"-"`
+	Key        string    `json:"key" db:"cache_key"`
+	Size       int64     `json:"size" db:"size"`
+	Created    time.Time `json:"created" db:"created"`
+	LastAccess time.Time `json:"last_access" db:"last_access"`
+}
+
+//GetName returns the name of the cache tarball
+func (c *Cache) GetName() string {
+	return fmt.Sprintf("%d.tar.gz", c.ID)
+}
+
+//GetPath returns the path of the cache tarball
+func (c *Cache) GetPath() string {
+	return url.QueryEscape("cache-" + c.ProjectKey)
+}
+
+// UploadCache uploads the tarball as the cache key of the project, replacing the existing one
+func UploadCache(projectKey, appName, key string, tarball io.ReadCloser) error {
+	uri := fmt.Sprintf("/project/%s/application/%s/cache?key=%s", projectKey, appName, url.QueryEscape(key))
+	data, code, err := Upload("POST", uri, tarball, SetHeader("Content-Type", "application/x-gzip"))
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		if e := DecodeError(data); e != nil {
+			return e
+		}
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+// GetCache returns the cache key of the project. If there is no such cache, it returns the
+// most recent cache with a key starting with one of the prefixes
+func GetCache(projectKey, appName, key string, prefixes []string) (*Cache, error) {
+	v := url.Values{}
+	v.Set("key", key)
+	for _, p := range prefixes {
+		v.Add("prefix", p)
+	}
+
+	uri := fmt.Sprintf("/project/%s/application/%s/cache?%s", projectKey, appName, v.Encode())
+	data, code, err := Request("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		if e := DecodeError(data); e != nil {
+			return nil, e
+		}
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	c := &Cache{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DownloadCache returns the tarball of the cache
+func DownloadCache(projectKey, appName string, c *Cache) (io.ReadCloser, error) {
+	uri := fmt.Sprintf("/project/%s/application/%s/cache/%d/download", projectKey, appName, c.ID)
+	reader, code, err := Stream("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		data, _ := ioutil.ReadAll(reader)
+		reader.Close()
+		if e := DecodeError(data); e != nil {
+			return nil, e
+		}
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+	return reader, nil
+}
+
+// ListCaches returns all caches of the project
+func ListCaches(projectKey string) ([]Cache, error) {
+	data, code, err := Request("GET", fmt.Sprintf("/project/%s/cache", projectKey), nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		if e := DecodeError(data); e != nil {
+			return nil, e
+		}
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	caches := []Cache{}
+	if err := json.Unmarshal(data, &caches); err != nil {
+		return nil, err
+	}
+	return caches, nil
+}
+
+// DeleteCache deletes a cache of the project
+func DeleteCache(projectKey string, id int64) error {
+	data, code, err := Request("DELETE", fmt.Sprintf("/project/%s/cache/%d", projectKey, id), nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		if e := DecodeError(data); e != nil {
+			return e
+		}
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package sdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetCacheNotFound(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg, status := ProcessError(ErrCacheNotFound, r.Header.Get("Accept-Language"))
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(Error{Message: msg})
+	}))
+	defer api.Close()
+	InitEndpoint(api.URL)
+
+	_, err := GetCache("KEY", "app", "key", nil)
+	if !ErrorIs(err, ErrCacheNotFound) {
+		t.Errorf("GetCache must return ErrCacheNotFound on a cache miss, got %v", err)
+	}
+
+	_, err = DownloadCache("KEY", "app", &Cache{ID: 1})
+	if !ErrorIs(err, ErrCacheNotFound) {
+		t.Errorf("DownloadCache must return ErrCacheNotFound on a cache miss, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package sdk
+
+import (
+	"strings"
+)
+
+// Concurrency policies
+const (
+	// ConcurrencyQueue holds a new build until the running builds of its group are done
+	ConcurrencyQueue = "queue"
+	// ConcurrencyCancelPrevious stops the running builds of the group and starts the new one
+	ConcurrencyCancelPrevious = "cancel-previous"
+)
+
+// DefaultConcurrencyKey groups builds by application, pipeline and environment
+const DefaultConcurrencyKey = "{{.cds.project}}/{{.cds.application}}/{{.cds.pipeline}}/{{.cds.environment}}"
+
+// Concurrency limits the number of builds of a pipeline running at the same time.
+// Builds sharing the same resolved key belong to the same concurrency group
+type Concurrency struct {
+	Key    string `json:"key,omitempty"`
+	Policy string `json:"policy"`
+}
+
+// IsValid checks the concurrency policy
+func (c *Concurrency) IsValid() error {
+	switch c.Policy {
+	case ConcurrencyQueue, ConcurrencyCancelPrevious:
+		return nil
+	}
+	return ErrInvalidConcurrency
+}
+
+// ResolveKey returns the concurrency group of a build, replacing {{.param}} with build parameters
+func (c *Concurrency) ResolveKey(params []Parameter) string {
+	key := c.Key
+	if key == "" {
+		key = DefaultConcurrencyKey
+	}
+	for _, p := range params {
+		key = strings.Replace(key, "{{."+p.Name+"}}", p.Value, -1)
+	}
+	return key
+}
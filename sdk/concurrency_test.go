@@ -0,0 +1,42 @@
+package sdk
+
+import (
+	"testing"
+)
+
+func TestConcurrencyResolveKey(t *testing.T) {
+	params := []Parameter{
+		{Name: "cds.project", Value: "KEY"},
+		{Name: "cds.application", Value: "app"},
+		{Name: "cds.pipeline", Value: "deploy"},
+		{Name: "cds.environment", Value: "prod"},
+		{Name: "git.branch", Value: "master"},
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", "KEY/app/deploy/prod"},
+		{"{{.cds.environment}}", "prod"},
+		{"deploy-{{.cds.environment}}-{{.git.branch}}", "deploy-prod-master"},
+		{"{{.cds.unknown}}", "{{.cds.unknown}}"},
+	}
+	for _, tt := range tests {
+		c := &Concurrency{Key: tt.key, Policy: ConcurrencyQueue}
+		if got := c.ResolveKey(params); got != tt.want {
+			t.Errorf("ResolveKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestConcurrencyIsValid(t *testing.T) {
+	for _, p := range []string{ConcurrencyQueue, ConcurrencyCancelPrevious} {
+		if err := (&Concurrency{Policy: p}).IsValid(); err != nil {
+			t.Errorf("policy %s must be valid: %s", p, err)
+		}
+	}
+	if err := (&Concurrency{Policy: "parallel"}).IsValid(); err != ErrInvalidConcurrency {
+		t.Errorf("policy parallel must be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+// Package condition implements the boolean expressions used as conditions on stages and triggers.
+//
+// An expression combines comparisons of build parameters and git metadata with and, or and not:
+//
+//	git.branch == "master" || git.tag =~ "^v[0-9]+"
+//	not git.draft && cds.pip.env in ["prod", "preprod"]
+//
+// Operators are ==, !=, =~ (regexp match), !~, <, <=, >, >= (numeric if both sides are numbers), in and not in.
+// and, or and not can also be written &&, || and !. Strings are quoted with " or ', and may use {{.name}} placeholders.
+// An unknown variable is an empty string, a variable which is not prefixed by git. or cds. is looked up as cds.pip.<name>
+package condition
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntaxError is returned by Parse when the expression is malformed
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d: %s", e.Pos+1, e.Msg)
+}
+
+// Condition is a parsed expression
+type Condition struct {
+	source string
+	root   node
+}
+
+// Parse parses the expression, returning a SyntaxError if it is malformed
+func Parse(s string) (*Condition, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, &SyntaxError{Pos: 0, Msg: "empty expression"}
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+	}
+	if err := checkScalar(root); err != nil {
+		return nil, err
+	}
+	return &Condition{source: s, root: root}, nil
+}
+
+// Validate checks the syntax of the expression. An empty expression is valid
+func Validate(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	_, err := Parse(s)
+	return err
+}
+
+// Eval parses and evaluates the expression. An empty expression is true
+func Eval(s string, vars map[string]string) (bool, error) {
+	if strings.TrimSpace(s) == "" {
+		return true, nil
+	}
+	c, err := Parse(s)
+	if err != nil {
+		return false, err
+	}
+	return c.Eval(vars)
+}
+
+// Eval evaluates the condition against the variables
+func (c *Condition) Eval(vars map[string]string) (bool, error) {
+	v, err := c.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	return v.truth(), nil
+}
+
+func (c *Condition) String() string {
+	return c.source
+}
@@ -0,0 +1,87 @@
+package condition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]string{
+		"git.branch":     "master",
+		"git.tag":        "v1.2.0",
+		"git.author":     "john",
+		"cds.pip.env":    "prod",
+		"cds.pip.count":  "10",
+		"cds.pip.deploy": "true",
+	}
+
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{`git.branch == "master"`, true},
+		{`git.branch != 'master'`, false},
+		{`git.tag =~ "^v[0-9]+"`, true},
+		{`git.tag !~ "^v[0-9]+"`, false},
+		{`git.branch == "master" and git.author == "john"`, true},
+		{`git.branch == "dev" or git.author == "john"`, true},
+		{`git.branch == "dev" || git.author == "jane"`, false},
+		{`not git.branch == "dev"`, true},
+		{`!(git.branch == "master" && env == "prod")`, false},
+		{`env in ["prod", "preprod"]`, true},
+		{`env not in ["prod", "preprod"]`, false},
+		{`cds.pip.env in []`, false},
+		{`count > 9`, true},
+		{`count >= 10 and count <= 10`, true},
+		{`count < 9.5`, false},
+		{`git.branch < "test"`, true},
+		{`deploy`, true},
+		{`unknown`, false},
+		{`unknown == ""`, true},
+		{`true and not false`, true},
+		{`git.branch == "{{.cds.pip.env}}"`, false},
+		{`env == "{{.cds.pip.env}}"`, true},
+		{`git.branch == "dev" or git.branch == "master" and env == "prod"`, true},
+	}
+
+	for _, tt := range tests {
+		res, err := Eval(tt.expr, vars)
+		if !assert.NoError(t, err, tt.expr) {
+			continue
+		}
+		assert.Equal(t, tt.expected, res, tt.expr)
+	}
+}
+
+func TestEvalEmpty(t *testing.T) {
+	res, err := Eval("  ", nil)
+	assert.NoError(t, err)
+	assert.True(t, res)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{`git.branch = "master"`, "syntax error at column 12: unexpected '=', did you mean '=='?"},
+		{`git.branch == "master`, "syntax error at column 15: unterminated string"},
+		{`git.branch ==`, "syntax error at column 14: expected a value, a variable or '(', got end of expression"},
+		{`(git.branch == "master"`, "syntax error at column 24: expected ')' to close '(' at column 1, got end of expression"},
+		{`git.branch == "master" git.tag`, "syntax error at column 24: unexpected 'git.tag'"},
+		{`git.branch in "master"`, "syntax error at column 15: expected a list like [\"a\", \"b\"] after 'in', got string \"master\""},
+		{`git.branch == ["master"]`, "syntax error at column 12: '==' cannot compare with a list, use 'in'"},
+		{`git.tag =~ "v[0-9"`, "syntax error at column 12: invalid regular expression \"v[0-9\": error parsing regexp: missing closing ]: `[0-9`"},
+		{`["a"]`, "syntax error at column 1: a list can only be used after 'in'"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.expr)
+		if !assert.Error(t, err, tt.expr) {
+			continue
+		}
+		assert.Equal(t, tt.err, err.Error(), tt.expr)
+		assert.Error(t, Validate(tt.expr), tt.expr)
+	}
+}
@@ -0,0 +1,206 @@
+package condition
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// value is the result of the evaluation of a node
+type value struct {
+	s      string
+	b      bool
+	isBool bool
+	list   []string
+}
+
+func (v value) String() string {
+	if v.isBool {
+		return strconv.FormatBool(v.b)
+	}
+	return v.s
+}
+
+// truth converts the value to a boolean: false, "", "false" and "0" are false
+func (v value) truth() bool {
+	if v.isBool {
+		return v.b
+	}
+	return v.s != "" && v.s != "false" && v.s != "0"
+}
+
+type node interface {
+	eval(vars map[string]string) (value, error)
+}
+
+type literalNode struct {
+	value string
+	pos   int
+}
+
+func (n *literalNode) eval(vars map[string]string) (value, error) {
+	return value{s: interpolate(n.value, vars)}, nil
+}
+
+type boolNode struct {
+	value bool
+}
+
+func (n *boolNode) eval(vars map[string]string) (value, error) {
+	return value{b: n.value, isBool: true}, nil
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n *variableNode) eval(vars map[string]string) (value, error) {
+	return value{s: lookup(n.name, vars)}, nil
+}
+
+type listNode struct {
+	items []node
+	pos   int
+}
+
+func (n *listNode) eval(vars map[string]string) (value, error) {
+	l := make([]string, 0, len(n.items))
+	for _, i := range n.items {
+		v, err := i.eval(vars)
+		if err != nil {
+			return value{}, err
+		}
+		l = append(l, v.String())
+	}
+	return value{list: l}, nil
+}
+
+type notNode struct {
+	x node
+}
+
+func (n *notNode) eval(vars map[string]string) (value, error) {
+	v, err := n.x.eval(vars)
+	if err != nil {
+		return value{}, err
+	}
+	return value{b: !v.truth(), isBool: true}, nil
+}
+
+type logicalNode struct {
+	or          bool
+	left, right node
+}
+
+func (n *logicalNode) eval(vars map[string]string) (value, error) {
+	l, err := n.left.eval(vars)
+	if err != nil {
+		return value{}, err
+	}
+	// Short-circuit evaluation
+	if l.truth() == n.or {
+		return value{b: n.or, isBool: true}, nil
+	}
+	r, err := n.right.eval(vars)
+	if err != nil {
+		return value{}, err
+	}
+	return value{b: r.truth(), isBool: true}, nil
+}
+
+type compareNode struct {
+	op          string
+	pos         int
+	left, right node
+	re          *regexp.Regexp
+}
+
+func (n *compareNode) eval(vars map[string]string) (value, error) {
+	l, err := n.left.eval(vars)
+	if err != nil {
+		return value{}, err
+	}
+	r, err := n.right.eval(vars)
+	if err != nil {
+		return value{}, err
+	}
+
+	var res bool
+	switch n.op {
+	case "==":
+		res = l.String() == r.String()
+	case "!=":
+		res = l.String() != r.String()
+	case "=~", "!~":
+		re := n.re
+		if re == nil {
+			if re, err = regexp.Compile(r.String()); err != nil {
+				return value{}, fmt.Errorf("invalid regular expression %q: %s", r.String(), err)
+			}
+		}
+		res = re.MatchString(l.String()) == (n.op == "=~")
+	case "<", "<=", ">", ">=":
+		res = compare(n.op, l.String(), r.String())
+	case "in", "not in":
+		for _, s := range r.list {
+			if s == l.String() {
+				res = true
+				break
+			}
+		}
+		if n.op == "not in" {
+			res = !res
+		}
+	}
+	return value{b: res, isBool: true}, nil
+}
+
+// compare compares numerically if both operands are numbers, else lexicographically
+func compare(op, l, r string) bool {
+	var c int
+	lf, errL := strconv.ParseFloat(l, 64)
+	rf, errR := strconv.ParseFloat(r, 64)
+	if errL == nil && errR == nil {
+		switch {
+		case lf < rf:
+			c = -1
+		case lf > rf:
+			c = 1
+		}
+	} else {
+		c = strings.Compare(l, r)
+	}
+
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+// lookup returns the value of the variable. Variables which are not git or cds variables are pipeline parameters
+func lookup(name string, vars map[string]string) string {
+	if v, ok := vars[name]; ok {
+		return v
+	}
+	if !strings.HasPrefix(name, "git.") && !strings.HasPrefix(name, "cds.") {
+		return vars["cds.pip."+name]
+	}
+	return ""
+}
+
+// interpolate replaces {{.name}} placeholders by the value of the variables
+func interpolate(s string, vars map[string]string) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	for k, v := range vars {
+		s = strings.Replace(s, "{{."+k+"}}", v, -1)
+	}
+	return s
+}
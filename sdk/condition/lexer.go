@@ -0,0 +1,152 @@
+package condition
+
+import (
+	"bytes"
+	"fmt"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokIn
+	tokTrue
+	tokFalse
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+var keywords = map[string]tokenKind{
+	"and":   tokAnd,
+	"or":    tokOr,
+	"not":   tokNot,
+	"in":    tokIn,
+	"true":  tokTrue,
+	"false": tokFalse,
+}
+
+// lex splits the expression into tokens
+func lex(s string) ([]token, error) {
+	var tokens []token
+	r := []rune(s)
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '(' || c == ')' || c == '[' || c == ']' || c == ',':
+			kind := map[rune]tokenKind{'(': tokLParen, ')': tokRParen, '[': tokLBracket, ']': tokRBracket, ',': tokComma}[c]
+			tokens = append(tokens, token{kind, string(c), i})
+			i++
+
+		case c == '"' || c == '\'':
+			start := i
+			var sb bytes.Buffer
+			i++
+			closed := false
+			for i < len(r) {
+				if r[i] == '\\' && i+1 < len(r) {
+					switch r[i+1] {
+					case 'n':
+						sb.WriteRune('\n')
+					case 't':
+						sb.WriteRune('\t')
+					default:
+						sb.WriteRune(r[i+1])
+					}
+					i += 2
+					continue
+				}
+				if r[i] == c {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(r[i])
+				i++
+			}
+			if !closed {
+				return nil, &SyntaxError{Pos: start, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, token{tokString, sb.String(), start})
+
+		case c == '&' || c == '|':
+			if i+1 >= len(r) || r[i+1] != c {
+				return nil, &SyntaxError{Pos: i, Msg: fmt.Sprintf("unexpected '%c', did you mean '%c%c'?", c, c, c)}
+			}
+			kind := tokAnd
+			if c == '|' {
+				kind = tokOr
+			}
+			tokens = append(tokens, token{kind, string([]rune{c, c}), i})
+			i += 2
+
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(r) && (r[i+1] == '=' || (r[i+1] == '~' && (c == '=' || c == '!'))) {
+				op += string(r[i+1])
+			}
+			switch op {
+			case "!":
+				tokens = append(tokens, token{tokNot, op, i})
+			case "=":
+				return nil, &SyntaxError{Pos: i, Msg: "unexpected '=', did you mean '=='?"}
+			default:
+				tokens = append(tokens, token{tokOp, op, i})
+			}
+			i += len(op)
+
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(r) && unicode.IsDigit(r[i+1])):
+			start := i
+			i++
+			for i < len(r) && (unicode.IsDigit(r[i]) || r[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, string(r[start:i]), start})
+
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(r) && (unicode.IsLetter(r[i]) || unicode.IsDigit(r[i]) || r[i] == '_' || r[i] == '.' || r[i] == '-') {
+				i++
+			}
+			text := string(r[start:i])
+			kind, ok := keywords[text]
+			if !ok {
+				kind = tokIdent
+			}
+			tokens = append(tokens, token{kind, text, start})
+
+		default:
+			return nil, &SyntaxError{Pos: i, Msg: fmt.Sprintf("unexpected character '%c'", c)}
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(r)}), nil
+}
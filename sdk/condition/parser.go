@@ -0,0 +1,208 @@
+package condition
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// parseOr parses: and { "or" and }
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses: unary { "and" unary }
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary parses: "not" unary | comparison
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses: operand [ op operand | ["not"] "in" list ]
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	switch {
+	case t.kind == tokOp:
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		c := &compareNode{op: t.text, pos: t.pos, left: left, right: right}
+		if err := c.check(); err != nil {
+			return nil, err
+		}
+		return c, nil
+
+	case t.kind == tokIn || (t.kind == tokNot && p.tokens[p.i+1].kind == tokIn):
+		op := "in"
+		if t.kind == tokNot {
+			p.next()
+			op = "not in"
+		}
+		p.next()
+		if p.peek().kind != tokLBracket {
+			return nil, &SyntaxError{Pos: p.peek().pos, Msg: fmt.Sprintf("expected a list like [\"a\", \"b\"] after '%s', got %s", op, p.peek())}
+		}
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		c := &compareNode{op: op, pos: t.pos, left: left, right: right}
+		if err := c.check(); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return left, nil
+}
+
+// parseOperand parses: string | number | true | false | variable | "(" expression ")" | list
+func (p *parser) parseOperand() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString, tokNumber:
+		return &literalNode{value: t.text, pos: t.pos}, nil
+	case tokTrue, tokFalse:
+		return &boolNode{value: t.kind == tokTrue}, nil
+	case tokIdent:
+		return &variableNode{name: t.text}, nil
+	case tokLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.kind != tokRParen {
+			return nil, &SyntaxError{Pos: c.pos, Msg: fmt.Sprintf("expected ')' to close '(' at column %d, got %s", t.pos+1, c)}
+		}
+		return x, nil
+	case tokLBracket:
+		l := &listNode{pos: t.pos}
+		if p.peek().kind == tokRBracket {
+			p.next()
+			return l, nil
+		}
+		for {
+			item, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := item.(*listNode); ok {
+				return nil, &SyntaxError{Pos: t.pos, Msg: "lists cannot be nested"}
+			}
+			l.items = append(l.items, item)
+			c := p.next()
+			if c.kind == tokRBracket {
+				return l, nil
+			}
+			if c.kind != tokComma {
+				return nil, &SyntaxError{Pos: c.pos, Msg: fmt.Sprintf("expected ',' or ']' in list, got %s", c)}
+			}
+		}
+	}
+	return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected a value, a variable or '(', got %s", t)}
+}
+
+// check verifies the operands of the comparison and compiles constant regexps
+func (c *compareNode) check() error {
+	_, leftIsList := c.left.(*listNode)
+	_, rightIsList := c.right.(*listNode)
+	if leftIsList {
+		return &SyntaxError{Pos: c.pos, Msg: fmt.Sprintf("a list cannot be on the left of '%s'", c.op)}
+	}
+
+	switch c.op {
+	case "in", "not in":
+		if !rightIsList {
+			return &SyntaxError{Pos: c.pos, Msg: fmt.Sprintf("'%s' expects a list", c.op)}
+		}
+		return nil
+	}
+	if rightIsList {
+		return &SyntaxError{Pos: c.pos, Msg: fmt.Sprintf("'%s' cannot compare with a list, use 'in'", c.op)}
+	}
+
+	if c.op == "=~" || c.op == "!~" {
+		if l, ok := c.right.(*literalNode); ok && !strings.Contains(l.value, "{{") {
+			re, err := regexp.Compile(l.value)
+			if err != nil {
+				return &SyntaxError{Pos: l.pos, Msg: fmt.Sprintf("invalid regular expression %q: %s", l.value, err)}
+			}
+			c.re = re
+		}
+	}
+	return nil
+}
+
+// checkScalar refuses a list as the whole expression or as an operand of and, or, not
+func checkScalar(n node) error {
+	switch x := n.(type) {
+	case *listNode:
+		return &SyntaxError{Pos: x.pos, Msg: "a list can only be used after 'in'"}
+	case *logicalNode:
+		if err := checkScalar(x.left); err != nil {
+			return err
+		}
+		return checkScalar(x.right)
+	case *notNode:
+		return checkScalar(x.x)
+	}
+	return nil
+}
@@ -105,6 +105,29 @@ var (
 	ErrJobAlreadyBooked                      = &Error{ID: 89, Status: http.StatusConflict}
 	ErrPipelineBuildNotFound                 = &Error{ID: 90, Status: http.StatusNotFound}
 	ErrAlreadyTaken                          = &Error{ID: 91, Status: http.StatusGone}
+	ErrInvalidHookSignature                  = &Error{ID: 92, Status: http.StatusUnauthorized}
+	ErrNoReceivedHook                        = &Error{ID: 93, Status: http.StatusNotFound}
+	ErrCacheNotFound                         = &Error{ID: 94, Status: http.StatusNotFound}
+	ErrCacheTooLarge                         = &Error{ID: 95, Status: http.StatusRequestEntityTooLarge}
+	ErrInvalidCondition                      = &Error{ID: 96, Status: http.StatusBadRequest}
+	ErrInvalidJobOutput                      = &Error{ID: 97, Status: http.StatusBadRequest}
+	ErrNoApprovalWaiting                     = &Error{ID: 98, Status: http.StatusBadRequest}
+	ErrNotApprover                           = &Error{ID: 99, Status: http.StatusForbidden}
+	ErrAlreadyDecided                        = &Error{ID: 100, Status: http.StatusConflict}
+	ErrInvalidConcurrency                    = &Error{ID: 101, Status: http.StatusBadRequest}
+	ErrInvalidJobRetry                       = &Error{ID: 102, Status: http.StatusBadRequest}
+	ErrInvalidArtifactRetention              = &Error{ID: 103, Status: http.StatusBadRequest}
+	ErrArtifactChecksum                      = &Error{ID: 104, Status: http.StatusBadRequest}
+	ErrInvalidUploadOffset                   = &Error{ID: 105, Status: http.StatusConflict}
+	ErrInvalidArtifactSignature              = &Error{ID: 106, Status: http.StatusForbidden}
+	ErrInvalidArtifactReference              = &Error{ID: 107, Status: http.StatusBadRequest}
+	ErrInvalidArtifactChannel                = &Error{ID: 108, Status: http.StatusBadRequest}
+	ErrInvalidScalingPolicy                  = &Error{ID: 109, Status: http.StatusBadRequest}
+	ErrNoScalingPolicy                       = &Error{ID: 110, Status: http.StatusNotFound}
+	ErrInvalidModelState                     = &Error{ID: 111, Status: http.StatusBadRequest}
+	ErrNoWorkerModelVersion                  = &Error{ID: 112, Status: http.StatusNotFound}
+	ErrInvalidJobMatrix                      = &Error{ID: 113, Status: http.StatusBadRequest}
+	ErrUploadCompleting                      = &Error{ID: 114, Status: http.StatusConflict}
 )
 
 var errorsAmericanEnglish = map[int]string{
@@ -201,6 +224,29 @@ You can safely use them in a String or Text parameter`,
 	ErrJobAlreadyBooked.ID:                      "Job already booked",
 	ErrPipelineBuildNotFound.ID:                 "Pipeline build not found",
 	ErrAlreadyTaken.ID:                          "This job is already taken by another worker",
+	ErrInvalidHookSignature.ID:                  "Invalid hook signature",
+	ErrNoReceivedHook.ID:                        "Received hook not found",
+	ErrCacheNotFound.ID:                         "Cache not found",
+	ErrCacheTooLarge.ID:                         "Cache is larger than the project quota",
+	ErrInvalidCondition.ID:                      "Invalid condition expression",
+	ErrInvalidJobOutput.ID:                      "Invalid job output",
+	ErrNoApprovalWaiting.ID:                     "No approval is waiting on this stage",
+	ErrNotApprover.ID:                           "You are not allowed to approve this stage",
+	ErrAlreadyDecided.ID:                        "You have already approved or rejected this stage",
+	ErrInvalidConcurrency.ID:                    "Invalid concurrency policy",
+	ErrInvalidJobRetry.ID:                       "Invalid job retry policy",
+	ErrInvalidArtifactRetention.ID:              "Invalid artifact retention policy: keep last or keep days must be set",
+	ErrArtifactChecksum.ID:                      "Artifact checksum does not match",
+	ErrInvalidUploadOffset.ID:                   "Invalid upload offset",
+	ErrInvalidArtifactSignature.ID:              "Invalid or expired artifact signature",
+	ErrInvalidArtifactReference.ID:              "Invalid artifact reference: project/application/pipeline/environment/build[/name] expected, build being a build number, a tag or @channel",
+	ErrInvalidArtifactChannel.ID:                "Invalid artifact channel name",
+	ErrInvalidScalingPolicy.ID:                  "Invalid scaling policy",
+	ErrNoScalingPolicy.ID:                       "Worker model has no scaling policy",
+	ErrInvalidModelState.ID:                     "Invalid worker model state",
+	ErrNoWorkerModelVersion.ID:                  "Worker model version does not exist",
+	ErrInvalidJobMatrix.ID:                      "Invalid job matrix: axes must have a name and at least one value",
+	ErrUploadCompleting.ID:                      "Upload is being completed",
 }
 
 var errorsFrench = map[int]string{
@@ -297,6 +343,29 @@ Vous pouvez les utiliser sans problème dans un paramêtre de type String ou Tex
 	ErrJobAlreadyBooked.ID:                      "Le job est déjà réservé",
 	ErrPipelineBuildNotFound.ID:                 "Le pipeline build n'a pu être trouvé",
 	ErrAlreadyTaken.ID:                          "Ce job est déjà en cours de traitement par un autre worker",
+	ErrInvalidHookSignature.ID:                  "Signature du hook invalide",
+	ErrNoReceivedHook.ID:                        "Le hook reçu n'existe pas",
+	ErrCacheNotFound.ID:                         "Le cache n'existe pas",
+	ErrCacheTooLarge.ID:                         "Le cache dépasse le quota du projet",
+	ErrInvalidCondition.ID:                      "Expression de condition invalide",
+	ErrInvalidJobOutput.ID:                      "Sortie de job invalide",
+	ErrNoApprovalWaiting.ID:                     "Aucune approbation n'est en attente sur ce stage",
+	ErrNotApprover.ID:                           "Vous n'êtes pas autorisé à approuver ce stage",
+	ErrAlreadyDecided.ID:                        "Vous avez déjà approuvé ou rejeté ce stage",
+	ErrInvalidConcurrency.ID:                    "Politique de concurrence invalide",
+	ErrInvalidJobRetry.ID:                       "Politique de relance du job invalide",
+	ErrInvalidArtifactRetention.ID:              "Politique de rétention des artefacts invalide : le nombre de builds ou de jours conservés doit être défini",
+	ErrArtifactChecksum.ID:                      "La somme de contrôle de l'artefact ne correspond pas",
+	ErrInvalidUploadOffset.ID:                   "Position d'envoi invalide",
+	ErrInvalidArtifactSignature.ID:              "Signature de l'artefact invalide ou expirée",
+	ErrInvalidArtifactReference.ID:              "Référence d'artefact invalide : projet/application/pipeline/environnement/build[/nom] attendu, build étant un numéro de build, un tag ou @canal",
+	ErrInvalidArtifactChannel.ID:                "Nom de canal d'artefacts invalide",
+	ErrInvalidScalingPolicy.ID:                  "Politique de mise à l'échelle invalide",
+	ErrNoScalingPolicy.ID:                       "Le modèle de worker n'a pas de politique de mise à l'échelle",
+	ErrInvalidModelState.ID:                     "État du modèle de worker invalide",
+	ErrNoWorkerModelVersion.ID:                  "La version du modèle de worker n'existe pas",
+	ErrInvalidJobMatrix.ID:                      "Matrice du job invalide : les axes doivent avoir un nom et au moins une valeur",
+	ErrUploadCompleting.ID:                      "L'envoi est en cours de finalisation",
 }
 
 var errorsLanguages = []map[int]string{
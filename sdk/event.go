@@ -1,6 +1,14 @@
 package sdk
 
 import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -38,6 +46,7 @@ type EventPipelineBuild struct {
 	Hash                  string `json:"hash,omitempty"`
 	RepositoryManagerName string `json:"repositoryManagerName,omitempty"`
 	RepositoryFullname    string `json:"repositoryFullname,omitempty"`
+	WaitingReason         string `json:"waitingReason,omitempty"`
 }
 
 // EventJob contains event data for a job
@@ -59,9 +68,116 @@ type EventJob struct {
 	Hash            string `json:"hash,omitempty"`
 }
 
+// EventStageApproval contains event data for a decision on a stage approval gate
+type EventStageApproval struct {
+	BuildNumber     int64  `json:"buildNumber,omitempty"`
+	StageName       string `json:"stageName,omitempty"`
+	Status          Status `json:"status,omitempty"`
+	User            string `json:"user,omitempty"`
+	Approved        bool   `json:"approved"`
+	Comment         string `json:"comment,omitempty"`
+	PipelineName    string `json:"pipelineName,omitempty"`
+	ProjectKey      string `json:"projectKey,omitempty"`
+	ApplicationName string `json:"applicationName,omitempty"`
+	EnvironmentName string `json:"environmentName,omitempty"`
+}
+
 // EventNotif contains event data for a job
 type EventNotif struct {
 	Recipients []string `json:"recipients"`
 	Subject    string   `json:"subject,omitempty"`
 	Body       string   `json:"body,omitempty"`
 }
+
+// EventFilter restricts the events received by StreamEvents. Empty fields match all events
+type EventFilter struct {
+	ProjectKey      string
+	ApplicationName string
+	PipelineName    string
+	Types           []string
+}
+
+// Values returns the filter as query parameters of /events
+func (f EventFilter) Values() url.Values {
+	v := url.Values{}
+	if f.ProjectKey != "" {
+		v.Set("project", f.ProjectKey)
+	}
+	if f.ApplicationName != "" {
+		v.Set("application", f.ApplicationName)
+	}
+	if f.PipelineName != "" {
+		v.Set("pipeline", f.PipelineName)
+	}
+	if len(f.Types) > 0 {
+		v.Set("type", strings.Join(f.Types, ","))
+	}
+	return v
+}
+
+// Delays between two reconnections of StreamEvents, doubled after each failed attempt
+var (
+	eventsReconnectDelay    = time.Second
+	eventsReconnectMaxDelay = 30 * time.Second
+)
+
+// StreamEvents sends on the events chan all events of the API matching the filter, as server-sent events.
+// The API closes streams after a while: StreamEvents reconnects with a backoff, it only returns when the API refuses the stream
+func StreamEvents(filter EventFilter, events chan<- Event) error {
+	delay := eventsReconnectDelay
+	for {
+		code, err := streamEvents(filter, events)
+		if code >= 400 && code < 500 {
+			return err
+		}
+		if code == http.StatusOK {
+			delay = eventsReconnectDelay
+		}
+		time.Sleep(delay)
+		if delay *= 2; delay > eventsReconnectMaxDelay {
+			delay = eventsReconnectMaxDelay
+		}
+	}
+}
+
+// streamEvents reads events until the end of one stream of /events, it returns the HTTP code of the stream
+func streamEvents(filter EventFilter, events chan<- Event) (int, error) {
+	path := "/events"
+	if q := filter.Values().Encode(); q != "" {
+		path += "?" + q
+	}
+
+	body, code, err := Stream("GET", path, nil, SetHeader("Accept", "text/event-stream"))
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	if code >= 300 {
+		data, _ := ioutil.ReadAll(body)
+		if e := DecodeError(data); e != nil {
+			return code, e
+		}
+		return code, fmt.Errorf("HTTP %d", code)
+	}
+
+	return code, readEvents(body, events)
+}
+
+// readEvents parses server-sent events: each event is a json sdk.Event on a "data:" line, other lines are ignored
+func readEvents(r io.Reader, events chan<- Event) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &e); err != nil {
+			return fmt.Errorf("cannot read event: %s", err)
+		}
+		events <- e
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,40 @@
+package sdk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadEvents(t *testing.T) {
+	stream := `: connected
+
+event: sdk.EventPipelineBuild
+data: {"type_event":"sdk.EventPipelineBuild","payload":{"ProjectKey":"KEY"}}
+
+: ping
+
+event: sdk.EventJob
+data: {"type_event":"sdk.EventJob","payload":{"ProjectKey":"KEY","JobName":"build"}}
+
+`
+	events := make(chan Event, 10)
+	if err := readEvents(strings.NewReader(stream), events); err != nil {
+		t.Fatalf("readEvents returns an error: %s", err)
+	}
+	close(events)
+
+	types := []string{}
+	for e := range events {
+		types = append(types, e.EventType)
+	}
+	if len(types) != 2 || types[0] != "sdk.EventPipelineBuild" || types[1] != "sdk.EventJob" {
+		t.Fatalf("Unexpected events: %v", types)
+	}
+}
+
+func TestEventFilterValues(t *testing.T) {
+	f := EventFilter{ProjectKey: "KEY", PipelineName: "build", Types: []string{"EventJob", "EventPipelineBuild"}}
+	if q := f.Values().Encode(); q != "pipeline=build&project=KEY&type=EventJob%2CEventPipelineBuild" {
+		t.Fatalf("Unexpected query: %s", q)
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/condition"
 )
 
 // Pipeline represents exported sdk.Pipeline
@@ -22,6 +24,14 @@ type Pipeline struct {
 	Jobs         map[string]Job            `json:"jobs,omitempty" yaml:"jobs,omitempty"`
 	Requirements []Requirement             `json:"requirements,omitempty" yaml:"requirements,omitempty" hcl:"requirement,omitempty"`
 	Steps        []Step                    `json:"steps,omitempty" yaml:"steps,omitempty" hcl:"step,omitempty"`
+	Timeout      string                    `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Concurrency  *Concurrency              `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+}
+
+// Concurrency represents an exported sdk.Concurrency
+type Concurrency struct {
+	Key    string `json:"key,omitempty" yaml:"key,omitempty"`
+	Policy string `json:"policy" yaml:"policy"`
 }
 
 // Stage represents exported sdk.Stage
@@ -29,14 +39,47 @@ type Stage struct {
 	Enabled    *bool             `json:"enabled,omitempty" yaml:"enabled,omitempty"`
 	Jobs       map[string]Job    `json:"jobs,omitempty" yaml:"jobs,omitempty"`
 	Conditions map[string]string `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Condition  string            `json:"condition,omitempty" yaml:"condition,omitempty"`
+	Approval   *StageApproval    `json:"approval,omitempty" yaml:"approval,omitempty"`
+}
+
+// StageApproval represents an exported sdk.StageApproval
+type StageApproval struct {
+	Groups   []string `json:"groups" yaml:"groups"`
+	Required int      `json:"required,omitempty" yaml:"required,omitempty"`
+	Timeout  string   `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
 
 // Job represents exported sdk.Job
 type Job struct {
-	Description  string        `json:"description,omitempty" yaml:"description,omitempty"`
-	Enabled      *bool         `json:"enabled,omitempty" yaml:"enabled,omitempty"`
-	Steps        []Step        `json:"steps,omitempty" yaml:"steps,omitempty" hcl:"step,omitempty"`
-	Requirements []Requirement `json:"requirements,omitempty" yaml:"requirements,omitempty" hcl:"requirement,omitempty"`
+	Description  string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Enabled      *bool                 `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Steps        []Step                `json:"steps,omitempty" yaml:"steps,omitempty" hcl:"step,omitempty"`
+	Requirements []Requirement         `json:"requirements,omitempty" yaml:"requirements,omitempty" hcl:"requirement,omitempty"`
+	Matrix       map[string]MatrixAxis `json:"matrix,omitempty" yaml:"matrix,omitempty" hcl:"matrix,omitempty"`
+	Timeout      string                `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Outputs      map[string]JobOutput  `json:"outputs,omitempty" yaml:"outputs,omitempty" hcl:"output,omitempty"`
+	Retry        *JobRetry             `json:"retry,omitempty" yaml:"retry,omitempty"`
+}
+
+// JobRetry represents an exported sdk.JobRetry
+type JobRetry struct {
+	MaxAttempts int      `json:"max_attempts" yaml:"max_attempts"`
+	Backoff     string   `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	On          []string `json:"on,omitempty" yaml:"on,omitempty"`
+}
+
+// JobOutput represents an exported sdk.JobOutput, outputs are sorted by name on import
+type JobOutput struct {
+	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// MatrixAxis represents an exported sdk.MatrixAxis, axes are sorted by name on import
+type MatrixAxis struct {
+	Values       []string          `json:"values" yaml:"values" hcl:"values"`
+	AllowFailure []string          `json:"allow_failure,omitempty" yaml:"allow_failure,omitempty" hcl:"allow_failure,omitempty"`
+	Models       map[string]string `json:"models,omitempty" yaml:"models,omitempty" hcl:"models,omitempty"`
 }
 
 // Step represents exported step used in a job
@@ -45,7 +88,7 @@ type Step map[string]interface{}
 func (s Step) IsValid() bool {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "final" {
+		if k != "enabled" && k != "final" && k != "timeout" {
 			keys = append(keys, k)
 		}
 	}
@@ -55,7 +98,7 @@ func (s Step) IsValid() bool {
 func (s Step) key() string {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "final" {
+		if k != "enabled" && k != "final" && k != "timeout" {
 			keys = append(keys, k)
 		}
 	}
@@ -246,6 +289,45 @@ func (s Step) IsFinal() (bool, error) {
 	return bS, nil
 }
 
+// Timeout returns the timeout of the step in seconds. It is either a duration like "10m" or a number of seconds
+func (s Step) Timeout() (int64, error) {
+	bI, ok := s["timeout"]
+	if !ok {
+		return 0, nil
+	}
+	return parseTimeout(bI)
+}
+
+// parseTimeout converts a duration like "1h30m" or a number of seconds into seconds
+func parseTimeout(v interface{}) (int64, error) {
+	switch t := v.(type) {
+	case string:
+		if t == "" {
+			return 0, nil
+		}
+		if n, err := strconv.ParseInt(t, 10, 64); err == nil {
+			return n, nil
+		}
+		d, err := time.ParseDuration(t)
+		if err != nil {
+			return 0, fmt.Errorf("Malformatted timeout : %s", err)
+		}
+		return int64(d / time.Second), nil
+	case int:
+		return int64(t), nil
+	case int64:
+		return t, nil
+	case float64:
+		return int64(t), nil
+	}
+	return 0, fmt.Errorf("Malformatted timeout : %v must be a duration", v)
+}
+
+// formatTimeout converts seconds into a duration like "1h30m0s"
+func formatTimeout(seconds int64) string {
+	return (time.Duration(seconds) * time.Second).String()
+}
+
 // Requirement represents an exported sdk.Requirement
 type Requirement struct {
 	Binary   string             `json:"binary,omitempty" yaml:"binary,omitempty"`
@@ -255,6 +337,8 @@ type Requirement struct {
 	Plugin   string             `json:"plugin,omitempty" yaml:"plugin,omitempty"`
 	Service  ServiceRequirement `json:"service,omitempty" yaml:"service,omitempty"`
 	Memory   string             `json:"memory,omitempty" yaml:"memory,omitempty"`
+	OS       string             `json:"os,omitempty" yaml:"os,omitempty"`
+	Arch     string             `json:"arch,omitempty" yaml:"arch,omitempty"`
 }
 
 // ServiceRequirement represents an exported sdk.Requirement of type ServiceRequirement
@@ -277,6 +361,13 @@ func NewPipeline(pip *sdk.Pipeline) (p *Pipeline) {
 		p.Type = pip.Type
 	}
 
+	if pip.Concurrency != nil {
+		p.Concurrency = &Concurrency{
+			Key:    pip.Concurrency.Key,
+			Policy: pip.Concurrency.Policy,
+		}
+	}
+
 	if len(pip.GroupPermission) > 0 {
 		p.Permissions = make(map[string]int, len(pip.GroupPermission))
 		for _, perm := range pip.GroupPermission {
@@ -298,14 +389,21 @@ func NewPipeline(pip *sdk.Pipeline) (p *Pipeline) {
 	case 0:
 		return
 	case 1:
-		if len(pip.Stages[0].Prerequisites) == 0 {
+		if len(pip.Stages[0].Prerequisites) == 0 && pip.Stages[0].Condition == "" && pip.Stages[0].Approval == nil {
 			switch len(pip.Stages[0].Jobs) {
 			case 0:
 				return
 			case 1:
-				p.Steps = newSteps(pip.Stages[0].Jobs[0].Action)
-				p.Requirements = newRequirements(pip.Stages[0].Jobs[0].Action.Requirements)
-				return
+				//A job matrix, job outputs or a retry policy can't be exported as pipeline steps
+				if len(pip.Stages[0].Jobs[0].Matrix) == 0 && len(pip.Stages[0].Jobs[0].Outputs) == 0 && pip.Stages[0].Jobs[0].Retry == nil {
+					p.Steps = newSteps(pip.Stages[0].Jobs[0].Action)
+					p.Requirements = newRequirements(pip.Stages[0].Jobs[0].Action.Requirements)
+					if pip.Stages[0].Jobs[0].Timeout > 0 {
+						p.Timeout = formatTimeout(pip.Stages[0].Jobs[0].Timeout)
+					}
+					return
+				}
+				p.Jobs = newJobs(pip.Stages[0].Jobs)
 			default:
 				p.Jobs = newJobs(pip.Stages[0].Jobs)
 			}
@@ -338,6 +436,16 @@ func newStages(stages []sdk.Stage) map[string]Stage {
 		for _, r := range s.Prerequisites {
 			st.Conditions[r.Parameter] = r.ExpectedValue
 		}
+		st.Condition = s.Condition
+		if s.Approval != nil {
+			st.Approval = &StageApproval{
+				Groups:   s.Approval.Groups,
+				Required: s.Approval.Required,
+			}
+			if s.Approval.Timeout > 0 {
+				st.Approval.Timeout = formatTimeout(s.Approval.Timeout)
+			}
+		}
 		st.Jobs = newJobs(s.Jobs)
 		res[fmt.Sprintf("%d|%s", order, s.Name)] = st
 	}
@@ -365,6 +473,10 @@ func newRequirements(req []sdk.Requirement) []Requirement {
 			res = append(res, Requirement{Service: ServiceRequirement{Name: r.Name, Value: r.Value}})
 		case sdk.MemoryRequirement:
 			res = append(res, Requirement{Memory: r.Value})
+		case sdk.OSRequirement:
+			res = append(res, Requirement{OS: r.Value})
+		case sdk.ArchRequirement:
+			res = append(res, Requirement{Arch: r.Value})
 		}
 	}
 	return res
@@ -384,11 +496,54 @@ func newJobs(jobs []sdk.Job) map[string]Job {
 		jo.Steps = newSteps(j.Action)
 		jo.Description = j.Action.Description
 		jo.Requirements = newRequirements(j.Action.Requirements)
+		jo.Matrix = newMatrix(j.Matrix)
+		jo.Outputs = newJobOutputs(j.Outputs)
+		if j.Timeout > 0 {
+			jo.Timeout = formatTimeout(j.Timeout)
+		}
+		if j.Retry != nil {
+			jo.Retry = &JobRetry{
+				MaxAttempts: j.Retry.MaxAttempts,
+				On:          j.Retry.On,
+			}
+			if j.Retry.Backoff > 0 {
+				jo.Retry.Backoff = formatTimeout(j.Retry.Backoff)
+			}
+		}
 		res[j.Action.Name] = jo
 	}
 	return res
 }
 
+func newJobOutputs(outputs []sdk.JobOutput) map[string]JobOutput {
+	if len(outputs) == 0 {
+		return nil
+	}
+	res := make(map[string]JobOutput, len(outputs))
+	for _, o := range outputs {
+		res[o.Name] = JobOutput{
+			Type:        o.Type,
+			Description: o.Description,
+		}
+	}
+	return res
+}
+
+func newMatrix(axes []sdk.MatrixAxis) map[string]MatrixAxis {
+	if len(axes) == 0 {
+		return nil
+	}
+	res := make(map[string]MatrixAxis, len(axes))
+	for _, a := range axes {
+		res[a.Name] = MatrixAxis{
+			Values:       a.Values,
+			AllowFailure: a.AllowFailure,
+			Models:       a.Models,
+		}
+	}
+	return res
+}
+
 func newSteps(a sdk.Action) []Step {
 	res := []Step{}
 	for i := range a.Actions {
@@ -400,6 +555,9 @@ func newSteps(a sdk.Action) []Step {
 		if a.Final {
 			s["final"] = a.Final
 		}
+		if a.Timeout > 0 {
+			s["timeout"] = formatTimeout(a.Timeout)
+		}
 
 		switch a.Type {
 		case sdk.BuiltinAction:
@@ -427,6 +585,10 @@ func newSteps(a sdk.Action) []Step {
 				if pipeline != nil {
 					artifactDownloadArgs["pipeline"] = pipeline.Value
 				}
+				reference := sdk.ParameterFind(a.Parameters, "reference")
+				if reference != nil && reference.Value != "" {
+					artifactDownloadArgs["reference"] = reference.Value
+				}
 				s["artifactDownload"] = artifactDownloadArgs
 			case sdk.ArtifactUpload:
 				artifactUploadArgs := map[string]string{}
@@ -475,6 +637,16 @@ func (p *Pipeline) Pipeline() (*sdk.Pipeline, error) {
 	pip.Name = p.Name
 	pip.Type = p.Type
 
+	if p.Concurrency != nil {
+		pip.Concurrency = &sdk.Concurrency{
+			Key:    p.Concurrency.Key,
+			Policy: p.Concurrency.Policy,
+		}
+		if err := pip.Concurrency.IsValid(); err != nil {
+			return nil, err
+		}
+	}
+
 	//Compute permissions
 	for g, p := range p.Permissions {
 		perm := sdk.GroupPermission{
@@ -500,6 +672,10 @@ func (p *Pipeline) Pipeline() (*sdk.Pipeline, error) {
 		if err != nil {
 			return nil, err
 		}
+		timeout, err := parseTimeout(p.Timeout)
+		if err != nil {
+			return nil, err
+		}
 		pip.Stages = []sdk.Stage{
 			sdk.Stage{
 				Name:       p.Name,
@@ -508,6 +684,7 @@ func (p *Pipeline) Pipeline() (*sdk.Pipeline, error) {
 				Jobs: []sdk.Job{
 					sdk.Job{
 						Enabled: true,
+						Timeout: timeout,
 						Action: sdk.Action{
 							Enabled: true,
 							Name:    p.Name,
@@ -575,6 +752,28 @@ func (p *Pipeline) Pipeline() (*sdk.Pipeline, error) {
 				})
 			}
 
+			//Compute stage condition expression
+			if err := condition.Validate(p.Stages[stageName].Condition); err != nil {
+				return nil, fmt.Errorf("invalid condition on stage %s: %s", name, err)
+			}
+			s.Condition = p.Stages[stageName].Condition
+
+			//Compute stage approval gate
+			if a := p.Stages[stageName].Approval; a != nil {
+				if len(a.Groups) == 0 {
+					return nil, fmt.Errorf("invalid approval on stage %s: no approver group", name)
+				}
+				timeout, err := parseTimeout(a.Timeout)
+				if err != nil {
+					return nil, err
+				}
+				s.Approval = &sdk.StageApproval{
+					Groups:   a.Groups,
+					Required: a.Required,
+					Timeout:  timeout,
+				}
+			}
+
 			//Compute jobs
 			for n, j := range p.Stages[stageName].Jobs {
 				job, err := computeJob(n, j)
@@ -598,6 +797,9 @@ func computeSteps(steps []Step) ([]sdk.Action, error) {
 		if err != nil {
 			return nil, err
 		}
+		if a.Timeout, err = s.Timeout(); err != nil {
+			return nil, err
+		}
 		res = append(res, *a)
 	}
 	return res, nil
@@ -682,10 +884,73 @@ func computeJob(name string, j Job) (*sdk.Job, error) {
 			name = r.Service.Name
 			val = r.Service.Value
 			tpe = sdk.ServiceRequirement
+		} else if r.OS != "" {
+			name = "os"
+			val = r.OS
+			tpe = sdk.OSRequirement
+		} else if r.Arch != "" {
+			name = "arch"
+			val = r.Arch
+			tpe = sdk.ArchRequirement
 		}
 		job.Action.Requirement(name, tpe, val)
 	}
 
+	//Compute matrix axes
+	axes := []string{}
+	for n := range j.Matrix {
+		axes = append(axes, n)
+	}
+	sort.Strings(axes)
+	for _, n := range axes {
+		a := j.Matrix[n]
+		if len(a.Values) == 0 {
+			return nil, fmt.Errorf("Malformatted matrix axis %s on job %s", n, name)
+		}
+		axis := sdk.MatrixAxis{Name: n, Values: a.Values}
+		if len(a.AllowFailure) > 0 {
+			axis.AllowFailure = a.AllowFailure
+		}
+		if len(a.Models) > 0 {
+			axis.Models = a.Models
+		}
+		job.Matrix = append(job.Matrix, axis)
+	}
+
+	timeout, err := parseTimeout(j.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	job.Timeout = timeout
+
+	//Compute outputs
+	outputs := []string{}
+	for n := range j.Outputs {
+		outputs = append(outputs, n)
+	}
+	sort.Strings(outputs)
+	for _, n := range outputs {
+		job.Outputs = append(job.Outputs, sdk.JobOutput{Name: n, Type: j.Outputs[n].Type, Description: j.Outputs[n].Description})
+	}
+	if err := sdk.CheckJobOutputs(job.Outputs); err != nil {
+		return nil, fmt.Errorf("Malformatted outputs on job %s: %s", name, err)
+	}
+
+	if j.Retry != nil {
+		backoff, err := parseTimeout(j.Retry.Backoff)
+		if err != nil {
+			return nil, err
+		}
+		job.Retry = &sdk.JobRetry{
+			MaxAttempts: j.Retry.MaxAttempts,
+			Backoff:     backoff,
+			On:          j.Retry.On,
+		}
+		if err := sdk.CheckJobRetry(job.Retry); err != nil {
+			return nil, fmt.Errorf("Malformatted retry policy on job %s: %s", name, err)
+		}
+	}
+
 	//Compute steps for the jobs
 	children, err := computeSteps(j.Steps)
 	if err != nil {
@@ -220,6 +220,10 @@ var (
 		arg: sdk.Pipeline{
 			Name: "MyPipeline t2_2",
 			Type: sdk.BuildPipeline,
+			Concurrency: &sdk.Concurrency{
+				Key:    "{{.cds.application}}-{{.git.branch}}",
+				Policy: sdk.ConcurrencyCancelPrevious,
+			},
 			GroupPermission: []sdk.GroupPermission{
 				sdk.GroupPermission{
 					Group: sdk.Group{
@@ -308,6 +312,12 @@ var (
 					BuildOrder: 2,
 					Name:       "stage 2",
 					Enabled:    true,
+					Condition:  `git.branch == "master" or param2 in ["a", "b"]`,
+					Approval: &sdk.StageApproval{
+						Groups:   []string{"release-managers"},
+						Required: 2,
+						Timeout:  3600,
+					},
 					Prerequisites: []sdk.Prerequisite{
 						{
 							Parameter:     "param1",
@@ -394,7 +404,59 @@ var (
 		},
 	}
 
-	testcases = []pipelineTestCase{t1_1, t1_2, t2_2}
+	t1_3 = pipelineTestCase{
+		name: "Pipeline with 1 stage and 1 job with a matrix",
+		arg: sdk.Pipeline{
+			Name: "MyPipeline t1_3",
+			Type: sdk.BuildPipeline,
+			Stages: []sdk.Stage{
+				{
+					BuildOrder: 1,
+					Name:       "MyPipeline t1_3",
+					Enabled:    true,
+					Jobs: []sdk.Job{
+						{
+							Enabled: true,
+							Timeout: 3600,
+							Retry: &sdk.JobRetry{
+								MaxAttempts: 3,
+								Backoff:     30,
+								On:          []string{sdk.FailureWorkerLost, sdk.FailureSpawnError},
+							},
+							Outputs: []sdk.JobOutput{
+								{Name: "coverage", Type: sdk.OutputNumber},
+								{Name: "version", Type: sdk.OutputString, Description: "Version of the tested code"},
+							},
+							Matrix: []sdk.MatrixAxis{
+								{Name: "go", Values: []string{"1.8", "tip"}, AllowFailure: []string{"tip"}, Models: map[string]string{"tip": "Go_tip"}},
+								{Name: "os", Values: []string{"linux", "darwin"}},
+							},
+							Action: sdk.Action{
+								Name: "Test",
+								Actions: []sdk.Action{
+									{
+										Type:    sdk.BuiltinAction,
+										Name:    sdk.ScriptAction,
+										Enabled: true,
+										Timeout: 600,
+										Parameters: []sdk.Parameter{
+											{
+												Name:  "script",
+												Type:  sdk.TextParameter,
+												Value: "go test ./...",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testcases = []pipelineTestCase{t1_1, t1_2, t2_2, t1_3}
 )
 
 func TestExportPipeline_YAML(t *testing.T) {
@@ -440,6 +502,7 @@ func TestExportAndImportPipeline_YAML(t *testing.T) {
 
 		assert.Equal(t, tc.arg.Name, transformedP.Name)
 		assert.Equal(t, tc.arg.Type, transformedP.Type)
+		assert.Equal(t, tc.arg.Concurrency, transformedP.Concurrency)
 		test.EqualValuesWithoutOrder(t, tc.arg.GroupPermission, transformedP.GroupPermission)
 		test.EqualValuesWithoutOrder(t, tc.arg.Parameter, transformedP.Parameter)
 		for _, s := range tc.arg.Stages {
@@ -453,6 +516,8 @@ func TestExportAndImportPipeline_YAML(t *testing.T) {
 				assert.Equal(t, s.BuildOrder, s1.BuildOrder, "Build order does not match")
 				assert.Equal(t, s.Enabled, s1.Enabled, "Enabled does not match")
 				test.EqualValuesWithoutOrder(t, s.Prerequisites, s1.Prerequisites)
+				assert.Equal(t, s.Condition, s1.Condition, "Condition does not match")
+				assert.Equal(t, s.Approval, s1.Approval, "Approval does not match")
 
 				for _, j := range s.Jobs {
 					var jobFound bool
@@ -466,12 +531,17 @@ func TestExportAndImportPipeline_YAML(t *testing.T) {
 						assert.Equal(t, j.Action.Name, j1.Action.Name)
 						assert.Equal(t, j.Enabled, j1.Action.Enabled)
 						assert.Equal(t, j.Action.Final, j1.Action.Final)
+						assert.Equal(t, j.Matrix, j1.Matrix)
+						assert.Equal(t, j.Timeout, j1.Timeout)
+						assert.Equal(t, j.Outputs, j1.Outputs)
+						assert.Equal(t, j.Retry, j1.Retry)
 
 						for i, s := range j.Action.Actions {
 							s1 := j1.Action.Actions[i]
 							if s.Name == s1.Name {
 								assert.Equal(t, s.Enabled, s1.Enabled, s.Name, s1.Name)
 								assert.Equal(t, s.Final, s1.Final)
+								assert.Equal(t, s.Timeout, s1.Timeout)
 								test.EqualValuesWithoutOrder(t, s.Parameters, s1.Parameters)
 							}
 						}
@@ -484,3 +554,23 @@ func TestExportAndImportPipeline_YAML(t *testing.T) {
 		}
 	}
 }
+
+func TestParseTimeout(t *testing.T) {
+	for v, expected := range map[interface{}]int64{
+		"":      0,
+		"90":    90,
+		"1h30m": 5400,
+		"10m0s": 600,
+		120:     120,
+		30.0:    30,
+	} {
+		n, err := parseTimeout(v)
+		test.NoError(t, err)
+		assert.Equal(t, expected, n, "%v", v)
+	}
+
+	_, err := parseTimeout("ten minutes")
+	assert.Error(t, err)
+	_, err = parseTimeout(true)
+	assert.Error(t, err)
+}
@@ -13,4 +13,6 @@ type Hatchery struct {
 	GroupID  int64     `json:"group_id"`
 	LastBeat time.Time `json:"-"`
 	Model    Model     `json:"model"`
+	// ModelType is the type of the models the hatchery spawns, empty for hatcheries which do not declare it
+	ModelType string `json:"model_type,omitempty"`
 }
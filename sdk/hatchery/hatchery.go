@@ -26,6 +26,9 @@ type Interface interface {
 var (
 	// Client is a CDS Client
 	Client sdk.HTTPClient
+
+	// dryRunMode is set when the hatchery only logs the workers it would spawn and kill
+	dryRunMode bool
 )
 
 // CheckRequirement checks binary requirement in path
@@ -81,6 +84,16 @@ func routine(h Interface, maxWorkers, provision int, hostname string, timestamp
 	}
 	log.Debug("routine> %d - models received: %d", timestamp, len(models))
 
+	// The workers of the other hatcheries count in the max warm workers of the models with a scaling policy
+	var workers []sdk.Worker
+	if hasScalingPolicy(h, models) {
+		var errw error
+		if workers, errw = sdk.GetWorkers(); errw != nil {
+			log.Warning("routine> %d - error on GetWorkers: %s", timestamp, errw)
+			return nil, errw
+		}
+	}
+
 	spawnedIDs := []int64{}
 	wg := &sync.WaitGroup{}
 
@@ -122,7 +135,25 @@ func routine(h Interface, maxWorkers, provision int, hostname string, timestamp
 			}
 
 			for _, model := range models {
+				// Jobs pinning a version of the model are run by a worker of this version
+				model, errv := modelForJob(model, job)
+				if errv != nil {
+					log.Warning("routine> %d - job %d - cannot load version of model %s pinned by the job: %s", timestamp, job.ID, model.Name, errv)
+					continue
+				}
+
+				// Models with a scaling policy are bounded by their max warm workers, over all hatcheries
+				if model.Scaling != nil && model.Type == h.ModelType() && h.WorkersStartedByModel(&model)+otherHatcheriesWorkers(h, &model, workers) >= model.Scaling.MaxWarm {
+					log.Debug("routine> %d - job %d - model %s reached its max warm workers", timestamp, job.ID, model.Name)
+					continue
+				}
 				if canRunJob(h, timestamp, job, &model, hostname) {
+					if dryRunMode {
+						log.Info("routine> %d - dry-run: would spawn worker %s for job %d", timestamp, model.Name, job.ID)
+						spawnedIDs = append(spawnedIDs, job.ID)
+						break
+					}
+
 					if err := sdk.BookPipelineBuildJob(job.ID); err != nil {
 						// perhaps already booked by another hatchery
 						log.Debug("routine> %d - cannot book job %d %s: %s", timestamp, job.ID, model.Name, err)
@@ -171,77 +202,16 @@ func routine(h Interface, maxWorkers, provision int, hostname string, timestamp
 	return spawnedIDs, nil
 }
 
-func provisioning(h Interface, provision int) {
-	if provision == 0 {
-		log.Debug("provisioning> no provisioning to do")
-		return
-	}
-
-	models, errwm := sdk.GetWorkerModels()
-	if errwm != nil {
-		log.Debug("provisioning> error on GetWorkerModels:%e", errwm)
-		return
-	}
-
-	for k := range models {
-		if h.WorkersStartedByModel(&models[k]) < provision {
-			if models[k].Type == h.ModelType() {
-				go func(m sdk.Model) {
-					if err := h.SpawnWorker(&m, nil); err != nil {
-						log.Warning("provisioning> cannot spawn worker for provisioning: %s", m.Name, err)
-					}
-				}(models[k])
-			}
-		}
-	}
-}
-
 func canRunJob(h Interface, timestamp int64, job *sdk.PipelineBuildJob, model *sdk.Model, hostname string) bool {
 	if model.Type != h.ModelType() {
 		return false
 	}
 
-	// Common check
-	for _, r := range job.Job.Action.Requirements {
-		// If requirement is a Model requirement, it's easy. It's either can or can't run
-		if r.Type == sdk.ModelRequirement && r.Value != model.Name {
-			log.Debug("canRunJob> %d - job %d - model requirement r.Value(%s) != model.Name(%s)", timestamp, job.ID, r.Value, model.Name)
-			return false
-		}
-
-		// If requirement is an hostname requirement, it's for a specific worker
-		if r.Type == sdk.HostnameRequirement && r.Value != hostname {
-			log.Debug("canRunJob> %d - job %d - hostname requirement r.Value(%s) != hostname(%s)", timestamp, job.ID, r.Value, hostname)
-			return false
-		}
-
-		// service and memory requirements are only supported by docker model
-		if model.Type != sdk.Docker && (r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement) {
-			log.Debug("canRunJob> %d - job %d - job with service requirement or memory requirement: only for model docker. current model:%s", timestamp, job.ID, model.Type)
-			return false
-		}
-
-		// Skip network access requirement as we can't check it
-		if r.Type == sdk.NetworkAccessRequirement || r.Type == sdk.PluginRequirement || r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
-			log.Debug("canRunJob> %d - job %d - job with service requirement or memory requirement: only for model docker. current model:%s", timestamp, job.ID, model.Type)
-			continue
-		}
-
-		if r.Type == sdk.BinaryRequirement {
-			found := false
-			// Check binary requirement against worker model capabilities
-			for _, c := range model.Capabilities {
-				if r.Value == c.Value || r.Value == c.Name {
-					found = true
-					break
-				}
-			}
-
-			if !found {
-				log.Debug("canRunJob> %d - job %d - model(%s) does not have binary %s(%s) for this job.", timestamp, job.ID, model.Name, r.Name, r.Value)
-				return false
-			}
+	if rejections := sdk.CheckModelRequirements(model, job, hostname); len(rejections) > 0 {
+		for _, r := range rejections {
+			log.Debug("canRunJob> %d - job %d - %s", timestamp, job.ID, r)
 		}
+		return false
 	}
 
 	return h.CanSpawn(model, job)
@@ -15,8 +15,9 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-// Create creates hatchery
-func Create(h Interface, api, token string, maxWorkers, provision int, requestSecondsTimeout int, maxFailures int, insecureSkipVerifyTLS bool, provisionSeconds, warningSeconds, criticalSeconds, graceSeconds int) {
+// Create creates hatchery. In dry-run mode, the hatchery only logs the workers it would spawn and kill
+func Create(h Interface, api, token string, maxWorkers, provision int, requestSecondsTimeout int, maxFailures int, insecureSkipVerifyTLS bool, provisionSeconds, warningSeconds, criticalSeconds, graceSeconds int, dryRun bool) {
+	dryRunMode = dryRun
 	Client = &http.Client{
 		Transport: &httpcontrol.Transport{
 			RequestTimeout:  time.Duration(requestSecondsTimeout) * time.Second,
@@ -55,7 +56,7 @@ func Create(h Interface, api, token string, maxWorkers, provision int, requestSe
 				log.Warning("Error on routine: %s", errR)
 			}
 		case <-tickerProvision:
-			provisioning(h, provision)
+			provisioning(h, provision, hostname)
 		}
 	}
 }
@@ -130,7 +131,7 @@ func hearbeat(m Interface, token string, maxFailures int) {
 			log.Info("hearbeat> Registered back: ID %d with model ID %d", m.Hatchery().ID, m.Hatchery().Model.ID)
 		}
 
-		if _, _, err := sdk.Request("PUT", fmt.Sprintf("/hatchery/%d", m.Hatchery().ID), nil); err != nil {
+		if _, _, err := sdk.Request("PUT", fmt.Sprintf("/hatchery/%d", m.Hatchery().ID), modelsStatusBody()); err != nil {
 			log.Info("heartbeat> cannot refresh beat: %s", err)
 			m.Hatchery().ID = 0
 			checkFailures(maxFailures, failures)
@@ -0,0 +1,223 @@
+package hatchery
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+var (
+	// workersIdleSince records since when the workers spawned by the hatchery are waiting, by worker ID
+	workersIdleSince = map[string]time.Time{}
+
+	// modelsStatus is the status of the models computed on the last provisioning, sent with the heartbeat
+	modelsStatus = struct {
+		sync.Mutex
+		status []sdk.ModelStatus
+	}{}
+
+	// introspectionSpawned records when a worker was spawned for the introspection of a model, by model ID
+	introspectionSpawned = map[int64]time.Time{}
+)
+
+// introspectionRetry is the delay before spawning another worker for a model introspection still pending
+const introspectionRetry = 10 * time.Minute
+
+// provisioning spawns workers in advance: models with a scaling policy are scaled according to
+// the waiting jobs, the others keep a flat provision of workers
+func provisioning(h Interface, provision int, hostname string) {
+	models, errwm := sdk.GetWorkerModels()
+	if errwm != nil {
+		log.Debug("provisioning> error on GetWorkerModels:%e", errwm)
+		return
+	}
+
+	var jobs []sdk.PipelineBuildJob
+	var workers []sdk.Worker
+	if hasScalingPolicy(h, models) {
+		var errbq, errw error
+		if jobs, errbq = sdk.GetBuildQueue(); errbq != nil {
+			log.Warning("provisioning> error on GetBuildQueue: %s", errbq)
+			return
+		}
+		if workers, errw = sdk.GetWorkers(); errw != nil {
+			log.Warning("provisioning> error on GetWorkers: %s", errw)
+			return
+		}
+	}
+	forgetBusyWorkers(h, workers)
+
+	now := time.Now()
+	status := []sdk.ModelStatus{}
+	for k := range models {
+		m := &models[k]
+		if m.Type != h.ModelType() || m.State == sdk.ModelStateDisabled {
+			continue
+		}
+
+		// Any worker of the model registering does the introspection, spawn one dedicated to it
+		if m.IntrospectionRequested && now.Sub(introspectionSpawned[m.ID]) > introspectionRetry {
+			introspectionSpawned[m.ID] = now
+			provisionWorker(h, *m, "introspection")
+		}
+
+		current := h.WorkersStartedByModel(m)
+		if m.Scaling == nil {
+			if current < provision {
+				provisionWorker(h, *m, "provisioning")
+			}
+			status = append(status, sdk.ModelStatus{ModelID: m.ID, ModelName: m.Name, ModelGroupID: m.GroupID, CurrentCount: int64(current), WantedCount: int64(provision)})
+			continue
+		}
+
+		wanted, building := scale(h, m, current, hostname, jobs, workers, now)
+		status = append(status, sdk.ModelStatus{ModelID: m.ID, ModelName: m.Name, ModelGroupID: m.GroupID, CurrentCount: int64(current), WantedCount: int64(wanted), BuildingCount: int64(building)})
+	}
+
+	modelsStatus.Lock()
+	modelsStatus.status = status
+	modelsStatus.Unlock()
+}
+
+// scale spawns or kills workers of a model with a scaling policy, it returns the wanted workers and the workers building
+func scale(h Interface, m *sdk.Model, current int, hostname string, jobs []sdk.PipelineBuildJob, workers []sdk.Worker, now time.Time) (int, int) {
+	var building int
+	var idle []sdk.Worker
+	for _, w := range workers {
+		if w.Model != m.ID || w.HatcheryID == 0 || w.HatcheryID != h.ID() {
+			continue
+		}
+		switch w.Status {
+		case sdk.StatusBuilding:
+			building++
+		case sdk.StatusWaiting:
+			idle = append(idle, w)
+		}
+	}
+
+	var waiting int
+	var oldest int64
+	for i := range jobs {
+		if jobs[i].BookedBy.ID != 0 || !canRunJob(h, now.Unix(), &jobs[i], m, hostname) {
+			continue
+		}
+		waiting++
+		if jobs[i].QueuedSeconds > oldest {
+			oldest = jobs[i].QueuedSeconds
+		}
+	}
+
+	// Max warm bounds the workers of the model over all hatcheries
+	others := otherHatcheriesWorkers(h, m, workers)
+	wanted := m.Scaling.Wanted(now, building, waiting)
+	if wanted > m.Scaling.MaxWarm-others {
+		wanted = m.Scaling.MaxWarm - others
+		if wanted < 0 {
+			wanted = 0
+		}
+	}
+	n := m.Scaling.SpawnCount(current, wanted, oldest)
+	log.Debug("scale> model %s current:%d wanted:%d building:%d waiting jobs:%d oldest:%ds other hatcheries:%d spawn:%d", m.Name, current, wanted, building, waiting, oldest, others, n)
+	for i := 0; i < n; i++ {
+		provisionWorker(h, *m, "scale")
+	}
+
+	// Kill the workers idle for too long beyond the wanted count
+	excess := current - wanted
+	for _, w := range idle {
+		since, ok := workersIdleSince[w.ID]
+		if !ok {
+			workersIdleSince[w.ID] = now
+			continue
+		}
+		if excess <= 0 || !m.Scaling.IsIdleExpired(since, now) {
+			continue
+		}
+		if dryRunMode {
+			log.Info("scale> dry-run: would kill idle worker %s", w.Name)
+			excess--
+			continue
+		}
+		if err := sdk.DisableWorker(w.ID); err != nil {
+			log.Warning("scale> cannot disable worker %s: %s", w.Name, err)
+			continue
+		}
+		log.Info("scale> Disabled idle worker %s\n", w.Name)
+		if err := h.KillWorker(w); err != nil {
+			log.Warning("scale> cannot kill worker %s: %s", w.Name, err)
+			continue
+		}
+		delete(workersIdleSince, w.ID)
+		excess--
+	}
+
+	return wanted, building
+}
+
+// provisionWorker spawns a worker of the model without job, it is only logged in dry-run mode
+func provisionWorker(h Interface, m sdk.Model, caller string) {
+	if dryRunMode {
+		log.Info("%s> dry-run: would spawn worker %s", caller, m.Name)
+		return
+	}
+
+	go func() {
+		if err := h.SpawnWorker(&m, nil); err != nil {
+			log.Warning("%s> cannot spawn worker %s: %s", caller, m.Name, err)
+		}
+	}()
+}
+
+func hasScalingPolicy(h Interface, models []sdk.Model) bool {
+	for _, m := range models {
+		if m.Type == h.ModelType() && m.Scaling != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// otherHatcheriesWorkers returns the workers of the model registered by the other hatcheries and not disabled
+func otherHatcheriesWorkers(h Interface, m *sdk.Model, workers []sdk.Worker) int {
+	var n int
+	for _, w := range workers {
+		if w.Model == m.ID && w.HatcheryID != h.ID() && w.Status != sdk.StatusDisabled {
+			n++
+		}
+	}
+	return n
+}
+
+// forgetBusyWorkers removes from workersIdleSince the workers which are no longer waiting
+func forgetBusyWorkers(h Interface, workers []sdk.Worker) {
+	waiting := map[string]bool{}
+	for _, w := range workers {
+		if w.HatcheryID == h.ID() && w.Status == sdk.StatusWaiting {
+			waiting[w.ID] = true
+		}
+	}
+	for id := range workersIdleSince {
+		if !waiting[id] {
+			delete(workersIdleSince, id)
+		}
+	}
+}
+
+// modelsStatusBody returns the status of the models to send with the heartbeat, none in dry-run mode
+func modelsStatusBody() []byte {
+	modelsStatus.Lock()
+	defer modelsStatus.Unlock()
+	if dryRunMode || modelsStatus.status == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(modelsStatus.status)
+	if err != nil {
+		log.Warning("modelsStatusBody> cannot marshal status: %s", err)
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,38 @@
+package hatchery
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// modelVersions caches the versions of models pinned by jobs, versions are immutable
+var modelVersions = struct {
+	sync.Mutex
+	versions map[string]sdk.ModelVersion
+}{versions: map[string]sdk.ModelVersion{}}
+
+// modelForJob returns the model at the version pinned by the job, or the model itself if the job does not pin its version
+func modelForJob(m sdk.Model, job *sdk.PipelineBuildJob) (sdk.Model, error) {
+	version := sdk.PinnedModelVersion(job.Job.Action.Requirements, m.Name)
+	if version == 0 || version == m.Version {
+		return m, nil
+	}
+
+	key := fmt.Sprintf("%d@%d", m.ID, version)
+	modelVersions.Lock()
+	v, ok := modelVersions.versions[key]
+	modelVersions.Unlock()
+	if !ok {
+		pv, err := sdk.GetWorkerModelVersion(m.ID, version)
+		if err != nil {
+			return m, err
+		}
+		v = *pv
+		modelVersions.Lock()
+		modelVersions.versions[key] = v
+		modelVersions.Unlock()
+	}
+	return m.WithVersion(v), nil
+}
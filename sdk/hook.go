@@ -4,20 +4,49 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 )
 
+// HookKindGeneric is the kind of hooks which are not linked to a repository
+const HookKindGeneric = "generic"
+
+// HookSignatureHeader is the header containing the HMAC-SHA256 signature of generic hooks payload,
+// formatted as sha256=<hex digest>
+const HookSignatureHeader = "X-Cds-Signature"
+
 // Hook used to link a git repository to a given pipeline
 type Hook struct {
-	ID            int64    `json:"id"`
-	UID           string   `json:"uid"`
-	Pipeline      Pipeline `json:"pipeline"`
-	ApplicationID int64    `json:"application_id"`
-	Kind          string   `json:"kind"`
-	Host          string   `json:"host"`
-	Project       string   `json:"project"`
-	Repository    string   `json:"repository"`
-	Enabled       bool     `json:"enabled"`
-	Link          string   `json:"link"`
+	ID            int64         `json:"id"`
+	UID           string        `json:"uid"`
+	Pipeline      Pipeline      `json:"pipeline"`
+	ApplicationID int64         `json:"application_id"`
+	Kind          string        `json:"kind"`
+	Host          string        `json:"host"`
+	Project       string        `json:"project"`
+	Repository    string        `json:"repository"`
+	Enabled       bool          `json:"enabled"`
+	Link          string        `json:"link"`
+	Secret        string        `json:"secret,omitempty"`
+	Environment   string        `json:"environment,omitempty"`
+	Mapping       []HookMapping `json:"mapping,omitempty"`
+	// RemoveSecret removes the secret of the hook on update, an empty secret keeps it
+	RemoveSecret bool `json:"remove_secret,omitempty"`
+}
+
+// HookMapping maps a value of a generic hook payload to a pipeline parameter.
+// Path is a JSONPath-style expression like $.repository.tags[0].name
+type HookMapping struct {
+	Parameter string `json:"parameter"`
+	Path      string `json:"path"`
+}
+
+// ReceivedHook is a payload received by CDS on a hook
+type ReceivedHook struct {
+	ID      int64     `json:"id"`
+	UID     string    `json:"uid"`
+	Link    string    `json:"link"`
+	Data    string    `json:"data"`
+	Created time.Time `json:"created"`
 }
 
 // AddHook creates a new hook between a pipeline and a repository
@@ -92,3 +121,92 @@ func DeleteHook(project, application, pipeline string, id int64) error {
 
 	return nil
 }
+
+// AddGenericHook creates a new hook on a pipeline which can be called by any third party
+func AddGenericHook(a *Application, p *Pipeline, env string, secret string, mapping []HookMapping) (*Hook, error) {
+	h := Hook{
+		Pipeline:      *p,
+		ApplicationID: a.ID,
+		Kind:          HookKindGeneric,
+		Environment:   env,
+		Secret:        secret,
+		Mapping:       mapping,
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/hook", p.ProjectKey, a.Name, p.Name)
+	data, code, err := Request("POST", uri, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	//API returns the application with all its hooks, the new one has the greatest id
+	var app Application
+	if err := json.Unmarshal(data, &app); err != nil {
+		return nil, err
+	}
+
+	var res *Hook
+	for i := range app.Hooks {
+		if app.Hooks[i].Kind != HookKindGeneric || app.Hooks[i].Pipeline.Name != p.Name {
+			continue
+		}
+		if res == nil || app.Hooks[i].ID > res.ID {
+			res = &app.Hooks[i]
+		}
+	}
+	if res == nil {
+		return nil, ErrNoHook
+	}
+
+	return res, nil
+}
+
+// GetReceivedHooks lists the last payloads received on a hook
+func GetReceivedHooks(project, application, pipeline string, id int64) ([]ReceivedHook, error) {
+	var received []ReceivedHook
+
+	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/hook/%d/received", project, application, pipeline, id)
+	data, code, err := Request("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	if err := json.Unmarshal(data, &received); err != nil {
+		return nil, err
+	}
+
+	return received, nil
+}
+
+// ReplayReceivedHook processes again a payload received on a hook
+func ReplayReceivedHook(project, application, pipeline string, id, receivedID int64) (*PipelineBuild, error) {
+	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/hook/%d/received/%d/replay", project, application, pipeline, id, receivedID)
+	data, code, err := Request("POST", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	pb := &PipelineBuild{}
+	if err := json.Unmarshal(data, pb); err != nil {
+		return nil, err
+	}
+
+	return pb, nil
+}
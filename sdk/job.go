@@ -1,10 +1,193 @@
 package sdk
 
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
 // Job is the element of a stage
 type Job struct {
-	PipelineActionID int64  `json:"pipeline_action_id"`
-	PipelineStageID  int64  `json:"pipeline_stage_id"`
-	Enabled          bool   `json:"enabled"`
-	LastModified     int64  `json:"last_modified"`
-	Action           Action `json:"action"`
+	PipelineActionID int64        `json:"pipeline_action_id"`
+	PipelineStageID  int64        `json:"pipeline_stage_id"`
+	Enabled          bool         `json:"enabled"`
+	LastModified     int64        `json:"last_modified"`
+	Action           Action       `json:"action"`
+	Matrix           []MatrixAxis `json:"matrix,omitempty"`
+	Timeout          int64        `json:"timeout,omitempty"` // in seconds, 0 means no timeout
+	Outputs          []JobOutput  `json:"outputs,omitempty"`
+	Retry            *JobRetry    `json:"retry,omitempty"`
+}
+
+// MatrixAxis is a dimension of a job matrix: the job runs once for each combination of the values of all axes.
+// Models sets the worker model requirement of the cells with the given value, and failures of the cells with
+// a value in AllowFailure don't fail the stage
+type MatrixAxis struct {
+	Name         string            `json:"name"`
+	Values       []string          `json:"values"`
+	AllowFailure []string          `json:"allow_failure,omitempty"`
+	Models       map[string]string `json:"models,omitempty"`
+}
+
+// MatrixCell is one combination of values of a job matrix
+type MatrixCell struct {
+	Axes         []string `json:"axes"`
+	Values       []string `json:"values"`
+	Model        string   `json:"model,omitempty"`
+	AllowFailure bool     `json:"allow_failure,omitempty"`
+}
+
+// ExpandMatrix returns all the cells of the matrix, the values of the last axis vary first.
+// It returns ErrInvalidJobMatrix if an axis has no name or no value
+func ExpandMatrix(axes []MatrixAxis) ([]MatrixCell, error) {
+	if len(axes) == 0 {
+		return nil, nil
+	}
+
+	cells := []MatrixCell{{}}
+	for _, axis := range axes {
+		// an axis without value would expand the job to nothing
+		if axis.Name == "" || len(axis.Values) == 0 {
+			return nil, ErrInvalidJobMatrix
+		}
+		expanded := make([]MatrixCell, 0, len(cells)*len(axis.Values))
+		for _, c := range cells {
+			for _, v := range axis.Values {
+				cell := MatrixCell{
+					Axes:         append(append([]string{}, c.Axes...), axis.Name),
+					Values:       append(append([]string{}, c.Values...), v),
+					Model:        c.Model,
+					AllowFailure: c.AllowFailure,
+				}
+				if m, ok := axis.Models[v]; ok {
+					cell.Model = m
+				}
+				for _, f := range axis.AllowFailure {
+					if f == v {
+						cell.AllowFailure = true
+					}
+				}
+				expanded = append(expanded, cell)
+			}
+		}
+		cells = expanded
+	}
+	return cells, nil
+}
+
+// String returns the values of the cell, ie: "go=1.8, os=linux"
+func (c MatrixCell) String() string {
+	res := make([]string, len(c.Axes))
+	for i := range c.Axes {
+		res[i] = fmt.Sprintf("%s=%s", c.Axes[i], c.Values[i])
+	}
+	return strings.Join(res, ", ")
+}
+
+// Parameters returns the values of the cell as cds.matrix.<axis> parameters
+func (c MatrixCell) Parameters() []Parameter {
+	params := make([]Parameter, len(c.Axes))
+	for i := range c.Axes {
+		params[i] = Parameter{
+			Name:  "cds.matrix." + c.Axes[i],
+			Type:  StringParameter,
+			Value: c.Values[i],
+		}
+	}
+	return params
+}
+
+// Job output types
+const (
+	OutputString  = "string"
+	OutputNumber  = "number"
+	OutputBoolean = "boolean"
+	OutputJSON    = "json"
+)
+
+// JobOutput is a typed value declared by a job and set during its execution with `worker output <name> <value>`.
+// It is available to the next stages and to the triggered pipelines as {{.cds.stage.<stage>.job.<job>.output.<name>}}
+type JobOutput struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Value       string `json:"value,omitempty"`
+}
+
+var jobOutputNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// CheckJobOutputs checks the names and the types of the outputs declared by a job
+func CheckJobOutputs(outputs []JobOutput) error {
+	names := map[string]bool{}
+	for _, o := range outputs {
+		if !jobOutputNameRegexp.MatchString(o.Name) {
+			return fmt.Errorf("invalid output name '%s', only letters, digits, '-' and '_' are allowed", o.Name)
+		}
+		if names[o.Name] {
+			return fmt.Errorf("output %s is declared twice", o.Name)
+		}
+		names[o.Name] = true
+
+		switch o.Type {
+		case "", OutputString, OutputNumber, OutputBoolean, OutputJSON:
+		default:
+			return fmt.Errorf("invalid type '%s' for output %s, expected %s, %s, %s or %s", o.Type, o.Name, OutputString, OutputNumber, OutputBoolean, OutputJSON)
+		}
+	}
+	return nil
+}
+
+// CheckValue checks the value matches the type of the output, an output without type is a string
+func (o JobOutput) CheckValue(value string) error {
+	var err error
+	switch o.Type {
+	case OutputNumber:
+		_, err = strconv.ParseFloat(value, 64)
+	case OutputBoolean:
+		_, err = strconv.ParseBool(value)
+	case OutputJSON:
+		var v interface{}
+		err = json.Unmarshal([]byte(value), &v)
+	}
+	if err != nil {
+		return fmt.Errorf("value of output %s is not a valid %s: %s", o.Name, o.Type, err)
+	}
+	return nil
+}
+
+// ResolveJobOutputs returns the declared outputs with their values. It fails if a declared output is missing or has a
+// value which does not match its type. Values of outputs which are not declared are ignored
+func ResolveJobOutputs(declared []JobOutput, values []JobOutput) ([]JobOutput, error) {
+	res := make([]JobOutput, 0, len(declared))
+	missing := []string{}
+	for _, d := range declared {
+		found := false
+		for _, v := range values {
+			if v.Name != d.Name {
+				continue
+			}
+			if err := d.CheckValue(v.Value); err != nil {
+				return nil, err
+			}
+			d.Value = v.Value
+			found = true
+		}
+		if !found {
+			missing = append(missing, d.Name)
+			continue
+		}
+		res = append(res, d)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("output(s) %s not set, use 'worker output <name> <value>'", strings.Join(missing, ", "))
+	}
+	return res, nil
+}
+
+// JobOutputParameterName returns the name of the build parameter holding an output of a job,
+// ie: cds.stage.Build.job.Compile.output.version
+func JobOutputParameterName(stage, job, output string) string {
+	return fmt.Sprintf("cds.stage.%s.job.%s.output.%s", stage, job, output)
 }
@@ -0,0 +1,102 @@
+package sdk
+
+import (
+	"testing"
+)
+
+func TestExpandMatrix(t *testing.T) {
+	cells, err := ExpandMatrix([]MatrixAxis{
+		{Name: "go", Values: []string{"1.8", "tip"}, AllowFailure: []string{"tip"}, Models: map[string]string{"1.8": "Go_1.8", "tip": "Go_tip"}},
+		{Name: "os", Values: []string{"linux", "darwin"}},
+	})
+	if err != nil {
+		t.Fatalf("ExpandMatrix() returns %s", err)
+	}
+
+	expected := []struct {
+		name         string
+		model        string
+		allowFailure bool
+	}{
+		{"go=1.8, os=linux", "Go_1.8", false},
+		{"go=1.8, os=darwin", "Go_1.8", false},
+		{"go=tip, os=linux", "Go_tip", true},
+		{"go=tip, os=darwin", "Go_tip", true},
+	}
+	if len(cells) != len(expected) {
+		t.Fatalf("ExpandMatrix() returns %d cells, want %d", len(cells), len(expected))
+	}
+	for i, e := range expected {
+		if cells[i].String() != e.name || cells[i].Model != e.model || cells[i].AllowFailure != e.allowFailure {
+			t.Errorf("cell %d = %s (%s, %v), want %s (%s, %v)", i, cells[i], cells[i].Model, cells[i].AllowFailure, e.name, e.model, e.allowFailure)
+		}
+	}
+
+	params := cells[3].Parameters()
+	if len(params) != 2 || params[0].Name != "cds.matrix.go" || params[0].Value != "tip" || params[1].Name != "cds.matrix.os" || params[1].Value != "darwin" {
+		t.Errorf("Unexpected parameters: %v", params)
+	}
+
+	if cells, err := ExpandMatrix(nil); cells != nil || err != nil {
+		t.Errorf("ExpandMatrix(nil) should be nil")
+	}
+
+	if _, err := ExpandMatrix([]MatrixAxis{{Name: "go", Values: []string{"1.8"}}, {Name: "os"}}); err != ErrInvalidJobMatrix {
+		t.Errorf("ExpandMatrix() with an empty axis should return ErrInvalidJobMatrix, got %v", err)
+	}
+}
+
+func TestCheckJobOutputs(t *testing.T) {
+	valid := []JobOutput{{Name: "version", Type: OutputString}, {Name: "coverage", Type: OutputNumber}, {Name: "report_url"}}
+	if err := CheckJobOutputs(valid); err != nil {
+		t.Errorf("CheckJobOutputs() returns %s", err)
+	}
+
+	invalid := [][]JobOutput{
+		{{Name: "my output", Type: OutputString}},
+		{{Name: "version", Type: "date"}},
+		{{Name: "version"}, {Name: "version"}},
+	}
+	for _, outputs := range invalid {
+		if err := CheckJobOutputs(outputs); err == nil {
+			t.Errorf("CheckJobOutputs(%v) should fail", outputs)
+		}
+	}
+}
+
+func TestResolveJobOutputs(t *testing.T) {
+	declared := []JobOutput{
+		{Name: "version", Type: OutputString},
+		{Name: "coverage", Type: OutputNumber},
+		{Name: "released", Type: OutputBoolean},
+		{Name: "metadata", Type: OutputJSON},
+	}
+
+	outputs, err := ResolveJobOutputs(declared, []JobOutput{
+		{Name: "metadata", Value: `{"arch": "amd64"}`},
+		{Name: "released", Value: "true"},
+		{Name: "coverage", Value: "87.5"},
+		{Name: "version", Value: "1.2.0"},
+		{Name: "unknown", Value: "foo"},
+	})
+	if err != nil {
+		t.Fatalf("ResolveJobOutputs() returns %s", err)
+	}
+	if len(outputs) != 4 || outputs[0].Name != "version" || outputs[0].Value != "1.2.0" || outputs[1].Type != OutputNumber || outputs[1].Value != "87.5" {
+		t.Errorf("Unexpected outputs: %v", outputs)
+	}
+
+	if _, err := ResolveJobOutputs(declared, []JobOutput{{Name: "version", Value: "1.2.0"}}); err == nil || err.Error() != "output(s) coverage, released, metadata not set, use 'worker output <name> <value>'" {
+		t.Errorf("ResolveJobOutputs() with missing outputs returns %v", err)
+	}
+
+	for _, o := range []JobOutput{{Name: "coverage", Value: "high"}, {Name: "released", Value: "yes"}, {Name: "metadata", Value: "{"}} {
+		if _, err := ResolveJobOutputs(declared, []JobOutput{o}); err == nil {
+			t.Errorf("ResolveJobOutputs() should fail with %s=%s", o.Name, o.Value)
+		}
+	}
+
+	if n := JobOutputParameterName("Build", "Compile", "version"); n != "cds.stage.Build.job.Compile.output.version" {
+		t.Errorf("JobOutputParameterName() = %s", n)
+	}
+}
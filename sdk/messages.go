@@ -56,6 +56,8 @@ var (
 	MsgSpawnInfoWorkerEnd                  = &Message{"MsgSpawnInfoWorkerEnd", trad{FR: "Le worker %s a terminé et a passé %s à travailler sur les étapes", EN: "Worker %s finished working on this job and took %s to work on the steps"}, nil}
 	MsgSpawnInfoJobTaken                   = &Message{"MsgSpawnInfoJobTaken", trad{FR: "Le job a été pris par le worker %s", EN: "Job was taken by worker %s"}, nil}
 	MsgSpawnInfoWorkerForJob               = &Message{"MsgSpawnInfoWorkerForJob", trad{FR: "Ce worker %s a été créé pour lancer ce job", EN: "This worker %s was created to take this action"}, nil}
+	MsgSpawnInfoJobTimeout                 = &Message{"MsgSpawnInfoJobTimeout", trad{FR: "Le job a été arrêté car il a dépassé son timeout de %s", EN: "Job was killed because it exceeded its timeout of %s"}, nil}
+	MsgSpawnInfoJobOutputError             = &Message{"MsgSpawnInfoJobOutputError", trad{FR: "Le job a échoué car ses sorties sont invalides : %s", EN: "Job failed because of its outputs: %s"}, nil}
 )
 
 // Messages contains all sdk Messages
@@ -97,6 +99,8 @@ var Messages = map[string]*Message{
 	MsgSpawnInfoWorkerEnd.ID:                  MsgSpawnInfoWorkerEnd,
 	MsgSpawnInfoJobTaken.ID:                   MsgSpawnInfoJobTaken,
 	MsgSpawnInfoWorkerForJob.ID:               MsgSpawnInfoWorkerForJob,
+	MsgSpawnInfoJobTimeout.ID:                 MsgSpawnInfoJobTimeout,
+	MsgSpawnInfoJobOutputError.ID:             MsgSpawnInfoJobOutputError,
 }
 
 //Message represent a struc format translated messages
@@ -28,6 +28,7 @@ type Pipeline struct {
 	AttachedApplication []Application     `json:"attached_application,omitempty"`
 	Permission          int               `json:"permission"`
 	LastModified        int64             `json:"last_modified"`
+	Concurrency         *Concurrency      `json:"concurrency,omitempty"`
 }
 
 // PipelineBuild Struct for history table
@@ -41,6 +42,10 @@ type PipelineBuild struct {
 	Done        time.Time   `json:"done,omitempty"`
 	Stages      []Stage     `json:"stages"`
 
+	// ConcurrencyKey is the concurrency group of the build, WaitingReason explains why it is held
+	ConcurrencyKey string `json:"concurrency_key,omitempty"`
+	WaitingReason  string `json:"waiting_reason,omitempty"`
+
 	Pipeline    Pipeline    `json:"pipeline"`
 	Application Application `json:"application"`
 	Environment Environment `json:"environment"`
@@ -268,6 +273,40 @@ func RestartPipeline(key, app, pip, env string, bn int) (chan Log, error) {
 	return StreamPipelineBuild(key, app, pip, env, bn, false)
 }
 
+// StopPipelineBuild stops all running jobs of a pipeline build, downstream pipelines are not triggered
+func StopPipelineBuild(key, app, pip, env string, bn int) error {
+	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/build/%d/stop?envName=%s", key, app, pip, bn, url.QueryEscape(env))
+
+	data, code, err := Request("POST", uri, nil)
+	if err != nil {
+		return err
+	}
+	if e := DecodeError(data); e != nil {
+		return e
+	}
+	if code > 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+// StopPipelineBuildJob stops a job of a pipeline build
+func StopPipelineBuildJob(key, app, pip, env string, bn int, jobID int64) error {
+	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/build/%d/job/%d/stop?envName=%s", key, app, pip, bn, jobID, url.QueryEscape(env))
+
+	data, code, err := Request("POST", uri, nil)
+	if err != nil {
+		return err
+	}
+	if e := DecodeError(data); e != nil {
+		return e
+	}
+	if code > 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
 //GetPipelineCommits returns list of commit between this build and the previous
 //one the same branch. If previous build is not available, it returns only the
 //last commit for the branch
@@ -0,0 +1,73 @@
+package sdk
+
+import (
+	"strings"
+)
+
+// osAliases maps the usual names of operating systems to their GOOS names
+var osAliases = map[string]string{
+	"osx":   "darwin",
+	"macos": "darwin",
+	"mac":   "darwin",
+	"win":   "windows",
+}
+
+// archAliases maps the names of CPU architectures given by uname to their GOARCH names
+var archAliases = map[string]string{
+	"x86_64":  "amd64",
+	"x64":     "amd64",
+	"aarch64": "arm64",
+	"armv8":   "arm64",
+	"armv7l":  "arm",
+	"armv7":   "arm",
+	"armv6l":  "arm",
+	"armhf":   "arm",
+	"i386":    "386",
+	"i686":    "386",
+	"x86":     "386",
+}
+
+// NormalizeOS returns the GOOS name of an operating system: "macos" is "darwin"
+func NormalizeOS(os string) string {
+	os = strings.ToLower(strings.TrimSpace(os))
+	if n, ok := osAliases[os]; ok {
+		return n
+	}
+	return os
+}
+
+// NormalizeArch returns the GOARCH name of a CPU architecture: "x86_64" is "amd64", "aarch64" is "arm64"
+func NormalizeArch(arch string) string {
+	arch = strings.ToLower(strings.TrimSpace(arch))
+	if n, ok := archAliases[arch]; ok {
+		return n
+	}
+	return arch
+}
+
+// PlatformCapabilities returns the os and arch capabilities of a worker running on goos and goarch
+func PlatformCapabilities(goos, goarch string) []Requirement {
+	var capas []Requirement
+	if goos != "" {
+		capas = append(capas, Requirement{Name: "os", Type: OSRequirement, Value: NormalizeOS(goos)})
+	}
+	if goarch != "" {
+		capas = append(capas, Requirement{Name: "arch", Type: ArchRequirement, Value: NormalizeArch(goarch)})
+	}
+	return capas
+}
+
+// MatchPlatformRequirement returns true if an os or arch requirement is satisfied by the capabilities of a model
+func MatchPlatformRequirement(r Requirement, capabilities []Requirement) bool {
+	normalize := NormalizeOS
+	if r.Type == ArchRequirement {
+		normalize = NormalizeArch
+	}
+
+	for _, c := range capabilities {
+		if c.Type == r.Type && normalize(c.Value) == normalize(r.Value) {
+			return true
+		}
+	}
+	return false
+}
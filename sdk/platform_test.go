@@ -0,0 +1,58 @@
+package sdk
+
+import (
+	"testing"
+)
+
+func TestNormalizePlatform(t *testing.T) {
+	archs := map[string]string{
+		"x86_64":  "amd64",
+		"amd64":   "amd64",
+		"AARCH64": "arm64",
+		"arm64":   "arm64",
+		"armv7l":  "arm",
+		"i686":    "386",
+		"ppc64le": "ppc64le",
+	}
+	for arch, want := range archs {
+		if got := NormalizeArch(arch); got != want {
+			t.Errorf("NormalizeArch(%q) = %q, want %q", arch, got, want)
+		}
+	}
+
+	oss := map[string]string{
+		"linux":  "linux",
+		"Linux":  "linux",
+		"macos":  "darwin",
+		"darwin": "darwin",
+	}
+	for os, want := range oss {
+		if got := NormalizeOS(os); got != want {
+			t.Errorf("NormalizeOS(%q) = %q, want %q", os, got, want)
+		}
+	}
+}
+
+func TestMatchPlatformRequirement(t *testing.T) {
+	capas := PlatformCapabilities("linux", "x86_64")
+
+	tests := []struct {
+		r    Requirement
+		want bool
+	}{
+		{Requirement{Name: "os", Type: OSRequirement, Value: "linux"}, true},
+		{Requirement{Name: "os", Type: OSRequirement, Value: "windows"}, false},
+		{Requirement{Name: "arch", Type: ArchRequirement, Value: "amd64"}, true},
+		{Requirement{Name: "arch", Type: ArchRequirement, Value: "x86_64"}, true},
+		{Requirement{Name: "arch", Type: ArchRequirement, Value: "arm64"}, false},
+	}
+	for _, tt := range tests {
+		if got := MatchPlatformRequirement(tt.r, capas); got != tt.want {
+			t.Errorf("MatchPlatformRequirement(%v) = %t, want %t", tt.r, got, tt.want)
+		}
+	}
+
+	if MatchPlatformRequirement(Requirement{Name: "arch", Type: ArchRequirement, Value: "amd64"}, nil) {
+		t.Errorf("models without arch capability must not match arch requirements")
+	}
+}
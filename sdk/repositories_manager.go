@@ -15,6 +15,10 @@ const (
 	Stash RepositoriesManagerType = "STASH"
 	//Github is valued to "GITHUB"
 	Github RepositoriesManagerType = "GITHUB"
+	//Gitlab is valued to "GITLAB"
+	Gitlab RepositoriesManagerType = "GITLAB"
+	//Gitea is valued to "GITEA", it also works with Gogs
+	Gitea RepositoriesManagerType = "GITEA"
 )
 
 //RepositoriesManager is the struct for every repositories manager.
@@ -37,6 +41,7 @@ type RepositoryPoller struct {
 	Application   Application                `json:"application" db:"-"`
 	Pipeline      Pipeline                   `json:"pipeline" db:"-"`
 	Enabled       bool                       `json:"enabled" db:"enabled"`
+	PullRequests  bool                       `json:"pull_requests" db:"pull_requests"`
 	DateCreation  time.Time                  `json:"date_creation" db:"date_creation"`
 	NextExecution *RepositoryPollerExecution `json:"next_execution" db:"-"`
 }
@@ -363,10 +368,12 @@ type VCSDeleteEvent struct {
 
 //VCSPullRequestEvent represents a push events for polling
 type VCSPullRequestEvent struct {
-	Action string       `json:"action"` // opened | closed
-	URL    string       `json:"url"`
-	User   VCSAuthor    `json:"user"`
-	Head   VCSPushEvent `json:"head"`
-	Base   VCSPushEvent `json:"base"`
-	Branch VCSBranch    `json:"branch"`
+	Action   string       `json:"action"` // opened | synchronize | closed
+	Number   int          `json:"number"`
+	URL      string       `json:"url"`
+	MergeRef string       `json:"merge_ref"` //Reference to checkout to build the pull request, like refs/pull/1/merge
+	User     VCSAuthor    `json:"user"`
+	Head     VCSPushEvent `json:"head"`
+	Base     VCSPushEvent `json:"base"`
+	Branch   VCSBranch    `json:"branch"`
 }
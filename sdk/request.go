@@ -89,11 +89,25 @@ func init() {
 		tr := &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 		}
-		client = &http.Client{Transport: tr}
+		client = &http.Client{Transport: tr, CheckRedirect: CheckRedirect}
 		return
 	}
 
-	client = http.DefaultClient
+	client = &http.Client{CheckRedirect: CheckRedirect}
+}
+
+// CheckRedirect follows the redirects of the CDS API, without sending the CDS credentials to another host
+// such as the object store of a presigned artifact URL
+func CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	if req.URL.Host != via[0].URL.Host {
+		req.Header.Del(AuthHeader)
+		req.Header.Del(SessionTokenHeader)
+		req.Header.Del("Authorization")
+	}
+	return nil
 }
 
 func initRequest(req *http.Request) {
@@ -164,8 +178,12 @@ type HTTPClient interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
-//SetHTTPClient aims to change the default http client of the sdk
+//SetHTTPClient aims to change the default http client of the sdk.
+//An *http.Client without redirect policy uses CheckRedirect
 func SetHTTPClient(c HTTPClient) {
+	if hc, ok := c.(*http.Client); ok && hc.CheckRedirect == nil {
+		hc.CheckRedirect = CheckRedirect
+	}
 	client = c
 }
 
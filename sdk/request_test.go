@@ -0,0 +1,44 @@
+package sdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckRedirectStripsCredentials(t *testing.T) {
+	var headers http.Header
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+	}))
+	defer store.Close()
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(AuthHeader) == "" || r.Header.Get(SessionTokenHeader) == "" {
+			t.Errorf("The API must receive the credentials")
+		}
+		http.Redirect(w, r, store.URL+"/bucket/artifact", http.StatusTemporaryRedirect)
+	}))
+	defer api.Close()
+
+	req, err := http.NewRequest("GET", api.URL+"/artifact/download", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(AuthHeader, "hash")
+	req.Header.Set(SessionTokenHeader, "token")
+	req.SetBasicAuth("user", "token")
+
+	c := &http.Client{CheckRedirect: CheckRedirect}
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	for _, h := range []string{AuthHeader, SessionTokenHeader, "Authorization"} {
+		if v := headers.Get(h); v != "" {
+			t.Errorf("Header %s has been sent to the object store: %s", h, v)
+		}
+	}
+}
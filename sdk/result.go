@@ -6,11 +6,14 @@ import (
 
 // Result refers to an build result after completion
 type Result struct {
-	ID         int64     `json:"id" yaml:"-"`
-	BuildID    int64     `json:"build_id" yaml:"build"`
-	Status     Status    `json:"status"`
-	Version    int64     `json:"version"`
-	Reason     string    `json:"reason"`
-	RemoteTime time.Time `json:"remote_time"`
-	Duration   string    `json:"duration"`
+	ID         int64       `json:"id" yaml:"-"`
+	BuildID    int64       `json:"build_id" yaml:"build"`
+	Status     Status      `json:"status"`
+	Version    int64       `json:"version"`
+	Reason     string      `json:"reason"`
+	RemoteTime time.Time   `json:"remote_time"`
+	Duration   string      `json:"duration"`
+	Outputs    []JobOutput `json:"outputs,omitempty"`
+	// FailureClass is the class of the failure of a failed job, non_zero_exit if empty
+	FailureClass string `json:"failure_class,omitempty"`
 }
@@ -0,0 +1,105 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+)
+
+// Failure classes of a failed job, a retry policy can retry only some of them
+const (
+	// FailureWorkerLost is the failure of a job whose worker disappeared while building
+	FailureWorkerLost = "worker_lost"
+	// FailureSpawnError is the failure of a job for which a hatchery could not spawn a worker
+	FailureSpawnError = "spawn_error"
+	// FailureNonZeroExit is the failure of a step of the job
+	FailureNonZeroExit = "non_zero_exit"
+	// FailureTimeout is the failure of a job or a step which reached its timeout
+	FailureTimeout = "timeout"
+	// FailureMissingOutputs is the failure of a job which did not set all the outputs it declares
+	FailureMissingOutputs = "missing_outputs"
+)
+
+// maxRetryBackoff bounds the delay between two attempts of a job
+const maxRetryBackoff = time.Hour
+
+// JobRetry is the retry policy of a job: a failed job is queued again until it reaches its max attempts.
+// The delay before an attempt is Backoff, doubled after each attempt
+type JobRetry struct {
+	MaxAttempts int      `json:"max_attempts"`
+	Backoff     int64    `json:"backoff,omitempty"` // in seconds
+	On          []string `json:"on,omitempty"`      // failure classes to retry, all if empty
+}
+
+// JobAttempt is a failed attempt of a job which has been retried, with its logs and spawn infos
+type JobAttempt struct {
+	Attempt      int          `json:"attempt"`
+	Status       string       `json:"status"`
+	FailureClass string       `json:"failure_class,omitempty"`
+	Reason       string       `json:"reason,omitempty"`
+	WorkerName   string       `json:"worker_name,omitempty"`
+	StepStatus   []StepStatus `json:"step_status,omitempty"`
+	Start        time.Time    `json:"start"`
+	Done         time.Time    `json:"done"`
+	SpawnInfos   []SpawnInfo  `json:"spawninfos,omitempty"`
+	Logs         []Log        `json:"logs,omitempty"`
+}
+
+// CheckJobRetry checks the retry policy of a job
+func CheckJobRetry(r *JobRetry) error {
+	if r == nil {
+		return nil
+	}
+	if r.MaxAttempts < 1 {
+		return fmt.Errorf("max attempts must be at least 1, got %d", r.MaxAttempts)
+	}
+	if r.Backoff < 0 {
+		return fmt.Errorf("backoff must be positive, got %d", r.Backoff)
+	}
+	for _, c := range r.On {
+		switch c {
+		case FailureWorkerLost, FailureSpawnError, FailureNonZeroExit, FailureTimeout, FailureMissingOutputs:
+		default:
+			return fmt.Errorf("invalid failure class '%s', expected %s, %s, %s, %s or %s", c, FailureWorkerLost, FailureSpawnError, FailureNonZeroExit, FailureTimeout, FailureMissingOutputs)
+		}
+	}
+	return nil
+}
+
+// Handles returns true if the policy retries the failure class
+func (r *JobRetry) Handles(class string) bool {
+	if r == nil {
+		return false
+	}
+	if len(r.On) == 0 {
+		return true
+	}
+	for _, c := range r.On {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// ShouldRetry returns true if a job which failed with the failure class after the given number of attempts must be retried
+func (r *JobRetry) ShouldRetry(class string, attempts int) bool {
+	if r == nil || class == "" {
+		return false
+	}
+	return attempts < r.MaxAttempts && r.Handles(class)
+}
+
+// Delay returns the delay to wait after the given number of attempts before the next one
+func (r *JobRetry) Delay(attempts int) time.Duration {
+	if r == nil || r.Backoff <= 0 || attempts < 1 {
+		return 0
+	}
+	d := time.Duration(r.Backoff) * time.Second
+	for i := 1; i < attempts && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return d
+}
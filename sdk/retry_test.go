@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJobRetryShouldRetry(t *testing.T) {
+	r := &JobRetry{MaxAttempts: 3, On: []string{FailureWorkerLost, FailureSpawnError}}
+
+	tests := []struct {
+		class    string
+		attempts int
+		want     bool
+	}{
+		{FailureWorkerLost, 1, true},
+		{FailureSpawnError, 2, true},
+		{FailureWorkerLost, 3, false},
+		{FailureNonZeroExit, 1, false},
+		{FailureTimeout, 1, false},
+		{"", 1, false},
+	}
+	for _, tt := range tests {
+		if got := r.ShouldRetry(tt.class, tt.attempts); got != tt.want {
+			t.Errorf("ShouldRetry(%q, %d) = %v, want %v", tt.class, tt.attempts, got, tt.want)
+		}
+	}
+
+	all := &JobRetry{MaxAttempts: 2}
+	if !all.ShouldRetry(FailureNonZeroExit, 1) {
+		t.Errorf("a policy without failure classes must retry all failures")
+	}
+
+	var none *JobRetry
+	if none.ShouldRetry(FailureWorkerLost, 1) {
+		t.Errorf("a job without retry policy must not be retried")
+	}
+}
+
+func TestJobRetryDelay(t *testing.T) {
+	r := &JobRetry{MaxAttempts: 10, Backoff: 30}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{9, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := r.Delay(tt.attempts); got != tt.want {
+			t.Errorf("Delay(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestCheckJobRetry(t *testing.T) {
+	tests := []struct {
+		retry   *JobRetry
+		wantErr bool
+	}{
+		{nil, false},
+		{&JobRetry{MaxAttempts: 3, Backoff: 10, On: []string{FailureNonZeroExit}}, false},
+		{&JobRetry{MaxAttempts: 0}, true},
+		{&JobRetry{MaxAttempts: 2, Backoff: -1}, true},
+		{&JobRetry{MaxAttempts: 2, On: []string{FailureTimeout, FailureMissingOutputs}}, false},
+		{&JobRetry{MaxAttempts: 2, On: []string{"oom"}}, true},
+	}
+	for _, tt := range tests {
+		if err := CheckJobRetry(tt.retry); (err != nil) != tt.wantErr {
+			t.Errorf("CheckJobRetry(%+v) = %v, want error: %v", tt.retry, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SpawnExplanation tells whether a hatchery can spawn a worker of a model for a queued job, with the reasons why it cannot
+type SpawnExplanation struct {
+	HatcheryID   int64    `json:"hatchery_id"`
+	HatcheryName string   `json:"hatchery_name"`
+	ModelID      int64    `json:"model_id"`
+	ModelName    string   `json:"model_name"`
+	ModelType    string   `json:"model_type"`
+	CanSpawn     bool     `json:"can_spawn"`
+	Rejections   []string `json:"rejections,omitempty"`
+}
+
+// JobExplanation explains which hatchery and model pairs can spawn a worker for a queued job
+type JobExplanation struct {
+	JobID         int64              `json:"job_id"`
+	JobName       string             `json:"job_name"`
+	QueuedSeconds int64              `json:"queued_seconds"`
+	BookedBy      string             `json:"booked_by,omitempty"`
+	Spawns        []SpawnExplanation `json:"spawns"`
+}
+
+// CheckModelRequirements returns the reasons why a worker of the model spawned on hostname cannot run the job, none if it can.
+// Network access and plugin requirements cannot be checked before spawning and are accepted
+func CheckModelRequirements(model *Model, job *PipelineBuildJob, hostname string) []string {
+	var rejections []string
+	if model.State == ModelStateDisabled {
+		rejections = append(rejections, fmt.Sprintf("model %s is disabled", model.Name))
+	}
+	for _, r := range job.Job.Action.Requirements {
+		switch r.Type {
+		case ModelRequirement:
+			name, version := ParseModelRequirement(r.Value)
+			if name != model.Name {
+				rejections = append(rejections, fmt.Sprintf("model requirement %s does not match model %s", r.Value, model.Name))
+			} else if version != 0 && version != model.Version {
+				rejections = append(rejections, fmt.Sprintf("model requirement %s does not match version %d of model %s", r.Value, model.Version, model.Name))
+			}
+		case HostnameRequirement:
+			if r.Value != hostname {
+				rejections = append(rejections, fmt.Sprintf("hostname requirement %s does not match hostname %s", r.Value, hostname))
+			}
+		case ServiceRequirement, MemoryRequirement:
+			if model.Type != Docker {
+				rejections = append(rejections, fmt.Sprintf("%s requirement %s is only supported by docker models, model %s is %s", r.Type, r.Name, model.Name, model.Type))
+			}
+		case OSRequirement, ArchRequirement:
+			if !MatchPlatformRequirement(r, model.Capabilities) {
+				rejections = append(rejections, fmt.Sprintf("model %s does not run on %s %s", model.Name, r.Type, r.Value))
+			}
+		case BinaryRequirement:
+			found := false
+			for _, c := range model.Capabilities {
+				if r.Value == c.Value || r.Value == c.Name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				rejections = append(rejections, fmt.Sprintf("model %s does not have binary %s(%s)", model.Name, r.Name, r.Value))
+			}
+		}
+	}
+	return rejections
+}
+
+// ExplainPipelineBuildJob explains which hatchery and model pairs can spawn a worker for a queued job
+func ExplainPipelineBuildJob(id int64) (*JobExplanation, error) {
+	data, code, err := Request("GET", fmt.Sprintf("/queue/%d/explain", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	var e JobExplanation
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckModelRequirements(t *testing.T) {
+	model := &Model{
+		Name:         "golang",
+		Type:         HostProcess,
+		Version:      2,
+		Capabilities: append([]Requirement{{Name: "go", Type: BinaryRequirement, Value: "go"}}, PlatformCapabilities("linux", "amd64")...),
+	}
+
+	job := func(reqs ...Requirement) *PipelineBuildJob {
+		j := &PipelineBuildJob{}
+		j.Job.Action.Requirements = reqs
+		return j
+	}
+
+	tests := []struct {
+		job  *PipelineBuildJob
+		want []string
+	}{
+		{job(), nil},
+		{job(Requirement{Name: "go", Type: BinaryRequirement, Value: "go"}), nil},
+		{job(Requirement{Name: "golang", Type: ModelRequirement, Value: "golang"}), nil},
+		{job(Requirement{Name: "host", Type: HostnameRequirement, Value: "build-01"}), nil},
+		{job(Requirement{Name: "net", Type: NetworkAccessRequirement, Value: "github.com:443"}), nil},
+		{job(Requirement{Name: "node", Type: BinaryRequirement, Value: "node"}), []string{"does not have binary node(node)"}},
+		{job(Requirement{Name: "java", Type: ModelRequirement, Value: "java"}), []string{"model requirement java"}},
+		{job(Requirement{Name: "golang", Type: ModelRequirement, Value: "golang@2"}), nil},
+		{job(Requirement{Name: "golang", Type: ModelRequirement, Value: "golang@1"}), []string{"does not match version 2"}},
+		{job(Requirement{Name: "os", Type: OSRequirement, Value: "linux"}), nil},
+		{job(Requirement{Name: "arch", Type: ArchRequirement, Value: "x86_64"}), nil},
+		{job(Requirement{Name: "arch", Type: ArchRequirement, Value: "arm64"}), []string{"does not run on arch arm64"}},
+		{job(Requirement{Name: "host", Type: HostnameRequirement, Value: "build-02"}), []string{"hostname requirement build-02"}},
+		{job(
+			Requirement{Name: "pg", Type: ServiceRequirement, Value: "postgres"},
+			Requirement{Name: "mem", Type: MemoryRequirement, Value: "1024"},
+		), []string{"service requirement pg", "memory requirement mem"}},
+	}
+	for i, tt := range tests {
+		got := CheckModelRequirements(model, tt.job, "build-01")
+		if len(got) != len(tt.want) {
+			t.Errorf("#%d: CheckModelRequirements = %v, want %v", i, got, tt.want)
+			continue
+		}
+		for j := range got {
+			if !strings.Contains(got[j], tt.want[j]) {
+				t.Errorf("#%d: rejection %q does not contain %q", i, got[j], tt.want[j])
+			}
+		}
+	}
+
+	docker := &Model{Name: "docker", Type: Docker}
+	if got := CheckModelRequirements(docker, job(Requirement{Name: "pg", Type: ServiceRequirement, Value: "postgres"}), ""); len(got) != 0 {
+		t.Errorf("docker models must support service requirements, got %v", got)
+	}
+
+	disabled := &Model{Name: "golang", Type: HostProcess, State: ModelStateDisabled}
+	if got := CheckModelRequirements(disabled, job(), ""); len(got) != 1 {
+		t.Errorf("disabled models must be rejected, got %v", got)
+	}
+}
@@ -15,6 +15,8 @@ type Stage struct {
 	Enabled           bool               `json:"enabled"`
 	PipelineBuildJobs []PipelineBuildJob `json:"builds"`
 	Prerequisites     []Prerequisite     `json:"prerequisites"`
+	Condition         string             `json:"condition,omitempty"`
+	Approval          *StageApproval     `json:"approval,omitempty"`
 	LastModified      int64              `json:"last_modified"`
 	Jobs              []Job              `json:"jobs"`
 	Status            Status             `json:"status"`
@@ -29,6 +29,7 @@ type PipelineTrigger struct {
 	Manual        bool           `json:"manual"`
 	Parameters    []Parameter    `json:"parameters"`
 	Prerequisites []Prerequisite `json:"prerequisites"`
+	Condition     string         `json:"condition,omitempty"`
 	LastModified  int64          `json:"last_modified"`
 }
 
@@ -37,7 +37,8 @@ type AuthOpts struct {
 	PrivateKey vcs.SSHKey
 }
 
-// CloneOpts is a optional structs for git clone command
+// CloneOpts is a optional structs for git clone command.
+// Branch can be a ref like refs/pull/1/merge: it is fetched after the clone and CheckoutCommit is ignored
 type CloneOpts struct {
 	Depth                   int
 	SingleBranch            bool
@@ -140,7 +141,7 @@ func gitCommand(repo string, path string, opts *CloneOpts) cmds {
 			}
 		}
 
-		if opts.Branch != "" {
+		if opts.Branch != "" && !isRef(opts.Branch) {
 			gitcmd.args = append(gitcmd.args, "--branch", opts.Branch)
 		} else if opts.SingleBranch {
 			gitcmd.args = append(gitcmd.args, "--single-branch")
@@ -159,18 +160,40 @@ func gitCommand(repo string, path string, opts *CloneOpts) cmds {
 
 	allCmd = append(allCmd, gitcmd)
 
+	//Locate the next git commands to the right directory
+	dir := path
+	if dir == "" {
+		t := strings.Split(repo, "/")
+		dir = strings.TrimSuffix(t[len(t)-1], ".git")
+	}
+
+	if opts != nil && isRef(opts.Branch) {
+		fetchCmd := cmd{
+			dir:  dir,
+			cmd:  "git",
+			args: []string{"fetch"},
+		}
+		if opts.Depth != 0 {
+			fetchCmd.args = append(fetchCmd.args, "--depth", fmt.Sprintf("%d", opts.Depth))
+		}
+		fetchCmd.args = append(fetchCmd.args, "origin", opts.Branch)
+
+		checkoutCmd := cmd{
+			dir:  dir,
+			cmd:  "git",
+			args: []string{"checkout", "FETCH_HEAD"},
+		}
+
+		allCmd = append(allCmd, fetchCmd, checkoutCmd)
+		return cmds(allCmd)
+	}
+
 	if opts != nil && opts.CheckoutCommit != "" {
 		resetCmd := cmd{
+			dir:  dir,
 			cmd:  "git",
 			args: []string{"reset", "--hard", opts.CheckoutCommit},
 		}
-		//Locate the git reset cmd to the right directory
-		if path == "" {
-			t := strings.Split(repo, "/")
-			resetCmd.dir = strings.TrimSuffix(t[len(t)-1], ".git")
-		} else {
-			resetCmd.dir = path
-		}
 
 		allCmd = append(allCmd, resetCmd)
 	}
@@ -178,6 +201,11 @@ func gitCommand(repo string, path string, opts *CloneOpts) cmds {
 	return cmds(allCmd)
 }
 
+//isRef returns true for full references like refs/pull/1/merge, which can't be cloned with --branch
+func isRef(branch string) bool {
+	return strings.HasPrefix(branch, "refs/") && !strings.HasPrefix(branch, "refs/heads/") && !strings.HasPrefix(branch, "refs/tags/")
+}
+
 type cmds []cmd
 
 func (c cmds) Strings() []string {
@@ -210,6 +210,23 @@ func Test_gitCommand(t *testing.T) {
 				"git reset --hard eb8b87a",
 			},
 		},
+		{
+			name: "Clone public repo over http and checkout pull request merge ref",
+			args: args{
+				repo: "https://github.com/ovh/cds.git",
+				path: "/tmp/Test_gitCommand-4",
+				opts: &CloneOpts{
+					Branch:         "refs/pull/1/merge",
+					Depth:          1,
+					CheckoutCommit: "eb8b87a",
+				},
+			},
+			want: []string{
+				"git clone https://github.com/ovh/cds.git /tmp/Test_gitCommand-4",
+				"git fetch --depth 1 origin refs/pull/1/merge",
+				"git checkout FETCH_HEAD",
+			},
+		},
 	}
 	for _, tt := range tests {
 		os.RemoveAll(tt.args.path)
@@ -16,6 +16,7 @@ type Worker struct {
 	HatcheryID int64     `json:"hatchery_id"`
 	Status     Status    `json:"status"` // Waiting, Building, Disabled, Unknown
 	Uptodate   bool      `json:"up_to_date"`
+	Introspect bool      `json:"introspect,omitempty"` // set on registration when the model of the worker must be introspected
 }
 
 // Existing worker type
@@ -37,14 +38,19 @@ var (
 // Model represents a worker model (ex: Go 1.5.1 Docker Images)
 // with specified capabilities (ex: go, golint and go2xunit binaries)
 type Model struct {
-	ID           int64         `json:"id" db:"id"`
-	Name         string        `json:"name"  db:"name"`
-	Type         string        `json:"type"  db:"type"`
-	Image        string        `json:"image" db:"image"`
-	Capabilities []Requirement `json:"capabilities" db:"-"`
-	CreatedBy    User          `json:"created_by" db:"-"`
-	OwnerID      int64         `json:"owner_id" db:"owner_id"` //DEPRECATED
-	GroupID      int64         `json:"group_id" db:"group_id"`
+	ID           int64               `json:"id" db:"id"`
+	Name         string              `json:"name"  db:"name"`
+	Type         string              `json:"type"  db:"type"`
+	Image        string              `json:"image" db:"image"`
+	Capabilities []Requirement       `json:"capabilities" db:"-"`
+	CreatedBy    User                `json:"created_by" db:"-"`
+	OwnerID      int64               `json:"owner_id" db:"owner_id"` //DEPRECATED
+	GroupID      int64               `json:"group_id" db:"group_id"`
+	Version      int64               `json:"version" db:"version"`
+	State        string              `json:"state" db:"state"`
+	Scaling      *ModelScalingPolicy `json:"scaling,omitempty" db:"-"`
+	// IntrospectionRequested is set when a worker of the model must be spawned to discover its capabilities
+	IntrospectionRequested bool `json:"introspection_requested,omitempty" db:"-"`
 }
 
 // ModelStatus sums up the number of worker deployed and wanted for a given model
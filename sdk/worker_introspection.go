@@ -0,0 +1,157 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Status of a worker model introspection
+const (
+	IntrospectionPending = "pending"
+	IntrospectionDone    = "done"
+)
+
+// ModelIntrospection is a capability discovery of a worker model: a worker of the model is spawned,
+// it discovers the binaries installed on its PATH, their versions, its memory, os and arch,
+// and the capabilities of the model are updated with what it found
+type ModelIntrospection struct {
+	ID          int64                `json:"id"`
+	ModelID     int64                `json:"model_id"`
+	Status      string               `json:"status"`
+	Requested   time.Time            `json:"requested"`
+	RequestedBy string               `json:"requested_by"`
+	Done        time.Time            `json:"done,omitempty"`
+	WorkerName  string               `json:"worker_name,omitempty"`
+	Result      *IntrospectionResult `json:"result,omitempty"`
+	Added       []Requirement        `json:"added,omitempty"`
+	Removed     []Requirement        `json:"removed,omitempty"`
+}
+
+// IntrospectionResult is what a worker discovered about its model. Checked are all the binaries looked up,
+// capabilities of binaries checked but not found are removed from the model
+type IntrospectionResult struct {
+	Capabilities []Requirement     `json:"capabilities"`
+	Checked      []string          `json:"checked"`
+	Versions     map[string]string `json:"versions,omitempty"`
+	Memory       int64             `json:"memory,omitempty"` // in MB
+	OS           string            `json:"os"`
+	Arch         string            `json:"arch"`
+}
+
+// MergeCapabilities returns the capabilities of a model once updated with an introspection result.
+// Binary capabilities are replaced by the ones discovered, except binaries which were not checked,
+// memory, os and arch capabilities are replaced if discovered, the others are kept
+func MergeCapabilities(current []Requirement, res IntrospectionResult) []Requirement {
+	checked := map[string]bool{}
+	for _, c := range res.Checked {
+		checked[c] = true
+	}
+	discovered := map[string]bool{}
+	for _, c := range res.Capabilities {
+		if c.Type != BinaryRequirement {
+			discovered[c.Type] = true
+		}
+	}
+
+	merged := []Requirement{}
+	for _, c := range current {
+		switch {
+		case discovered[c.Type]:
+			continue
+		case c.Type == BinaryRequirement && checked[c.Value]:
+			continue
+		}
+		merged = append(merged, c)
+	}
+
+	for _, c := range res.Capabilities {
+		found := false
+		for _, m := range merged {
+			if m.Type == c.Type && m.Value == c.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, c)
+		}
+	}
+	return merged
+}
+
+// DiffCapabilities returns the capabilities added and removed between two lists of capabilities
+func DiffCapabilities(before, after []Requirement) ([]Requirement, []Requirement) {
+	contains := func(reqs []Requirement, r Requirement) bool {
+		for _, c := range reqs {
+			if c.Type == r.Type && c.Name == r.Name && c.Value == r.Value {
+				return true
+			}
+		}
+		return false
+	}
+
+	var added, removed []Requirement
+	for _, c := range after {
+		if !contains(before, c) {
+			added = append(added, c)
+		}
+	}
+	for _, c := range before {
+		if !contains(after, c) {
+			removed = append(removed, c)
+		}
+	}
+	return added, removed
+}
+
+// RequestWorkerModelIntrospection requests the introspection of a worker model, done by the next worker of the model
+func RequestWorkerModelIntrospection(modelID int64) (*ModelIntrospection, error) {
+	data, code, err := Request("POST", fmt.Sprintf("/worker/model/%d/introspection", modelID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	var i ModelIntrospection
+	if err := json.Unmarshal(data, &i); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// GetWorkerModelIntrospections retrieves the history of the introspections of a worker model, latest first
+func GetWorkerModelIntrospections(modelID int64) ([]ModelIntrospection, error) {
+	data, code, err := Request("GET", fmt.Sprintf("/worker/model/%d/introspection", modelID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	var is []ModelIntrospection
+	if err := json.Unmarshal(data, &is); err != nil {
+		return nil, err
+	}
+	return is, nil
+}
+
+// PostWorkerModelIntrospection sends the result of the introspection of the model of the calling worker
+func PostWorkerModelIntrospection(res IntrospectionResult) error {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	_, code, err := Request("POST", "/worker/introspection", data)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
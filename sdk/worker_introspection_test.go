@@ -0,0 +1,63 @@
+package sdk
+
+import (
+	"testing"
+)
+
+func TestMergeCapabilities(t *testing.T) {
+	current := []Requirement{
+		{Name: "go", Type: BinaryRequirement, Value: "go"},
+		{Name: "npm", Type: BinaryRequirement, Value: "npm"},
+		{Name: "legacy", Type: BinaryRequirement, Value: "legacy"},
+		{Name: "memory", Type: MemoryRequirement, Value: "512"},
+		{Name: "github", Type: NetworkAccessRequirement, Value: "github.com:443"},
+	}
+	res := IntrospectionResult{
+		Checked: []string{"go", "npm", "git"},
+		Capabilities: []Requirement{
+			{Name: "go", Type: BinaryRequirement, Value: "go"},
+			{Name: "git", Type: BinaryRequirement, Value: "git"},
+			{Name: "memory", Type: MemoryRequirement, Value: "2048"},
+		},
+	}
+
+	merged := MergeCapabilities(current, res)
+	want := []Requirement{
+		{Name: "legacy", Type: BinaryRequirement, Value: "legacy"},
+		{Name: "github", Type: NetworkAccessRequirement, Value: "github.com:443"},
+		{Name: "go", Type: BinaryRequirement, Value: "go"},
+		{Name: "git", Type: BinaryRequirement, Value: "git"},
+		{Name: "memory", Type: MemoryRequirement, Value: "2048"},
+	}
+	if len(merged) != len(want) {
+		t.Fatalf("MergeCapabilities = %v, want %v", merged, want)
+	}
+	for i := range want {
+		if merged[i] != want[i] {
+			t.Errorf("capability %d = %v, want %v", i, merged[i], want[i])
+		}
+	}
+
+	added, removed := DiffCapabilities(current, merged)
+	if len(added) != 2 || added[0].Value != "git" || added[1].Value != "2048" {
+		t.Errorf("unexpected added capabilities %v", added)
+	}
+	if len(removed) != 2 || removed[0].Value != "npm" || removed[1].Value != "512" {
+		t.Errorf("unexpected removed capabilities %v", removed)
+	}
+}
+
+func TestMergePlatformCapabilities(t *testing.T) {
+	current := append([]Requirement{{Name: "go", Type: BinaryRequirement, Value: "go"}}, PlatformCapabilities("linux", "amd64")...)
+
+	merged := MergeCapabilities(current, IntrospectionResult{Checked: []string{}})
+	if len(merged) != len(current) {
+		t.Errorf("os and arch capabilities must be kept when not discovered, got %v", merged)
+	}
+
+	merged = MergeCapabilities(current, IntrospectionResult{Capabilities: PlatformCapabilities("linux", "arm64")})
+	added, removed := DiffCapabilities(current, merged)
+	if len(added) != 1 || added[0].Value != "arm64" || len(removed) != 1 || removed[0].Value != "amd64" {
+		t.Errorf("arch capability must be replaced, added %v removed %v", added, removed)
+	}
+}
@@ -0,0 +1,191 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// States of a worker model: deprecated models are still spawned but raise warnings on the pipelines using them,
+// disabled models are not spawned anymore
+const (
+	ModelStateActive     = "active"
+	ModelStateDeprecated = "deprecated"
+	ModelStateDisabled   = "disabled"
+)
+
+// AvailableModelStates list all existing states of worker models
+var AvailableModelStates = []string{
+	ModelStateActive,
+	ModelStateDeprecated,
+	ModelStateDisabled,
+}
+
+// ModelVersion is an immutable version of a worker model. Every change of the type, image or capabilities
+// of a model creates a new version, the version of the model is its current version
+type ModelVersion struct {
+	ModelID      int64         `json:"model_id"`
+	Version      int64         `json:"version"`
+	Type         string        `json:"type"`
+	Image        string        `json:"image"`
+	Capabilities []Requirement `json:"capabilities"`
+	Created      time.Time     `json:"created"`
+	CreatedBy    string        `json:"created_by"`
+}
+
+// ModelAudit is a change made on a worker model
+type ModelAudit struct {
+	ID       int64     `json:"id"`
+	ModelID  int64     `json:"model_id"`
+	Created  time.Time `json:"created"`
+	Username string    `json:"username"`
+	Change   string    `json:"change"`
+	Detail   string    `json:"detail"`
+}
+
+// Changes recorded in the audit of worker models
+const (
+	ModelAuditCreated        = "created"
+	ModelAuditUpdated        = "updated"
+	ModelAuditNewVersion     = "new_version"
+	ModelAuditCurrentVersion = "current_version"
+	ModelAuditState          = "state"
+)
+
+// ParseModelRequirement splits the value of a model requirement into the model name and the pinned version,
+// 0 if the requirement is not pinned: "golang@3" is the version 3 of the model golang
+func ParseModelRequirement(value string) (string, int64) {
+	i := strings.LastIndex(value, "@")
+	if i <= 0 {
+		return value, 0
+	}
+	version, err := strconv.ParseInt(value[i+1:], 10, 64)
+	if err != nil || version <= 0 {
+		return value, 0
+	}
+	return value[:i], version
+}
+
+// PinnedModelVersion returns the version of the model pinned by a model requirement, 0 if the model is not pinned
+func PinnedModelVersion(reqs []Requirement, modelName string) int64 {
+	for _, r := range reqs {
+		if r.Type != ModelRequirement {
+			continue
+		}
+		if name, version := ParseModelRequirement(r.Value); name == modelName {
+			return version
+		}
+	}
+	return 0
+}
+
+// IsValidModelState returns true if state is a state of worker models
+func IsValidModelState(state string) bool {
+	for _, s := range AvailableModelStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVersionChanged returns true if the type, image or capabilities of the model differ from m,
+// which requires a new version of the model
+func (m Model) IsVersionChanged(n Model) bool {
+	if m.Type != n.Type || m.Image != n.Image || len(m.Capabilities) != len(n.Capabilities) {
+		return true
+	}
+	added, removed := DiffCapabilities(m.Capabilities, n.Capabilities)
+	return len(added) > 0 || len(removed) > 0
+}
+
+// WithVersion returns a copy of the model at the given version
+func (m Model) WithVersion(v ModelVersion) Model {
+	m.Version = v.Version
+	m.Type = v.Type
+	m.Image = v.Image
+	m.Capabilities = v.Capabilities
+	return m
+}
+
+// GetWorkerModelVersions retrieves all the versions of a worker model, latest first
+func GetWorkerModelVersions(modelID int64) ([]ModelVersion, error) {
+	data, code, err := Request("GET", fmt.Sprintf("/worker/model/%d/version", modelID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	var vs []ModelVersion
+	if err := json.Unmarshal(data, &vs); err != nil {
+		return nil, err
+	}
+	return vs, nil
+}
+
+// GetWorkerModelVersion retrieves a version of a worker model
+func GetWorkerModelVersion(modelID, version int64) (*ModelVersion, error) {
+	data, code, err := Request("GET", fmt.Sprintf("/worker/model/%d/version/%d", modelID, version), nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	var v ModelVersion
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// SetWorkerModelCurrentVersion moves the current version of a worker model to an existing version
+func SetWorkerModelCurrentVersion(modelID, version int64) error {
+	_, code, err := Request("PUT", fmt.Sprintf("/worker/model/%d/version/%d/current", modelID, version), nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+// SetWorkerModelState sets the state of a worker model: active, deprecated or disabled
+func SetWorkerModelState(modelID int64, state string) error {
+	data, err := json.Marshal(map[string]string{"state": state})
+	if err != nil {
+		return err
+	}
+
+	_, code, err := Request("PUT", fmt.Sprintf("/worker/model/%d/state", modelID), data)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+// GetWorkerModelAudits retrieves the changes made on a worker model, latest first
+func GetWorkerModelAudits(modelID int64) ([]ModelAudit, error) {
+	data, code, err := Request("GET", fmt.Sprintf("/worker/model/%d/audit", modelID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	var as []ModelAudit
+	if err := json.Unmarshal(data, &as); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
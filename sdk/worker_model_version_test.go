@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"testing"
+)
+
+func TestParseModelRequirement(t *testing.T) {
+	tests := []struct {
+		value   string
+		name    string
+		version int64
+	}{
+		{"golang", "golang", 0},
+		{"golang@3", "golang", 3},
+		{"golang@latest", "golang@latest", 0},
+		{"golang@0", "golang@0", 0},
+		{"@3", "@3", 0},
+		{"my@model@12", "my@model", 12},
+	}
+	for _, tt := range tests {
+		name, version := ParseModelRequirement(tt.value)
+		if name != tt.name || version != tt.version {
+			t.Errorf("ParseModelRequirement(%q) = %q, %d, want %q, %d", tt.value, name, version, tt.name, tt.version)
+		}
+	}
+}
+
+func TestModelIsVersionChanged(t *testing.T) {
+	m := Model{
+		Name:         "golang",
+		Type:         Docker,
+		Image:        "golang:1.8",
+		Capabilities: []Requirement{{Name: "go", Type: BinaryRequirement, Value: "go"}},
+	}
+
+	renamed := m
+	renamed.Name = "go"
+	renamed.GroupID = 2
+	if m.IsVersionChanged(renamed) {
+		t.Errorf("renaming a model must not create a new version")
+	}
+
+	image := m
+	image.Image = "golang:1.9"
+	if !m.IsVersionChanged(image) {
+		t.Errorf("changing the image of a model must create a new version")
+	}
+
+	capas := m
+	capas.Capabilities = []Requirement{{Name: "go", Type: BinaryRequirement, Value: "go"}, {Name: "git", Type: BinaryRequirement, Value: "git"}}
+	if !m.IsVersionChanged(capas) {
+		t.Errorf("changing the capabilities of a model must create a new version")
+	}
+}
@@ -0,0 +1,281 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ModelScalingPolicy is the scaling policy of a worker model: hatcheries keep between MinWarm idle workers
+// and MaxWarm workers of the model, MaxWarm counting the registered workers of all hatcheries. When jobs are waiting, ScaleUpStep workers are spawned on each provisioning,
+// one more step for each JobAgeSeconds the oldest job has waited. Idle workers beyond the wanted count are
+// killed after IdleMinutes
+type ModelScalingPolicy struct {
+	ModelID       int64                  `json:"model_id"`
+	MinWarm       int                    `json:"min_warm"`
+	MaxWarm       int                    `json:"max_warm"`
+	ScaleUpStep   int                    `json:"scale_up_step,omitempty"`
+	JobAgeSeconds int64                  `json:"job_age_seconds,omitempty"`
+	IdleMinutes   int                    `json:"idle_minutes,omitempty"`
+	Timezone      string                 `json:"timezone,omitempty"`
+	Schedules     []ModelScalingSchedule `json:"schedules,omitempty"`
+}
+
+// ModelScalingSchedule raises the idle workers kept by a scaling policy during a time window,
+// such as office hours. Days is a list of days (mon,wed) or a range (mon-fri), Start and End are HH:MM
+type ModelScalingSchedule struct {
+	Days    string `json:"days"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	MinWarm int    `json:"min_warm"`
+}
+
+var scalingWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// CheckModelScalingPolicy checks the scaling policy of a worker model
+func CheckModelScalingPolicy(p *ModelScalingPolicy) error {
+	if p.MinWarm < 0 || p.MaxWarm < 1 || p.MinWarm > p.MaxWarm {
+		return fmt.Errorf("max warm must be at least 1 and greater than min warm, got min %d and max %d", p.MinWarm, p.MaxWarm)
+	}
+	if p.ScaleUpStep < 0 || p.JobAgeSeconds < 0 || p.IdleMinutes < 0 {
+		return fmt.Errorf("scale up step, job age and idle minutes must be positive")
+	}
+	if _, err := time.LoadLocation(p.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %s: %s", p.Timezone, err)
+	}
+	for _, s := range p.Schedules {
+		if _, err := s.weekdays(); err != nil {
+			return err
+		}
+		start, errs := parseScheduleTime(s.Start)
+		if errs != nil {
+			return errs
+		}
+		end, erre := parseScheduleTime(s.End)
+		if erre != nil {
+			return erre
+		}
+		if start >= end {
+			return fmt.Errorf("schedule %s must end after its start", s)
+		}
+		if s.MinWarm < 0 || s.MinWarm > p.MaxWarm {
+			return fmt.Errorf("schedule %s must keep between 0 and max warm (%d) workers", s, p.MaxWarm)
+		}
+	}
+	return nil
+}
+
+// ActiveMinWarm returns the idle workers to keep at the given time: the highest min warm of the active schedules
+// or the min warm of the policy
+func (p *ModelScalingPolicy) ActiveMinWarm(now time.Time) int {
+	if loc, err := time.LoadLocation(p.Timezone); err == nil {
+		now = now.In(loc)
+	}
+
+	min := p.MinWarm
+	for _, s := range p.Schedules {
+		if s.IsActive(now) && s.MinWarm > min {
+			min = s.MinWarm
+		}
+	}
+	return min
+}
+
+// Wanted returns the number of workers wanted for the model, given the workers currently building and the jobs waiting for the model
+func (p *ModelScalingPolicy) Wanted(now time.Time, building, waitingJobs int) int {
+	idle := p.ActiveMinWarm(now)
+	if waitingJobs > idle {
+		idle = waitingJobs
+	}
+	wanted := building + idle
+	if wanted > p.MaxWarm {
+		wanted = p.MaxWarm
+	}
+	return wanted
+}
+
+// SpawnCount returns the number of workers to spawn now to reach the wanted count. The step grows with the age of the oldest waiting job
+func (p *ModelScalingPolicy) SpawnCount(current, wanted int, oldestJobSeconds int64) int {
+	if wanted <= current {
+		return 0
+	}
+
+	step := p.ScaleUpStep
+	if step == 0 {
+		step = 1
+	}
+	if p.JobAgeSeconds > 0 && oldestJobSeconds > 0 {
+		step *= 1 + int(oldestJobSeconds/p.JobAgeSeconds)
+	}
+	if step > wanted-current {
+		step = wanted - current
+	}
+	return step
+}
+
+// IsIdleExpired returns true if a worker idle since the given time can be killed
+func (p *ModelScalingPolicy) IsIdleExpired(idleSince, now time.Time) bool {
+	return now.Sub(idleSince) >= time.Duration(p.IdleMinutes)*time.Minute
+}
+
+// IsActive returns true if the schedule is active at the given time
+func (s ModelScalingSchedule) IsActive(now time.Time) bool {
+	days, err := s.weekdays()
+	if err != nil || !days[now.Weekday()] {
+		return false
+	}
+	start, errs := parseScheduleTime(s.Start)
+	end, erre := parseScheduleTime(s.End)
+	if errs != nil || erre != nil {
+		return false
+	}
+	minutes := now.Hour()*60 + now.Minute()
+	return minutes >= start && minutes < end
+}
+
+// String returns the schedule as parsed by ParseModelScalingSchedule
+func (s ModelScalingSchedule) String() string {
+	return fmt.Sprintf("%s %s-%s %d", s.Days, s.Start, s.End, s.MinWarm)
+}
+
+// ParseModelScalingSchedule parses a schedule "days HH:MM-HH:MM min_warm", such as "mon-fri 08:00-19:00 3"
+func ParseModelScalingSchedule(str string) (ModelScalingSchedule, error) {
+	fields := strings.Fields(str)
+	if len(fields) != 3 {
+		return ModelScalingSchedule{}, fmt.Errorf("invalid schedule '%s', expected 'days HH:MM-HH:MM min_warm'", str)
+	}
+
+	hours := strings.Split(fields[1], "-")
+	if len(hours) != 2 {
+		return ModelScalingSchedule{}, fmt.Errorf("invalid schedule hours '%s', expected HH:MM-HH:MM", fields[1])
+	}
+
+	min, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return ModelScalingSchedule{}, fmt.Errorf("invalid schedule min warm '%s'", fields[2])
+	}
+
+	s := ModelScalingSchedule{Days: strings.ToLower(fields[0]), Start: hours[0], End: hours[1], MinWarm: min}
+	if _, err := s.weekdays(); err != nil {
+		return ModelScalingSchedule{}, err
+	}
+	if _, err := parseScheduleTime(s.Start); err != nil {
+		return ModelScalingSchedule{}, err
+	}
+	if _, err := parseScheduleTime(s.End); err != nil {
+		return ModelScalingSchedule{}, err
+	}
+	return s, nil
+}
+
+func (s ModelScalingSchedule) weekdays() (map[time.Weekday]bool, error) {
+	days := map[time.Weekday]bool{}
+	for _, d := range strings.Split(strings.ToLower(s.Days), ",") {
+		bounds := strings.Split(d, "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("invalid schedule days '%s'", s.Days)
+		}
+		from, ok := scalingWeekdays[bounds[0]]
+		if !ok {
+			return nil, fmt.Errorf("invalid schedule day '%s'", bounds[0])
+		}
+		to := from
+		if len(bounds) == 2 {
+			if to, ok = scalingWeekdays[bounds[1]]; !ok {
+				return nil, fmt.Errorf("invalid schedule day '%s'", bounds[1])
+			}
+		}
+		for day := from; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseScheduleTime returns the minutes since midnight of HH:MM, 24:00 being the end of the day
+func parseScheduleTime(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		if s == "24:00" {
+			return 24 * 60, nil
+		}
+		return 0, fmt.Errorf("invalid schedule time '%s', expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// GetWorkerModelScaling retrieves the scaling policy of a worker model
+func GetWorkerModelScaling(modelID int64) (*ModelScalingPolicy, error) {
+	data, code, err := Request("GET", fmt.Sprintf("/worker/model/%d/scaling", modelID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	var p ModelScalingPolicy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// UpdateWorkerModelScaling sets the scaling policy of a worker model
+func UpdateWorkerModelScaling(p ModelScalingPolicy) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	_, code, err := Request("PUT", fmt.Sprintf("/worker/model/%d/scaling", p.ModelID), data)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+// DeleteWorkerModelScaling removes the scaling policy of a worker model, hatcheries then use their flat provisioning
+func DeleteWorkerModelScaling(modelID int64) error {
+	_, code, err := Request("DELETE", fmt.Sprintf("/worker/model/%d/scaling", modelID), nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+// GetWorkerModelStatus retrieves the workers started, wanted and building of each worker model, summed over all hatcheries
+func GetWorkerModelStatus() ([]ModelStatus, error) {
+	data, code, err := Request("GET", "/worker/model/status", nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	var status []ModelStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,117 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseModelScalingSchedule(t *testing.T) {
+	s, err := ParseModelScalingSchedule("Mon-Fri 08:00-19:00 3")
+	if err != nil {
+		t.Fatalf("ParseModelScalingSchedule: %s", err)
+	}
+	if s.Days != "mon-fri" || s.Start != "08:00" || s.End != "19:00" || s.MinWarm != 3 {
+		t.Errorf("unexpected schedule %+v", s)
+	}
+	if s.String() != "mon-fri 08:00-19:00 3" {
+		t.Errorf("String() = %s", s)
+	}
+
+	for _, str := range []string{"", "mon-fri 08:00-19:00", "monday 08:00-19:00 3", "mon 8h-19h 3", "mon 08:00-19:00 x", "mon-tue-wed 08:00-19:00 1"} {
+		if _, err := ParseModelScalingSchedule(str); err == nil {
+			t.Errorf("ParseModelScalingSchedule(%q) must fail", str)
+		}
+	}
+}
+
+func TestModelScalingScheduleIsActive(t *testing.T) {
+	office := ModelScalingSchedule{Days: "mon-fri", Start: "08:00", End: "19:00", MinWarm: 3}
+	weekend := ModelScalingSchedule{Days: "sat-sun", Start: "00:00", End: "24:00", MinWarm: 1}
+
+	// 2017-05-01 is a monday
+	tests := []struct {
+		s    ModelScalingSchedule
+		now  time.Time
+		want bool
+	}{
+		{office, time.Date(2017, 5, 1, 8, 0, 0, 0, time.UTC), true},
+		{office, time.Date(2017, 5, 5, 18, 59, 0, 0, time.UTC), true},
+		{office, time.Date(2017, 5, 1, 19, 0, 0, 0, time.UTC), false},
+		{office, time.Date(2017, 5, 6, 10, 0, 0, 0, time.UTC), false},
+		{weekend, time.Date(2017, 5, 7, 23, 59, 0, 0, time.UTC), true},
+		{weekend, time.Date(2017, 5, 1, 10, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := tt.s.IsActive(tt.now); got != tt.want {
+			t.Errorf("%s IsActive(%s) = %v, want %v", tt.s, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestModelScalingPolicy(t *testing.T) {
+	p := &ModelScalingPolicy{
+		MinWarm:       1,
+		MaxWarm:       10,
+		ScaleUpStep:   2,
+		JobAgeSeconds: 60,
+		IdleMinutes:   15,
+		Timezone:      "Europe/Paris",
+		Schedules:     []ModelScalingSchedule{{Days: "mon-fri", Start: "08:00", End: "19:00", MinWarm: 4}},
+	}
+	if err := CheckModelScalingPolicy(p); err != nil {
+		t.Fatalf("CheckModelScalingPolicy: %s", err)
+	}
+
+	// 07:00 UTC is 09:00 in Paris
+	monday := time.Date(2017, 5, 1, 7, 0, 0, 0, time.UTC)
+	night := time.Date(2017, 5, 1, 22, 0, 0, 0, time.UTC)
+	if got := p.ActiveMinWarm(monday); got != 4 {
+		t.Errorf("ActiveMinWarm during office hours = %d, want 4", got)
+	}
+	if got := p.ActiveMinWarm(night); got != 1 {
+		t.Errorf("ActiveMinWarm at night = %d, want 1", got)
+	}
+
+	if got := p.Wanted(night, 2, 0); got != 3 {
+		t.Errorf("Wanted without waiting jobs = %d, want 3", got)
+	}
+	if got := p.Wanted(night, 2, 5); got != 7 {
+		t.Errorf("Wanted with waiting jobs = %d, want 7", got)
+	}
+	if got := p.Wanted(monday, 8, 5); got != 10 {
+		t.Errorf("Wanted must be bounded by max warm, got %d", got)
+	}
+
+	if got := p.SpawnCount(3, 3, 0); got != 0 {
+		t.Errorf("SpawnCount when wanted is reached = %d, want 0", got)
+	}
+	if got := p.SpawnCount(0, 10, 10); got != 2 {
+		t.Errorf("SpawnCount with fresh jobs = %d, want 2", got)
+	}
+	if got := p.SpawnCount(0, 10, 130); got != 6 {
+		t.Errorf("SpawnCount with old jobs = %d, want 6", got)
+	}
+	if got := p.SpawnCount(8, 10, 600); got != 2 {
+		t.Errorf("SpawnCount must not exceed wanted, got %d", got)
+	}
+
+	if p.IsIdleExpired(night.Add(-10*time.Minute), night) {
+		t.Errorf("worker idle for 10 minutes must not be killed")
+	}
+	if !p.IsIdleExpired(night.Add(-15*time.Minute), night) {
+		t.Errorf("worker idle for 15 minutes must be killed")
+	}
+
+	invalids := []ModelScalingPolicy{
+		{MinWarm: 2, MaxWarm: 1},
+		{MinWarm: 0, MaxWarm: 0},
+		{MaxWarm: 1, Timezone: "Nowhere/Never"},
+		{MaxWarm: 1, Schedules: []ModelScalingSchedule{{Days: "mon", Start: "19:00", End: "08:00"}}},
+		{MaxWarm: 1, Schedules: []ModelScalingSchedule{{Days: "mon", Start: "08:00", End: "19:00", MinWarm: 2}}},
+	}
+	for _, i := range invalids {
+		if err := CheckModelScalingPolicy(&i); err == nil {
+			t.Errorf("CheckModelScalingPolicy(%+v) must fail", i)
+		}
+	}
+}
@@ -12,6 +12,7 @@ export class Action {
     final: boolean;
     last_modified: boolean;
     enabled: boolean;
+    timeout: number;
 
     // UI parameter
     hasChanged: boolean;
@@ -4,4 +4,12 @@ export class Artifact {
     tag: string;
     download_hash: string;
     size: number;
+    pinned: boolean;
+}
+
+export class ArtifactRetention {
+    id: number;
+    keep_last: number;
+    keep_days: number;
+    keep_deployed: boolean;
 }
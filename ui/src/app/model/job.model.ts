@@ -6,6 +6,12 @@ export class Job {
     enabled: boolean;
     last_modified: boolean;
     step_status: Array<StepStatus>;
+    matrix: Array<MatrixAxis>;
+    timeout: number;
+    outputs: Array<JobOutput>;
+    retry: JobRetry;
+    retries: number;
+    failure_class: string;
 
 
     // UI parameter
@@ -20,3 +26,23 @@ export class StepStatus {
     step_order: number;
     status: string;
 }
+
+export class MatrixAxis {
+    name: string;
+    values: Array<string>;
+    allow_failure: Array<string>;
+    models: {};
+}
+
+export class JobOutput {
+    name: string;
+    type: string;
+    description: string;
+    value: string;
+}
+
+export class JobRetry {
+    max_attempts: number;
+    backoff: number;
+    on: Array<string>;
+}
@@ -5,7 +5,7 @@ import {User} from './user.model';
 import {Application} from './application.model';
 import {Environment} from './environment.model';
 import {Artifact} from './artifact.model';
-import {Job} from './job.model';
+import {Job, JobOutput} from './job.model';
 import {Commit} from './repositories.model';
 
 export class Pipeline {
@@ -20,6 +20,7 @@ export class Pipeline {
     last_modified: number;
     projectKey: string;
     attached_application: Array<Application>;
+    concurrency: Concurrency;
 
     // true if someone has updated the pipeline ( used for warnings )
     externalChange: boolean;
@@ -68,6 +69,11 @@ export class Pipeline {
     }
 }
 
+export class Concurrency {
+    key: string;
+    policy: string;
+}
+
 export class PipelineRunRequest {
     parameters: Array<Parameter>;
     env: Environment;
@@ -91,6 +97,8 @@ export class PipelineBuild {
     start: string;
     done: string;
     stages: Array<Stage>;
+    concurrency_key: string;
+    waiting_reason: string;
     pipeline: Pipeline;
     application: Application;
     environment: Environment;
@@ -126,6 +134,7 @@ export class PipelineBuildJob {
     model: number;
     pipeline_build_id: number;
     spawninfos: Array<SpawnInfo>;
+    outputs: Array<JobOutput>;
 }
 
 export class SpawnInfo {
@@ -140,6 +149,18 @@ export class BuildResult {
     step_logs: Log;
 }
 
+export class JobAttempt {
+    attempt: number;
+    status: string;
+    failure_class: string;
+    reason: string;
+    worker_name: string;
+    start: string;
+    done: string;
+    spawninfos: Array<SpawnInfo>;
+    logs: Array<Log>;
+}
+
 export interface Log {
     id: number;
     action_build_id: number;
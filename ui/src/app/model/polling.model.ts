@@ -6,6 +6,7 @@ export class RepositoryPoller {
     application: Application;
     pipeline: Pipeline;
     enabled: boolean;
+    pull_requests: boolean;
     date_creation: Date;
     next_execution: RepositoryPollerExecution;
 
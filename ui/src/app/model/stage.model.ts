@@ -10,9 +10,26 @@ export class Stage {
   jobs: Array<Job>;
   builds: Array<PipelineBuildJob>;
   prerequisites: Array<Prerequisite>;
+  condition: string;
+  approval: StageApproval;
   last_modified: number;
 
   // UI params
   hasChanged: boolean;
   edit: boolean;
 }
+
+export class StageApproval {
+  groups: Array<string>;
+  required: number;
+  timeout: number;
+  start: string;
+  decisions: Array<ApprovalDecision>;
+}
+
+export class ApprovalDecision {
+  user: string;
+  approved: boolean;
+  comment: string;
+  date: string;
+}
@@ -18,6 +18,7 @@ export class Trigger {
     manual: boolean;
     parameters: Array<Parameter>;
     prerequisites: Array<Prerequisite>;
+    condition: string;
     last_modified: number;
 
     // flag to know if variable data has changed